@@ -12,17 +12,22 @@ import (
 
 func main() {
 	userClient := client.NewUserServiceClient("localhost:50051")
-	userHandler := handler.NewUserHandler(userClient)
-
 	instrumentClient := client.NewInstrumentServiceClient("localhost:50052")
-	instrumentHandler := handler.NewInstrumentHandler(instrumentClient)
-
 	cartClient := client.NewCartServiceClient("localhost:50053")
+
+	userHandler := handler.NewUserHandler(userClient, cartClient)
+	instrumentHandler := handler.NewInstrumentHandler(instrumentClient)
 	cartHandler := handler.NewCartHandler(cartClient)
 
 	orderClient := client.NewOrderServiceClien("localhost:50054")
 	orderHandler := handler.NewOrderHandle(orderClient, userClient)
 
+	wishlistClient := client.NewWishlistServiceClient("localhost:50055")
+	wishlistHandler := handler.NewWishlistHandler(wishlistClient)
+
+	rentalClient := client.NewRentalServiceClient("localhost:50056")
+	rentalHandler := handler.NewRentalHandler(rentalClient)
+
 	r := mux.NewRouter()
 
 	// User routes
@@ -31,28 +36,101 @@ func main() {
 	r.HandleFunc("/users", userHandler.GetAllUsers).Methods("GET")
 	r.HandleFunc("/users/{id}", userHandler.GetUser).Methods("GET")
 	r.HandleFunc("/users/{id}", userHandler.UpdateUser).Methods("PUT")
+	r.HandleFunc("/users/{id}", userHandler.PatchUser).Methods("PATCH")
 	r.HandleFunc("/users/{id}", userHandler.DeleteUser).Methods("DELETE")
+	r.HandleFunc("/users/{id}/restore", userHandler.RestoreUser).Methods("POST")
 	r.HandleFunc("/users/cache/clear", userHandler.DeleteAllUsersCache).Methods("DELETE")
 	r.HandleFunc("/users/confirm", userHandler.ConfirmUser).Methods("POST")
+	r.HandleFunc("/users/{id}/addresses", userHandler.GetAddresses).Methods("GET")
+	r.HandleFunc("/users/{id}/addresses", userHandler.AddAddress).Methods("POST")
+	r.HandleFunc("/users/{id}/addresses/{address_id}", userHandler.UpdateAddress).Methods("PUT")
+	r.HandleFunc("/users/{id}/addresses/{address_id}", userHandler.DeleteAddress).Methods("DELETE")
+	r.HandleFunc("/users/{id}/addresses/{address_id}/default", userHandler.SetDefaultAddress).Methods("POST")
+	r.HandleFunc("/users/{id}/cart-reminders", userHandler.SetCartRemindersOptOut).Methods("PUT")
+	r.HandleFunc("/users/{id}/store-credit", userHandler.GetStoreCredit).Methods("GET")
+	r.HandleFunc("/users/{id}/store-credit", userHandler.AddStoreCredit).Methods("POST")
 
 	// Instrument routes
 	r.HandleFunc("/instruments", instrumentHandler.CreateInstrument).Methods("POST")
 	r.HandleFunc("/instruments", instrumentHandler.GetAllInstruments).Methods("GET")
 	r.HandleFunc("/instruments/{id}", instrumentHandler.GetInstrumentByID).Methods("GET")
 	r.HandleFunc("/instruments/{id}", instrumentHandler.UpdateInstrumentByID).Methods("PUT")
+	r.HandleFunc("/instruments/{id}", instrumentHandler.PatchInstrument).Methods("PATCH")
 	r.HandleFunc("/instruments/{id}", instrumentHandler.DeleteInstrumentByID).Methods("DELETE")
+	r.HandleFunc("/instruments/{id}/restore", instrumentHandler.RestoreInstrument).Methods("POST")
 	r.HandleFunc("/instruments/cache/clear", instrumentHandler.ClearInstrumentCache).Methods("DELETE")
+	r.HandleFunc("/instruments/sku/{sku}", instrumentHandler.GetInstrumentBySKU).Methods("GET")
+	r.HandleFunc("/instruments/{id}/media", instrumentHandler.UploadInstrumentMedia).Methods("POST")
+	r.HandleFunc("/instruments/{id}/media/{media_id}", instrumentHandler.DeleteInstrumentMedia).Methods("DELETE")
+	r.HandleFunc("/instruments/{id}/price-history", instrumentHandler.GetPriceHistory).Methods("GET")
+	r.HandleFunc("/instruments/{id}/price-schedules", instrumentHandler.SchedulePriceChange).Methods("POST")
+	r.HandleFunc("/instruments/{id}/price-schedules/{schedule_id}", instrumentHandler.CancelPriceSchedule).Methods("DELETE")
+	r.HandleFunc("/instruments/{id}/reviews", instrumentHandler.CreateReview).Methods("POST")
+	r.HandleFunc("/instruments/{id}/reviews", instrumentHandler.GetInstrumentReviews).Methods("GET")
+	r.HandleFunc("/reviews", instrumentHandler.GetReviews).Methods("GET")
+	r.HandleFunc("/reviews/{id}/moderation", instrumentHandler.ModerateReview).Methods("POST")
+	r.HandleFunc("/reviews/{id}/helpful", instrumentHandler.VoteReviewHelpful).Methods("POST")
+	r.HandleFunc("/instruments/{id}/serial-units", instrumentHandler.AddSerialUnit).Methods("POST")
+	r.HandleFunc("/instruments/{id}/serial-units", instrumentHandler.GetSerialUnits).Methods("GET")
+	r.HandleFunc("/serial-units/{serial}", instrumentHandler.GetSerialUnit).Methods("GET")
+	r.HandleFunc("/serial-units/{serial}", instrumentHandler.UpdateSerialUnit).Methods("PATCH")
+	r.HandleFunc("/serial-units/{serial}/warranty", instrumentHandler.RegisterWarranty).Methods("POST")
+	r.HandleFunc("/trade-ins", instrumentHandler.SubmitTradeIn).Methods("POST")
+	r.HandleFunc("/trade-ins", instrumentHandler.GetTradeIns).Methods("GET")
+	r.HandleFunc("/trade-ins/{id}/photos", instrumentHandler.UploadTradeInPhoto).Methods("POST")
+	r.HandleFunc("/trade-ins/{id}/appraisal", instrumentHandler.AppraiseTradeIn).Methods("POST")
+	r.HandleFunc("/trade-ins/{id}/response", instrumentHandler.RespondTradeInOffer).Methods("POST")
+	r.HandleFunc("/trade-ins/{id}/receive", instrumentHandler.ReceiveTradeIn).Methods("POST")
+	r.HandleFunc("/categories", instrumentHandler.CreateCategory).Methods("POST")
+	r.HandleFunc("/categories", instrumentHandler.GetCategories).Methods("GET")
+	r.HandleFunc("/brands", instrumentHandler.CreateBrand).Methods("POST")
+	r.HandleFunc("/brands", instrumentHandler.GetBrands).Methods("GET")
 
 	// Cart routes
 	r.HandleFunc("/cart/add", cartHandler.AddToCart).Methods("POST")
 	r.HandleFunc("/cart/get", cartHandler.GetCart).Methods("GET")
 	r.HandleFunc("/cart/remove", cartHandler.RemoveFromCart).Methods("POST")
 	r.HandleFunc("/cart/clear", cartHandler.ClearCart).Methods("POST")
+	r.HandleFunc("/cart/items/{instrument_id}", cartHandler.UpdateCartItemQuantity).Methods("PATCH")
+	r.HandleFunc("/cart/items/{instrument_id}/move", cartHandler.MoveCartItem).Methods("POST")
+	r.HandleFunc("/cart/lists", cartHandler.CreateCartList).Methods("POST")
+	r.HandleFunc("/cart/lists/{list_id}", cartHandler.DeleteCartList).Methods("DELETE")
+	r.HandleFunc("/cart/coupon/apply", cartHandler.ApplyCoupon).Methods("POST")
+	r.HandleFunc("/cart/coupon/remove", cartHandler.RemoveCoupon).Methods("POST")
+	r.HandleFunc("/promotions", cartHandler.CreatePromotion).Methods("POST")
+	r.HandleFunc("/promotions", cartHandler.GetPromotions).Methods("GET")
 
 	// Order routes
 	r.HandleFunc("/orders", orderHandler.CreateOrder).Methods("POST")
+	r.HandleFunc("/instruments/{id}/recommendations", orderHandler.GetRecommendations).Methods("GET")
 	r.HandleFunc("/orders", orderHandler.GetOrders).Methods("GET")
 	r.HandleFunc("/orders", orderHandler.DeleteOrder).Methods("DELETE")
+	r.HandleFunc("/orders/{id}/pay", orderHandler.PayOrder).Methods("POST")
+	r.HandleFunc("/orders/{id}/invoice", orderHandler.GetInvoice).Methods("GET")
+	r.HandleFunc("/orders/{id}/shipments", orderHandler.CreateShipment).Methods("POST")
+	r.HandleFunc("/orders/{id}/shipments/{shipment_id}/events", orderHandler.AddTrackingEvent).Methods("POST")
+	r.HandleFunc("/orders/{id}/tracking", orderHandler.GetTracking).Methods("GET")
+	r.HandleFunc("/shipping/quote", orderHandler.QuoteShipping).Methods("POST")
+
+	// Wishlist routes
+	r.HandleFunc("/wishlists", wishlistHandler.CreateWishlist).Methods("POST")
+	r.HandleFunc("/wishlists", wishlistHandler.GetWishlists).Methods("GET")
+	r.HandleFunc("/wishlists/shared/{token}", wishlistHandler.GetSharedWishlist).Methods("GET")
+	r.HandleFunc("/wishlists/{id}", wishlistHandler.DeleteWishlist).Methods("DELETE")
+	r.HandleFunc("/wishlists/{id}/items", wishlistHandler.AddWishlistItem).Methods("POST")
+	r.HandleFunc("/wishlists/{id}/items/{instrument_id}", wishlistHandler.RemoveWishlistItem).Methods("DELETE")
+	r.HandleFunc("/wishlists/{id}/share", wishlistHandler.ShareWishlist).Methods("POST")
+
+	// Rental routes
+	r.HandleFunc("/instruments/{id}/rental-plan", rentalHandler.SetRentalPlan).Methods("PUT")
+	r.HandleFunc("/instruments/{id}/rental-plan", rentalHandler.GetRentalPlan).Methods("GET")
+	r.HandleFunc("/instruments/{id}/rental-units", rentalHandler.AddRentalUnit).Methods("POST")
+	r.HandleFunc("/instruments/{id}/availability", rentalHandler.GetAvailability).Methods("GET")
+	r.HandleFunc("/rental-units/{id}/status", rentalHandler.SetRentalUnitStatus).Methods("POST")
+	r.HandleFunc("/rentals", rentalHandler.CreateRental).Methods("POST")
+	r.HandleFunc("/rentals", rentalHandler.GetRentals).Methods("GET")
+	r.HandleFunc("/rentals/{id}/extend", rentalHandler.ExtendRental).Methods("POST")
+	r.HandleFunc("/rentals/{id}/return", rentalHandler.ReturnRental).Methods("POST")
 
 	log.Println("API Gateway запущен на порту 8081")
 	if err := http.ListenAndServe(":8081", r); err != nil {
@@ -0,0 +1,15 @@
+package client
+
+import (
+	"google.golang.org/grpc"
+	"gotune/rental/proto"
+	"log"
+)
+
+func NewRentalServiceClient(connStr string) proto.RentalServiceClient {
+	conn, err := grpc.Dial(connStr, grpc.WithInsecure())
+	if err != nil {
+		log.Fatalf("Не удалось подключиться к RentalService: %v", err)
+	}
+	return proto.NewRentalServiceClient(conn)
+}
@@ -0,0 +1,15 @@
+package client
+
+import (
+	"google.golang.org/grpc"
+	"gotune/wishlist/proto"
+	"log"
+)
+
+func NewWishlistServiceClient(connStr string) proto.WishlistServiceClient {
+	conn, err := grpc.Dial(connStr, grpc.WithInsecure())
+	if err != nil {
+		log.Fatalf("Не удалось подключиться к WishlistService: %v", err)
+	}
+	return proto.NewWishlistServiceClient(conn)
+}
@@ -5,6 +5,10 @@ import (
 	"encoding/json"
 	"net/http"
 
+	"github.com/gorilla/mux"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
 	"gotune/cart/proto"
 )
 
@@ -29,6 +33,7 @@ func writeError(w http.ResponseWriter, status int, msg string) {
 type AddToCartRequest struct {
 	UserID       string `json:"user_id"`
 	InstrumentID string `json:"instrument_id"`
+	SKU          string `json:"sku"`
 	Quantity     int32  `json:"quantity"`
 }
 
@@ -45,13 +50,25 @@ func (h *CartHandler) AddToCart(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// без user_id товар попадает в гостевую корзину
+	var token string
+	if req.UserID == "" {
+		var err error
+		if token, err = ensureCartToken(w, r); err != nil {
+			writeError(w, http.StatusInternalServerError, "Ошибка создания корзины")
+			return
+		}
+	}
+
 	_, err := h.CartClient.AddToCart(context.Background(), &proto.AddToCartRequest{
 		UserId:       req.UserID,
 		InstrumentId: req.InstrumentID,
+		Sku:          req.SKU,
 		Quantity:     req.Quantity,
+		CartToken:    token,
 	})
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, "Ошибка добавления в корзину")
+		writeCartError(w, err, "Ошибка добавления в корзину")
 		return
 	}
 
@@ -60,25 +77,29 @@ func (h *CartHandler) AddToCart(w http.ResponseWriter, r *http.Request) {
 
 func (h *CartHandler) GetCart(w http.ResponseWriter, r *http.Request) {
 	userID := r.URL.Query().Get("user_id")
-	if userID == "" {
+	token := cartToken(r)
+	if userID == "" && token == "" {
 		writeError(w, http.StatusBadRequest, "Отсутствует user_id")
 		return
 	}
 
 	resp, err := h.CartClient.GetCart(context.Background(), &proto.GetCartRequest{
-		UserId: userID,
+		UserId:    userID,
+		CartToken: token,
 	})
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, "Ошибка получения корзины")
+		writeCartError(w, err, "Ошибка получения корзины")
 		return
 	}
 
-	writeJSON(w, http.StatusOK, resp.Items)
+	setETag(w, resp.Version)
+	writeJSON(w, http.StatusOK, resp)
 }
 
 type RemoveFromCartRequest struct {
 	UserID       string `json:"user_id"`
 	InstrumentID string `json:"instrument_id"`
+	SKU          string `json:"sku"`
 }
 
 func (h *CartHandler) RemoveFromCart(w http.ResponseWriter, r *http.Request) {
@@ -93,13 +114,21 @@ func (h *CartHandler) RemoveFromCart(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusBadRequest, "Неверный формат запроса")
 		return
 	}
+	version, err := requireIfMatch(r)
+	if err != nil {
+		writeIfMatchError(w, err)
+		return
+	}
 
-	_, err := h.CartClient.RemoveFromCart(context.Background(), &proto.RemoveFromCartRequest{
-		UserId:       req.UserID,
-		InstrumentId: req.InstrumentID,
+	_, err = h.CartClient.RemoveFromCart(context.Background(), &proto.RemoveFromCartRequest{
+		UserId:          req.UserID,
+		InstrumentId:    req.InstrumentID,
+		Sku:             req.SKU,
+		CartToken:       cartToken(r),
+		ExpectedVersion: version,
 	})
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, "Ошибка удаления из корзины")
+		writeCartItemError(w, err, "Ошибка удаления из корзины")
 		return
 	}
 
@@ -124,7 +153,8 @@ func (h *CartHandler) ClearCart(w http.ResponseWriter, r *http.Request) {
 	}
 
 	_, err := h.CartClient.ClearCart(context.Background(), &proto.ClearCartRequest{
-		UserId: req.UserID,
+		UserId:    req.UserID,
+		CartToken: cartToken(r),
 	})
 	if err != nil {
 		writeError(w, http.StatusInternalServerError, "Ошибка очистки корзины")
@@ -133,3 +163,283 @@ func (h *CartHandler) ClearCart(w http.ResponseWriter, r *http.Request) {
 
 	writeJSON(w, http.StatusOK, map[string]bool{"success": true})
 }
+
+type ApplyCouponRequest struct {
+	UserID string `json:"user_id"`
+	Code   string `json:"code"`
+}
+
+func (h *CartHandler) ApplyCoupon(w http.ResponseWriter, r *http.Request) {
+	if r.Body == nil {
+		writeError(w, http.StatusBadRequest, "Пустое тело запроса")
+		return
+	}
+	defer r.Body.Close()
+
+	var req ApplyCouponRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Неверный формат запроса")
+		return
+	}
+
+	resp, err := h.CartClient.ApplyCoupon(context.Background(), &proto.ApplyCouponRequest{
+		UserId: req.UserID,
+		Code:   req.Code,
+	})
+	if err != nil {
+		writeCartError(w, err, "Ошибка применения купона")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp.Promotion)
+}
+
+func (h *CartHandler) RemoveCoupon(w http.ResponseWriter, r *http.Request) {
+	if r.Body == nil {
+		writeError(w, http.StatusBadRequest, "Пустое тело запроса")
+		return
+	}
+	defer r.Body.Close()
+
+	var req ClearCartRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Неверный формат запроса")
+		return
+	}
+
+	_, err := h.CartClient.RemoveCoupon(context.Background(), &proto.RemoveCouponRequest{
+		UserId: req.UserID,
+	})
+	if err != nil {
+		writeCartError(w, err, "Ошибка удаления купона")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]bool{"success": true})
+}
+
+type CreatePromotionRequest struct {
+	Name              string   `json:"name"`
+	Code              string   `json:"code"`
+	Type              string   `json:"type"`
+	Value             float64  `json:"value"`
+	InstrumentIDs     []string `json:"instrument_ids"`
+	Categories        []string `json:"categories"`
+	BuyQuantity       int32    `json:"buy_quantity"`
+	GetQuantity       int32    `json:"get_quantity"`
+	MinSubtotal       float64  `json:"min_subtotal"`
+	StartsAt          int64    `json:"starts_at"`
+	EndsAt            int64    `json:"ends_at"`
+	UsageLimit        int32    `json:"usage_limit"`
+	UsageLimitPerUser int32    `json:"usage_limit_per_user"`
+	Active            bool     `json:"active"`
+}
+
+func (h *CartHandler) CreatePromotion(w http.ResponseWriter, r *http.Request) {
+	if r.Body == nil {
+		writeError(w, http.StatusBadRequest, "Пустое тело запроса")
+		return
+	}
+	defer r.Body.Close()
+
+	var req CreatePromotionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Неверный формат запроса")
+		return
+	}
+
+	resp, err := h.CartClient.CreatePromotion(context.Background(), &proto.CreatePromotionRequest{
+		Promotion: &proto.Promotion{
+			Name:              req.Name,
+			Code:              req.Code,
+			Type:              req.Type,
+			Value:             req.Value,
+			InstrumentIds:     req.InstrumentIDs,
+			Categories:        req.Categories,
+			BuyQuantity:       req.BuyQuantity,
+			GetQuantity:       req.GetQuantity,
+			MinSubtotal:       req.MinSubtotal,
+			StartsAt:          req.StartsAt,
+			EndsAt:            req.EndsAt,
+			UsageLimit:        req.UsageLimit,
+			UsageLimitPerUser: req.UsageLimitPerUser,
+			Active:            req.Active,
+		},
+	})
+	if err != nil {
+		writeCartError(w, err, "Ошибка создания акции")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, map[string]string{"id": resp.Id})
+}
+
+func (h *CartHandler) GetPromotions(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.CartClient.GetPromotions(context.Background(), &proto.GetPromotionsRequest{})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "Ошибка получения акций")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp.Promotions)
+}
+
+func writeCartError(w http.ResponseWriter, err error, fallback string) {
+	if st, ok := status.FromError(err); ok {
+		switch st.Code() {
+		case codes.InvalidArgument:
+			writeError(w, http.StatusBadRequest, st.Message())
+			return
+		case codes.NotFound:
+			writeError(w, http.StatusNotFound, st.Message())
+			return
+		case codes.AlreadyExists, codes.Aborted:
+			writeError(w, http.StatusConflict, st.Message())
+			return
+		case codes.FailedPrecondition, codes.ResourceExhausted:
+			writeError(w, http.StatusUnprocessableEntity, st.Message())
+			return
+		}
+	}
+	writeError(w, http.StatusInternalServerError, fallback)
+}
+
+// writeCartItemError — writeCartError для запросов с If-Match: корзина,
+// изменённая после чтения, даёт 412 вместо 409.
+func writeCartItemError(w http.ResponseWriter, err error, fallback string) {
+	if status.Code(err) == codes.Aborted {
+		writeError(w, http.StatusPreconditionFailed, status.Convert(err).Message())
+		return
+	}
+	writeCartError(w, err, fallback)
+}
+
+type UpdateCartItemRequest struct {
+	UserID   string `json:"user_id"`
+	SKU      string `json:"sku"`
+	Quantity int32  `json:"quantity"`
+}
+
+func (h *CartHandler) UpdateCartItemQuantity(w http.ResponseWriter, r *http.Request) {
+	if r.Body == nil {
+		writeError(w, http.StatusBadRequest, "Пустое тело запроса")
+		return
+	}
+	defer r.Body.Close()
+
+	var req UpdateCartItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Неверный формат запроса")
+		return
+	}
+	version, err := requireIfMatch(r)
+	if err != nil {
+		writeIfMatchError(w, err)
+		return
+	}
+
+	_, err = h.CartClient.UpdateCartItemQuantity(context.Background(), &proto.UpdateCartItemQuantityRequest{
+		UserId:          req.UserID,
+		InstrumentId:    mux.Vars(r)["instrument_id"],
+		Sku:             req.SKU,
+		Quantity:        req.Quantity,
+		CartToken:       cartToken(r),
+		ExpectedVersion: version,
+	})
+	if err != nil {
+		writeCartItemError(w, err, "Ошибка изменения количества")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]bool{"success": true})
+}
+
+type CreateCartListRequest struct {
+	UserID string `json:"user_id"`
+	Name   string `json:"name"`
+}
+
+func (h *CartHandler) CreateCartList(w http.ResponseWriter, r *http.Request) {
+	if r.Body == nil {
+		writeError(w, http.StatusBadRequest, "Пустое тело запроса")
+		return
+	}
+	defer r.Body.Close()
+
+	var req CreateCartListRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Неверный формат запроса")
+		return
+	}
+
+	resp, err := h.CartClient.CreateCartList(context.Background(), &proto.CreateCartListRequest{
+		UserId: req.UserID,
+		Name:   req.Name,
+	})
+	if err != nil {
+		writeCartError(w, err, "Ошибка создания списка")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, map[string]string{"list_id": resp.ListId})
+}
+
+func (h *CartHandler) DeleteCartList(w http.ResponseWriter, r *http.Request) {
+	if r.Body == nil {
+		writeError(w, http.StatusBadRequest, "Пустое тело запроса")
+		return
+	}
+	defer r.Body.Close()
+
+	var req ClearCartRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Неверный формат запроса")
+		return
+	}
+
+	_, err := h.CartClient.DeleteCartList(context.Background(), &proto.DeleteCartListRequest{
+		UserId: req.UserID,
+		ListId: mux.Vars(r)["list_id"],
+	})
+	if err != nil {
+		writeCartError(w, err, "Ошибка удаления списка")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]bool{"success": true})
+}
+
+type MoveCartItemRequest struct {
+	UserID string `json:"user_id"`
+	SKU    string `json:"sku"`
+	From   string `json:"from"`
+	To     string `json:"to"`
+}
+
+func (h *CartHandler) MoveCartItem(w http.ResponseWriter, r *http.Request) {
+	if r.Body == nil {
+		writeError(w, http.StatusBadRequest, "Пустое тело запроса")
+		return
+	}
+	defer r.Body.Close()
+
+	var req MoveCartItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Неверный формат запроса")
+		return
+	}
+
+	_, err := h.CartClient.MoveCartItem(context.Background(), &proto.MoveCartItemRequest{
+		UserId:       req.UserID,
+		InstrumentId: mux.Vars(r)["instrument_id"],
+		Sku:          req.SKU,
+		From:         req.From,
+		To:           req.To,
+	})
+	if err != nil {
+		writeCartError(w, err, "Ошибка перемещения товара")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]bool{"success": true})
+}
@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+)
+
+// Гостевая корзина определяется случайным токеном в cookie. Срок жизни
+// cookie совпадает со сроком хранения гостевой корзины в сервисе корзин.
+const (
+	cartTokenCookie = "cart_token"
+	cartTokenMaxAge = 30 * 24 * 60 * 60
+)
+
+func cartToken(r *http.Request) string {
+	cookie, err := r.Cookie(cartTokenCookie)
+	if err != nil {
+		return ""
+	}
+	return cookie.Value
+}
+
+// ensureCartToken возвращает токен из cookie или выдаёт новый.
+func ensureCartToken(w http.ResponseWriter, r *http.Request) (string, error) {
+	if token := cartToken(r); token != "" {
+		return token, nil
+	}
+
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(buf)
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     cartTokenCookie,
+		Value:    token,
+		Path:     "/",
+		MaxAge:   cartTokenMaxAge,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+	return token, nil
+}
+
+func clearCartToken(w http.ResponseWriter) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     cartTokenCookie,
+		Value:    "",
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+	})
+}
@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+var (
+	errIfMatchRequired = errors.New("укажите заголовок If-Match с ETag, полученным при чтении")
+	errInvalidIfMatch  = errors.New("неверный заголовок If-Match")
+)
+
+// setETag отдаёт версию документа как сильный ETag: "3".
+func setETag(w http.ResponseWriter, version int64) {
+	if version > 0 {
+		w.Header().Set("ETag", strconv.Quote(strconv.FormatInt(version, 10)))
+	}
+}
+
+// ifMatchVersion возвращает версию из заголовка If-Match; 0 — заголовка
+// нет. Слабый ETag (W/"3") принимается так же, как сильный: версия
+// меняется при любом изменении документа.
+func ifMatchVersion(r *http.Request) (int64, error) {
+	value := strings.TrimSpace(r.Header.Get("If-Match"))
+	if value == "" {
+		return 0, nil
+	}
+	value = strings.TrimPrefix(value, "W/")
+	unquoted, err := strconv.Unquote(value)
+	if err != nil {
+		return 0, errInvalidIfMatch
+	}
+	version, err := strconv.ParseInt(unquoted, 10, 64)
+	if err != nil || version <= 0 {
+		return 0, errInvalidIfMatch
+	}
+	return version, nil
+}
+
+// requireIfMatch — ifMatchVersion для запросов, где версия обязательна.
+func requireIfMatch(r *http.Request) (int64, error) {
+	version, err := ifMatchVersion(r)
+	if err == nil && version == 0 {
+		return 0, errIfMatchRequired
+	}
+	return version, err
+}
+
+func writeIfMatchError(w http.ResponseWriter, err error) {
+	if errors.Is(err, errIfMatchRequired) {
+		writeError(w, http.StatusPreconditionRequired, err.Error())
+		return
+	}
+	writeError(w, http.StatusBadRequest, err.Error())
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func requestWithIfMatch(value string) *http.Request {
+	r := httptest.NewRequest(http.MethodPut, "/instruments/1", nil)
+	if value != "" {
+		r.Header.Set("If-Match", value)
+	}
+	return r
+}
+
+func TestIfMatchVersion(t *testing.T) {
+	version, err := ifMatchVersion(requestWithIfMatch(`"3"`))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), version)
+
+	version, err = ifMatchVersion(requestWithIfMatch(` W/"7" `))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), version)
+
+	version, err = ifMatchVersion(requestWithIfMatch(""))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), version)
+
+	for _, value := range []string{`3`, `"abc"`, `"0"`, `"-1"`, `*`} {
+		_, err := ifMatchVersion(requestWithIfMatch(value))
+		assert.ErrorIs(t, err, errInvalidIfMatch, value)
+	}
+}
+
+func TestRequireIfMatch(t *testing.T) {
+	version, err := requireIfMatch(requestWithIfMatch(`"2"`))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), version)
+
+	_, err = requireIfMatch(requestWithIfMatch(""))
+	assert.ErrorIs(t, err, errIfMatchRequired)
+
+	_, err = requireIfMatch(requestWithIfMatch(`"x"`))
+	assert.ErrorIs(t, err, errInvalidIfMatch)
+}
+
+func TestWriteIfMatchError(t *testing.T) {
+	w := httptest.NewRecorder()
+	writeIfMatchError(w, errIfMatchRequired)
+	assert.Equal(t, http.StatusPreconditionRequired, w.Code)
+
+	w = httptest.NewRecorder()
+	writeIfMatchError(w, errInvalidIfMatch)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestSetETag(t *testing.T) {
+	w := httptest.NewRecorder()
+	setETag(w, 4)
+	assert.Equal(t, `"4"`, w.Header().Get("ETag"))
+
+	w = httptest.NewRecorder()
+	setETag(w, 0)
+	assert.Empty(t, w.Header().Get("ETag"))
+}
+
+func TestVersionConflictIsPreconditionFailed(t *testing.T) {
+	conflict := status.Error(codes.Aborted, "изменено")
+
+	w := httptest.NewRecorder()
+	writeInstrumentError(w, conflict, "ошибка")
+	assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+
+	w = httptest.NewRecorder()
+	writeUpdateUserError(w, conflict)
+	assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+
+	w = httptest.NewRecorder()
+	writeCartItemError(w, conflict, "ошибка")
+	assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+
+	// без If-Match конфликт корзины — обычный 409
+	w = httptest.NewRecorder()
+	writeCartError(w, conflict, "ошибка")
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
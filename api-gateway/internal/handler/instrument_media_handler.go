@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"gotune/instruments/proto"
+)
+
+const (
+	maxMediaUploadBytes = 25 << 20
+	mediaChunkSize      = 64 << 10
+)
+
+// UploadInstrumentMedia принимает multipart-форму с полем file и
+// необязательным kind (image или audio; по умолчанию определяется по
+// Content-Type файла) и передаёт файл в сервис инструментов частями.
+func (h *InstrumentHandler) UploadInstrumentMedia(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxMediaUploadBytes)
+	if err := r.ParseMultipartForm(8 << 20); err != nil {
+		writeError(w, http.StatusBadRequest, "Неверная multipart-форма или файл слишком большой")
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Отсутствует файл")
+		return
+	}
+	defer file.Close()
+
+	kind := r.FormValue("kind")
+	if kind == "" {
+		kind = strings.SplitN(header.Header.Get("Content-Type"), "/", 2)[0]
+	}
+
+	stream, err := h.InstrumentClient.UploadInstrumentMedia(context.Background())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "Ошибка загрузки файла")
+		return
+	}
+
+	chunk := make([]byte, mediaChunkSize)
+	first := true
+	for {
+		n, readErr := file.Read(chunk)
+		if n > 0 || first {
+			req := &proto.UploadInstrumentMediaRequest{Data: chunk[:n]}
+			if first {
+				req.InstrumentId = mux.Vars(r)["id"]
+				req.Kind = kind
+				req.Filename = header.Filename
+				first = false
+			}
+			// при ошибке отправки сервис уже закрыл поток, причину вернёт CloseAndRecv
+			if err := stream.Send(req); err != nil {
+				break
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			writeError(w, http.StatusBadRequest, "Ошибка чтения файла")
+			return
+		}
+	}
+
+	resp, err := stream.CloseAndRecv()
+	if err != nil {
+		writeInstrumentError(w, err, "Ошибка загрузки файла")
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(resp)
+}
+
+func (h *InstrumentHandler) DeleteInstrumentMedia(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	_, err := h.InstrumentClient.DeleteInstrumentMedia(context.Background(), &proto.DeleteInstrumentMediaRequest{
+		InstrumentId: vars["id"],
+		MediaId:      vars["media_id"],
+	})
+	if err != nil {
+		writeInstrumentError(w, err, "Ошибка удаления файла")
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]bool{
+		"success": true,
+	})
+}
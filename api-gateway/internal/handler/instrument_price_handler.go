@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"gotune/instruments/proto"
+)
+
+// GetPriceHistory возвращает изменения цены инструмента, от новых к
+// старым, и его ожидающие и действующие расписания цен.
+func (h *InstrumentHandler) GetPriceHistory(w http.ResponseWriter, r *http.Request) {
+	var limit int64
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 32)
+		if err != nil || n < 0 {
+			writeError(w, http.StatusBadRequest, "Неверный параметр limit")
+			return
+		}
+		limit = n
+	}
+
+	resp, err := h.InstrumentClient.GetPriceHistory(context.Background(), &proto.GetPriceHistoryRequest{
+		InstrumentId: mux.Vars(r)["id"],
+		Limit:        int32(limit),
+	})
+	if err != nil {
+		writeInstrumentError(w, err, "Ошибка получения истории цен")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// SchedulePriceChangeRequest — время начала и окончания в Unix-секундах,
+// ends_at можно не указывать.
+type SchedulePriceChangeRequest struct {
+	Price    float64 `json:"price"`
+	StartsAt int64   `json:"starts_at"`
+	EndsAt   int64   `json:"ends_at,omitempty"`
+}
+
+func (h *InstrumentHandler) SchedulePriceChange(w http.ResponseWriter, r *http.Request) {
+	var req SchedulePriceChangeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Неверный формат запроса")
+		return
+	}
+
+	resp, err := h.InstrumentClient.SchedulePriceChange(context.Background(), &proto.SchedulePriceChangeRequest{
+		InstrumentId: mux.Vars(r)["id"],
+		Price:        req.Price,
+		StartsAt:     req.StartsAt,
+		EndsAt:       req.EndsAt,
+	})
+	if err != nil {
+		writeInstrumentError(w, err, "Ошибка планирования цены")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, resp)
+}
+
+func (h *InstrumentHandler) CancelPriceSchedule(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	_, err := h.InstrumentClient.CancelPriceSchedule(context.Background(), &proto.CancelPriceScheduleRequest{
+		Id:           vars["schedule_id"],
+		InstrumentId: vars["id"],
+	})
+	if err != nil {
+		writeInstrumentError(w, err, "Ошибка отмены расписания цены")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]bool{"success": true})
+}
@@ -4,8 +4,14 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/gorilla/mux"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 
 	"gotune/instruments/proto"
 )
@@ -19,9 +25,22 @@ func NewInstrumentHandler(client proto.InstrumentServiceClient) *InstrumentHandl
 }
 
 type CreateInstrumentRequest struct {
-	Name        string  `json:"name"`
-	Description string  `json:"description"`
-	Price       float64 `json:"price"`
+	Name            string  `json:"name"`
+	Description     string  `json:"description"`
+	Price           float64 `json:"price"`
+	WeightKg        float64 `json:"weight_kg"`
+	LengthCm        float64 `json:"length_cm"`
+	WidthCm         float64 `json:"width_cm"`
+	HeightCm        float64 `json:"height_cm"`
+	TaxCategory     string  `json:"tax_category"`
+	Category        string  `json:"category"`
+	Brand           string  `json:"brand"`
+	MaxCartQuantity int32   `json:"max_cart_quantity"`
+	Stock           int32   `json:"stock"`
+	// значения атрибутов категории: строка для enum, число или true/false
+	Attributes map[string]interface{} `json:"attributes"`
+	// варианты (цвет, исполнение и т.п.); остаток инструмента считается по ним
+	Variants []*proto.Variant `json:"variants"`
 }
 
 func (h *InstrumentHandler) CreateInstrument(w http.ResponseWriter, r *http.Request) {
@@ -32,12 +51,23 @@ func (h *InstrumentHandler) CreateInstrument(w http.ResponseWriter, r *http.Requ
 	}
 
 	resp, err := h.InstrumentClient.CreateInstrument(context.Background(), &proto.CreateInstrumentRequest{
-		Name:        req.Name,
-		Description: req.Description,
-		Price:       req.Price,
+		Name:            req.Name,
+		Description:     req.Description,
+		Price:           req.Price,
+		WeightKg:        req.WeightKg,
+		LengthCm:        req.LengthCm,
+		WidthCm:         req.WidthCm,
+		HeightCm:        req.HeightCm,
+		TaxCategory:     req.TaxCategory,
+		Category:        req.Category,
+		Brand:           req.Brand,
+		MaxCartQuantity: req.MaxCartQuantity,
+		Stock:           req.Stock,
+		Attributes:      attributeValues(req.Attributes),
+		Variants:        req.Variants,
 	})
 	if err != nil {
-		http.Error(w, "Ошибка при создании инструмента", http.StatusInternalServerError)
+		writeInstrumentError(w, err, "Ошибка при создании инструмента")
 		return
 	}
 
@@ -47,6 +77,12 @@ func (h *InstrumentHandler) CreateInstrument(w http.ResponseWriter, r *http.Requ
 }
 
 func (h *InstrumentHandler) GetAllInstruments(w http.ResponseWriter, r *http.Request) {
+	// с параметрами запроса — поиск с фильтрами и пагинацией
+	if len(r.URL.Query()) > 0 {
+		h.SearchInstruments(w, r)
+		return
+	}
+
 	resp, err := h.InstrumentClient.GetAllInstruments(context.Background(), &proto.GetAllInstrumentsRequest{})
 	if err != nil {
 		http.Error(w, "Ошибка получения инструментов", http.StatusInternalServerError)
@@ -67,6 +103,108 @@ func (h *InstrumentHandler) GetInstrumentByID(w http.ResponseWriter, r *http.Req
 		return
 	}
 
+	setETag(w, resp.Version)
+	json.NewEncoder(w).Encode(resp)
+}
+
+var instrumentPatchFields = map[string]bool{
+	"name": true, "description": true, "price": true,
+	"weight_kg": true, "length_cm": true, "width_cm": true, "height_cm": true,
+	"tax_category": true, "category": true, "brand": true,
+	"max_cart_quantity": true, "stock": true, "attributes": true, "variants": true,
+}
+
+// PatchInstrument применяет JSON merge-patch: меняются только поля из
+// тела, null сбрасывает поле. Атрибуты сливаются с текущими по ключам,
+// variants заменяется целиком. If-Match обязателен.
+func (h *InstrumentHandler) PatchInstrument(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	version, err := requireIfMatch(r)
+	if err != nil {
+		writeIfMatchError(w, err)
+		return
+	}
+
+	var req UpdateInstrumentRequest
+	paths, patch, err := decodeMergePatch(r, instrumentPatchFields, &req)
+	if err != nil {
+		writePatchError(w, err)
+		return
+	}
+
+	attributes := attributeValues(req.Attributes)
+	if raw, ok := patch["attributes"]; ok && string(raw) != "null" {
+		current, err := h.InstrumentClient.GetInstrumentByID(context.Background(), &proto.GetInstrumentByIDRequest{Id: id})
+		if err != nil {
+			writeInstrumentError(w, err, "Инструмент не найден")
+			return
+		}
+		attributes = mergeAttributes(current.Attributes, req.Attributes)
+	}
+
+	resp, err := h.InstrumentClient.UpdateInstrumentByID(context.Background(), &proto.UpdateInstrumentByIDRequest{
+		Id:              id,
+		Name:            req.Name,
+		Description:     req.Description,
+		Price:           req.Price,
+		WeightKg:        req.WeightKg,
+		LengthCm:        req.LengthCm,
+		WidthCm:         req.WidthCm,
+		HeightCm:        req.HeightCm,
+		TaxCategory:     req.TaxCategory,
+		Category:        req.Category,
+		Brand:           req.Brand,
+		MaxCartQuantity: req.MaxCartQuantity,
+		Stock:           req.Stock,
+		Attributes:      attributes,
+		Variants:        req.Variants,
+		UpdateMask:      &fieldmaskpb.FieldMask{Paths: paths},
+		ExpectedVersion: version,
+	})
+	if err != nil {
+		writeInstrumentError(w, err, "Ошибка при обновлении инструмента")
+		return
+	}
+
+	setETag(w, resp.Version)
+	json.NewEncoder(w).Encode(map[string]bool{
+		"success": true,
+	})
+}
+
+// mergeAttributes сливает текущие атрибуты с изменениями: null удаляет
+// атрибут, остальные значения заменяют прежние.
+func mergeAttributes(current []*proto.AttributeValue, changes map[string]interface{}) []*proto.AttributeValue {
+	merged := map[string]*proto.AttributeValue{}
+	for _, a := range current {
+		merged[a.Key] = a
+	}
+	for key, value := range changes {
+		if value == nil {
+			delete(merged, key)
+			continue
+		}
+		merged[key] = attributeValues(map[string]interface{}{key: value})[0]
+	}
+
+	result := make([]*proto.AttributeValue, 0, len(merged))
+	for _, a := range merged {
+		result = append(result, a)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Key < result[j].Key })
+	return result
+}
+
+func (h *InstrumentHandler) GetInstrumentBySKU(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.InstrumentClient.GetInstrumentBySKU(context.Background(), &proto.GetInstrumentBySKURequest{
+		Sku: mux.Vars(r)["sku"],
+	})
+	if err != nil {
+		writeInstrumentError(w, err, "Ошибка поиска по SKU")
+		return
+	}
+
 	json.NewEncoder(w).Encode(resp)
 }
 
@@ -77,7 +215,22 @@ func (h *InstrumentHandler) DeleteInstrumentByID(w http.ResponseWriter, r *http.
 		Id: id,
 	})
 	if err != nil {
-		http.Error(w, "Ошибка при удалении инструмента", http.StatusInternalServerError)
+		writeInstrumentError(w, err, "Ошибка при удалении инструмента")
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]bool{
+		"success": true,
+	})
+}
+
+// RestoreInstrument возвращает удалённый инструмент в каталог.
+func (h *InstrumentHandler) RestoreInstrument(w http.ResponseWriter, r *http.Request) {
+	_, err := h.InstrumentClient.RestoreInstrument(context.Background(), &proto.RestoreInstrumentRequest{
+		Id: mux.Vars(r)["id"],
+	})
+	if err != nil {
+		writeInstrumentError(w, err, "Ошибка при восстановлении инструмента")
 		return
 	}
 
@@ -87,31 +240,61 @@ func (h *InstrumentHandler) DeleteInstrumentByID(w http.ResponseWriter, r *http.
 }
 
 type UpdateInstrumentRequest struct {
-	Name        string  `json:"name,omitempty"`
-	Description string  `json:"description,omitempty"`
-	Price       float64 `json:"price,omitempty"`
+	Name            string                 `json:"name,omitempty"`
+	Description     string                 `json:"description,omitempty"`
+	Price           float64                `json:"price,omitempty"`
+	WeightKg        float64                `json:"weight_kg,omitempty"`
+	LengthCm        float64                `json:"length_cm,omitempty"`
+	WidthCm         float64                `json:"width_cm,omitempty"`
+	HeightCm        float64                `json:"height_cm,omitempty"`
+	TaxCategory     string                 `json:"tax_category,omitempty"`
+	Category        string                 `json:"category,omitempty"`
+	Brand           string                 `json:"brand,omitempty"`
+	MaxCartQuantity int32                  `json:"max_cart_quantity,omitempty"`
+	Stock           int32                  `json:"stock,omitempty"`
+	Attributes      map[string]interface{} `json:"attributes,omitempty"`
+	Variants        []*proto.Variant       `json:"variants,omitempty"`
 }
 
 func (h *InstrumentHandler) UpdateInstrumentByID(w http.ResponseWriter, r *http.Request) {
 	id := mux.Vars(r)["id"]
 
+	version, err := requireIfMatch(r)
+	if err != nil {
+		writeIfMatchError(w, err)
+		return
+	}
+
 	var req UpdateInstrumentRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, "Неверный формат запроса", http.StatusBadRequest)
 		return
 	}
 
-	_, err := h.InstrumentClient.UpdateInstrumentByID(context.Background(), &proto.UpdateInstrumentByIDRequest{
-		Id:          id,
-		Name:        req.Name,
-		Description: req.Description,
-		Price:       req.Price,
+	resp, err := h.InstrumentClient.UpdateInstrumentByID(context.Background(), &proto.UpdateInstrumentByIDRequest{
+		Id:              id,
+		Name:            req.Name,
+		Description:     req.Description,
+		Price:           req.Price,
+		WeightKg:        req.WeightKg,
+		LengthCm:        req.LengthCm,
+		WidthCm:         req.WidthCm,
+		HeightCm:        req.HeightCm,
+		TaxCategory:     req.TaxCategory,
+		Category:        req.Category,
+		Brand:           req.Brand,
+		MaxCartQuantity: req.MaxCartQuantity,
+		Stock:           req.Stock,
+		Attributes:      attributeValues(req.Attributes),
+		Variants:        req.Variants,
+		ExpectedVersion: version,
 	})
 	if err != nil {
-		http.Error(w, "Ошибка при обновлении инструмента", http.StatusInternalServerError)
+		writeInstrumentError(w, err, "Ошибка при обновлении инструмента")
 		return
 	}
 
+	setETag(w, resp.Version)
 	json.NewEncoder(w).Encode(map[string]bool{
 		"success": true,
 	})
@@ -120,3 +303,210 @@ func (h *InstrumentHandler) UpdateInstrumentByID(w http.ResponseWriter, r *http.
 func (h *InstrumentHandler) ClearInstrumentCache(w http.ResponseWriter, r *http.Request) {
 	http.Error(w, "Not implemented", http.StatusNotImplemented)
 }
+
+func (h *InstrumentHandler) SearchInstruments(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	req := &proto.SearchInstrumentsRequest{
+		Query:     query.Get("q"),
+		Category:  query.Get("category"),
+		Brand:     query.Get("brand"),
+		Sort:      query.Get("sort"),
+		Cursor:    query.Get("cursor"),
+		Condition: query.Get("condition"),
+	}
+
+	var err error
+	if v := query.Get("min_price"); v != "" {
+		if req.MinPrice, err = strconv.ParseFloat(v, 64); err != nil {
+			writeError(w, http.StatusBadRequest, "Некорректный min_price")
+			return
+		}
+	}
+	if v := query.Get("max_price"); v != "" {
+		if req.MaxPrice, err = strconv.ParseFloat(v, 64); err != nil {
+			writeError(w, http.StatusBadRequest, "Некорректный max_price")
+			return
+		}
+	}
+	if v := query.Get("in_stock"); v != "" {
+		if req.InStock, err = strconv.ParseBool(v); err != nil {
+			writeError(w, http.StatusBadRequest, "Некорректный in_stock")
+			return
+		}
+	}
+	if v := query.Get("page_size"); v != "" {
+		size, err := strconv.ParseInt(v, 10, 32)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "Некорректный page_size")
+			return
+		}
+		req.PageSize = int32(size)
+	}
+
+	// attr.<key>=a,b — любое из значений, attr.<key>.min / attr.<key>.max — диапазон
+	filters := map[string]*proto.AttributeFilter{}
+	var keys []string
+	for name, values := range query {
+		if !strings.HasPrefix(name, "attr.") || len(values) == 0 {
+			continue
+		}
+		key, bound := strings.TrimPrefix(name, "attr."), ""
+		if i := strings.LastIndex(key, "."); i >= 0 {
+			key, bound = key[:i], key[i+1:]
+		}
+		f, ok := filters[key]
+		if !ok {
+			f = &proto.AttributeFilter{Key: key}
+			filters[key] = f
+			keys = append(keys, key)
+		}
+
+		switch bound {
+		case "":
+			f.Values = append(f.Values, strings.Split(values[0], ",")...)
+		case "min", "max":
+			v, err := strconv.ParseFloat(values[0], 64)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, "Некорректная граница атрибута "+key)
+				return
+			}
+			if bound == "min" {
+				f.Min = v
+			} else {
+				f.Max = v
+			}
+		default:
+			writeError(w, http.StatusBadRequest, "Некорректный параметр "+name)
+			return
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		req.Attributes = append(req.Attributes, filters[key])
+	}
+
+	resp, err := h.InstrumentClient.SearchInstruments(context.Background(), req)
+	if err != nil {
+		writeInstrumentError(w, err, "Ошибка поиска инструментов")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+type CreateCategoryRequest struct {
+	Slug       string                       `json:"slug"`
+	Name       string                       `json:"name"`
+	Parent     string                       `json:"parent"`
+	Attributes []*proto.AttributeDefinition `json:"attributes"`
+}
+
+func (h *InstrumentHandler) CreateCategory(w http.ResponseWriter, r *http.Request) {
+	var req CreateCategoryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Неверный формат запроса")
+		return
+	}
+
+	resp, err := h.InstrumentClient.CreateCategory(context.Background(), &proto.CreateCategoryRequest{
+		Slug:       req.Slug,
+		Name:       req.Name,
+		Parent:     req.Parent,
+		Attributes: req.Attributes,
+	})
+	if err != nil {
+		writeInstrumentError(w, err, "Ошибка создания категории")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, resp.Category)
+}
+
+func (h *InstrumentHandler) GetCategories(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.InstrumentClient.GetCategories(context.Background(), &proto.GetCategoriesRequest{})
+	if err != nil {
+		writeInstrumentError(w, err, "Ошибка получения категорий")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp.Categories)
+}
+
+type CreateBrandRequest struct {
+	Slug string `json:"slug"`
+	Name string `json:"name"`
+}
+
+func (h *InstrumentHandler) CreateBrand(w http.ResponseWriter, r *http.Request) {
+	var req CreateBrandRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Неверный формат запроса")
+		return
+	}
+
+	resp, err := h.InstrumentClient.CreateBrand(context.Background(), &proto.CreateBrandRequest{
+		Slug: req.Slug,
+		Name: req.Name,
+	})
+	if err != nil {
+		writeInstrumentError(w, err, "Ошибка создания бренда")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, resp.Brand)
+}
+
+func (h *InstrumentHandler) GetBrands(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.InstrumentClient.GetBrands(context.Background(), &proto.GetBrandsRequest{})
+	if err != nil {
+		writeInstrumentError(w, err, "Ошибка получения брендов")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp.Brands)
+}
+
+// attributeValues переводит атрибуты из JSON: строка — значение enum,
+// число — number, true/false — boolean.
+func attributeValues(attrs map[string]interface{}) []*proto.AttributeValue {
+	keys := make([]string, 0, len(attrs))
+	for k := range attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var result []*proto.AttributeValue
+	for _, k := range keys {
+		v := &proto.AttributeValue{Key: k}
+		switch value := attrs[k].(type) {
+		case string:
+			v.EnumValue = value
+		case float64:
+			v.NumberValue = value
+		case bool:
+			v.BoolValue = value
+		}
+		result = append(result, v)
+	}
+	return result
+}
+
+func writeInstrumentError(w http.ResponseWriter, err error, fallback string) {
+	if st, ok := status.FromError(err); ok {
+		switch st.Code() {
+		case codes.InvalidArgument:
+			writeError(w, http.StatusBadRequest, st.Message())
+			return
+		case codes.NotFound:
+			writeError(w, http.StatusNotFound, st.Message())
+			return
+		case codes.AlreadyExists, codes.FailedPrecondition:
+			writeError(w, http.StatusConflict, st.Message())
+			return
+		case codes.Aborted:
+			writeError(w, http.StatusPreconditionFailed, st.Message())
+			return
+		}
+	}
+	writeError(w, http.StatusInternalServerError, fallback)
+}
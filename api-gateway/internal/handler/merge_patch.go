@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"sort"
+)
+
+const mergePatchContentType = "application/merge-patch+json"
+
+var errUnsupportedPatchType = errors.New("поддерживается только " + mergePatchContentType)
+
+// decodeMergePatch разбирает тело JSON merge-patch (RFC 7396): dst
+// заполняется значениями, null даёт нулевое значение поля. Возвращает
+// имена полей из тела — по ним строится маска обновления — и сырые
+// значения для полей, которые сливаются по-особому.
+func decodeMergePatch(r *http.Request, allowed map[string]bool, dst interface{}) ([]string, map[string]json.RawMessage, error) {
+	if ct := r.Header.Get("Content-Type"); ct != "" {
+		mediaType, _, _ := mime.ParseMediaType(ct)
+		if mediaType != mergePatchContentType && mediaType != "application/json" {
+			return nil, nil, errUnsupportedPatchType
+		}
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	var patch map[string]json.RawMessage
+	if err := json.Unmarshal(body, &patch); err != nil || patch == nil {
+		return nil, nil, errors.New("тело запроса должно быть JSON-объектом")
+	}
+	if err := json.Unmarshal(body, dst); err != nil {
+		return nil, nil, errors.New("неверный формат запроса")
+	}
+
+	paths := make([]string, 0, len(patch))
+	for field := range patch {
+		if !allowed[field] {
+			return nil, nil, fmt.Errorf("поле %q нельзя изменить", field)
+		}
+		paths = append(paths, field)
+	}
+	if len(paths) == 0 {
+		return nil, nil, errors.New("нет полей для изменения")
+	}
+	sort.Strings(paths)
+	return paths, patch, nil
+}
+
+func writePatchError(w http.ResponseWriter, err error) {
+	if errors.Is(err, errUnsupportedPatchType) {
+		writeError(w, http.StatusUnsupportedMediaType, err.Error())
+		return
+	}
+	writeError(w, http.StatusBadRequest, err.Error())
+}
@@ -5,6 +5,10 @@ import (
 	"encoding/json"
 	"net/http"
 
+	"github.com/gorilla/mux"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
 	"gotune/order/proto"
 	usersproto "gotune/users/proto"
 )
@@ -22,9 +26,13 @@ func NewOrderHandle(orderClient proto.OrderServiceClient, userClient usersproto.
 }
 
 type CreateOrderRequest struct {
-	UserID string `json:"user_id"`
-	Items  []struct {
+	UserID         string `json:"user_id"`
+	AddressID      string `json:"address_id"`
+	ShippingOption string `json:"shipping_option"`
+	CouponCode     string `json:"coupon_code"`
+	Items          []struct {
 		InstrumentID string `json:"instrument_id"`
+		SKU          string `json:"sku"`
 		Quantity     int32  `json:"quantity"`
 	} `json:"items"`
 }
@@ -46,20 +54,34 @@ func (h *OrderHandler) CreateOrder(w http.ResponseWriter, r *http.Request) {
 	for _, item := range req.Items {
 		items = append(items, &proto.OrderItem{
 			InstrumentId: item.InstrumentID,
+			Sku:          item.SKU,
 			Quantity:     item.Quantity,
 		})
 	}
 
-	_, err = h.OrderClient.CreateOrder(context.Background(), &proto.CreateOrderRequest{
-		UserId: req.UserID,
-		Items:  items,
+	resp, err := h.OrderClient.CreateOrder(context.Background(), &proto.CreateOrderRequest{
+		UserId:         req.UserID,
+		Items:          items,
+		AddressId:      req.AddressID,
+		ShippingOption: req.ShippingOption,
+		CouponCode:     req.CouponCode,
 	})
 	if err != nil {
+		if st, ok := status.FromError(err); ok {
+			switch st.Code() {
+			case codes.FailedPrecondition, codes.NotFound, codes.ResourceExhausted:
+				http.Error(w, st.Message(), http.StatusUnprocessableEntity)
+				return
+			}
+		}
 		http.Error(w, "Ошибка создания заказа", http.StatusInternalServerError)
 		return
 	}
 
 	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]string{
+		"order_id": resp.OrderId,
+	})
 }
 
 func (h *OrderHandler) GetOrders(w http.ResponseWriter, r *http.Request) {
@@ -115,3 +137,277 @@ func (h *OrderHandler) DeleteOrder(w http.ResponseWriter, r *http.Request) {
 
 	json.NewEncoder(w).Encode(map[string]bool{"success": true})
 }
+
+type PayOrderRequest struct {
+	UserID string `json:"user_id"`
+}
+
+func (h *OrderHandler) PayOrder(w http.ResponseWriter, r *http.Request) {
+	orderID := mux.Vars(r)["id"]
+
+	var req PayOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Неверный формат запроса", http.StatusBadRequest)
+		return
+	}
+
+	resp, err := h.OrderClient.PayOrder(context.Background(), &proto.PayOrderRequest{
+		OrderId: orderID,
+		UserId:  req.UserID,
+	})
+	if err != nil {
+		if st, ok := status.FromError(err); ok {
+			switch st.Code() {
+			case codes.NotFound:
+				http.Error(w, "Заказ не найден", http.StatusNotFound)
+				return
+			case codes.FailedPrecondition:
+				http.Error(w, "Заказ уже оплачен", http.StatusConflict)
+				return
+			}
+		}
+		http.Error(w, "Ошибка оплаты заказа", http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success":        resp.Success,
+		"invoice_number": resp.InvoiceNumber,
+	})
+}
+
+func (h *OrderHandler) GetInvoice(w http.ResponseWriter, r *http.Request) {
+	orderID := mux.Vars(r)["id"]
+	userID := r.URL.Query().Get("user_id")
+	if userID == "" {
+		http.Error(w, "Отсутствует user_id", http.StatusBadRequest)
+		return
+	}
+
+	resp, err := h.OrderClient.GetInvoice(context.Background(), &proto.GetInvoiceRequest{
+		OrderId: orderID,
+		UserId:  userID,
+		Format:  r.URL.Query().Get("format"),
+	})
+	if err != nil {
+		if st, ok := status.FromError(err); ok {
+			switch st.Code() {
+			case codes.NotFound:
+				http.Error(w, "Счёт не найден", http.StatusNotFound)
+				return
+			case codes.InvalidArgument:
+				http.Error(w, st.Message(), http.StatusBadRequest)
+				return
+			}
+		}
+		http.Error(w, "Ошибка получения счёта", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", resp.ContentType)
+	if resp.ContentType == "application/pdf" {
+		w.Header().Set("Content-Disposition", "inline; filename=\""+resp.InvoiceNumber+".pdf\"")
+	}
+	w.Write(resp.Content)
+}
+
+// CreateShipmentRequest — serials задаёт конкретные экземпляры для
+// первой отправки заказа, остальные подбираются со склада.
+type CreateShipmentRequest struct {
+	Carrier        string            `json:"carrier"`
+	TrackingNumber string            `json:"tracking_number"`
+	Serials        []ShipmentSerials `json:"serials,omitempty"`
+}
+
+type ShipmentSerials struct {
+	InstrumentID  string   `json:"instrument_id"`
+	SKU           string   `json:"sku,omitempty"`
+	SerialNumbers []string `json:"serial_numbers"`
+}
+
+func (h *OrderHandler) CreateShipment(w http.ResponseWriter, r *http.Request) {
+	orderID := mux.Vars(r)["id"]
+
+	var req CreateShipmentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Неверный формат запроса", http.StatusBadRequest)
+		return
+	}
+
+	var serials []*proto.ShipmentSerials
+	for _, s := range req.Serials {
+		serials = append(serials, &proto.ShipmentSerials{
+			InstrumentId:  s.InstrumentID,
+			Sku:           s.SKU,
+			SerialNumbers: s.SerialNumbers,
+		})
+	}
+
+	resp, err := h.OrderClient.CreateShipment(context.Background(), &proto.CreateShipmentRequest{
+		OrderId:        orderID,
+		Carrier:        req.Carrier,
+		TrackingNumber: req.TrackingNumber,
+		Serials:        serials,
+	})
+	if err != nil {
+		writeShipmentError(w, err, "Ошибка создания отправления")
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]string{
+		"shipment_id": resp.ShipmentId,
+	})
+}
+
+type AddTrackingEventRequest struct {
+	Status      string `json:"status"`
+	Location    string `json:"location"`
+	Description string `json:"description"`
+	OccurredAt  int64  `json:"occurred_at"`
+}
+
+func (h *OrderHandler) AddTrackingEvent(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	var req AddTrackingEventRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Неверный формат запроса", http.StatusBadRequest)
+		return
+	}
+
+	resp, err := h.OrderClient.AddTrackingEvent(context.Background(), &proto.AddTrackingEventRequest{
+		OrderId:    vars["id"],
+		ShipmentId: vars["shipment_id"],
+		Event: &proto.TrackingEvent{
+			Status:      req.Status,
+			Location:    req.Location,
+			Description: req.Description,
+			OccurredAt:  req.OccurredAt,
+		},
+	})
+	if err != nil {
+		writeShipmentError(w, err, "Ошибка добавления события доставки")
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]bool{"success": resp.Success})
+}
+
+func (h *OrderHandler) GetTracking(w http.ResponseWriter, r *http.Request) {
+	orderID := mux.Vars(r)["id"]
+	userID := r.URL.Query().Get("user_id")
+	if userID == "" {
+		http.Error(w, "Отсутствует user_id", http.StatusBadRequest)
+		return
+	}
+
+	resp, err := h.OrderClient.GetTracking(context.Background(), &proto.GetTrackingRequest{
+		OrderId: orderID,
+		UserId:  userID,
+	})
+	if err != nil {
+		writeShipmentError(w, err, "Ошибка получения информации о доставке")
+		return
+	}
+
+	json.NewEncoder(w).Encode(resp)
+}
+
+func writeShipmentError(w http.ResponseWriter, err error, fallback string) {
+	if st, ok := status.FromError(err); ok {
+		switch st.Code() {
+		case codes.NotFound:
+			http.Error(w, st.Message(), http.StatusNotFound)
+			return
+		case codes.InvalidArgument:
+			http.Error(w, st.Message(), http.StatusBadRequest)
+			return
+		case codes.FailedPrecondition:
+			http.Error(w, st.Message(), http.StatusConflict)
+			return
+		}
+	}
+	http.Error(w, fallback, http.StatusInternalServerError)
+}
+
+type QuoteShippingRequest struct {
+	UserID    string `json:"user_id"`
+	AddressID string `json:"address_id"`
+	Address   *struct {
+		City       string `json:"city"`
+		Region     string `json:"region"`
+		PostalCode string `json:"postal_code"`
+		Country    string `json:"country"`
+	} `json:"address"`
+	Items []struct {
+		InstrumentID string `json:"instrument_id"`
+		SKU          string `json:"sku"`
+		Quantity     int32  `json:"quantity"`
+	} `json:"items"`
+}
+
+func (h *OrderHandler) QuoteShipping(w http.ResponseWriter, r *http.Request) {
+	var req QuoteShippingRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Неверный формат запроса", http.StatusBadRequest)
+		return
+	}
+
+	quoteReq := &proto.QuoteShippingRequest{
+		UserId:    req.UserID,
+		AddressId: req.AddressID,
+	}
+	if req.Address != nil {
+		quoteReq.Address = &proto.ShippingAddress{
+			City:       req.Address.City,
+			Region:     req.Address.Region,
+			PostalCode: req.Address.PostalCode,
+			Country:    req.Address.Country,
+		}
+	}
+	for _, item := range req.Items {
+		quoteReq.Items = append(quoteReq.Items, &proto.OrderItem{
+			InstrumentId: item.InstrumentID,
+			Sku:          item.SKU,
+			Quantity:     item.Quantity,
+		})
+	}
+
+	resp, err := h.OrderClient.QuoteShipping(context.Background(), quoteReq)
+	if err != nil {
+		writeShipmentError(w, err, "Ошибка расчёта доставки")
+		return
+	}
+
+	json.NewEncoder(w).Encode(resp.Options)
+}
+
+// GetRecommendations возвращает инструменты, которые покупают вместе
+// с инструментом; limit — сколько вернуть, по умолчанию все сохранённые.
+func (h *OrderHandler) GetRecommendations(w http.ResponseWriter, r *http.Request) {
+	limit, err := queryInt32(r.URL.Query().Get("limit"))
+	if err != nil || limit < 0 {
+		writeError(w, http.StatusBadRequest, "Неверный параметр limit")
+		return
+	}
+
+	resp, err := h.OrderClient.GetRecommendations(context.Background(), &proto.GetRecommendationsRequest{
+		InstrumentId: mux.Vars(r)["id"],
+		Limit:        limit,
+	})
+	if err != nil {
+		if status.Code(err) == codes.InvalidArgument {
+			writeError(w, http.StatusBadRequest, status.Convert(err).Message())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "Ошибка получения рекомендаций")
+		return
+	}
+
+	recommendations := resp.Recommendations
+	if recommendations == nil {
+		recommendations = []*proto.Recommendation{}
+	}
+	writeJSON(w, http.StatusOK, recommendations)
+}
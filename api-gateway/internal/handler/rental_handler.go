@@ -0,0 +1,244 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"gotune/rental/proto"
+)
+
+type RentalHandler struct {
+	RentalClient proto.RentalServiceClient
+}
+
+func NewRentalHandler(client proto.RentalServiceClient) *RentalHandler {
+	return &RentalHandler{RentalClient: client}
+}
+
+type SetRentalPlanRequest struct {
+	DailyRate     float64 `json:"daily_rate"`
+	MonthlyRate   float64 `json:"monthly_rate"`
+	Deposit       float64 `json:"deposit"`
+	LateFeePerDay float64 `json:"late_fee_per_day"`
+}
+
+func (h *RentalHandler) SetRentalPlan(w http.ResponseWriter, r *http.Request) {
+	var req SetRentalPlanRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Неверный формат запроса")
+		return
+	}
+
+	resp, err := h.RentalClient.SetRentalPlan(context.Background(), &proto.SetRentalPlanRequest{
+		InstrumentId:  mux.Vars(r)["id"],
+		DailyRate:     req.DailyRate,
+		MonthlyRate:   req.MonthlyRate,
+		Deposit:       req.Deposit,
+		LateFeePerDay: req.LateFeePerDay,
+	})
+	if err != nil {
+		writeRentalError(w, err, "Ошибка сохранения тарифа аренды")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *RentalHandler) GetRentalPlan(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.RentalClient.GetRentalPlan(context.Background(), &proto.GetRentalPlanRequest{
+		InstrumentId: mux.Vars(r)["id"],
+	})
+	if err != nil {
+		writeRentalError(w, err, "Ошибка получения тарифа аренды")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+type AddRentalUnitRequest struct {
+	SerialNumber string `json:"serial_number"`
+}
+
+func (h *RentalHandler) AddRentalUnit(w http.ResponseWriter, r *http.Request) {
+	var req AddRentalUnitRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Неверный формат запроса")
+		return
+	}
+
+	resp, err := h.RentalClient.AddRentalUnit(context.Background(), &proto.AddRentalUnitRequest{
+		InstrumentId: mux.Vars(r)["id"],
+		SerialNumber: req.SerialNumber,
+	})
+	if err != nil {
+		writeRentalError(w, err, "Ошибка добавления экземпляра")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, resp)
+}
+
+type SetRentalUnitStatusRequest struct {
+	Status string `json:"status"`
+}
+
+func (h *RentalHandler) SetRentalUnitStatus(w http.ResponseWriter, r *http.Request) {
+	var req SetRentalUnitStatusRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Неверный формат запроса")
+		return
+	}
+
+	resp, err := h.RentalClient.SetRentalUnitStatus(context.Background(), &proto.SetRentalUnitStatusRequest{
+		UnitId: mux.Vars(r)["id"],
+		Status: req.Status,
+	})
+	if err != nil {
+		writeRentalError(w, err, "Ошибка смены статуса экземпляра")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// GetAvailability — from и to в Unix-секундах, по умолчанию ближайшие
+// 30 дней.
+func (h *RentalHandler) GetAvailability(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	from, err := queryUnix(query.Get("from"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Неверный параметр from")
+		return
+	}
+	to, err := queryUnix(query.Get("to"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Неверный параметр to")
+		return
+	}
+
+	resp, err := h.RentalClient.GetAvailability(context.Background(), &proto.GetAvailabilityRequest{
+		InstrumentId: mux.Vars(r)["id"],
+		From:         from,
+		To:           to,
+	})
+	if err != nil {
+		writeRentalError(w, err, "Ошибка получения календаря аренды")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// CreateRentalRequest — даты в Unix-секундах; unit_id можно не указывать,
+// тогда выдаётся любой свободный экземпляр.
+type CreateRentalRequest struct {
+	UserID       string `json:"user_id"`
+	InstrumentID string `json:"instrument_id"`
+	UnitID       string `json:"unit_id,omitempty"`
+	StartDate    int64  `json:"start_date"`
+	EndDate      int64  `json:"end_date"`
+}
+
+func (h *RentalHandler) CreateRental(w http.ResponseWriter, r *http.Request) {
+	var req CreateRentalRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Неверный формат запроса")
+		return
+	}
+
+	resp, err := h.RentalClient.CreateRental(context.Background(), &proto.CreateRentalRequest{
+		UserId:       req.UserID,
+		InstrumentId: req.InstrumentID,
+		UnitId:       req.UnitID,
+		StartDate:    req.StartDate,
+		EndDate:      req.EndDate,
+	})
+	if err != nil {
+		writeRentalError(w, err, "Ошибка оформления аренды")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, resp)
+}
+
+func (h *RentalHandler) GetRentals(w http.ResponseWriter, r *http.Request) {
+	userID := r.URL.Query().Get("user_id")
+	if userID == "" {
+		writeError(w, http.StatusBadRequest, "Отсутствует user_id")
+		return
+	}
+
+	resp, err := h.RentalClient.GetRentals(context.Background(), &proto.GetRentalsRequest{UserId: userID})
+	if err != nil {
+		writeRentalError(w, err, "Ошибка получения аренд")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp.Rentals)
+}
+
+type ExtendRentalRequest struct {
+	EndDate int64 `json:"end_date"`
+}
+
+func (h *RentalHandler) ExtendRental(w http.ResponseWriter, r *http.Request) {
+	var req ExtendRentalRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Неверный формат запроса")
+		return
+	}
+
+	resp, err := h.RentalClient.ExtendRental(context.Background(), &proto.ExtendRentalRequest{
+		RentalId: mux.Vars(r)["id"],
+		EndDate:  req.EndDate,
+	})
+	if err != nil {
+		writeRentalError(w, err, "Ошибка продления аренды")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *RentalHandler) ReturnRental(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.RentalClient.ReturnRental(context.Background(), &proto.ReturnRentalRequest{
+		RentalId: mux.Vars(r)["id"],
+	})
+	if err != nil {
+		writeRentalError(w, err, "Ошибка возврата аренды")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func queryUnix(v string) (int64, error) {
+	if v == "" {
+		return 0, nil
+	}
+	return strconv.ParseInt(v, 10, 64)
+}
+
+func writeRentalError(w http.ResponseWriter, err error, fallback string) {
+	if st, ok := status.FromError(err); ok {
+		switch st.Code() {
+		case codes.InvalidArgument:
+			writeError(w, http.StatusBadRequest, st.Message())
+			return
+		case codes.NotFound:
+			writeError(w, http.StatusNotFound, st.Message())
+			return
+		case codes.AlreadyExists, codes.FailedPrecondition, codes.Aborted:
+			writeError(w, http.StatusConflict, st.Message())
+			return
+		}
+	}
+	writeError(w, http.StatusInternalServerError, fallback)
+}
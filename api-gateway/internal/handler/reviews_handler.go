@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"gotune/instruments/proto"
+)
+
+type CreateReviewRequest struct {
+	UserID string `json:"user_id"`
+	Rating int32  `json:"rating"`
+	Text   string `json:"text"`
+}
+
+func (h *InstrumentHandler) CreateReview(w http.ResponseWriter, r *http.Request) {
+	var req CreateReviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Неверный формат запроса")
+		return
+	}
+
+	resp, err := h.InstrumentClient.CreateReview(context.Background(), &proto.CreateReviewRequest{
+		InstrumentId: mux.Vars(r)["id"],
+		UserId:       req.UserID,
+		Rating:       req.Rating,
+		Text:         req.Text,
+	})
+	if err != nil {
+		writeInstrumentError(w, err, "Ошибка создания отзыва")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, resp)
+}
+
+// GetInstrumentReviews возвращает одобренные отзывы инструмента;
+// параметры: sort (newest, helpful, rating_desc, rating_asc), limit, offset.
+func (h *InstrumentHandler) GetInstrumentReviews(w http.ResponseWriter, r *http.Request) {
+	h.getReviews(w, r, mux.Vars(r)["id"], "")
+}
+
+// GetReviews — отзывы всех инструментов для модерации, по умолчанию
+// ожидающие проверки.
+func (h *InstrumentHandler) GetReviews(w http.ResponseWriter, r *http.Request) {
+	reviewStatus := r.URL.Query().Get("status")
+	if reviewStatus == "" {
+		reviewStatus = "pending"
+	}
+	h.getReviews(w, r, "", reviewStatus)
+}
+
+func (h *InstrumentHandler) getReviews(w http.ResponseWriter, r *http.Request, instrumentID, reviewStatus string) {
+	query := r.URL.Query()
+	limit, err := queryInt32(query.Get("limit"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Неверный параметр limit")
+		return
+	}
+	offset, err := queryInt32(query.Get("offset"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Неверный параметр offset")
+		return
+	}
+
+	resp, err := h.InstrumentClient.GetReviews(context.Background(), &proto.GetReviewsRequest{
+		InstrumentId: instrumentID,
+		Status:       reviewStatus,
+		Sort:         query.Get("sort"),
+		Limit:        limit,
+		Offset:       offset,
+	})
+	if err != nil {
+		writeInstrumentError(w, err, "Ошибка получения отзывов")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+type ModerateReviewRequest struct {
+	Status string `json:"status"`
+	Note   string `json:"note"`
+}
+
+func (h *InstrumentHandler) ModerateReview(w http.ResponseWriter, r *http.Request) {
+	var req ModerateReviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Неверный формат запроса")
+		return
+	}
+
+	resp, err := h.InstrumentClient.ModerateReview(context.Background(), &proto.ModerateReviewRequest{
+		Id:     mux.Vars(r)["id"],
+		Status: req.Status,
+		Note:   req.Note,
+	})
+	if err != nil {
+		writeInstrumentError(w, err, "Ошибка модерации отзыва")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+type VoteReviewRequest struct {
+	UserID string `json:"user_id"`
+}
+
+func (h *InstrumentHandler) VoteReviewHelpful(w http.ResponseWriter, r *http.Request) {
+	var req VoteReviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Неверный формат запроса")
+		return
+	}
+
+	resp, err := h.InstrumentClient.VoteReviewHelpful(context.Background(), &proto.VoteReviewHelpfulRequest{
+		ReviewId: mux.Vars(r)["id"],
+		UserId:   req.UserID,
+	})
+	if err != nil {
+		writeInstrumentError(w, err, "Ошибка голосования за отзыв")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func queryInt32(v string) (int32, error) {
+	if v == "" {
+		return 0, nil
+	}
+	n, err := strconv.ParseInt(v, 10, 32)
+	return int32(n), err
+}
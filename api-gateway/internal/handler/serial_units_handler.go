@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"gotune/instruments/proto"
+)
+
+type AddSerialUnitRequest struct {
+	SKU          string `json:"sku,omitempty"`
+	SerialNumber string `json:"serial_number"`
+	Condition    string `json:"condition,omitempty"`
+	Location     string `json:"location,omitempty"`
+}
+
+func (h *InstrumentHandler) AddSerialUnit(w http.ResponseWriter, r *http.Request) {
+	var req AddSerialUnitRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Неверный формат запроса")
+		return
+	}
+
+	resp, err := h.InstrumentClient.AddSerialUnit(context.Background(), &proto.AddSerialUnitRequest{
+		InstrumentId: mux.Vars(r)["id"],
+		Sku:          req.SKU,
+		SerialNumber: req.SerialNumber,
+		Condition:    req.Condition,
+		Location:     req.Location,
+	})
+	if err != nil {
+		writeInstrumentError(w, err, "Ошибка добавления экземпляра")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, resp)
+}
+
+// GetSerialUnits — экземпляры инструмента, фильтры sku и status
+// необязательны.
+func (h *InstrumentHandler) GetSerialUnits(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	resp, err := h.InstrumentClient.GetSerialUnits(context.Background(), &proto.GetSerialUnitsRequest{
+		InstrumentId: mux.Vars(r)["id"],
+		Sku:          query.Get("sku"),
+		Status:       query.Get("status"),
+	})
+	if err != nil {
+		writeInstrumentError(w, err, "Ошибка получения экземпляров")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp.Units)
+}
+
+func (h *InstrumentHandler) GetSerialUnit(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.InstrumentClient.GetSerialUnit(context.Background(), &proto.GetSerialUnitRequest{
+		SerialNumber: mux.Vars(r)["serial"],
+	})
+	if err != nil {
+		writeInstrumentError(w, err, "Ошибка получения экземпляра")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+type UpdateSerialUnitRequest struct {
+	Condition string `json:"condition,omitempty"`
+	Location  string `json:"location,omitempty"`
+	Status    string `json:"status,omitempty"`
+}
+
+func (h *InstrumentHandler) UpdateSerialUnit(w http.ResponseWriter, r *http.Request) {
+	var req UpdateSerialUnitRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Неверный формат запроса")
+		return
+	}
+
+	resp, err := h.InstrumentClient.UpdateSerialUnit(context.Background(), &proto.UpdateSerialUnitRequest{
+		SerialNumber: mux.Vars(r)["serial"],
+		Condition:    req.Condition,
+		Location:     req.Location,
+		Status:       req.Status,
+	})
+	if err != nil {
+		writeInstrumentError(w, err, "Ошибка изменения экземпляра")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+type RegisterWarrantyRequest struct {
+	UserID string `json:"user_id"`
+}
+
+func (h *InstrumentHandler) RegisterWarranty(w http.ResponseWriter, r *http.Request) {
+	var req RegisterWarrantyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Неверный формат запроса")
+		return
+	}
+
+	resp, err := h.InstrumentClient.RegisterWarranty(context.Background(), &proto.RegisterWarrantyRequest{
+		SerialNumber: mux.Vars(r)["serial"],
+		UserId:       req.UserID,
+	})
+	if err != nil {
+		writeInstrumentError(w, err, "Ошибка регистрации гарантии")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, resp)
+}
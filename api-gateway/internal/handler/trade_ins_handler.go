@@ -0,0 +1,206 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"gotune/instruments/proto"
+)
+
+type SubmitTradeInRequest struct {
+	UserID       string `json:"user_id"`
+	Name         string `json:"name"`
+	Brand        string `json:"brand,omitempty"`
+	Description  string `json:"description,omitempty"`
+	SerialNumber string `json:"serial_number,omitempty"`
+	Condition    string `json:"condition"`
+}
+
+func (h *InstrumentHandler) SubmitTradeIn(w http.ResponseWriter, r *http.Request) {
+	var req SubmitTradeInRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Неверный формат запроса")
+		return
+	}
+
+	resp, err := h.InstrumentClient.SubmitTradeIn(context.Background(), &proto.SubmitTradeInRequest{
+		UserId:       req.UserID,
+		Name:         req.Name,
+		Brand:        req.Brand,
+		Description:  req.Description,
+		SerialNumber: req.SerialNumber,
+		Condition:    req.Condition,
+	})
+	if err != nil {
+		writeInstrumentError(w, err, "Ошибка создания заявки")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, resp)
+}
+
+// GetTradeIns — заявки пользователя (user_id) или очередь заявок для
+// сотрудников; status необязателен.
+func (h *InstrumentHandler) GetTradeIns(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	resp, err := h.InstrumentClient.GetTradeIns(context.Background(), &proto.GetTradeInsRequest{
+		UserId: query.Get("user_id"),
+		Status: query.Get("status"),
+	})
+	if err != nil {
+		writeInstrumentError(w, err, "Ошибка получения заявок")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp.TradeIns)
+}
+
+// UploadTradeInPhoto принимает multipart-форму с полями file и user_id.
+func (h *InstrumentHandler) UploadTradeInPhoto(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxMediaUploadBytes)
+	if err := r.ParseMultipartForm(8 << 20); err != nil {
+		writeError(w, http.StatusBadRequest, "Неверная multipart-форма или файл слишком большой")
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Отсутствует файл")
+		return
+	}
+	defer file.Close()
+
+	stream, err := h.InstrumentClient.UploadTradeInPhoto(context.Background())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "Ошибка загрузки фото")
+		return
+	}
+
+	chunk := make([]byte, mediaChunkSize)
+	first := true
+	for {
+		n, readErr := file.Read(chunk)
+		if n > 0 || first {
+			req := &proto.UploadTradeInPhotoRequest{Data: chunk[:n]}
+			if first {
+				req.TradeInId = mux.Vars(r)["id"]
+				req.UserId = r.FormValue("user_id")
+				req.Filename = header.Filename
+				first = false
+			}
+			// при ошибке отправки сервис уже закрыл поток, причину вернёт CloseAndRecv
+			if err := stream.Send(req); err != nil {
+				break
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			writeError(w, http.StatusBadRequest, "Ошибка чтения файла")
+			return
+		}
+	}
+
+	resp, err := stream.CloseAndRecv()
+	if err != nil {
+		writeInstrumentError(w, err, "Ошибка загрузки фото")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, resp)
+}
+
+type AppraiseTradeInRequest struct {
+	Condition   string  `json:"condition,omitempty"`
+	OfferAmount float64 `json:"offer_amount,omitempty"`
+	Note        string  `json:"note,omitempty"`
+	Reject      bool    `json:"reject,omitempty"`
+}
+
+func (h *InstrumentHandler) AppraiseTradeIn(w http.ResponseWriter, r *http.Request) {
+	var req AppraiseTradeInRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Неверный формат запроса")
+		return
+	}
+
+	resp, err := h.InstrumentClient.AppraiseTradeIn(context.Background(), &proto.AppraiseTradeInRequest{
+		Id:          mux.Vars(r)["id"],
+		Condition:   req.Condition,
+		OfferAmount: req.OfferAmount,
+		Note:        req.Note,
+		Reject:      req.Reject,
+	})
+	if err != nil {
+		writeInstrumentError(w, err, "Ошибка оценки заявки")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+type RespondTradeInOfferRequest struct {
+	UserID string `json:"user_id"`
+	Accept bool   `json:"accept"`
+}
+
+func (h *InstrumentHandler) RespondTradeInOffer(w http.ResponseWriter, r *http.Request) {
+	var req RespondTradeInOfferRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Неверный формат запроса")
+		return
+	}
+
+	resp, err := h.InstrumentClient.RespondTradeInOffer(context.Background(), &proto.RespondTradeInOfferRequest{
+		Id:     mux.Vars(r)["id"],
+		UserId: req.UserID,
+		Accept: req.Accept,
+	})
+	if err != nil {
+		writeInstrumentError(w, err, "Ошибка ответа на предложение")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+type ReceiveTradeInRequest struct {
+	Price       float64 `json:"price"`
+	Name        string  `json:"name,omitempty"`
+	Description string  `json:"description,omitempty"`
+	Category    string  `json:"category,omitempty"`
+	Brand       string  `json:"brand,omitempty"`
+	Location    string  `json:"location,omitempty"`
+}
+
+// ReceiveTradeIn — инструмент получен магазином: начисляется кредит
+// и создаётся б/у товар. Запрос можно повторить после ошибки.
+func (h *InstrumentHandler) ReceiveTradeIn(w http.ResponseWriter, r *http.Request) {
+	var req ReceiveTradeInRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Неверный формат запроса")
+		return
+	}
+
+	resp, err := h.InstrumentClient.ReceiveTradeIn(context.Background(), &proto.ReceiveTradeInRequest{
+		Id:          mux.Vars(r)["id"],
+		Price:       req.Price,
+		Name:        req.Name,
+		Description: req.Description,
+		Category:    req.Category,
+		Brand:       req.Brand,
+		Location:    req.Location,
+	})
+	if err != nil {
+		writeInstrumentError(w, err, "Ошибка приёма инструмента")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
@@ -3,21 +3,25 @@ package handler
 import (
 	"context"
 	"encoding/json"
+	"log"
 	"net/http"
 
 	"github.com/gorilla/mux"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 
+	cartproto "gotune/cart/proto"
 	"gotune/users/proto"
 )
 
 type UserHandler struct {
 	UserClient proto.UserServiceClient
+	CartClient cartproto.CartServiceClient
 }
 
-func NewUserHandler(client proto.UserServiceClient) *UserHandler {
-	return &UserHandler{UserClient: client}
+func NewUserHandler(client proto.UserServiceClient, cartClient cartproto.CartServiceClient) *UserHandler {
+	return &UserHandler{UserClient: client, CartClient: cartClient}
 }
 
 type RegisterRequest struct {
@@ -79,8 +83,23 @@ func (h *UserHandler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Переносим гостевую корзину в корзину пользователя. Ошибка объединения
+	// не должна мешать входу, поэтому она только логируется.
+	if token := cartToken(r); token != "" {
+		_, err := h.CartClient.MergeCarts(context.Background(), &cartproto.MergeCartsRequest{
+			UserId:    resp.UserId,
+			CartToken: token,
+		})
+		if err != nil {
+			log.Printf("Ошибка объединения корзин пользователя %s: %v", resp.UserId, err)
+		} else {
+			clearCartToken(w)
+		}
+	}
+
 	json.NewEncoder(w).Encode(map[string]string{
-		"token": resp.Token,
+		"token":   resp.Token,
+		"user_id": resp.UserId,
 	})
 }
 
@@ -106,6 +125,7 @@ func (h *UserHandler) GetUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	setETag(w, resp.Version)
 	json.NewEncoder(w).Encode(map[string]string{
 		"user_id":  resp.UserId,
 		"username": resp.Username,
@@ -123,6 +143,12 @@ func (h *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	userID := vars["id"]
 
+	version, err := requireIfMatch(r)
+	if err != nil {
+		writeIfMatchError(w, err)
+		return
+	}
+
 	var req UpdateUserRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, "Неверный формат запроса", http.StatusBadRequest)
@@ -130,34 +156,80 @@ func (h *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 	}
 
 	resp, err := h.UserClient.UpdateUser(context.Background(), &proto.UpdateUserRequest{
-		UserId:   userID,
-		Username: req.Username,
-		Email:    req.Email,
-		Password: req.Password,
+		UserId:          userID,
+		Username:        req.Username,
+		Email:           req.Email,
+		Password:        req.Password,
+		ExpectedVersion: version,
 	})
 	if err != nil {
-		if st, ok := status.FromError(err); ok {
-			switch st.Code() {
-			case codes.NotFound:
-				http.Error(w, "Пользователь не найден", http.StatusNotFound)
-				return
-			case codes.AlreadyExists:
-				http.Error(w, "Email уже зарегистрирован другим пользователем", http.StatusConflict)
-				return
-			default:
-				http.Error(w, "Ошибка при обновлении пользователя", http.StatusInternalServerError)
-				return
-			}
-		}
-		http.Error(w, "Ошибка при обновлении пользователя", http.StatusInternalServerError)
+		writeUpdateUserError(w, err)
 		return
 	}
 
+	setETag(w, resp.Version)
 	json.NewEncoder(w).Encode(map[string]bool{
 		"success": resp.Success,
 	})
 }
 
+var userPatchFields = map[string]bool{"username": true, "email": true, "password": true}
+
+// PatchUser применяет JSON merge-patch к профилю: меняются только поля
+// из тела. If-Match обязателен.
+func (h *UserHandler) PatchUser(w http.ResponseWriter, r *http.Request) {
+	version, err := requireIfMatch(r)
+	if err != nil {
+		writeIfMatchError(w, err)
+		return
+	}
+
+	var req UpdateUserRequest
+	paths, _, err := decodeMergePatch(r, userPatchFields, &req)
+	if err != nil {
+		writePatchError(w, err)
+		return
+	}
+
+	resp, err := h.UserClient.UpdateUser(context.Background(), &proto.UpdateUserRequest{
+		UserId:          mux.Vars(r)["id"],
+		Username:        req.Username,
+		Email:           req.Email,
+		Password:        req.Password,
+		UpdateMask:      &fieldmaskpb.FieldMask{Paths: paths},
+		ExpectedVersion: version,
+	})
+	if err != nil {
+		writeUpdateUserError(w, err)
+		return
+	}
+
+	setETag(w, resp.Version)
+	json.NewEncoder(w).Encode(map[string]bool{
+		"success": resp.Success,
+	})
+}
+
+func writeUpdateUserError(w http.ResponseWriter, err error) {
+	if st, ok := status.FromError(err); ok {
+		switch st.Code() {
+		case codes.NotFound:
+			http.Error(w, "Пользователь не найден", http.StatusNotFound)
+			return
+		case codes.AlreadyExists:
+			http.Error(w, "Email уже зарегистрирован другим пользователем", http.StatusConflict)
+			return
+		case codes.InvalidArgument:
+			http.Error(w, st.Message(), http.StatusBadRequest)
+			return
+		case codes.Aborted:
+			http.Error(w, st.Message(), http.StatusPreconditionFailed)
+			return
+		}
+	}
+	http.Error(w, "Ошибка при обновлении пользователя", http.StatusInternalServerError)
+}
+
 func (h *UserHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	userID := vars["id"]
@@ -184,6 +256,32 @@ func (h *UserHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
 		"success": resp.Success,
 	})
 }
+
+// RestoreUser восстанавливает удалённого пользователя.
+func (h *UserHandler) RestoreUser(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.UserClient.RestoreUser(context.Background(), &proto.RestoreUserRequest{
+		UserId: mux.Vars(r)["id"],
+	})
+	if err != nil {
+		if st, ok := status.FromError(err); ok {
+			switch st.Code() {
+			case codes.NotFound:
+				http.Error(w, "Удалённый пользователь не найден", http.StatusNotFound)
+				return
+			case codes.InvalidArgument:
+				http.Error(w, st.Message(), http.StatusBadRequest)
+				return
+			}
+		}
+		http.Error(w, "Ошибка при восстановлении пользователя", http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]bool{
+		"success": resp.Success,
+	})
+}
+
 func (h *UserHandler) DeleteAllUsersCache(w http.ResponseWriter, r *http.Request) {
 	resp, err := h.UserClient.DeleteAllUsersCache(context.Background(), &proto.DeleteAllUsersCacheRequest{})
 	if err != nil {
@@ -222,3 +320,224 @@ func (h *UserHandler) ConfirmUser(w http.ResponseWriter, r *http.Request) {
 		"message": resp.Message,
 	})
 }
+
+type AddressRequest struct {
+	FullName   string `json:"full_name"`
+	Phone      string `json:"phone"`
+	Line1      string `json:"line1"`
+	Line2      string `json:"line2"`
+	City       string `json:"city"`
+	Region     string `json:"region"`
+	PostalCode string `json:"postal_code"`
+	Country    string `json:"country"`
+	IsDefault  bool   `json:"is_default"`
+}
+
+func (a AddressRequest) toProto(id string) *proto.Address {
+	return &proto.Address{
+		Id:         id,
+		FullName:   a.FullName,
+		Phone:      a.Phone,
+		Line1:      a.Line1,
+		Line2:      a.Line2,
+		City:       a.City,
+		Region:     a.Region,
+		PostalCode: a.PostalCode,
+		Country:    a.Country,
+		IsDefault:  a.IsDefault,
+	}
+}
+
+func writeAddressError(w http.ResponseWriter, err error, fallback string) {
+	if st, ok := status.FromError(err); ok {
+		switch st.Code() {
+		case codes.NotFound:
+			http.Error(w, "Пользователь или адрес не найден", http.StatusNotFound)
+			return
+		case codes.InvalidArgument:
+			http.Error(w, st.Message(), http.StatusBadRequest)
+			return
+		}
+	}
+	http.Error(w, fallback, http.StatusInternalServerError)
+}
+
+func (h *UserHandler) AddAddress(w http.ResponseWriter, r *http.Request) {
+	userID := mux.Vars(r)["id"]
+
+	var req AddressRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Неверный формат запроса", http.StatusBadRequest)
+		return
+	}
+
+	resp, err := h.UserClient.AddAddress(context.Background(), &proto.AddAddressRequest{
+		UserId:  userID,
+		Address: req.toProto(""),
+	})
+	if err != nil {
+		writeAddressError(w, err, "Ошибка при добавлении адреса")
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]string{
+		"address_id": resp.AddressId,
+	})
+}
+
+func (h *UserHandler) GetAddresses(w http.ResponseWriter, r *http.Request) {
+	userID := mux.Vars(r)["id"]
+
+	resp, err := h.UserClient.GetAddresses(context.Background(), &proto.GetAddressesRequest{
+		UserId: userID,
+	})
+	if err != nil {
+		writeAddressError(w, err, "Ошибка получения адресов")
+		return
+	}
+
+	json.NewEncoder(w).Encode(resp.Addresses)
+}
+
+func (h *UserHandler) UpdateAddress(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	var req AddressRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Неверный формат запроса", http.StatusBadRequest)
+		return
+	}
+
+	resp, err := h.UserClient.UpdateAddress(context.Background(), &proto.UpdateAddressRequest{
+		UserId:  vars["id"],
+		Address: req.toProto(vars["address_id"]),
+	})
+	if err != nil {
+		writeAddressError(w, err, "Ошибка при обновлении адреса")
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]bool{
+		"success": resp.Success,
+	})
+}
+
+func (h *UserHandler) DeleteAddress(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	resp, err := h.UserClient.DeleteAddress(context.Background(), &proto.DeleteAddressRequest{
+		UserId:    vars["id"],
+		AddressId: vars["address_id"],
+	})
+	if err != nil {
+		writeAddressError(w, err, "Ошибка при удалении адреса")
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]bool{
+		"success": resp.Success,
+	})
+}
+
+func (h *UserHandler) SetDefaultAddress(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	resp, err := h.UserClient.SetDefaultAddress(context.Background(), &proto.SetDefaultAddressRequest{
+		UserId:    vars["id"],
+		AddressId: vars["address_id"],
+	})
+	if err != nil {
+		writeAddressError(w, err, "Ошибка при смене адреса по умолчанию")
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]bool{
+		"success": resp.Success,
+	})
+}
+
+type CartRemindersRequest struct {
+	OptOut bool `json:"opt_out"`
+}
+
+func (h *UserHandler) SetCartRemindersOptOut(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	var req CartRemindersRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Неверный формат запроса", http.StatusBadRequest)
+		return
+	}
+
+	resp, err := h.UserClient.SetCartRemindersOptOut(context.Background(), &proto.SetCartRemindersOptOutRequest{
+		UserId: vars["id"],
+		OptOut: req.OptOut,
+	})
+	if err != nil {
+		http.Error(w, "Пользователь не найден", http.StatusNotFound)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]bool{
+		"success": resp.Success,
+	})
+}
+
+type AddStoreCreditRequest struct {
+	Amount    float64 `json:"amount"`
+	Reason    string  `json:"reason"`
+	Reference string  `json:"reference"`
+}
+
+// AddStoreCredit — ручное начисление кредита сотрудником. Повтор с тем
+// же reference не начисляет повторно и возвращает applied=false.
+func (h *UserHandler) AddStoreCredit(w http.ResponseWriter, r *http.Request) {
+	var req AddStoreCreditRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Неверный формат запроса", http.StatusBadRequest)
+		return
+	}
+
+	resp, err := h.UserClient.AddStoreCredit(context.Background(), &proto.AddStoreCreditRequest{
+		UserId:    mux.Vars(r)["id"],
+		Amount:    req.Amount,
+		Reason:    req.Reason,
+		Reference: req.Reference,
+	})
+	if err != nil {
+		writeStoreCreditError(w, err, "Ошибка начисления кредита")
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"balance": resp.Balance,
+		"applied": resp.Applied,
+	})
+}
+
+func (h *UserHandler) GetStoreCredit(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.UserClient.GetStoreCredit(context.Background(), &proto.GetStoreCreditRequest{
+		UserId: mux.Vars(r)["id"],
+	})
+	if err != nil {
+		writeStoreCreditError(w, err, "Ошибка получения кредита")
+		return
+	}
+
+	json.NewEncoder(w).Encode(resp)
+}
+
+func writeStoreCreditError(w http.ResponseWriter, err error, fallback string) {
+	if st, ok := status.FromError(err); ok {
+		switch st.Code() {
+		case codes.NotFound:
+			http.Error(w, "Пользователь не найден", http.StatusNotFound)
+			return
+		case codes.InvalidArgument:
+			http.Error(w, st.Message(), http.StatusBadRequest)
+			return
+		}
+	}
+	http.Error(w, fallback, http.StatusInternalServerError)
+}
@@ -0,0 +1,225 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"gotune/wishlist/proto"
+)
+
+type WishlistHandler struct {
+	WishlistClient proto.WishlistServiceClient
+}
+
+func NewWishlistHandler(client proto.WishlistServiceClient) *WishlistHandler {
+	return &WishlistHandler{WishlistClient: client}
+}
+
+type CreateWishlistRequest struct {
+	UserID string `json:"user_id"`
+	Name   string `json:"name"`
+}
+
+func (h *WishlistHandler) CreateWishlist(w http.ResponseWriter, r *http.Request) {
+	if r.Body == nil {
+		writeError(w, http.StatusBadRequest, "Пустое тело запроса")
+		return
+	}
+	defer r.Body.Close()
+
+	var req CreateWishlistRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Неверный формат запроса")
+		return
+	}
+
+	resp, err := h.WishlistClient.CreateWishlist(context.Background(), &proto.CreateWishlistRequest{
+		UserId: req.UserID,
+		Name:   req.Name,
+	})
+	if err != nil {
+		writeWishlistError(w, err, "Ошибка создания списка желаний")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, map[string]string{"wishlist_id": resp.WishlistId})
+}
+
+func (h *WishlistHandler) GetWishlists(w http.ResponseWriter, r *http.Request) {
+	userID := r.URL.Query().Get("user_id")
+	if userID == "" {
+		writeError(w, http.StatusBadRequest, "Отсутствует user_id")
+		return
+	}
+
+	resp, err := h.WishlistClient.GetWishlists(context.Background(), &proto.GetWishlistsRequest{UserId: userID})
+	if err != nil {
+		writeWishlistError(w, err, "Ошибка получения списков желаний")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp.Wishlists)
+}
+
+type WishlistOwnerRequest struct {
+	UserID string `json:"user_id"`
+}
+
+func (h *WishlistHandler) DeleteWishlist(w http.ResponseWriter, r *http.Request) {
+	if r.Body == nil {
+		writeError(w, http.StatusBadRequest, "Пустое тело запроса")
+		return
+	}
+	defer r.Body.Close()
+
+	var req WishlistOwnerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Неверный формат запроса")
+		return
+	}
+
+	_, err := h.WishlistClient.DeleteWishlist(context.Background(), &proto.DeleteWishlistRequest{
+		UserId:     req.UserID,
+		WishlistId: mux.Vars(r)["id"],
+	})
+	if err != nil {
+		writeWishlistError(w, err, "Ошибка удаления списка желаний")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]bool{"success": true})
+}
+
+type AddWishlistItemRequest struct {
+	UserID            string `json:"user_id"`
+	InstrumentID      string `json:"instrument_id"`
+	NotifyPriceDrop   bool   `json:"notify_price_drop"`
+	NotifyBackInStock bool   `json:"notify_back_in_stock"`
+}
+
+func (h *WishlistHandler) AddWishlistItem(w http.ResponseWriter, r *http.Request) {
+	if r.Body == nil {
+		writeError(w, http.StatusBadRequest, "Пустое тело запроса")
+		return
+	}
+	defer r.Body.Close()
+
+	var req AddWishlistItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Неверный формат запроса")
+		return
+	}
+
+	_, err := h.WishlistClient.AddWishlistItem(context.Background(), &proto.AddWishlistItemRequest{
+		UserId:            req.UserID,
+		WishlistId:        mux.Vars(r)["id"],
+		InstrumentId:      req.InstrumentID,
+		NotifyPriceDrop:   req.NotifyPriceDrop,
+		NotifyBackInStock: req.NotifyBackInStock,
+	})
+	if err != nil {
+		writeWishlistError(w, err, "Ошибка добавления в список желаний")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]bool{"success": true})
+}
+
+func (h *WishlistHandler) RemoveWishlistItem(w http.ResponseWriter, r *http.Request) {
+	if r.Body == nil {
+		writeError(w, http.StatusBadRequest, "Пустое тело запроса")
+		return
+	}
+	defer r.Body.Close()
+
+	var req WishlistOwnerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Неверный формат запроса")
+		return
+	}
+
+	vars := mux.Vars(r)
+	_, err := h.WishlistClient.RemoveWishlistItem(context.Background(), &proto.RemoveWishlistItemRequest{
+		UserId:       req.UserID,
+		WishlistId:   vars["id"],
+		InstrumentId: vars["instrument_id"],
+	})
+	if err != nil {
+		writeWishlistError(w, err, "Ошибка удаления из списка желаний")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]bool{"success": true})
+}
+
+type ShareWishlistRequest struct {
+	UserID string `json:"user_id"`
+	Public bool   `json:"public"`
+}
+
+func (h *WishlistHandler) ShareWishlist(w http.ResponseWriter, r *http.Request) {
+	if r.Body == nil {
+		writeError(w, http.StatusBadRequest, "Пустое тело запроса")
+		return
+	}
+	defer r.Body.Close()
+
+	var req ShareWishlistRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Неверный формат запроса")
+		return
+	}
+
+	resp, err := h.WishlistClient.ShareWishlist(context.Background(), &proto.ShareWishlistRequest{
+		UserId:     req.UserID,
+		WishlistId: mux.Vars(r)["id"],
+		Public:     req.Public,
+	})
+	if err != nil {
+		writeWishlistError(w, err, "Ошибка публикации списка желаний")
+		return
+	}
+
+	result := map[string]string{"share_token": resp.ShareToken}
+	if resp.ShareToken != "" {
+		result["share_url"] = "/wishlists/shared/" + resp.ShareToken
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (h *WishlistHandler) GetSharedWishlist(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.WishlistClient.GetSharedWishlist(context.Background(), &proto.GetSharedWishlistRequest{
+		ShareToken: mux.Vars(r)["token"],
+	})
+	if err != nil {
+		writeWishlistError(w, err, "Ошибка получения списка желаний")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func writeWishlistError(w http.ResponseWriter, err error, fallback string) {
+	if st, ok := status.FromError(err); ok {
+		switch st.Code() {
+		case codes.InvalidArgument:
+			writeError(w, http.StatusBadRequest, st.Message())
+			return
+		case codes.NotFound:
+			writeError(w, http.StatusNotFound, st.Message())
+			return
+		case codes.AlreadyExists:
+			writeError(w, http.StatusConflict, st.Message())
+			return
+		case codes.FailedPrecondition:
+			writeError(w, http.StatusUnprocessableEntity, st.Message())
+			return
+		}
+	}
+	writeError(w, http.StatusInternalServerError, fallback)
+}
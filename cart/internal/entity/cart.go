@@ -1,14 +1,59 @@
 package entity
 
-import "go.mongodb.org/mongo-driver/bson/primitive"
+import (
+	"time"
 
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CartItem.SKU — выбранный вариант инструмента; пусто для инструментов
+// без вариантов. Позиция определяется парой инструмент + SKU.
 type CartItem struct {
 	InstrumentID primitive.ObjectID `bson:"instrument_id"`
+	SKU          string             `bson:"sku,omitempty"`
 	Quantity     int32              `bson:"quantity"`
 }
 
+// ItemKey идентифицирует позицию корзины.
+type ItemKey struct {
+	InstrumentID primitive.ObjectID
+	SKU          string
+}
+
+func (i CartItem) Key() ItemKey {
+	return ItemKey{InstrumentID: i.InstrumentID, SKU: i.SKU}
+}
+
+// CartList — именованный список пользователя («апгрейд студии» и т.п.),
+// из которого товары можно перенести в корзину.
+type CartList struct {
+	ID        primitive.ObjectID `bson:"_id"`
+	Name      string             `bson:"name"`
+	Items     []CartItem         `bson:"items"`
+	CreatedAt time.Time          `bson:"created_at"`
+}
+
+// Cart.CouponCode — купон, применённый к корзине; он проверяется повторно
+// при оформлении заказа. Гостевые корзины (коллекция guest_carts) вместо
+// UserID хранят Token и ExpiresAt.
+//
+// SavedItems — отложенные товары («сохранить на потом»), Lists — именованные
+// списки; оба хранятся в документе корзины, чтобы перенос товара был одной
+// записью.
+//
+// Revision увеличивается при каждом изменении корзины и отдаётся клиенту
+// как версия для оптимистической блокировки, RemindedRevision —
+// ревизия, о которой уже отправлено напоминание о брошенной корзине.
 type Cart struct {
-	ID     primitive.ObjectID `bson:"_id,omitempty"`
-	UserID primitive.ObjectID `bson:"user_id"`
-	Items  []CartItem         `bson:"items"`
+	ID               primitive.ObjectID `bson:"_id,omitempty"`
+	UserID           primitive.ObjectID `bson:"user_id,omitempty"`
+	Token            string             `bson:"token,omitempty"`
+	Items            []CartItem         `bson:"items"`
+	SavedItems       []CartItem         `bson:"saved_items,omitempty"`
+	Lists            []CartList         `bson:"lists,omitempty"`
+	CouponCode       string             `bson:"coupon_code,omitempty"`
+	ExpiresAt        *time.Time         `bson:"expires_at,omitempty"`
+	UpdatedAt        time.Time          `bson:"updated_at,omitempty"`
+	Revision         int64              `bson:"revision"`
+	RemindedRevision int64              `bson:"reminded_revision,omitempty"`
 }
@@ -0,0 +1,65 @@
+package entity
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	PromotionPercentage   = "percentage"
+	PromotionFixedAmount  = "fixed_amount"
+	PromotionFreeShipping = "free_shipping"
+	PromotionBuyXGetY     = "buy_x_get_y"
+)
+
+// Promotion — купон (если задан Code) или автоматическая акция.
+// Пустые InstrumentIDs и Categories означают, что акция действует
+// на все товары корзины.
+type Promotion struct {
+	ID                primitive.ObjectID   `bson:"_id,omitempty"`
+	Name              string               `bson:"name"`
+	Code              string               `bson:"code,omitempty"`
+	Type              string               `bson:"type"`
+	Value             float64              `bson:"value"` // процент или сумма скидки
+	InstrumentIDs     []primitive.ObjectID `bson:"instrument_ids,omitempty"`
+	Categories        []string             `bson:"categories,omitempty"`
+	BuyQuantity       int32                `bson:"buy_quantity,omitempty"`
+	GetQuantity       int32                `bson:"get_quantity,omitempty"`
+	MinSubtotal       float64              `bson:"min_subtotal,omitempty"`
+	StartsAt          time.Time            `bson:"starts_at"`
+	EndsAt            time.Time            `bson:"ends_at"`
+	UsageLimit        int32                `bson:"usage_limit"`          // 0 — без ограничений
+	UsageLimitPerUser int32                `bson:"usage_limit_per_user"` // 0 — без ограничений
+	UsedCount         int32                `bson:"used_count"`
+	Active            bool                 `bson:"active"`
+	CreatedAt         time.Time            `bson:"created_at"`
+}
+
+func (p *Promotion) IsCoupon() bool {
+	return p.Code != ""
+}
+
+// InWindow проверяет срок действия; нулевые границы не ограничивают.
+func (p *Promotion) InWindow(now time.Time) bool {
+	if !p.StartsAt.IsZero() && now.Before(p.StartsAt) {
+		return false
+	}
+	if !p.EndsAt.IsZero() && !now.Before(p.EndsAt) {
+		return false
+	}
+	return true
+}
+
+// PromotionUsage — использование акции заказом. UserLimited отмечает, что
+// использование учтено в счётчике пользователя, CartCoupon — что купон
+// был снят с корзины; при откате заказа оба действия отменяются.
+type PromotionUsage struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty"`
+	PromotionID primitive.ObjectID `bson:"promotion_id"`
+	UserID      primitive.ObjectID `bson:"user_id"`
+	OrderID     primitive.ObjectID `bson:"order_id"`
+	UsedAt      time.Time          `bson:"used_at"`
+	UserLimited bool               `bson:"user_limited,omitempty"`
+	CartCoupon  bool               `bson:"cart_coupon,omitempty"`
+}
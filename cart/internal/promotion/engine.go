@@ -0,0 +1,212 @@
+package promotion
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"gotune/cart/internal/entity"
+)
+
+type Line struct {
+	InstrumentID string
+	SKU          string
+	Category     string
+	CategoryPath []string // категория и её предки, акция на раздел действует на подразделы
+	UnitPrice    float64
+	Quantity     int32
+}
+
+// Key идентифицирует позицию: SKU варианта уникален во всём каталоге,
+// для инструментов без вариантов используется идентификатор инструмента.
+func (l Line) Key() string {
+	if l.SKU != "" {
+		return l.SKU
+	}
+	return l.InstrumentID
+}
+
+type Discount struct {
+	PromotionID  string
+	Name         string
+	Code         string
+	Type         string
+	Amount       float64
+	FreeShipping bool
+}
+
+type Result struct {
+	Subtotal      float64
+	DiscountTotal float64
+	Total         float64
+	Discounts     []Discount
+	// LineDiscounts — скидка по каждой позиции (ключ Line.Key); нужна для
+	// расчёта налога с уже уменьшенной суммы.
+	LineDiscounts map[string]float64
+	FreeShipping  bool
+}
+
+// Evaluate применяет акции к позициям корзины. Сначала применяются
+// автоматические акции, затем купоны; скидка на позицию никогда не
+// превышает её стоимость. Неактивные и просроченные акции пропускаются.
+func Evaluate(promotions []entity.Promotion, lines []Line, now time.Time) *Result {
+	res := &Result{LineDiscounts: map[string]float64{}}
+
+	remaining := make([]float64, len(lines))
+	for i, l := range lines {
+		remaining[i] = l.UnitPrice * float64(l.Quantity)
+		res.Subtotal += remaining[i]
+	}
+	res.Subtotal = round(res.Subtotal)
+
+	ordered := make([]entity.Promotion, len(promotions))
+	copy(ordered, promotions)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return !ordered[i].IsCoupon() && ordered[j].IsCoupon()
+	})
+
+	for _, p := range ordered {
+		if !p.Active || !p.InWindow(now) || res.Subtotal < p.MinSubtotal {
+			continue
+		}
+
+		eligible := eligibleLines(&p, lines)
+		if len(eligible) == 0 {
+			continue
+		}
+
+		perLine := make([]float64, len(lines))
+		freeShipping := false
+		switch p.Type {
+		case entity.PromotionPercentage:
+			for _, i := range eligible {
+				perLine[i] = remaining[i] * p.Value / 100
+			}
+		case entity.PromotionFixedAmount:
+			distribute(perLine, remaining, eligible, p.Value)
+		case entity.PromotionBuyXGetY:
+			buyXGetY(perLine, remaining, lines, eligible, p.BuyQuantity, p.GetQuantity)
+		case entity.PromotionFreeShipping:
+			freeShipping = true
+		default:
+			continue
+		}
+
+		var amount float64
+		for i := range perLine {
+			d := round(math.Min(perLine[i], remaining[i]))
+			if d <= 0 {
+				continue
+			}
+			remaining[i] -= d
+			key := lines[i].Key()
+			res.LineDiscounts[key] = round(res.LineDiscounts[key] + d)
+			amount += d
+		}
+		amount = round(amount)
+
+		if amount == 0 && !freeShipping {
+			continue
+		}
+		res.FreeShipping = res.FreeShipping || freeShipping
+		res.DiscountTotal = round(res.DiscountTotal + amount)
+		res.Discounts = append(res.Discounts, Discount{
+			PromotionID:  p.ID.Hex(),
+			Name:         p.Name,
+			Code:         p.Code,
+			Type:         p.Type,
+			Amount:       amount,
+			FreeShipping: freeShipping,
+		})
+	}
+
+	res.Total = round(res.Subtotal - res.DiscountTotal)
+	return res
+}
+
+func eligibleLines(p *entity.Promotion, lines []Line) []int {
+	var result []int
+	for i, l := range lines {
+		if l.Quantity <= 0 || !matches(p, l) {
+			continue
+		}
+		result = append(result, i)
+	}
+	return result
+}
+
+func matches(p *entity.Promotion, l Line) bool {
+	if len(p.InstrumentIDs) == 0 && len(p.Categories) == 0 {
+		return true
+	}
+	for _, id := range p.InstrumentIDs {
+		if id.Hex() == l.InstrumentID {
+			return true
+		}
+	}
+	for _, c := range p.Categories {
+		if c == l.Category {
+			return true
+		}
+		for _, ancestor := range l.CategoryPath {
+			if c == ancestor {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// distribute делит фиксированную скидку между позициями пропорционально
+// их остаточной стоимости.
+func distribute(perLine, remaining []float64, eligible []int, value float64) {
+	var base float64
+	for _, i := range eligible {
+		base += remaining[i]
+	}
+	if base <= 0 {
+		return
+	}
+	value = math.Min(value, base)
+	for _, i := range eligible {
+		perLine[i] = value * remaining[i] / base
+	}
+}
+
+// buyXGetY: из каждых buy+get подходящих единиц товара get самых дешёвых
+// достаются бесплатно.
+func buyXGetY(perLine, remaining []float64, lines []Line, eligible []int, buy, get int32) {
+	if buy <= 0 || get <= 0 {
+		return
+	}
+
+	var units int32
+	for _, i := range eligible {
+		units += lines[i].Quantity
+	}
+	free := units / (buy + get) * get
+	if free == 0 {
+		return
+	}
+
+	cheapest := make([]int, len(eligible))
+	copy(cheapest, eligible)
+	sort.SliceStable(cheapest, func(a, b int) bool {
+		return lines[cheapest[a]].UnitPrice < lines[cheapest[b]].UnitPrice
+	})
+	for _, i := range cheapest {
+		n := lines[i].Quantity
+		if n > free {
+			n = free
+		}
+		perLine[i] = math.Min(lines[i].UnitPrice*float64(n), remaining[i])
+		free -= n
+		if free == 0 {
+			return
+		}
+	}
+}
+
+func round(v float64) float64 {
+	return math.Round(v*100) / 100
+}
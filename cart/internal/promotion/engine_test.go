@@ -0,0 +1,116 @@
+package promotion
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"gotune/cart/internal/entity"
+)
+
+var now = time.Date(2026, 11, 25, 12, 0, 0, 0, time.UTC)
+
+func TestBuyTwoStringsGetOneFree(t *testing.T) {
+	promo := entity.Promotion{
+		ID:          primitive.NewObjectID(),
+		Name:        "3 по цене 2",
+		Type:        entity.PromotionBuyXGetY,
+		Categories:  []string{"strings"},
+		BuyQuantity: 2,
+		GetQuantity: 1,
+		Active:      true,
+	}
+	lines := []Line{
+		{InstrumentID: "a", Category: "strings", UnitPrice: 12, Quantity: 2},
+		{InstrumentID: "b", Category: "strings", UnitPrice: 8, Quantity: 1},
+		{InstrumentID: "c", Category: "guitars", UnitPrice: 500, Quantity: 1},
+	}
+
+	res := Evaluate([]entity.Promotion{promo}, lines, now)
+	assert.Equal(t, 532.0, res.Subtotal)
+	assert.Equal(t, 8.0, res.DiscountTotal)
+	assert.Equal(t, 8.0, res.LineDiscounts["b"])
+	assert.Equal(t, 524.0, res.Total)
+}
+
+func TestCouponAppliesAfterAutomaticPromotion(t *testing.T) {
+	category := entity.Promotion{
+		Name:       "Гитары -10%",
+		Type:       entity.PromotionPercentage,
+		Value:      10,
+		Categories: []string{"guitars"},
+		Active:     true,
+	}
+	coupon := entity.Promotion{
+		Name:   "Минус 100",
+		Code:   "SALE100",
+		Type:   entity.PromotionFixedAmount,
+		Value:  100,
+		Active: true,
+	}
+	lines := []Line{
+		{InstrumentID: "g", Category: "guitars", UnitPrice: 600, Quantity: 1},
+		{InstrumentID: "s", Category: "strings", UnitPrice: 60, Quantity: 1},
+	}
+
+	res := Evaluate([]entity.Promotion{coupon, category}, lines, now)
+	assert.Len(t, res.Discounts, 2)
+	assert.Equal(t, "Гитары -10%", res.Discounts[0].Name)
+	assert.Equal(t, 60.0, res.Discounts[0].Amount)
+	// 100 делится пропорционально остаткам 540 и 60
+	assert.Equal(t, 150.0, res.LineDiscounts["g"])
+	assert.Equal(t, 10.0, res.LineDiscounts["s"])
+	assert.Equal(t, 500.0, res.Total)
+}
+
+func TestFixedAmountNeverExceedsSubtotal(t *testing.T) {
+	coupon := entity.Promotion{Code: "BIG", Type: entity.PromotionFixedAmount, Value: 1000, Active: true}
+	res := Evaluate([]entity.Promotion{coupon}, []Line{{InstrumentID: "a", UnitPrice: 30, Quantity: 1}}, now)
+	assert.Equal(t, 30.0, res.DiscountTotal)
+	assert.Equal(t, 0.0, res.Total)
+}
+
+func TestSkipsExpiredAndBelowMinimum(t *testing.T) {
+	expired := entity.Promotion{
+		Type:   entity.PromotionPercentage,
+		Value:  50,
+		EndsAt: now.Add(-time.Hour),
+		Active: true,
+	}
+	minimum := entity.Promotion{
+		Code:        "FREESHIP",
+		Type:        entity.PromotionFreeShipping,
+		MinSubtotal: 100,
+		Active:      true,
+	}
+	lines := []Line{{InstrumentID: "a", UnitPrice: 40, Quantity: 2}}
+
+	res := Evaluate([]entity.Promotion{expired, minimum}, lines, now)
+	assert.Empty(t, res.Discounts)
+	assert.False(t, res.FreeShipping)
+
+	lines[0].Quantity = 3
+	res = Evaluate([]entity.Promotion{expired, minimum}, lines, now)
+	assert.True(t, res.FreeShipping)
+	assert.Equal(t, 0.0, res.DiscountTotal)
+}
+
+func TestCategoryPromotionAppliesToSubcategories(t *testing.T) {
+	promo := entity.Promotion{
+		Name:       "Гитары -10%",
+		Type:       entity.PromotionPercentage,
+		Value:      10,
+		Categories: []string{"guitars"},
+		Active:     true,
+	}
+	lines := []Line{
+		{InstrumentID: "e", Category: "solid-body", CategoryPath: []string{"guitars", "electric", "solid-body"}, UnitPrice: 800, Quantity: 1},
+		{InstrumentID: "d", Category: "drums", CategoryPath: []string{"drums"}, UnitPrice: 300, Quantity: 1},
+	}
+
+	res := Evaluate([]entity.Promotion{promo}, lines, now)
+	assert.Equal(t, 80.0, res.LineDiscounts["e"])
+	assert.Zero(t, res.LineDiscounts["d"])
+}
@@ -2,6 +2,8 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -12,11 +14,33 @@ import (
 )
 
 type CartRepository interface {
-	AddToCart(ctx context.Context, userID, instrumentID primitive.ObjectID, quantity int32) error
-	GetCart(ctx context.Context, userID primitive.ObjectID) ([]entity.CartItem, error)
-	RemoveFromCart(ctx context.Context, userID, instrumentID primitive.ObjectID) error
+	AddToCart(ctx context.Context, userID primitive.ObjectID, key entity.ItemKey, quantity, limit int32) error
+	GetCart(ctx context.Context, userID primitive.ObjectID) (*entity.Cart, error)
+	RemoveFromCart(ctx context.Context, userID primitive.ObjectID, key entity.ItemKey, version int64) error
 	ClearCart(ctx context.Context, userID primitive.ObjectID) error
-	UpdateQuantity(ctx context.Context, userID, instrumentID primitive.ObjectID, quantity int32) error
+	UpdateQuantity(ctx context.Context, userID primitive.ObjectID, key entity.ItemKey, quantity int32, version int64) error
+	SetCoupon(ctx context.Context, userID primitive.ObjectID, code string) error
+	FindAbandoned(ctx context.Context, updatedBefore time.Time, limit int64) ([]entity.Cart, error)
+	MarkReminded(ctx context.Context, cartID primitive.ObjectID, revision int64) (bool, error)
+	SaveContents(ctx context.Context, cart *entity.Cart) error
+}
+
+var (
+	ErrCartModified = errors.New("корзина изменена параллельным запросом")
+	// ErrQuantityLimit — с добавленным количеством инструмента в корзине
+	// станет больше его лимита
+	ErrQuantityLimit = errors.New("превышен лимит количества инструмента в корзине")
+)
+
+// GuestCartRepository хранит корзины анонимных посетителей по токену.
+// Каждое изменение продлевает срок жизни корзины, по истечении которого
+// MongoDB удаляет её по TTL-индексу.
+type GuestCartRepository interface {
+	AddToCart(ctx context.Context, token string, key entity.ItemKey, quantity, limit int32, expiresAt time.Time) error
+	GetCart(ctx context.Context, token string) (*entity.Cart, error)
+	RemoveFromCart(ctx context.Context, token string, key entity.ItemKey, version int64, expiresAt time.Time) error
+	UpdateQuantity(ctx context.Context, token string, key entity.ItemKey, quantity int32, version int64, expiresAt time.Time) error
+	ClearCart(ctx context.Context, token string) error
 }
 
 type cartRepository struct {
@@ -29,74 +53,336 @@ func NewCartRepositories(db *mongo.Database) CartRepository {
 	}
 }
 
-func (r *cartRepository) AddToCart(ctx context.Context, userID, instrumentID primitive.ObjectID, quantity int32) error {
-	// сначала проверим, есть ли уже такой инструмент в корзине
-	filter := bson.M{"user_id": userID, "items.instrument_id": instrumentID}
-	update := bson.M{
-		"$inc": bson.M{"items.$.quantity": quantity},
+type guestCartRepository struct {
+	collection *mongo.Collection
+}
+
+func NewGuestCartRepository(db *mongo.Database) GuestCartRepository {
+	return &guestCartRepository{
+		collection: db.Collection("guest_carts"),
 	}
-	res, err := r.collection.UpdateOne(ctx, filter, update)
+}
+
+func (r *cartRepository) AddToCart(ctx context.Context, userID primitive.ObjectID, key entity.ItemKey, quantity, limit int32) error {
+	return addItem(ctx, r.collection, bson.M{"user_id": userID}, key, quantity, limit, bson.M{})
+}
+
+func (r *cartRepository) GetCart(ctx context.Context, userID primitive.ObjectID) (*entity.Cart, error) {
+	cart, err := findCart(ctx, r.collection, bson.M{"user_id": userID})
 	if err != nil {
-		return err
+		return nil, err
 	}
-	if res.MatchedCount == 0 {
-		filter = bson.M{"user_id": userID}
-		update = bson.M{
-			"$push": bson.M{
-				"items": bson.M{
-					"instrument_id": instrumentID,
-					"quantity":      quantity,
-				},
-			},
-		}
-		_, err = r.collection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	cart.UserID = userID
+	return cart, nil
+}
+
+func (r *cartRepository) RemoveFromCart(ctx context.Context, userID primitive.ObjectID, key entity.ItemKey, version int64) error {
+	return removeItem(ctx, r.collection, bson.M{"user_id": userID}, key, version, bson.M{})
+}
+
+// ClearCart очищает активную корзину и снимает купон; отложенные товары
+// и именованные списки остаются.
+func (r *cartRepository) ClearCart(ctx context.Context, userID primitive.ObjectID) error {
+	update := touch(bson.M{"$unset": bson.M{"coupon_code": ""}}, bson.M{"items": []entity.CartItem{}})
+	_, err := r.collection.UpdateOne(ctx, bson.M{"user_id": userID}, update)
+	return err
+}
+
+func (r *cartRepository) UpdateQuantity(ctx context.Context, userID primitive.ObjectID, key entity.ItemKey, quantity int32, version int64) error {
+	return setQuantity(ctx, r.collection, bson.M{"user_id": userID}, key, quantity, version, bson.M{})
+}
+
+// SetCoupon сохраняет купон корзины; пустой код снимает купон.
+func (r *cartRepository) SetCoupon(ctx context.Context, userID primitive.ObjectID, code string) error {
+	update := touch(bson.M{}, bson.M{"coupon_code": code})
+	if code == "" {
+		update = touch(bson.M{"$unset": bson.M{"coupon_code": ""}}, nil)
 	}
+	_, err := r.collection.UpdateOne(ctx, bson.M{"user_id": userID}, update, options.Update().SetUpsert(true))
 	return err
 }
 
-func (r *cartRepository) GetCart(ctx context.Context, userID primitive.ObjectID) ([]entity.CartItem, error) {
+// FindAbandoned ищет непустые корзины пользователей, которые не менялись
+// с updatedBefore и о текущей ревизии которых ещё не напоминали.
+func (r *cartRepository) FindAbandoned(ctx context.Context, updatedBefore time.Time, limit int64) ([]entity.Cart, error) {
+	filter := bson.M{
+		"user_id":    bson.M{"$exists": true},
+		"items.0":    bson.M{"$exists": true},
+		"updated_at": bson.M{"$lt": updatedBefore},
+		"$expr": bson.M{"$lt": bson.A{
+			bson.M{"$ifNull": bson.A{"$reminded_revision", 0}},
+			"$revision",
+		}},
+	}
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetLimit(limit).SetSort(bson.M{"updated_at": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var carts []entity.Cart
+	if err := cursor.All(ctx, &carts); err != nil {
+		return nil, err
+	}
+	return carts, nil
+}
+
+// MarkReminded атомарно отмечает ревизию корзины как напомненную. false
+// означает, что корзина успела измениться или напоминание уже отправлено.
+func (r *cartRepository) MarkReminded(ctx context.Context, cartID primitive.ObjectID, revision int64) (bool, error) {
+	filter := bson.M{
+		"_id":               cartID,
+		"revision":          revision,
+		"reminded_revision": bson.M{"$ne": revision},
+	}
+	res, err := r.collection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"reminded_revision": revision}})
+	if err != nil {
+		return false, err
+	}
+	return res.ModifiedCount == 1, nil
+}
+
+func (r *guestCartRepository) AddToCart(ctx context.Context, token string, key entity.ItemKey, quantity, limit int32, expiresAt time.Time) error {
+	return addItem(ctx, r.collection, bson.M{"token": token}, key, quantity, limit, bson.M{"expires_at": expiresAt})
+}
+
+func (r *guestCartRepository) GetCart(ctx context.Context, token string) (*entity.Cart, error) {
+	return findCart(ctx, r.collection, bson.M{"token": token})
+}
+
+func (r *guestCartRepository) RemoveFromCart(ctx context.Context, token string, key entity.ItemKey, version int64, expiresAt time.Time) error {
+	return removeItem(ctx, r.collection, bson.M{"token": token}, key, version, bson.M{"expires_at": expiresAt})
+}
+
+func (r *guestCartRepository) UpdateQuantity(ctx context.Context, token string, key entity.ItemKey, quantity int32, version int64, expiresAt time.Time) error {
+	return setQuantity(ctx, r.collection, bson.M{"token": token}, key, quantity, version, bson.M{"expires_at": expiresAt})
+}
+
+func (r *guestCartRepository) ClearCart(ctx context.Context, token string) error {
+	_, err := r.collection.DeleteOne(ctx, bson.M{"token": token})
+	return err
+}
+
+// addItem увеличивает количество позиции в корзине owner или добавляет
+// новую. Если limit больше 0, суммарное количество всех вариантов
+// инструмента не превысит его: проверка входит в фильтр обновления, и два
+// одновременных добавления не обойдут лимит. set — дополнительные поля,
+// обновляемые вместе с позицией.
+func addItem(ctx context.Context, collection *mongo.Collection, owner bson.M, key entity.ItemKey, quantity, limit int32, set bson.M) error {
+	if limit > 0 && quantity > limit {
+		return ErrQuantityLimit
+	}
+	item := entity.CartItem{
+		InstrumentID: key.InstrumentID,
+		SKU:          key.SKU,
+		Quantity:     quantity,
+	}
+
+	for attempt := 0; attempt < 3; attempt++ {
+		// позиция уже есть в корзине
+		filter := withItemLimit(owner, key.InstrumentID, limit-quantity, limit > 0)
+		filter["items"] = bson.M{"$elemMatch": itemMatch(key)}
+		res, err := collection.UpdateOne(ctx, filter, touch(bson.M{
+			"$inc": bson.M{"items.$.quantity": quantity},
+		}, set))
+		if err != nil {
+			return err
+		}
+		if res.MatchedCount > 0 {
+			return nil
+		}
+
+		// корзина есть, позиции нет
+		filter = withItemLimit(owner, key.InstrumentID, limit-quantity, limit > 0)
+		filter["items"] = bson.M{"$not": bson.M{"$elemMatch": itemMatch(key)}}
+		res, err = collection.UpdateOne(ctx, filter, touch(bson.M{
+			"$push": bson.M{"items": item},
+		}, set))
+		if err != nil {
+			return err
+		}
+		if res.MatchedCount > 0 {
+			return nil
+		}
+
+		// корзины нет — создаём её с позицией; существующая не меняется
+		fields := bson.M{"items": []entity.CartItem{item}, "updated_at": time.Now(), "revision": 1}
+		for k, v := range set {
+			fields[k] = v
+		}
+		res, err = collection.UpdateOne(ctx, owner, bson.M{"$setOnInsert": fields}, options.Update().SetUpsert(true))
+		if mongo.IsDuplicateKeyError(err) {
+			// корзину создал параллельный запрос
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if res.UpsertedCount > 0 {
+			return nil
+		}
+		// корзина есть, но ни одно обновление не прошло: либо позицию
+		// параллельно добавили или удалили, либо лимит исчерпан. Повторная
+		// неудача означает лимит.
+		if attempt > 0 && limit > 0 {
+			return ErrQuantityLimit
+		}
+	}
+	return ErrCartModified
+}
+
+// withItemLimit копирует фильтр владельца и, если check, добавляет
+// условие, что позиций инструмента в корзине не больше maxQuantity.
+func withItemLimit(owner bson.M, instrumentID primitive.ObjectID, maxQuantity int32, check bool) bson.M {
+	filter := bson.M{}
+	for k, v := range owner {
+		filter[k] = v
+	}
+	if !check {
+		return filter
+	}
+	filter["$expr"] = bson.M{"$lte": bson.A{
+		bson.M{"$sum": bson.M{"$map": bson.M{
+			"input": bson.M{"$filter": bson.M{
+				"input": bson.M{"$ifNull": bson.A{"$items", bson.A{}}},
+				"cond":  bson.M{"$eq": bson.A{"$$this.instrument_id", instrumentID}},
+			}},
+			"in": "$$this.quantity",
+		}}},
+		maxQuantity,
+	}}
+	return filter
+}
+
+func findCart(ctx context.Context, collection *mongo.Collection, owner bson.M) (*entity.Cart, error) {
 	var cart entity.Cart
-	err := r.collection.FindOne(ctx, bson.M{"user_id": userID}).Decode(&cart)
+	err := collection.FindOne(ctx, owner).Decode(&cart)
 	if err == mongo.ErrNoDocuments {
-		return []entity.CartItem{}, nil
+		return &entity.Cart{Items: []entity.CartItem{}}, nil
 	}
 	if err != nil {
 		return nil, err
 	}
-	return cart.Items, nil
+	return &cart, nil
 }
 
-func (r *cartRepository) RemoveFromCart(ctx context.Context, userID, instrumentID primitive.ObjectID) error {
-	filter := bson.M{"user_id": userID}
-	update := bson.M{
+// removeItem удаляет позицию из корзины owner. Если version больше 0,
+// запись проходит только при этой ревизии корзины, иначе возвращается
+// ErrCartModified.
+func removeItem(ctx context.Context, collection *mongo.Collection, owner bson.M, key entity.ItemKey, version int64, set bson.M) error {
+	update := touch(bson.M{
 		"$pull": bson.M{
-			"items": bson.M{
-				"instrument_id": instrumentID,
-			},
+			"items": itemMatch(key),
 		},
+	}, set)
+	res, err := collection.UpdateOne(ctx, withRevision(owner, version), update)
+	if err != nil {
+		return err
 	}
-	_, err := r.collection.UpdateOne(ctx, filter, update)
-	return err
+	if version > 0 && res.MatchedCount == 0 {
+		return ErrCartModified
+	}
+	return nil
 }
 
-func (r *cartRepository) ClearCart(ctx context.Context, userID primitive.ObjectID) error {
-	_, err := r.collection.DeleteOne(ctx, bson.M{"user_id": userID})
-	return err
+// setQuantity задаёт количество позиции; version — как в removeItem.
+func setQuantity(ctx context.Context, collection *mongo.Collection, owner bson.M, key entity.ItemKey, quantity int32, version int64, set bson.M) error {
+	filter := withRevision(owner, version)
+	filter["items"] = bson.M{"$elemMatch": itemMatch(key)}
+	fields := bson.M{"items.$.quantity": quantity}
+	for k, v := range set {
+		fields[k] = v
+	}
+	res, err := collection.UpdateOne(ctx, filter, touch(bson.M{}, fields))
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		if version > 0 {
+			n, err := collection.CountDocuments(ctx, withRevision(owner, version))
+			if err != nil {
+				return err
+			}
+			if n == 0 {
+				return ErrCartModified
+			}
+		}
+		return mongo.ErrNoDocuments
+	}
+	return nil
 }
 
-func (r *cartRepository) UpdateQuantity(ctx context.Context, userID, instrumentID primitive.ObjectID, quantity int32) error {
-	filter := bson.M{"user_id": userID, "items.instrument_id": instrumentID}
-	update := bson.M{
-		"$set": bson.M{
-			"items.$.quantity": quantity,
-		},
+// withRevision копирует фильтр owner и при version больше 0 добавляет
+// условие на ревизию корзины.
+func withRevision(owner bson.M, version int64) bson.M {
+	filter := bson.M{}
+	for k, v := range owner {
+		filter[k] = v
+	}
+	if version > 0 {
+		filter["revision"] = version
+	}
+	return filter
+}
+
+// itemMatch выбирает элемент items по позиции. Позиции без варианта
+// хранятся без поля sku.
+func itemMatch(key entity.ItemKey) bson.M {
+	if key.SKU == "" {
+		return bson.M{"instrument_id": key.InstrumentID, "sku": bson.M{"$in": bson.A{nil, ""}}}
 	}
+	return bson.M{"instrument_id": key.InstrumentID, "sku": key.SKU}
+}
+
+// SaveContents целиком сохраняет позиции, отложенные товары и списки
+// корзины. Запись проходит, только если ревизия корзины не изменилась
+// с момента чтения, иначе возвращается ErrCartModified.
+func (r *cartRepository) SaveContents(ctx context.Context, cart *entity.Cart) error {
+	if cart.ID.IsZero() {
+		cart.ID = primitive.NewObjectID()
+		cart.UpdatedAt = time.Now()
+		cart.Revision = 1
+		_, err := r.collection.InsertOne(ctx, cart)
+		if mongo.IsDuplicateKeyError(err) {
+			return ErrCartModified
+		}
+		return err
+	}
+
+	filter := bson.M{"_id": cart.ID, "revision": cart.Revision}
+	if cart.Revision == 0 {
+		// корзины, созданные до появления ревизий
+		filter["revision"] = bson.M{"$exists": false}
+	}
+	update := touch(bson.M{}, bson.M{
+		"items":       cart.Items,
+		"saved_items": cart.SavedItems,
+		"lists":       cart.Lists,
+	})
 	res, err := r.collection.UpdateOne(ctx, filter, update)
 	if err != nil {
 		return err
 	}
 	if res.MatchedCount == 0 {
-		return mongo.ErrNoDocuments
+		return ErrCartModified
 	}
 	return nil
 }
+
+// touch дополняет обновление полями set, отметкой updated_at и увеличивает
+// ревизию корзины.
+func touch(update bson.M, set bson.M) bson.M {
+	fields := bson.M{"updated_at": time.Now()}
+	for k, v := range set {
+		fields[k] = v
+	}
+	update["$set"] = fields
+
+	inc, _ := update["$inc"].(bson.M)
+	if inc == nil {
+		inc = bson.M{}
+	}
+	inc["revision"] = 1
+	update["$inc"] = inc
+	return update
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+
+	"gotune/cart/internal/entity"
+)
+
+func TestWithRevision(t *testing.T) {
+	userID := primitive.NewObjectID()
+	owner := bson.M{"user_id": userID}
+
+	assert.Equal(t, bson.M{"user_id": userID}, withRevision(owner, 0))
+	assert.Equal(t, bson.M{"user_id": userID, "revision": int64(3)}, withRevision(owner, 3))
+	// фильтр владельца не меняется
+	assert.Equal(t, bson.M{"user_id": userID}, owner)
+}
+
+func TestSetQuantityConflicts(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	key := entity.ItemKey{InstrumentID: primitive.NewObjectID()}
+	owner := bson.M{"user_id": primitive.NewObjectID()}
+	notMatched := mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 0}, bson.E{Key: "nModified", Value: 0})
+
+	mt.Run("ревизия изменилась", func(mt *mtest.T) {
+		ns := mt.Coll.Database().Name() + "." + mt.Coll.Name()
+		mt.AddMockResponses(notMatched, mtest.CreateCursorResponse(0, ns, mtest.FirstBatch))
+
+		err := setQuantity(context.Background(), mt.Coll, owner, key, 2, 3, bson.M{})
+		assert.ErrorIs(t, err, ErrCartModified)
+	})
+
+	mt.Run("позиции нет при актуальной ревизии", func(mt *mtest.T) {
+		ns := mt.Coll.Database().Name() + "." + mt.Coll.Name()
+		mt.AddMockResponses(notMatched, mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, bson.D{{Key: "n", Value: 1}}))
+
+		err := setQuantity(context.Background(), mt.Coll, owner, key, 2, 3, bson.M{})
+		assert.ErrorIs(t, err, mongo.ErrNoDocuments)
+	})
+
+	mt.Run("без версии ревизия не проверяется", func(mt *mtest.T) {
+		mt.AddMockResponses(notMatched)
+
+		err := setQuantity(context.Background(), mt.Coll, owner, key, 2, 0, bson.M{})
+		assert.ErrorIs(t, err, mongo.ErrNoDocuments)
+	})
+
+	mt.Run("количество изменено", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}))
+
+		assert.NoError(t, setQuantity(context.Background(), mt.Coll, owner, key, 2, 3, bson.M{}))
+	})
+}
+
+func TestAddItemLimit(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	key := entity.ItemKey{InstrumentID: primitive.NewObjectID()}
+	owner := bson.M{"user_id": primitive.NewObjectID()}
+	notMatched := mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 0}, bson.E{Key: "nModified", Value: 0})
+
+	mt.Run("больше лимита за один раз", func(mt *mtest.T) {
+		err := addItem(context.Background(), mt.Coll, owner, key, 4, 3, bson.M{})
+		assert.ErrorIs(t, err, ErrQuantityLimit)
+	})
+
+	mt.Run("лимит в фильтре обновления", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}))
+
+		assert.NoError(t, addItem(context.Background(), mt.Coll, owner, key, 2, 3, bson.M{}))
+		filter := mt.GetStartedEvent().Command.Lookup("updates").Array().Index(0).Value().Document().Lookup("q").Document()
+		limit := filter.Lookup("$expr", "$lte").Array().Index(1).Value().Int32()
+		assert.Equal(t, int32(1), limit)
+	})
+
+	mt.Run("без лимита условия нет", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}))
+
+		assert.NoError(t, addItem(context.Background(), mt.Coll, owner, key, 2, 0, bson.M{}))
+		filter := mt.GetStartedEvent().Command.Lookup("updates").Array().Index(0).Value().Document().Lookup("q").Document()
+		_, err := filter.LookupErr("$expr")
+		assert.Error(t, err)
+	})
+
+	mt.Run("лимит исчерпан", func(mt *mtest.T) {
+		// две попытки: $inc, $push и upsert без вставки
+		for i := 0; i < 2; i++ {
+			mt.AddMockResponses(notMatched, notMatched, mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 0}))
+		}
+
+		err := addItem(context.Background(), mt.Coll, owner, key, 2, 3, bson.M{})
+		assert.ErrorIs(t, err, ErrQuantityLimit)
+	})
+
+	mt.Run("новая корзина", func(mt *mtest.T) {
+		mt.AddMockResponses(notMatched, notMatched, mtest.CreateSuccessResponse(
+			bson.E{Key: "n", Value: 1},
+			bson.E{Key: "nModified", Value: 0},
+			bson.E{Key: "upserted", Value: bson.A{bson.D{{Key: "index", Value: 0}, {Key: "_id", Value: primitive.NewObjectID()}}}},
+		))
+
+		assert.NoError(t, addItem(context.Background(), mt.Coll, owner, key, 2, 3, bson.M{}))
+	})
+}
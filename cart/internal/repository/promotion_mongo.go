@@ -0,0 +1,213 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"gotune/cart/internal/entity"
+)
+
+var ErrUsageLimitReached = errors.New("лимит использований акции исчерпан")
+
+type PromotionRepository interface {
+	Create(ctx context.Context, promotion *entity.Promotion) (primitive.ObjectID, error)
+	GetAll(ctx context.Context) ([]entity.Promotion, error)
+	FindByID(ctx context.Context, id primitive.ObjectID) (*entity.Promotion, error)
+	FindByCode(ctx context.Context, code string) (*entity.Promotion, error)
+	FindActiveAutomatic(ctx context.Context, now time.Time) ([]entity.Promotion, error)
+	CountUserUsages(ctx context.Context, promotionID, userID primitive.ObjectID) (int64, error)
+	ReserveUsage(ctx context.Context, promotionID primitive.ObjectID) error
+	ReleaseUsage(ctx context.Context, promotionID primitive.ObjectID) error
+	ReserveUserUsage(ctx context.Context, promotionID, userID primitive.ObjectID, limit int32) error
+	ReleaseUserUsage(ctx context.Context, promotionID, userID primitive.ObjectID) error
+	RecordUsage(ctx context.Context, usage *entity.PromotionUsage) error
+	FindUsagesByOrder(ctx context.Context, orderID primitive.ObjectID) ([]entity.PromotionUsage, error)
+	DeleteUsage(ctx context.Context, id primitive.ObjectID) (bool, error)
+}
+
+type promotionRepository struct {
+	collection *mongo.Collection
+	usages     *mongo.Collection
+	userUsages *mongo.Collection
+}
+
+func NewPromotionRepository(db *mongo.Database) PromotionRepository {
+	return &promotionRepository{
+		collection: db.Collection("promotions"),
+		usages:     db.Collection("promotion_usages"),
+		userUsages: db.Collection("promotion_user_usages"),
+	}
+}
+
+func (r *promotionRepository) Create(ctx context.Context, promotion *entity.Promotion) (primitive.ObjectID, error) {
+	promotion.Code = normalizeCode(promotion.Code)
+	res, err := r.collection.InsertOne(ctx, promotion)
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+	return res.InsertedID.(primitive.ObjectID), nil
+}
+
+func (r *promotionRepository) GetAll(ctx context.Context) ([]entity.Promotion, error) {
+	return r.find(ctx, bson.M{})
+}
+
+func (r *promotionRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*entity.Promotion, error) {
+	var promotion entity.Promotion
+	if err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&promotion); err != nil {
+		return nil, err
+	}
+	return &promotion, nil
+}
+
+func (r *promotionRepository) FindByCode(ctx context.Context, code string) (*entity.Promotion, error) {
+	var promotion entity.Promotion
+	err := r.collection.FindOne(ctx, bson.M{"code": normalizeCode(code)}).Decode(&promotion)
+	if err != nil {
+		return nil, err
+	}
+	return &promotion, nil
+}
+
+func (r *promotionRepository) FindActiveAutomatic(ctx context.Context, now time.Time) ([]entity.Promotion, error) {
+	promotions, err := r.find(ctx, bson.M{
+		"active": true,
+		"code":   bson.M{"$exists": false},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var result []entity.Promotion
+	for _, p := range promotions {
+		if p.InWindow(now) {
+			result = append(result, p)
+		}
+	}
+	return result, nil
+}
+
+func (r *promotionRepository) CountUserUsages(ctx context.Context, promotionID, userID primitive.ObjectID) (int64, error) {
+	return r.usages.CountDocuments(ctx, bson.M{
+		"promotion_id": promotionID,
+		"user_id":      userID,
+	})
+}
+
+// ReserveUsage атомарно увеличивает счётчик использований, только если
+// глобальный лимит ещё не достигнут.
+func (r *promotionRepository) ReserveUsage(ctx context.Context, promotionID primitive.ObjectID) error {
+	filter := bson.M{
+		"_id": promotionID,
+		"$or": bson.A{
+			bson.M{"usage_limit": 0},
+			bson.M{"$expr": bson.M{"$lt": bson.A{"$used_count", "$usage_limit"}}},
+		},
+	}
+	res, err := r.collection.UpdateOne(ctx, filter, bson.M{"$inc": bson.M{"used_count": 1}})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrUsageLimitReached
+	}
+	return nil
+}
+
+func (r *promotionRepository) ReleaseUsage(ctx context.Context, promotionID primitive.ObjectID) error {
+	filter := bson.M{"_id": promotionID, "used_count": bson.M{"$gt": 0}}
+	_, err := r.collection.UpdateOne(ctx, filter, bson.M{"$inc": bson.M{"used_count": -1}})
+	return err
+}
+
+// ReserveUserUsage атомарно увеличивает счётчик использований акции
+// пользователем, только если лимит на пользователя ещё не достигнут.
+// Счётчик хранится отдельным документом: при исчерпанном лимите фильтр
+// не совпадает, а вставка нового документа с тем же _id падает на
+// дубликате ключа.
+func (r *promotionRepository) ReserveUserUsage(ctx context.Context, promotionID, userID primitive.ObjectID, limit int32) error {
+	filter := bson.M{
+		"_id":   userUsageID(promotionID, userID),
+		"count": bson.M{"$lt": limit},
+	}
+	_, err := r.userUsages.UpdateOne(ctx, filter, bson.M{"$inc": bson.M{"count": 1}}, options.Update().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrUsageLimitReached
+	}
+	return err
+}
+
+func (r *promotionRepository) ReleaseUserUsage(ctx context.Context, promotionID, userID primitive.ObjectID) error {
+	filter := bson.M{"_id": userUsageID(promotionID, userID), "count": bson.M{"$gt": 0}}
+	_, err := r.userUsages.UpdateOne(ctx, filter, bson.M{"$inc": bson.M{"count": -1}})
+	return err
+}
+
+func (r *promotionRepository) RecordUsage(ctx context.Context, usage *entity.PromotionUsage) error {
+	res, err := r.usages.InsertOne(ctx, usage)
+	if err != nil {
+		return err
+	}
+	usage.ID = res.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *promotionRepository) FindUsagesByOrder(ctx context.Context, orderID primitive.ObjectID) ([]entity.PromotionUsage, error) {
+	cursor, err := r.usages.Find(ctx, bson.M{"order_id": orderID})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var usages []entity.PromotionUsage
+	if err := cursor.All(ctx, &usages); err != nil {
+		return nil, err
+	}
+	return usages, nil
+}
+
+// DeleteUsage удаляет запись об использовании и сообщает, была ли она,
+// чтобы повторный откат не вернул счётчики дважды.
+func (r *promotionRepository) DeleteUsage(ctx context.Context, id primitive.ObjectID) (bool, error) {
+	res, err := r.usages.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return false, err
+	}
+	return res.DeletedCount > 0, nil
+}
+
+func userUsageID(promotionID, userID primitive.ObjectID) bson.D {
+	return bson.D{{Key: "promotion_id", Value: promotionID}, {Key: "user_id", Value: userID}}
+}
+
+func (r *promotionRepository) find(ctx context.Context, filter bson.M) ([]entity.Promotion, error) {
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var promotions []entity.Promotion
+	for cursor.Next(ctx) {
+		var p entity.Promotion
+		if err := cursor.Decode(&p); err != nil {
+			return nil, err
+		}
+		promotions = append(promotions, p)
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+	return promotions, nil
+}
+
+func normalizeCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"time"
+
+	"gotune/cart/internal/repository"
+	"gotune/events"
+)
+
+const abandonedCartBatchSize = 100
+
+// AbandonedCartJob периодически ищет корзины, которые не менялись дольше
+// abandonAfter, и публикует событие cart_abandoned. Перед публикацией
+// ревизия корзины помечается как напомненная, поэтому на одну ревизию
+// приходится не больше одного события.
+type AbandonedCartJob struct {
+	repo           repository.CartRepository
+	eventPublisher *events.EventPublisher
+	abandonAfter   time.Duration
+	interval       time.Duration
+}
+
+func NewAbandonedCartJob(repo repository.CartRepository, publisher *events.EventPublisher, abandonAfter, interval time.Duration) *AbandonedCartJob {
+	return &AbandonedCartJob{
+		repo:           repo,
+		eventPublisher: publisher,
+		abandonAfter:   abandonAfter,
+		interval:       interval,
+	}
+}
+
+func (j *AbandonedCartJob) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		if n, err := j.RunOnce(ctx); err != nil {
+			log.Printf("Ошибка поиска брошенных корзин: %v", err)
+		} else if n > 0 {
+			log.Printf("Найдено брошенных корзин: %d", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (j *AbandonedCartJob) RunOnce(ctx context.Context) (int, error) {
+	before := time.Now().Add(-j.abandonAfter)
+	found := 0
+
+	for {
+		carts, err := j.repo.FindAbandoned(ctx, before, abandonedCartBatchSize)
+		if err != nil {
+			return found, err
+		}
+
+		for _, cart := range carts {
+			claimed, err := j.repo.MarkReminded(ctx, cart.ID, cart.Revision)
+			if err != nil {
+				return found, err
+			}
+			if !claimed {
+				continue
+			}
+
+			_ = j.eventPublisher.Publish("cart_abandoned", map[string]string{
+				"cart_id":    cart.ID.Hex(),
+				"user_id":    cart.UserID.Hex(),
+				"revision":   strconv.FormatInt(cart.Revision, 10),
+				"updated_at": cart.UpdatedAt.Format(time.RFC3339),
+			})
+			found++
+		}
+
+		if len(carts) < abandonedCartBatchSize {
+			return found, nil
+		}
+	}
+}
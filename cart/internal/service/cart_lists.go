@@ -0,0 +1,229 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"gotune/cart/internal/entity"
+	"gotune/cart/internal/repository"
+	"gotune/cart/proto"
+	instrumentsproto "gotune/instruments/proto"
+)
+
+const (
+	LocationCart  = "cart"
+	LocationSaved = "saved"
+
+	maxCartLists          = 20
+	maxCartListNameLength = 50
+	cartSaveAttempts      = 3
+)
+
+// CreateCartList создаёт именованный список в корзине пользователя.
+// Названия списков уникальны без учёта регистра.
+func (s *CartService) CreateCartList(ctx context.Context, req *proto.CreateCartListRequest) (*proto.CreateCartListResponse, error) {
+	userID, err := primitive.ObjectIDFromHex(req.UserId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid user ID: %v", err)
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len([]rune(name)) > maxCartListNameLength {
+		return nil, status.Errorf(codes.InvalidArgument, "название списка должно быть от 1 до %d символов", maxCartListNameLength)
+	}
+
+	list := entity.CartList{
+		ID:        primitive.NewObjectID(),
+		Name:      name,
+		Items:     []entity.CartItem{},
+		CreatedAt: time.Now(),
+	}
+	err = s.modifyCart(ctx, userID, func(cart *entity.Cart) error {
+		if len(cart.Lists) >= maxCartLists {
+			return status.Errorf(codes.FailedPrecondition, "можно создать не более %d списков", maxCartLists)
+		}
+		for _, l := range cart.Lists {
+			if strings.EqualFold(l.Name, name) {
+				return status.Errorf(codes.AlreadyExists, "список %q уже существует", name)
+			}
+		}
+		cart.Lists = append(cart.Lists, list)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.invalidateCartCache(ctx, req.UserId)
+
+	return &proto.CreateCartListResponse{ListId: list.ID.Hex()}, nil
+}
+
+// DeleteCartList удаляет список вместе с его товарами.
+func (s *CartService) DeleteCartList(ctx context.Context, req *proto.DeleteCartListRequest) (*proto.DeleteCartListResponse, error) {
+	userID, err := primitive.ObjectIDFromHex(req.UserId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid user ID: %v", err)
+	}
+
+	err = s.modifyCart(ctx, userID, func(cart *entity.Cart) error {
+		for i, l := range cart.Lists {
+			if l.ID.Hex() == req.ListId {
+				cart.Lists = append(cart.Lists[:i], cart.Lists[i+1:]...)
+				return nil
+			}
+		}
+		return status.Errorf(codes.NotFound, "список %s не найден", req.ListId)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.invalidateCartCache(ctx, req.UserId)
+
+	return &proto.DeleteCartListResponse{Success: true}, nil
+}
+
+// MoveCartItem переносит позицию целиком между корзиной, отложенными
+// товарами и списками. Если позиция уже есть в месте назначения,
+// количества складываются; при переносе в корзину проверяется лимит
+// инструмента по всем его вариантам.
+func (s *CartService) MoveCartItem(ctx context.Context, req *proto.MoveCartItemRequest) (*proto.MoveCartItemResponse, error) {
+	userID, err := primitive.ObjectIDFromHex(req.UserId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid user ID: %v", err)
+	}
+	key, err := itemKey(req.InstrumentId, req.Sku)
+	if err != nil {
+		return nil, err
+	}
+	if req.From == req.To {
+		return nil, status.Errorf(codes.InvalidArgument, "источник и назначение совпадают")
+	}
+
+	var inst *instrumentsproto.Instrument
+	if req.To == LocationCart {
+		inst, err = s.instrumentClient.GetInstrumentByID(ctx, &instrumentsproto.GetInstrumentByIDRequest{Id: req.InstrumentId})
+		if err != nil {
+			return nil, status.Errorf(codes.NotFound, "инструмент %s не найден", req.InstrumentId)
+		}
+	}
+
+	var oldQuantity, newQuantity int32
+	err = s.modifyCart(ctx, userID, func(cart *entity.Cart) error {
+		oldQuantity = quantityAt(cart, LocationCart, key)
+
+		if _, err := moveItem(cart, key, req.From, req.To); err != nil {
+			return err
+		}
+		if inst != nil {
+			_, total := lineQuantities(cart.Items, key)
+			if err := checkMaxQuantity(inst, total); err != nil {
+				return err
+			}
+		}
+
+		newQuantity = quantityAt(cart, LocationCart, key)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.invalidateCartCache(ctx, req.UserId)
+
+	if req.From == LocationCart || req.To == LocationCart {
+		s.publishCartUpdated(cartRef{userID: userID}, key, oldQuantity, newQuantity)
+	}
+
+	return &proto.MoveCartItemResponse{Success: true}, nil
+}
+
+// modifyCart читает корзину пользователя, применяет change и сохраняет
+// результат. Если корзину параллельно изменили, попытка повторяется.
+func (s *CartService) modifyCart(ctx context.Context, userID primitive.ObjectID, change func(cart *entity.Cart) error) error {
+	for attempt := 0; attempt < cartSaveAttempts; attempt++ {
+		cart, err := s.repo.GetCart(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if err := change(cart); err != nil {
+			return err
+		}
+
+		err = s.repo.SaveContents(ctx, cart)
+		if !errors.Is(err, repository.ErrCartModified) {
+			return err
+		}
+	}
+	return status.Errorf(codes.Aborted, "корзина изменена параллельным запросом, повторите попытку")
+}
+
+// moveItem переносит позицию key из from в to и возвращает итоговое
+// количество в месте назначения.
+func moveItem(cart *entity.Cart, key entity.ItemKey, from, to string) (int32, error) {
+	source, err := itemsAt(cart, from)
+	if err != nil {
+		return 0, err
+	}
+	target, err := itemsAt(cart, to)
+	if err != nil {
+		return 0, err
+	}
+
+	var item *entity.CartItem
+	for i := range *source {
+		if (*source)[i].Key() == key {
+			found := (*source)[i]
+			item = &found
+			*source = append((*source)[:i], (*source)[i+1:]...)
+			break
+		}
+	}
+	if item == nil {
+		return 0, status.Errorf(codes.NotFound, "инструмента %s нет в %s", key.InstrumentID.Hex(), from)
+	}
+
+	for i := range *target {
+		if (*target)[i].Key() == key {
+			(*target)[i].Quantity += item.Quantity
+			return (*target)[i].Quantity, nil
+		}
+	}
+	*target = append(*target, *item)
+	return item.Quantity, nil
+}
+
+func itemsAt(cart *entity.Cart, location string) (*[]entity.CartItem, error) {
+	switch location {
+	case LocationCart:
+		return &cart.Items, nil
+	case LocationSaved:
+		return &cart.SavedItems, nil
+	}
+	for i := range cart.Lists {
+		if cart.Lists[i].ID.Hex() == location {
+			return &cart.Lists[i].Items, nil
+		}
+	}
+	return nil, status.Errorf(codes.NotFound, "список %s не найден", location)
+}
+
+func quantityAt(cart *entity.Cart, location string, key entity.ItemKey) int32 {
+	items, err := itemsAt(cart, location)
+	if err != nil {
+		return 0
+	}
+	for _, item := range *items {
+		if item.Key() == key {
+			return item.Quantity
+		}
+	}
+	return 0
+}
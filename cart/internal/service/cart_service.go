@@ -3,17 +3,23 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
+	"strconv"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/redis/go-redis/v9"
-	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
+	"gotune/cart/internal/entity"
 	"gotune/cart/internal/repository"
 	"gotune/cart/metrics"
 	"gotune/cart/proto"
 	"gotune/events"
+	instrumentsproto "gotune/instruments/proto"
 )
 
 const (
@@ -22,17 +28,33 @@ const (
 )
 
 type CartService struct {
-	repo           repository.CartRepository
-	cache          *redis.Client
-	eventPublisher *events.EventPublisher
+	repo             repository.CartRepository
+	guestRepo        repository.GuestCartRepository
+	promotionRepo    repository.PromotionRepository
+	instrumentClient instrumentsproto.InstrumentServiceClient
+	cache            *redis.Client
+	eventPublisher   *events.EventPublisher
+	mergeStrategy    string
 	proto.UnimplementedCartServiceServer
 }
 
-func NewCartService(repo repository.CartRepository, publisher *events.EventPublisher, cache *redis.Client) *CartService {
+func NewCartService(
+	repo repository.CartRepository,
+	guestRepo repository.GuestCartRepository,
+	promotionRepo repository.PromotionRepository,
+	instrumentClient instrumentsproto.InstrumentServiceClient,
+	publisher *events.EventPublisher,
+	cache *redis.Client,
+	mergeStrategy string,
+) *CartService {
 	return &CartService{
-		repo:           repo,
-		cache:          cache,
-		eventPublisher: publisher,
+		repo:             repo,
+		guestRepo:        guestRepo,
+		promotionRepo:    promotionRepo,
+		instrumentClient: instrumentClient,
+		cache:            cache,
+		eventPublisher:   publisher,
+		mergeStrategy:    mergeStrategy,
 	}
 }
 
@@ -41,33 +63,66 @@ func (s *CartService) AddToCart(ctx context.Context, req *proto.AddToCartRequest
 	timer := prometheus.NewTimer(metrics.CartCreateDuration)
 	defer timer.ObserveDuration()
 
-	userID, err := primitive.ObjectIDFromHex(req.UserId)
+	ref, err := newCartRef(req.UserId, req.CartToken)
 	if err != nil {
-		return nil, fmt.Errorf("invalid user ID: %w", err)
+		return nil, err
+	}
+	key, err := itemKey(req.InstrumentId, req.Sku)
+	if err != nil {
+		return nil, err
+	}
+	if req.Quantity <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "количество должно быть положительным")
+	}
+	inst, err := s.instrumentClient.GetInstrumentByID(ctx, &instrumentsproto.GetInstrumentByIDRequest{Id: req.InstrumentId})
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "инструмент %s не найден", req.InstrumentId)
 	}
-	instrumentID, err := primitive.ObjectIDFromHex(req.InstrumentId)
+	if err := checkVariant(inst, key.SKU); err != nil {
+		return nil, err
+	}
+
+	oldQuantity, _, err := s.itemQuantity(ctx, ref, key, 0)
 	if err != nil {
-		return nil, fmt.Errorf("invalid instrument ID: %w", err)
+		return nil, err
 	}
 
-	if err := s.repo.AddToCart(ctx, userID, instrumentID, req.Quantity); err != nil {
+	err = s.addItem(ctx, ref, key, req.Quantity, inst.MaxCartQuantity)
+	if errors.Is(err, repository.ErrQuantityLimit) {
+		return nil, status.Errorf(codes.FailedPrecondition, "%s: не более %d шт. в корзине", inst.Name, inst.MaxCartQuantity)
+	}
+	if err != nil {
 		return nil, err
 	}
 
-	s.invalidateCartCache(ctx, req.UserId)
+	s.invalidate(ctx, ref)
 
-	_ = s.eventPublisher.Publish("cart_updated", map[string]string{
-		"user_id":       req.UserId,
-		"instrument_id": req.InstrumentId,
-	})
+	s.publishCartUpdated(ref, key, oldQuantity, oldQuantity+req.Quantity)
 
 	metrics.CartCreatedTotal.Inc()
 
 	return &proto.AddToCartResponse{Success: true}, nil
 }
 
+// GetCart отдаёт корзину с актуальными ценами из каталога. В кэше хранится
+// только состав корзины, цены подставляются при каждом запросе.
 func (s *CartService) GetCart(ctx context.Context, req *proto.GetCartRequest) (*proto.GetCartResponse, error) {
-	cacheKey := cartCacheKeyPrefix + req.UserId
+	ref, err := newCartRef(req.UserId, req.CartToken)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.loadCart(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	s.priceCart(ctx, resp)
+	return resp, nil
+}
+
+func (s *CartService) loadCart(ctx context.Context, ref cartRef) (*proto.GetCartResponse, error) {
+	cacheKey := ref.cacheKey()
 	cached, err := s.cache.Get(ctx, cacheKey).Result()
 	if err == nil {
 		var resp proto.GetCartResponse
@@ -76,70 +131,198 @@ func (s *CartService) GetCart(ctx context.Context, req *proto.GetCartRequest) (*
 		}
 	}
 
-	userID, err := primitive.ObjectIDFromHex(req.UserId)
-	if err != nil {
-		return nil, fmt.Errorf("invalid user ID: %w", err)
-	}
-
-	items, err := s.repo.GetCart(ctx, userID)
+	cart, err := s.fetchCart(ctx, ref)
 	if err != nil {
 		return nil, err
 	}
 
-	var protoItems []*proto.CartItem
-	for _, item := range items {
-		protoItems = append(protoItems, &proto.CartItem{
-			InstrumentId: item.InstrumentID.Hex(),
-			Quantity:     item.Quantity,
+	resp := &proto.GetCartResponse{
+		Items:      cartItemsToProto(cart.Items),
+		CouponCode: cart.CouponCode,
+		SavedItems: cartItemsToProto(cart.SavedItems),
+		Version:    cart.Revision,
+	}
+	for _, list := range cart.Lists {
+		resp.Lists = append(resp.Lists, &proto.CartList{
+			Id:    list.ID.Hex(),
+			Name:  list.Name,
+			Items: cartItemsToProto(list.Items),
 		})
 	}
-
-	resp := &proto.GetCartResponse{Items: protoItems}
 	data, _ := json.Marshal(resp)
 	_ = s.cache.Set(ctx, cacheKey, data, cartCacheTTL)
 
 	return resp, nil
 }
 
+// priceCart подставляет название и цену каждого инструмента. Инструменты
+// и варианты, которых больше нет в каталоге, остаются в корзине, но
+// помечаются недоступными и не входят в сумму. Subtotal считается только по
+// активной корзине, без отложенных товаров и списков.
+func (s *CartService) priceCart(ctx context.Context, resp *proto.GetCartResponse) {
+	instruments := map[string]*instrumentsproto.Instrument{}
+	for _, item := range allCartItems(resp) {
+		if _, ok := instruments[item.InstrumentId]; ok {
+			continue
+		}
+		inst, err := s.instrumentClient.GetInstrumentByID(ctx, &instrumentsproto.GetInstrumentByIDRequest{Id: item.InstrumentId})
+		if err == nil {
+			instruments[item.InstrumentId] = inst
+		}
+	}
+	applyPrices(resp, instruments)
+}
+
+func applyPrices(resp *proto.GetCartResponse, instruments map[string]*instrumentsproto.Instrument) {
+	resp.Subtotal = priceItems(resp.Items, instruments)
+	priceItems(resp.SavedItems, instruments)
+	for _, list := range resp.Lists {
+		priceItems(list.Items, instruments)
+	}
+}
+
+func priceItems(items []*proto.CartItem, instruments map[string]*instrumentsproto.Instrument) float64 {
+	var subtotal float64
+	for _, item := range items {
+		inst, ok := instruments[item.InstrumentId]
+		if !ok {
+			item.Available = false
+			continue
+		}
+		price := inst.Price
+		if item.Sku != "" {
+			variant := findVariant(inst, item.Sku)
+			if variant == nil {
+				item.Available = false
+				continue
+			}
+			if variant.Price > 0 {
+				price = variant.Price
+			}
+			item.Variant = variantTitle(variant.Options)
+		}
+		item.Available = true
+		item.Name = inst.Name
+		item.UnitPrice = price
+		item.LineTotal = math.Round(price*float64(item.Quantity)*100) / 100
+		subtotal += item.LineTotal
+	}
+	return math.Round(subtotal*100) / 100
+}
+
+func allCartItems(resp *proto.GetCartResponse) []*proto.CartItem {
+	items := append([]*proto.CartItem{}, resp.Items...)
+	items = append(items, resp.SavedItems...)
+	for _, list := range resp.Lists {
+		items = append(items, list.Items...)
+	}
+	return items
+}
+
+func cartItemsToProto(items []entity.CartItem) []*proto.CartItem {
+	var result []*proto.CartItem
+	for _, item := range items {
+		result = append(result, &proto.CartItem{
+			InstrumentId: item.InstrumentID.Hex(),
+			Sku:          item.SKU,
+			Quantity:     item.Quantity,
+		})
+	}
+	return result
+}
+
 func (s *CartService) RemoveFromCart(ctx context.Context, req *proto.RemoveFromCartRequest) (*proto.RemoveFromCartResponse, error) {
-	userID, err := primitive.ObjectIDFromHex(req.UserId)
+	ref, err := newCartRef(req.UserId, req.CartToken)
 	if err != nil {
-		return nil, fmt.Errorf("invalid user ID: %w", err)
+		return nil, err
 	}
-	instrumentID, err := primitive.ObjectIDFromHex(req.InstrumentId)
+	key, err := itemKey(req.InstrumentId, req.Sku)
 	if err != nil {
-		return nil, fmt.Errorf("invalid instrument ID: %w", err)
+		return nil, err
 	}
 
-	if err := s.repo.RemoveFromCart(ctx, userID, instrumentID); err != nil {
+	oldQuantity, _, err := s.itemQuantity(ctx, ref, key, req.ExpectedVersion)
+	if err != nil {
 		return nil, err
 	}
 
-	s.invalidateCartCache(ctx, req.UserId)
+	err = s.removeItem(ctx, ref, key, req.ExpectedVersion)
+	if errors.Is(err, repository.ErrCartModified) {
+		return nil, cartVersionConflict()
+	}
+	if err != nil {
+		return nil, err
+	}
 
-	_ = s.eventPublisher.Publish("cart_updated", map[string]string{
-		"user_id":       req.UserId,
-		"instrument_id": req.InstrumentId,
-	})
+	s.invalidate(ctx, ref)
+
+	s.publishCartUpdated(ref, key, oldQuantity, 0)
 
 	return &proto.RemoveFromCartResponse{Success: true}, nil
 }
 
+// UpdateCartItemQuantity задаёт точное количество позиции; 0 удаляет её.
+// С expected_version запрос проходит, только если корзина не менялась.
+func (s *CartService) UpdateCartItemQuantity(ctx context.Context, req *proto.UpdateCartItemQuantityRequest) (*proto.UpdateCartItemQuantityResponse, error) {
+	ref, err := newCartRef(req.UserId, req.CartToken)
+	if err != nil {
+		return nil, err
+	}
+	key, err := itemKey(req.InstrumentId, req.Sku)
+	if err != nil {
+		return nil, err
+	}
+	if req.Quantity < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "количество не может быть отрицательным")
+	}
+
+	oldQuantity, total, err := s.itemQuantity(ctx, ref, key, req.ExpectedVersion)
+	if err != nil {
+		return nil, err
+	}
+	if oldQuantity == 0 {
+		return nil, status.Errorf(codes.NotFound, "инструмента %s нет в корзине", req.InstrumentId)
+	}
+
+	if req.Quantity == 0 {
+		err = s.removeItem(ctx, ref, key, req.ExpectedVersion)
+	} else {
+		inst, lookupErr := s.instrumentClient.GetInstrumentByID(ctx, &instrumentsproto.GetInstrumentByIDRequest{Id: req.InstrumentId})
+		if lookupErr != nil {
+			return nil, status.Errorf(codes.NotFound, "инструмент %s не найден", req.InstrumentId)
+		}
+		if err := checkMaxQuantity(inst, total-oldQuantity+req.Quantity); err != nil {
+			return nil, err
+		}
+		err = s.setItemQuantity(ctx, ref, key, req.Quantity, req.ExpectedVersion)
+	}
+	if errors.Is(err, repository.ErrCartModified) {
+		return nil, cartVersionConflict()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s.invalidate(ctx, ref)
+
+	s.publishCartUpdated(ref, key, oldQuantity, req.Quantity)
+
+	return &proto.UpdateCartItemQuantityResponse{Success: true}, nil
+}
+
 func (s *CartService) ClearCart(ctx context.Context, req *proto.ClearCartRequest) (*proto.ClearCartResponse, error) {
-	userID, err := primitive.ObjectIDFromHex(req.UserId)
+	ref, err := newCartRef(req.UserId, req.CartToken)
 	if err != nil {
-		return nil, fmt.Errorf("invalid user ID: %w", err)
+		return nil, err
 	}
 
-	if err := s.repo.ClearCart(ctx, userID); err != nil {
+	if err := s.clearCart(ctx, ref); err != nil {
 		return nil, err
 	}
 
-	s.invalidateCartCache(ctx, req.UserId)
+	s.invalidate(ctx, ref)
 
-	_ = s.eventPublisher.Publish("cart_cleared", map[string]string{
-		"user_id": req.UserId,
-	})
+	_ = s.eventPublisher.Publish("cart_cleared", ref.eventFields())
 
 	return &proto.ClearCartResponse{Success: true}, nil
 }
@@ -159,6 +342,47 @@ func (s *CartService) DeleteAllCartCache(ctx context.Context, req *proto.DeleteA
 	return &proto.DeleteAllCartCacheResponse{Success: true}, nil
 }
 
+// itemQuantity возвращает текущее количество позиции в корзине (0, если
+// её там нет) и суммарное количество всех вариантов инструмента. При
+// version больше 0 ревизия корзины должна с ней совпадать.
+func (s *CartService) itemQuantity(ctx context.Context, ref cartRef, key entity.ItemKey, version int64) (int32, int32, error) {
+	cart, err := s.fetchCart(ctx, ref)
+	if err != nil {
+		return 0, 0, err
+	}
+	if version > 0 && cart.Revision != version {
+		return 0, 0, cartVersionConflict()
+	}
+	line, total := lineQuantities(cart.Items, key)
+	return line, total, nil
+}
+
+func cartVersionConflict() error {
+	return status.Errorf(codes.Aborted, "корзина изменена, обновите её и повторите")
+}
+
+func checkMaxQuantity(inst *instrumentsproto.Instrument, quantity int32) error {
+	if inst.MaxCartQuantity > 0 && quantity > inst.MaxCartQuantity {
+		return status.Errorf(codes.FailedPrecondition, "%s: не более %d шт. в корзине", inst.Name, inst.MaxCartQuantity)
+	}
+	return nil
+}
+
+func (s *CartService) publishCartUpdated(ref cartRef, key entity.ItemKey, oldQuantity, newQuantity int32) {
+	payload := ref.eventFields()
+	payload["instrument_id"] = key.InstrumentID.Hex()
+	if key.SKU != "" {
+		payload["sku"] = key.SKU
+	}
+	payload["old_quantity"] = strconv.Itoa(int(oldQuantity))
+	payload["new_quantity"] = strconv.Itoa(int(newQuantity))
+	_ = s.eventPublisher.Publish("cart_updated", payload)
+}
+
+func (s *CartService) invalidate(ctx context.Context, ref cartRef) {
+	s.cache.Del(ctx, ref.cacheKey())
+}
+
 func (s *CartService) invalidateCartCache(ctx context.Context, userID string) {
 	s.cache.Del(ctx, cartCacheKeyPrefix+userID)
 }
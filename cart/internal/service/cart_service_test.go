@@ -4,6 +4,11 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"gotune/cart/internal/entity"
+	"gotune/cart/proto"
+	instrumentsproto "gotune/instruments/proto"
 )
 
 type CartItem struct {
@@ -20,3 +25,152 @@ func TestAddToCartItem(t *testing.T) {
 	assert.Equal(t, "product123", item.ProductID)
 	assert.True(t, item.Quantity > 0)
 }
+
+func TestApplyPricesSkipsUnavailableItems(t *testing.T) {
+	resp := &proto.GetCartResponse{Items: []*proto.CartItem{
+		{InstrumentId: "a", Quantity: 3},
+		{InstrumentId: "deleted", Quantity: 1},
+	}}
+	applyPrices(resp, map[string]*instrumentsproto.Instrument{
+		"a": {Id: "a", Name: "Медиатор", Price: 0.7},
+	})
+
+	assert.True(t, resp.Items[0].Available)
+	assert.Equal(t, "Медиатор", resp.Items[0].Name)
+	assert.Equal(t, 2.1, resp.Items[0].LineTotal)
+	assert.False(t, resp.Items[1].Available)
+	assert.Equal(t, 2.1, resp.Subtotal)
+}
+
+func TestCheckMaxQuantity(t *testing.T) {
+	unlimited := &instrumentsproto.Instrument{Name: "Медиатор"}
+	assert.NoError(t, checkMaxQuantity(unlimited, 1000))
+
+	limited := &instrumentsproto.Instrument{Name: "Gibson Les Paul", MaxCartQuantity: 2}
+	assert.NoError(t, checkMaxQuantity(limited, 2))
+	assert.Error(t, checkMaxQuantity(limited, 3))
+}
+
+func TestMergeQuantity(t *testing.T) {
+	assert.Equal(t, int32(5), mergeQuantity(MergeStrategySum, 2, 3))
+	assert.Equal(t, int32(3), mergeQuantity(MergeStrategyMax, 2, 3))
+	assert.Equal(t, int32(4), mergeQuantity(MergeStrategyMax, 4, 1))
+}
+
+func TestMergeItem(t *testing.T) {
+	guitar := &instrumentsproto.Instrument{
+		Name:            "Gibson Les Paul",
+		MaxCartQuantity: 5,
+		Variants:        []*instrumentsproto.Variant{{Sku: "LP-RED"}, {Sku: "LP-BLK"}},
+	}
+	item := entity.CartItem{InstrumentID: primitive.NewObjectID(), SKU: "LP-RED", Quantity: 3}
+
+	quantity, err := mergeItem(MergeStrategySum, guitar, item, 1, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(4), quantity)
+
+	// лимит общий для всех вариантов: у пользователя уже 3 LP-BLK
+	quantity, err = mergeItem(MergeStrategySum, guitar, item, 0, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), quantity)
+
+	// вариант сняли с продажи
+	item.SKU = "LP-SUNBURST"
+	_, err = mergeItem(MergeStrategySum, guitar, item, 0, 0)
+	assert.Error(t, err)
+
+	// у инструмента появились варианты, а в гостевой корзине позиция без SKU
+	item.SKU = ""
+	_, err = mergeItem(MergeStrategySum, guitar, item, 0, 0)
+	assert.Error(t, err)
+}
+
+func TestNewCartRef(t *testing.T) {
+	ref, err := newCartRef("", "0123456789abcdef0123456789abcdef")
+	assert.NoError(t, err)
+	assert.True(t, ref.guest())
+	assert.Equal(t, "cart:guest:0123456789abcdef0123456789abcdef", ref.cacheKey())
+
+	_, err = newCartRef("", "short")
+	assert.Error(t, err)
+	_, err = newCartRef("not-an-id", "")
+	assert.Error(t, err)
+}
+
+func TestMoveItem(t *testing.T) {
+	guitar := primitive.NewObjectID()
+	key := entity.ItemKey{InstrumentID: guitar}
+	list := entity.CartList{ID: primitive.NewObjectID(), Name: "На день рождения"}
+	cart := &entity.Cart{
+		Items:      []entity.CartItem{{InstrumentID: guitar, Quantity: 1}},
+		SavedItems: []entity.CartItem{{InstrumentID: guitar, Quantity: 2}},
+		Lists:      []entity.CartList{list},
+	}
+
+	quantity, err := moveItem(cart, key, LocationSaved, LocationCart)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(3), quantity)
+	assert.Empty(t, cart.SavedItems)
+
+	quantity, err = moveItem(cart, key, LocationCart, list.ID.Hex())
+	assert.NoError(t, err)
+	assert.Equal(t, int32(3), quantity)
+	assert.Empty(t, cart.Items)
+	assert.Len(t, cart.Lists[0].Items, 1)
+
+	_, err = moveItem(cart, key, LocationSaved, LocationCart)
+	assert.Error(t, err)
+	_, err = moveItem(cart, key, list.ID.Hex(), "unknown")
+	assert.Error(t, err)
+	assert.Len(t, cart.Lists[0].Items, 1)
+}
+
+func TestMoveItemKeepsVariantsApart(t *testing.T) {
+	guitar := primitive.NewObjectID()
+	cart := &entity.Cart{
+		Items:      []entity.CartItem{{InstrumentID: guitar, SKU: "STRAT-SB", Quantity: 1}},
+		SavedItems: []entity.CartItem{{InstrumentID: guitar, SKU: "STRAT-BK", Quantity: 1}},
+	}
+
+	quantity, err := moveItem(cart, entity.ItemKey{InstrumentID: guitar, SKU: "STRAT-BK"}, LocationSaved, LocationCart)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), quantity)
+	assert.Len(t, cart.Items, 2)
+
+	line, total := lineQuantities(cart.Items, entity.ItemKey{InstrumentID: guitar, SKU: "STRAT-SB"})
+	assert.Equal(t, int32(1), line)
+	assert.Equal(t, int32(2), total)
+}
+
+func TestCheckVariant(t *testing.T) {
+	plain := &instrumentsproto.Instrument{Name: "Медиатор"}
+	assert.NoError(t, checkVariant(plain, ""))
+	assert.Error(t, checkVariant(plain, "PICK-1"))
+
+	strat := &instrumentsproto.Instrument{Name: "Fender Stratocaster", Variants: []*instrumentsproto.Variant{
+		{Sku: "STRAT-SB", Options: map[string]string{"color": "sunburst"}},
+	}}
+	assert.NoError(t, checkVariant(strat, "STRAT-SB"))
+	assert.Error(t, checkVariant(strat, ""))
+	assert.Error(t, checkVariant(strat, "STRAT-RED"))
+}
+
+func TestApplyPricesUsesVariantPrice(t *testing.T) {
+	resp := &proto.GetCartResponse{Items: []*proto.CartItem{
+		{InstrumentId: "s", Sku: "STRAT-LH", Quantity: 1},
+		{InstrumentId: "s", Sku: "STRAT-SB", Quantity: 2},
+		{InstrumentId: "s", Sku: "STRAT-OLD", Quantity: 1},
+	}}
+	applyPrices(resp, map[string]*instrumentsproto.Instrument{
+		"s": {Id: "s", Name: "Fender Stratocaster", Price: 1000, Variants: []*instrumentsproto.Variant{
+			{Sku: "STRAT-LH", Options: map[string]string{"color": "sunburst", "handedness": "left"}, Price: 1100},
+			{Sku: "STRAT-SB", Options: map[string]string{"color": "sunburst", "handedness": "right"}},
+		}},
+	})
+
+	assert.Equal(t, 1100.0, resp.Items[0].UnitPrice)
+	assert.Equal(t, "color: sunburst, handedness: left", resp.Items[0].Variant)
+	assert.Equal(t, 1000.0, resp.Items[1].UnitPrice)
+	assert.False(t, resp.Items[2].Available)
+	assert.Equal(t, 3100.0, resp.Subtotal)
+}
@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"gotune/cart/internal/entity"
+	"gotune/cart/internal/repository"
+	"gotune/cart/proto"
+	instrumentsproto "gotune/instruments/proto"
+)
+
+const (
+	guestCartTTL = 30 * 24 * time.Hour
+
+	MergeStrategySum = "sum"
+	MergeStrategyMax = "max"
+)
+
+// cartRef указывает либо на корзину пользователя, либо на гостевую
+// корзину по токену, который выдаёт шлюз.
+type cartRef struct {
+	userID primitive.ObjectID
+	token  string
+}
+
+func newCartRef(userID, token string) (cartRef, error) {
+	if userID != "" {
+		id, err := primitive.ObjectIDFromHex(userID)
+		if err != nil {
+			return cartRef{}, status.Errorf(codes.InvalidArgument, "invalid user ID: %v", err)
+		}
+		return cartRef{userID: id}, nil
+	}
+	if len(token) < 16 || len(token) > 128 {
+		return cartRef{}, status.Errorf(codes.InvalidArgument, "не указан пользователь или токен корзины")
+	}
+	return cartRef{token: token}, nil
+}
+
+func (c cartRef) guest() bool {
+	return c.token != ""
+}
+
+func (c cartRef) cacheKey() string {
+	if c.guest() {
+		return cartCacheKeyPrefix + "guest:" + c.token
+	}
+	return cartCacheKeyPrefix + c.userID.Hex()
+}
+
+func (c cartRef) eventFields() map[string]string {
+	if c.guest() {
+		return map[string]string{"cart_token": c.token}
+	}
+	return map[string]string{"user_id": c.userID.Hex()}
+}
+
+func (s *CartService) fetchCart(ctx context.Context, ref cartRef) (*entity.Cart, error) {
+	if ref.guest() {
+		return s.guestRepo.GetCart(ctx, ref.token)
+	}
+	return s.repo.GetCart(ctx, ref.userID)
+}
+
+// addItem добавляет quantity к позиции; limit больше 0 ограничивает
+// суммарное количество инструмента в корзине.
+func (s *CartService) addItem(ctx context.Context, ref cartRef, key entity.ItemKey, quantity, limit int32) error {
+	if ref.guest() {
+		return s.guestRepo.AddToCart(ctx, ref.token, key, quantity, limit, time.Now().Add(guestCartTTL))
+	}
+	return s.repo.AddToCart(ctx, ref.userID, key, quantity, limit)
+}
+
+// removeItem и setItemQuantity при version больше 0 меняют корзину,
+// только если её ревизия не изменилась.
+func (s *CartService) removeItem(ctx context.Context, ref cartRef, key entity.ItemKey, version int64) error {
+	if ref.guest() {
+		return s.guestRepo.RemoveFromCart(ctx, ref.token, key, version, time.Now().Add(guestCartTTL))
+	}
+	return s.repo.RemoveFromCart(ctx, ref.userID, key, version)
+}
+
+func (s *CartService) setItemQuantity(ctx context.Context, ref cartRef, key entity.ItemKey, quantity int32, version int64) error {
+	if ref.guest() {
+		return s.guestRepo.UpdateQuantity(ctx, ref.token, key, quantity, version, time.Now().Add(guestCartTTL))
+	}
+	return s.repo.UpdateQuantity(ctx, ref.userID, key, quantity, version)
+}
+
+func (s *CartService) clearCart(ctx context.Context, ref cartRef) error {
+	if ref.guest() {
+		return s.guestRepo.ClearCart(ctx, ref.token)
+	}
+	return s.repo.ClearCart(ctx, ref.userID)
+}
+
+// MergeCarts переносит гостевую корзину в корзину пользователя после входа.
+// Для позиций, которые есть в обеих корзинах, количество выбирается
+// по стратегии: sum складывает, max оставляет большее. Суммарное
+// количество всех вариантов не превышает лимит инструмента. Позиции,
+// удалённые из каталога или с несуществующим вариантом, пропускаются.
+func (s *CartService) MergeCarts(ctx context.Context, req *proto.MergeCartsRequest) (*proto.MergeCartsResponse, error) {
+	if req.UserId == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "не указан пользователь")
+	}
+	userRef, err := newCartRef(req.UserId, "")
+	if err != nil {
+		return nil, err
+	}
+	guestRef, err := newCartRef("", req.CartToken)
+	if err != nil {
+		return nil, err
+	}
+
+	strategy := req.Strategy
+	if strategy == "" {
+		strategy = s.mergeStrategy
+	}
+	if strategy != MergeStrategySum && strategy != MergeStrategyMax {
+		return nil, status.Errorf(codes.InvalidArgument, "неизвестная стратегия объединения: %s", strategy)
+	}
+
+	guestCart, err := s.fetchCart(ctx, guestRef)
+	if err != nil {
+		return nil, err
+	}
+	if len(guestCart.Items) == 0 {
+		return &proto.MergeCartsResponse{}, nil
+	}
+
+	userCart, err := s.fetchCart(ctx, userRef)
+	if err != nil {
+		return nil, err
+	}
+	existing := map[entity.ItemKey]int32{}
+	totals := map[primitive.ObjectID]int32{}
+	for _, item := range userCart.Items {
+		existing[item.Key()] = item.Quantity
+		totals[item.InstrumentID] += item.Quantity
+	}
+
+	var merged int32
+	for _, item := range guestCart.Items {
+		inst, err := s.instrumentClient.GetInstrumentByID(ctx, &instrumentsproto.GetInstrumentByIDRequest{Id: item.InstrumentID.Hex()})
+		if err != nil {
+			continue
+		}
+
+		key := item.Key()
+		oldQuantity, inCart := existing[key]
+		quantity, err := mergeItem(strategy, inst, item, oldQuantity, totals[item.InstrumentID])
+		if err != nil {
+			log.Printf("Позиция %s гостевой корзины пропущена: %v", item.InstrumentID.Hex(), err)
+			continue
+		}
+		if quantity <= oldQuantity {
+			continue
+		}
+
+		if inCart {
+			err = s.setItemQuantity(ctx, userRef, key, quantity, 0)
+		} else {
+			err = s.addItem(ctx, userRef, key, quantity, inst.MaxCartQuantity)
+		}
+		if errors.Is(err, repository.ErrQuantityLimit) {
+			// лимит заняли параллельным добавлением
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		existing[key] = quantity
+		totals[item.InstrumentID] += quantity - oldQuantity
+		merged++
+	}
+
+	if err := s.clearCart(ctx, guestRef); err != nil {
+		return nil, err
+	}
+
+	s.invalidate(ctx, userRef)
+	s.invalidate(ctx, guestRef)
+
+	_ = s.eventPublisher.Publish("carts_merged", map[string]string{
+		"user_id":    req.UserId,
+		"cart_token": req.CartToken,
+		"strategy":   strategy,
+	})
+
+	return &proto.MergeCartsResponse{MergedItems: merged}, nil
+}
+
+// mergeItem проверяет вариант гостевой позиции и возвращает её количество
+// в корзине пользователя после объединения. oldQuantity — количество
+// позиции у пользователя, total — всех вариантов инструмента.
+func mergeItem(strategy string, inst *instrumentsproto.Instrument, item entity.CartItem, oldQuantity, total int32) (int32, error) {
+	if err := checkVariant(inst, item.SKU); err != nil {
+		return 0, err
+	}
+	quantity := mergeQuantity(strategy, oldQuantity, item.Quantity)
+	if inst.MaxCartQuantity > 0 {
+		// лимит общий для всех вариантов инструмента
+		allowed := inst.MaxCartQuantity - (total - oldQuantity)
+		if quantity > allowed {
+			quantity = allowed
+		}
+	}
+	return quantity, nil
+}
+
+func mergeQuantity(strategy string, userQuantity, guestQuantity int32) int32 {
+	if strategy == MergeStrategyMax {
+		if guestQuantity > userQuantity {
+			return guestQuantity
+		}
+		return userQuantity
+	}
+	return userQuantity + guestQuantity
+}
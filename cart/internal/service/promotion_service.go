@@ -0,0 +1,459 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"gotune/cart/internal/entity"
+	"gotune/cart/internal/promotion"
+	"gotune/cart/internal/repository"
+	"gotune/cart/proto"
+)
+
+func (s *CartService) ApplyCoupon(ctx context.Context, req *proto.ApplyCouponRequest) (*proto.ApplyCouponResponse, error) {
+	userID, err := primitive.ObjectIDFromHex(req.UserId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid user ID: %v", err)
+	}
+
+	promo, err := s.promotionRepo.FindByCode(ctx, req.Code)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "купон не найден")
+	}
+	if err := s.checkPromotion(ctx, promo, userID, time.Now()); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.SetCoupon(ctx, userID, promo.Code); err != nil {
+		return nil, err
+	}
+
+	s.invalidateCartCache(ctx, req.UserId)
+
+	_ = s.eventPublisher.Publish("coupon_applied", map[string]string{
+		"user_id": req.UserId,
+		"code":    promo.Code,
+	})
+
+	return &proto.ApplyCouponResponse{Promotion: promotionToProto(promo)}, nil
+}
+
+func (s *CartService) RemoveCoupon(ctx context.Context, req *proto.RemoveCouponRequest) (*proto.RemoveCouponResponse, error) {
+	userID, err := primitive.ObjectIDFromHex(req.UserId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid user ID: %v", err)
+	}
+
+	if err := s.repo.SetCoupon(ctx, userID, ""); err != nil {
+		return nil, err
+	}
+
+	s.invalidateCartCache(ctx, req.UserId)
+
+	return &proto.RemoveCouponResponse{Success: true}, nil
+}
+
+func (s *CartService) CreatePromotion(ctx context.Context, req *proto.CreatePromotionRequest) (*proto.CreatePromotionResponse, error) {
+	promo, err := promotionFromProto(req.Promotion)
+	if err != nil {
+		return nil, err
+	}
+	promo.CreatedAt = time.Now()
+
+	id, err := s.promotionRepo.Create(ctx, promo)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, status.Errorf(codes.AlreadyExists, "купон %s уже существует", promo.Code)
+		}
+		return nil, err
+	}
+
+	return &proto.CreatePromotionResponse{Id: id.Hex()}, nil
+}
+
+func (s *CartService) GetPromotions(ctx context.Context, req *proto.GetPromotionsRequest) (*proto.GetPromotionsResponse, error) {
+	promotions, err := s.promotionRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var protoPromotions []*proto.Promotion
+	for i := range promotions {
+		protoPromotions = append(protoPromotions, promotionToProto(&promotions[i]))
+	}
+	return &proto.GetPromotionsResponse{Promotions: protoPromotions}, nil
+}
+
+// EvaluatePromotions считает скидки без списания лимитов — для предпросмотра
+// корзины и расчёта заказа.
+func (s *CartService) EvaluatePromotions(ctx context.Context, req *proto.EvaluatePromotionsRequest) (*proto.PromotionResult, error) {
+	userID, err := primitive.ObjectIDFromHex(req.UserId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid user ID: %v", err)
+	}
+
+	now := time.Now()
+	promotions, _, err := s.collectPromotions(ctx, userID, req.CouponCode, now)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := pricedLines(req.Lines)
+	return promotionResultToProto(promotion.Evaluate(promotions, lines, now), lines), nil
+}
+
+// RedeemPromotions считает скидки для оформляемого заказа и списывает
+// использования всех применённых акций. Если лимит какой-либо акции
+// исчерпан, уже списанные использования возвращаются. Если заказ затем
+// не сохранится, списание отменяется через ReleasePromotions.
+func (s *CartService) RedeemPromotions(ctx context.Context, req *proto.RedeemPromotionsRequest) (*proto.PromotionResult, error) {
+	userID, err := primitive.ObjectIDFromHex(req.UserId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid user ID: %v", err)
+	}
+	orderID, err := primitive.ObjectIDFromHex(req.OrderId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid order ID: %v", err)
+	}
+
+	now := time.Now()
+	promotions, fromCart, err := s.collectPromotions(ctx, userID, req.CouponCode, now)
+	if err != nil {
+		return nil, err
+	}
+	byID := map[string]*entity.Promotion{}
+	for i := range promotions {
+		byID[promotions[i].ID.Hex()] = &promotions[i]
+	}
+
+	lines := pricedLines(req.Lines)
+	result := promotion.Evaluate(promotions, lines, now)
+
+	var redeemed []entity.PromotionUsage
+	couponRedeemed := false
+	for _, d := range result.Discounts {
+		p := byID[d.PromotionID]
+		usage := entity.PromotionUsage{
+			PromotionID: p.ID,
+			UserID:      userID,
+			OrderID:     orderID,
+			UsedAt:      now,
+			UserLimited: p.UsageLimitPerUser > 0,
+			CartCoupon:  fromCart && p.IsCoupon(),
+		}
+		if err := s.redeemUsage(ctx, p, &usage); err != nil {
+			for i := range redeemed {
+				if relErr := s.releaseUsage(ctx, &redeemed[i]); relErr != nil {
+					log.Printf("Ошибка возврата использования акции %s: %v", redeemed[i].PromotionID.Hex(), relErr)
+				}
+			}
+			if errors.Is(err, repository.ErrUsageLimitReached) {
+				return nil, status.Errorf(codes.ResourceExhausted, "акция %q больше недоступна", d.Name)
+			}
+			return nil, err
+		}
+		redeemed = append(redeemed, usage)
+		couponRedeemed = couponRedeemed || usage.CartCoupon
+	}
+
+	if couponRedeemed {
+		_ = s.repo.SetCoupon(ctx, userID, "")
+		s.invalidateCartCache(ctx, req.UserId)
+	}
+
+	if len(redeemed) > 0 {
+		_ = s.eventPublisher.Publish("promotions_redeemed", map[string]string{
+			"user_id":  req.UserId,
+			"order_id": req.OrderId,
+		})
+	}
+
+	return promotionResultToProto(result, lines), nil
+}
+
+// ReleasePromotions возвращает использования акций несохранённого заказа
+// и снятый с корзины купон. Повторный вызов ничего не меняет.
+func (s *CartService) ReleasePromotions(ctx context.Context, req *proto.ReleasePromotionsRequest) (*proto.ReleasePromotionsResponse, error) {
+	userID, err := primitive.ObjectIDFromHex(req.UserId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid user ID: %v", err)
+	}
+	orderID, err := primitive.ObjectIDFromHex(req.OrderId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid order ID: %v", err)
+	}
+
+	usages, err := s.promotionRepo.FindUsagesByOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	var released int32
+	for i := range usages {
+		u := &usages[i]
+		if u.UserID != userID {
+			continue
+		}
+		if err := s.releaseUsage(ctx, u); err != nil {
+			return nil, err
+		}
+		released++
+
+		if u.CartCoupon {
+			promo, err := s.promotionRepo.FindByID(ctx, u.PromotionID)
+			if err != nil {
+				return nil, err
+			}
+			if err := s.repo.SetCoupon(ctx, userID, promo.Code); err != nil {
+				return nil, err
+			}
+			s.invalidateCartCache(ctx, req.UserId)
+		}
+	}
+
+	return &proto.ReleasePromotionsResponse{Released: released}, nil
+}
+
+// redeemUsage списывает использование акции из общего лимита и лимита
+// пользователя и записывает его; при ошибке списанное возвращается.
+func (s *CartService) redeemUsage(ctx context.Context, p *entity.Promotion, usage *entity.PromotionUsage) error {
+	if err := s.promotionRepo.ReserveUsage(ctx, p.ID); err != nil {
+		return err
+	}
+	if usage.UserLimited {
+		if err := s.promotionRepo.ReserveUserUsage(ctx, p.ID, usage.UserID, p.UsageLimitPerUser); err != nil {
+			_ = s.promotionRepo.ReleaseUsage(ctx, p.ID)
+			return err
+		}
+	}
+	if err := s.promotionRepo.RecordUsage(ctx, usage); err != nil {
+		_ = s.promotionRepo.ReleaseUsage(ctx, p.ID)
+		if usage.UserLimited {
+			_ = s.promotionRepo.ReleaseUserUsage(ctx, p.ID, usage.UserID)
+		}
+		return err
+	}
+	return nil
+}
+
+// releaseUsage удаляет запись об использовании и возвращает счётчики,
+// только если запись ещё была.
+func (s *CartService) releaseUsage(ctx context.Context, usage *entity.PromotionUsage) error {
+	deleted, err := s.promotionRepo.DeleteUsage(ctx, usage.ID)
+	if err != nil || !deleted {
+		return err
+	}
+	if err := s.promotionRepo.ReleaseUsage(ctx, usage.PromotionID); err != nil {
+		return err
+	}
+	if usage.UserLimited {
+		return s.promotionRepo.ReleaseUserUsage(ctx, usage.PromotionID, usage.UserID)
+	}
+	return nil
+}
+
+// collectPromotions возвращает автоматические акции, доступные пользователю,
+// и купон — из запроса или из корзины. fromCart сообщает, что купон взят
+// из корзины. Недоступный купон — ошибка, недоступные автоматические акции
+// просто пропускаются.
+func (s *CartService) collectPromotions(ctx context.Context, userID primitive.ObjectID, code string, now time.Time) ([]entity.Promotion, bool, error) {
+	fromCart := false
+	if code == "" {
+		cart, err := s.repo.GetCart(ctx, userID)
+		if err != nil {
+			return nil, false, err
+		}
+		code = cart.CouponCode
+		fromCart = code != ""
+	}
+
+	automatic, err := s.promotionRepo.FindActiveAutomatic(ctx, now)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var promotions []entity.Promotion
+	for i := range automatic {
+		if s.checkPromotion(ctx, &automatic[i], userID, now) == nil {
+			promotions = append(promotions, automatic[i])
+		}
+	}
+
+	if code != "" {
+		coupon, err := s.promotionRepo.FindByCode(ctx, code)
+		if err != nil {
+			return nil, false, status.Errorf(codes.NotFound, "купон не найден")
+		}
+		if err := s.checkPromotion(ctx, coupon, userID, now); err != nil {
+			return nil, false, err
+		}
+		promotions = append(promotions, *coupon)
+	}
+
+	return promotions, fromCart, nil
+}
+
+func (s *CartService) checkPromotion(ctx context.Context, p *entity.Promotion, userID primitive.ObjectID, now time.Time) error {
+	if !p.Active || !p.InWindow(now) {
+		return status.Errorf(codes.FailedPrecondition, "акция %q не действует", p.Name)
+	}
+	if p.UsageLimit > 0 && p.UsedCount >= p.UsageLimit {
+		return status.Errorf(codes.ResourceExhausted, "лимит использований акции %q исчерпан", p.Name)
+	}
+	if p.UsageLimitPerUser > 0 {
+		used, err := s.promotionRepo.CountUserUsages(ctx, p.ID, userID)
+		if err != nil {
+			return err
+		}
+		if used >= int64(p.UsageLimitPerUser) {
+			return status.Errorf(codes.ResourceExhausted, "вы уже использовали акцию %q", p.Name)
+		}
+	}
+	return nil
+}
+
+func promotionFromProto(p *proto.Promotion) (*entity.Promotion, error) {
+	if p == nil || p.Name == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "не указано название акции")
+	}
+
+	switch p.Type {
+	case entity.PromotionPercentage:
+		if p.Value <= 0 || p.Value > 100 {
+			return nil, status.Errorf(codes.InvalidArgument, "процент скидки должен быть от 0 до 100")
+		}
+	case entity.PromotionFixedAmount:
+		if p.Value <= 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "сумма скидки должна быть положительной")
+		}
+	case entity.PromotionBuyXGetY:
+		if p.BuyQuantity <= 0 || p.GetQuantity <= 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "для акции buy_x_get_y нужны buy_quantity и get_quantity")
+		}
+	case entity.PromotionFreeShipping:
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "неизвестный тип акции: %s", p.Type)
+	}
+
+	promo := &entity.Promotion{
+		Name:              p.Name,
+		Code:              p.Code,
+		Type:              p.Type,
+		Value:             p.Value,
+		Categories:        p.Categories,
+		BuyQuantity:       p.BuyQuantity,
+		GetQuantity:       p.GetQuantity,
+		MinSubtotal:       p.MinSubtotal,
+		UsageLimit:        p.UsageLimit,
+		UsageLimitPerUser: p.UsageLimitPerUser,
+		Active:            p.Active,
+	}
+	if p.StartsAt > 0 {
+		promo.StartsAt = time.Unix(p.StartsAt, 0)
+	}
+	if p.EndsAt > 0 {
+		promo.EndsAt = time.Unix(p.EndsAt, 0)
+	}
+	if !promo.StartsAt.IsZero() && !promo.EndsAt.IsZero() && !promo.EndsAt.After(promo.StartsAt) {
+		return nil, status.Errorf(codes.InvalidArgument, "окончание акции должно быть позже начала")
+	}
+	for _, hex := range p.InstrumentIds {
+		id, err := primitive.ObjectIDFromHex(hex)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid instrument ID: %v", err)
+		}
+		promo.InstrumentIDs = append(promo.InstrumentIDs, id)
+	}
+	return promo, nil
+}
+
+func promotionToProto(p *entity.Promotion) *proto.Promotion {
+	result := &proto.Promotion{
+		Id:                p.ID.Hex(),
+		Name:              p.Name,
+		Code:              p.Code,
+		Type:              p.Type,
+		Value:             p.Value,
+		Categories:        p.Categories,
+		BuyQuantity:       p.BuyQuantity,
+		GetQuantity:       p.GetQuantity,
+		MinSubtotal:       p.MinSubtotal,
+		UsageLimit:        p.UsageLimit,
+		UsageLimitPerUser: p.UsageLimitPerUser,
+		UsedCount:         p.UsedCount,
+		Active:            p.Active,
+	}
+	if !p.StartsAt.IsZero() {
+		result.StartsAt = p.StartsAt.Unix()
+	}
+	if !p.EndsAt.IsZero() {
+		result.EndsAt = p.EndsAt.Unix()
+	}
+	for _, id := range p.InstrumentIDs {
+		result.InstrumentIds = append(result.InstrumentIds, id.Hex())
+	}
+	return result
+}
+
+func pricedLines(lines []*proto.PricedLine) []promotion.Line {
+	result := make([]promotion.Line, 0, len(lines))
+	for _, l := range lines {
+		result = append(result, promotion.Line{
+			InstrumentID: l.InstrumentId,
+			SKU:          l.Sku,
+			Category:     l.Category,
+			CategoryPath: l.CategoryPath,
+			UnitPrice:    l.UnitPrice,
+			Quantity:     l.Quantity,
+		})
+	}
+	return result
+}
+
+func promotionResultToProto(r *promotion.Result, lines []promotion.Line) *proto.PromotionResult {
+	result := &proto.PromotionResult{
+		Subtotal:      r.Subtotal,
+		DiscountTotal: r.DiscountTotal,
+		Total:         r.Total,
+		FreeShipping:  r.FreeShipping,
+	}
+	for _, d := range r.Discounts {
+		result.Discounts = append(result.Discounts, &proto.AppliedDiscount{
+			PromotionId:  d.PromotionID,
+			Name:         d.Name,
+			Code:         d.Code,
+			Type:         d.Type,
+			Amount:       d.Amount,
+			FreeShipping: d.FreeShipping,
+		})
+	}
+	seen := map[string]bool{}
+	for _, l := range lines {
+		amount, ok := r.LineDiscounts[l.Key()]
+		if !ok || seen[l.Key()] {
+			continue
+		}
+		seen[l.Key()] = true
+		result.LineDiscounts = append(result.LineDiscounts, &proto.LineDiscount{
+			InstrumentId: l.InstrumentID,
+			Sku:          l.SKU,
+			Amount:       amount,
+		})
+	}
+	sort.Slice(result.LineDiscounts, func(i, j int) bool {
+		a, b := result.LineDiscounts[i], result.LineDiscounts[j]
+		if a.InstrumentId != b.InstrumentId {
+			return a.InstrumentId < b.InstrumentId
+		}
+		return a.Sku < b.Sku
+	})
+	return result
+}
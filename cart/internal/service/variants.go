@@ -0,0 +1,80 @@
+package service
+
+import (
+	"sort"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"gotune/cart/internal/entity"
+	instrumentsproto "gotune/instruments/proto"
+)
+
+// itemKey разбирает позицию из запроса. SKU хранится в верхнем регистре,
+// как в каталоге.
+func itemKey(instrumentID, sku string) (entity.ItemKey, error) {
+	id, err := primitive.ObjectIDFromHex(instrumentID)
+	if err != nil {
+		return entity.ItemKey{}, status.Errorf(codes.InvalidArgument, "invalid instrument ID: %v", err)
+	}
+	return entity.ItemKey{InstrumentID: id, SKU: strings.ToUpper(strings.TrimSpace(sku))}, nil
+}
+
+// checkVariant проверяет, что для инструмента с вариантами выбран
+// существующий вариант, а для инструмента без вариантов SKU не указан.
+func checkVariant(inst *instrumentsproto.Instrument, sku string) error {
+	if len(inst.Variants) == 0 {
+		if sku != "" {
+			return status.Errorf(codes.InvalidArgument, "у инструмента %s нет вариантов", inst.Name)
+		}
+		return nil
+	}
+	if sku == "" {
+		return status.Errorf(codes.InvalidArgument, "выберите вариант инструмента %s", inst.Name)
+	}
+	if findVariant(inst, sku) == nil {
+		return status.Errorf(codes.NotFound, "вариант %s не найден", sku)
+	}
+	return nil
+}
+
+func findVariant(inst *instrumentsproto.Instrument, sku string) *instrumentsproto.Variant {
+	for _, v := range inst.Variants {
+		if v.Sku == sku {
+			return v
+		}
+	}
+	return nil
+}
+
+// lineQuantities возвращает количество позиции key и суммарное количество
+// всех вариантов её инструмента: лимит инструмента общий для вариантов.
+func lineQuantities(items []entity.CartItem, key entity.ItemKey) (line, total int32) {
+	for _, item := range items {
+		if item.InstrumentID != key.InstrumentID {
+			continue
+		}
+		total += item.Quantity
+		if item.SKU == key.SKU {
+			line = item.Quantity
+		}
+	}
+	return line, total
+}
+
+// variantTitle описывает вариант в виде "finish: sunburst, handedness: left".
+func variantTitle(options map[string]string) string {
+	names := make([]string, 0, len(options))
+	for name := range options {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, name+": "+options[name])
+	}
+	return strings.Join(parts, ", ")
+}
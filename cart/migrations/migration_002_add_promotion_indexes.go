@@ -0,0 +1,30 @@
+package migrations
+
+import (
+	"context"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func Migration002_AddPromotionIndexes(db *mongo.Database) error {
+	_, err := db.Collection("promotions").Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys: bson.D{{Key: "code", Value: 1}},
+		// у автоматических акций кода нет, поэтому индекс разреженный
+		Options: options.Index().SetUnique(true).SetSparse(true),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Collection("promotion_usages").Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys: bson.D{{Key: "promotion_id", Value: 1}, {Key: "user_id", Value: 1}},
+	})
+	if err != nil {
+		return err
+	}
+	log.Println("✅ Migration002_AddPromotionIndexes applied")
+	return nil
+}
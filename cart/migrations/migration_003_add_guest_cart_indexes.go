@@ -0,0 +1,31 @@
+package migrations
+
+import (
+	"context"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func Migration003_AddGuestCartIndexes(db *mongo.Database) error {
+	guestCarts := db.Collection("guest_carts")
+
+	_, err := guestCarts.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "token", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			// гостевая корзина удаляется сразу по наступлении expires_at
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+	})
+	if err != nil {
+		return err
+	}
+	log.Println("✅ Migration003_AddGuestCartIndexes applied")
+	return nil
+}
@@ -0,0 +1,20 @@
+package migrations
+
+import (
+	"context"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func Migration004_AddCartUpdatedAtIndex(db *mongo.Database) error {
+	_, err := db.Collection("carts").Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys: bson.D{{Key: "updated_at", Value: 1}},
+	})
+	if err != nil {
+		return err
+	}
+	log.Println("✅ Migration004_AddCartUpdatedAtIndex applied")
+	return nil
+}
@@ -0,0 +1,40 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// indexNotFoundCode — код ошибки MongoDB IndexNotFound.
+const indexNotFoundCode = 27
+
+// Migration001 создала уникальный индекс по несуществующему полю userId.
+// У всех корзин userId равен null, поэтому индекс допускал только одну
+// корзину на всю коллекцию, а корзина пользователя по user_id уникальной
+// не была. Индекс userId_1 удаляется и заменяется индексом по user_id.
+func Migration005_AddCartUserIDUniqueIndex(db *mongo.Database) error {
+	carts := db.Collection("carts")
+
+	_, err := carts.Indexes().DropOne(context.Background(), "userId_1")
+	var cmdErr mongo.CommandError
+	if err != nil && !(errors.As(err, &cmdErr) && cmdErr.Code == indexNotFoundCode) {
+		return err
+	}
+
+	_, err = carts.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys: bson.D{{Key: "user_id", Value: 1}},
+		Options: options.Index().
+			SetUnique(true).
+			SetPartialFilterExpression(bson.M{"user_id": bson.M{"$exists": true}}),
+	})
+	if err != nil {
+		return err
+	}
+	log.Println("✅ Migration005_AddCartUserIDUniqueIndex applied")
+	return nil
+}
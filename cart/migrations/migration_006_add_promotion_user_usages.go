@@ -0,0 +1,73 @@
+package migrations
+
+import (
+	"context"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Migration006 заполняет счётчики использований акций пользователями по
+// уже записанным использованиям акций с лимитом на пользователя и
+// добавляет индекс для отката использований заказа.
+func Migration006_AddPromotionUserUsages(db *mongo.Database) error {
+	ctx := context.Background()
+	usages := db.Collection("promotion_usages")
+
+	_, err := usages.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "order_id", Value: 1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	var limited []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	cursor, err := db.Collection("promotions").Find(ctx, bson.M{"usage_limit_per_user": bson.M{"$gt": 0}})
+	if err != nil {
+		return err
+	}
+	if err := cursor.All(ctx, &limited); err != nil {
+		return err
+	}
+
+	counters := db.Collection("promotion_user_usages")
+	for _, p := range limited {
+		cursor, err := usages.Aggregate(ctx, mongo.Pipeline{
+			{{Key: "$match", Value: bson.M{"promotion_id": p.ID}}},
+			{{Key: "$group", Value: bson.M{"_id": "$user_id", "count": bson.M{"$sum": 1}}}},
+		})
+		if err != nil {
+			return err
+		}
+		var perUser []struct {
+			UserID primitive.ObjectID `bson:"_id"`
+			Count  int32              `bson:"count"`
+		}
+		if err := cursor.All(ctx, &perUser); err != nil {
+			return err
+		}
+
+		for _, u := range perUser {
+			_, err := counters.UpdateOne(ctx,
+				bson.M{"_id": bson.D{{Key: "promotion_id", Value: p.ID}, {Key: "user_id", Value: u.UserID}}},
+				bson.M{"$set": bson.M{"count": u.Count}},
+				options.Update().SetUpsert(true),
+			)
+			if err != nil {
+				return err
+			}
+		}
+		_, err = usages.UpdateMany(ctx, bson.M{"promotion_id": p.ID}, bson.M{"$set": bson.M{"user_limited": true}})
+		if err != nil {
+			return err
+		}
+	}
+
+	log.Println("✅ Migration006_AddPromotionUserUsages applied")
+	return nil
+}
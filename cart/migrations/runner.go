@@ -17,6 +17,11 @@ type Migration struct {
 func RunM(db *mongo.Database) error {
 	migrations := []Migration{
 		{Name: "Migration001_AddCartUserIndex", Func: Migration001_AddCartUserIndex},
+		{Name: "Migration002_AddPromotionIndexes", Func: Migration002_AddPromotionIndexes},
+		{Name: "Migration003_AddGuestCartIndexes", Func: Migration003_AddGuestCartIndexes},
+		{Name: "Migration004_AddCartUpdatedAtIndex", Func: Migration004_AddCartUpdatedAtIndex},
+		{Name: "Migration005_AddCartUserIDUniqueIndex", Func: Migration005_AddCartUserIDUniqueIndex},
+		{Name: "Migration006_AddPromotionUserUsages", Func: Migration006_AddPromotionUserUsages},
 	}
 
 	applied := db.Collection("migrations")
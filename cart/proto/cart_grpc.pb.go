@@ -25,9 +25,20 @@ type CartServiceClient interface {
 	AddToCart(ctx context.Context, in *AddToCartRequest, opts ...grpc.CallOption) (*AddToCartResponse, error)
 	GetCart(ctx context.Context, in *GetCartRequest, opts ...grpc.CallOption) (*GetCartResponse, error)
 	RemoveFromCart(ctx context.Context, in *RemoveFromCartRequest, opts ...grpc.CallOption) (*RemoveFromCartResponse, error)
+	UpdateCartItemQuantity(ctx context.Context, in *UpdateCartItemQuantityRequest, opts ...grpc.CallOption) (*UpdateCartItemQuantityResponse, error)
+	MergeCarts(ctx context.Context, in *MergeCartsRequest, opts ...grpc.CallOption) (*MergeCartsResponse, error)
+	CreateCartList(ctx context.Context, in *CreateCartListRequest, opts ...grpc.CallOption) (*CreateCartListResponse, error)
+	DeleteCartList(ctx context.Context, in *DeleteCartListRequest, opts ...grpc.CallOption) (*DeleteCartListResponse, error)
+	MoveCartItem(ctx context.Context, in *MoveCartItemRequest, opts ...grpc.CallOption) (*MoveCartItemResponse, error)
 	ClearCart(ctx context.Context, in *ClearCartRequest, opts ...grpc.CallOption) (*ClearCartResponse, error)
-	// Новый метод для сброса кэша корзин всех пользователей (использовать только в админке)
 	DeleteAllCartCache(ctx context.Context, in *DeleteAllCartCacheRequest, opts ...grpc.CallOption) (*DeleteAllCartCacheResponse, error)
+	ApplyCoupon(ctx context.Context, in *ApplyCouponRequest, opts ...grpc.CallOption) (*ApplyCouponResponse, error)
+	RemoveCoupon(ctx context.Context, in *RemoveCouponRequest, opts ...grpc.CallOption) (*RemoveCouponResponse, error)
+	CreatePromotion(ctx context.Context, in *CreatePromotionRequest, opts ...grpc.CallOption) (*CreatePromotionResponse, error)
+	GetPromotions(ctx context.Context, in *GetPromotionsRequest, opts ...grpc.CallOption) (*GetPromotionsResponse, error)
+	EvaluatePromotions(ctx context.Context, in *EvaluatePromotionsRequest, opts ...grpc.CallOption) (*PromotionResult, error)
+	RedeemPromotions(ctx context.Context, in *RedeemPromotionsRequest, opts ...grpc.CallOption) (*PromotionResult, error)
+	ReleasePromotions(ctx context.Context, in *ReleasePromotionsRequest, opts ...grpc.CallOption) (*ReleasePromotionsResponse, error)
 }
 
 type cartServiceClient struct {
@@ -65,6 +76,51 @@ func (c *cartServiceClient) RemoveFromCart(ctx context.Context, in *RemoveFromCa
 	return out, nil
 }
 
+func (c *cartServiceClient) UpdateCartItemQuantity(ctx context.Context, in *UpdateCartItemQuantityRequest, opts ...grpc.CallOption) (*UpdateCartItemQuantityResponse, error) {
+	out := new(UpdateCartItemQuantityResponse)
+	err := c.cc.Invoke(ctx, "/cart.CartService/UpdateCartItemQuantity", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cartServiceClient) MergeCarts(ctx context.Context, in *MergeCartsRequest, opts ...grpc.CallOption) (*MergeCartsResponse, error) {
+	out := new(MergeCartsResponse)
+	err := c.cc.Invoke(ctx, "/cart.CartService/MergeCarts", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cartServiceClient) CreateCartList(ctx context.Context, in *CreateCartListRequest, opts ...grpc.CallOption) (*CreateCartListResponse, error) {
+	out := new(CreateCartListResponse)
+	err := c.cc.Invoke(ctx, "/cart.CartService/CreateCartList", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cartServiceClient) DeleteCartList(ctx context.Context, in *DeleteCartListRequest, opts ...grpc.CallOption) (*DeleteCartListResponse, error) {
+	out := new(DeleteCartListResponse)
+	err := c.cc.Invoke(ctx, "/cart.CartService/DeleteCartList", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cartServiceClient) MoveCartItem(ctx context.Context, in *MoveCartItemRequest, opts ...grpc.CallOption) (*MoveCartItemResponse, error) {
+	out := new(MoveCartItemResponse)
+	err := c.cc.Invoke(ctx, "/cart.CartService/MoveCartItem", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *cartServiceClient) ClearCart(ctx context.Context, in *ClearCartRequest, opts ...grpc.CallOption) (*ClearCartResponse, error) {
 	out := new(ClearCartResponse)
 	err := c.cc.Invoke(ctx, "/cart.CartService/ClearCart", in, out, opts...)
@@ -83,6 +139,69 @@ func (c *cartServiceClient) DeleteAllCartCache(ctx context.Context, in *DeleteAl
 	return out, nil
 }
 
+func (c *cartServiceClient) ApplyCoupon(ctx context.Context, in *ApplyCouponRequest, opts ...grpc.CallOption) (*ApplyCouponResponse, error) {
+	out := new(ApplyCouponResponse)
+	err := c.cc.Invoke(ctx, "/cart.CartService/ApplyCoupon", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cartServiceClient) RemoveCoupon(ctx context.Context, in *RemoveCouponRequest, opts ...grpc.CallOption) (*RemoveCouponResponse, error) {
+	out := new(RemoveCouponResponse)
+	err := c.cc.Invoke(ctx, "/cart.CartService/RemoveCoupon", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cartServiceClient) CreatePromotion(ctx context.Context, in *CreatePromotionRequest, opts ...grpc.CallOption) (*CreatePromotionResponse, error) {
+	out := new(CreatePromotionResponse)
+	err := c.cc.Invoke(ctx, "/cart.CartService/CreatePromotion", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cartServiceClient) GetPromotions(ctx context.Context, in *GetPromotionsRequest, opts ...grpc.CallOption) (*GetPromotionsResponse, error) {
+	out := new(GetPromotionsResponse)
+	err := c.cc.Invoke(ctx, "/cart.CartService/GetPromotions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cartServiceClient) EvaluatePromotions(ctx context.Context, in *EvaluatePromotionsRequest, opts ...grpc.CallOption) (*PromotionResult, error) {
+	out := new(PromotionResult)
+	err := c.cc.Invoke(ctx, "/cart.CartService/EvaluatePromotions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cartServiceClient) RedeemPromotions(ctx context.Context, in *RedeemPromotionsRequest, opts ...grpc.CallOption) (*PromotionResult, error) {
+	out := new(PromotionResult)
+	err := c.cc.Invoke(ctx, "/cart.CartService/RedeemPromotions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cartServiceClient) ReleasePromotions(ctx context.Context, in *ReleasePromotionsRequest, opts ...grpc.CallOption) (*ReleasePromotionsResponse, error) {
+	out := new(ReleasePromotionsResponse)
+	err := c.cc.Invoke(ctx, "/cart.CartService/ReleasePromotions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // CartServiceServer is the server API for CartService service.
 // All implementations must embed UnimplementedCartServiceServer
 // for forward compatibility
@@ -90,9 +209,20 @@ type CartServiceServer interface {
 	AddToCart(context.Context, *AddToCartRequest) (*AddToCartResponse, error)
 	GetCart(context.Context, *GetCartRequest) (*GetCartResponse, error)
 	RemoveFromCart(context.Context, *RemoveFromCartRequest) (*RemoveFromCartResponse, error)
+	UpdateCartItemQuantity(context.Context, *UpdateCartItemQuantityRequest) (*UpdateCartItemQuantityResponse, error)
+	MergeCarts(context.Context, *MergeCartsRequest) (*MergeCartsResponse, error)
+	CreateCartList(context.Context, *CreateCartListRequest) (*CreateCartListResponse, error)
+	DeleteCartList(context.Context, *DeleteCartListRequest) (*DeleteCartListResponse, error)
+	MoveCartItem(context.Context, *MoveCartItemRequest) (*MoveCartItemResponse, error)
 	ClearCart(context.Context, *ClearCartRequest) (*ClearCartResponse, error)
-	// Новый метод для сброса кэша корзин всех пользователей (использовать только в админке)
 	DeleteAllCartCache(context.Context, *DeleteAllCartCacheRequest) (*DeleteAllCartCacheResponse, error)
+	ApplyCoupon(context.Context, *ApplyCouponRequest) (*ApplyCouponResponse, error)
+	RemoveCoupon(context.Context, *RemoveCouponRequest) (*RemoveCouponResponse, error)
+	CreatePromotion(context.Context, *CreatePromotionRequest) (*CreatePromotionResponse, error)
+	GetPromotions(context.Context, *GetPromotionsRequest) (*GetPromotionsResponse, error)
+	EvaluatePromotions(context.Context, *EvaluatePromotionsRequest) (*PromotionResult, error)
+	RedeemPromotions(context.Context, *RedeemPromotionsRequest) (*PromotionResult, error)
+	ReleasePromotions(context.Context, *ReleasePromotionsRequest) (*ReleasePromotionsResponse, error)
 	mustEmbedUnimplementedCartServiceServer()
 }
 
@@ -109,12 +239,48 @@ func (UnimplementedCartServiceServer) GetCart(context.Context, *GetCartRequest)
 func (UnimplementedCartServiceServer) RemoveFromCart(context.Context, *RemoveFromCartRequest) (*RemoveFromCartResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RemoveFromCart not implemented")
 }
+func (UnimplementedCartServiceServer) UpdateCartItemQuantity(context.Context, *UpdateCartItemQuantityRequest) (*UpdateCartItemQuantityResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateCartItemQuantity not implemented")
+}
+func (UnimplementedCartServiceServer) MergeCarts(context.Context, *MergeCartsRequest) (*MergeCartsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MergeCarts not implemented")
+}
+func (UnimplementedCartServiceServer) CreateCartList(context.Context, *CreateCartListRequest) (*CreateCartListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateCartList not implemented")
+}
+func (UnimplementedCartServiceServer) DeleteCartList(context.Context, *DeleteCartListRequest) (*DeleteCartListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteCartList not implemented")
+}
+func (UnimplementedCartServiceServer) MoveCartItem(context.Context, *MoveCartItemRequest) (*MoveCartItemResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MoveCartItem not implemented")
+}
 func (UnimplementedCartServiceServer) ClearCart(context.Context, *ClearCartRequest) (*ClearCartResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ClearCart not implemented")
 }
 func (UnimplementedCartServiceServer) DeleteAllCartCache(context.Context, *DeleteAllCartCacheRequest) (*DeleteAllCartCacheResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteAllCartCache not implemented")
 }
+func (UnimplementedCartServiceServer) ApplyCoupon(context.Context, *ApplyCouponRequest) (*ApplyCouponResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ApplyCoupon not implemented")
+}
+func (UnimplementedCartServiceServer) RemoveCoupon(context.Context, *RemoveCouponRequest) (*RemoveCouponResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveCoupon not implemented")
+}
+func (UnimplementedCartServiceServer) CreatePromotion(context.Context, *CreatePromotionRequest) (*CreatePromotionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreatePromotion not implemented")
+}
+func (UnimplementedCartServiceServer) GetPromotions(context.Context, *GetPromotionsRequest) (*GetPromotionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPromotions not implemented")
+}
+func (UnimplementedCartServiceServer) EvaluatePromotions(context.Context, *EvaluatePromotionsRequest) (*PromotionResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EvaluatePromotions not implemented")
+}
+func (UnimplementedCartServiceServer) RedeemPromotions(context.Context, *RedeemPromotionsRequest) (*PromotionResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RedeemPromotions not implemented")
+}
+func (UnimplementedCartServiceServer) ReleasePromotions(context.Context, *ReleasePromotionsRequest) (*ReleasePromotionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReleasePromotions not implemented")
+}
 func (UnimplementedCartServiceServer) mustEmbedUnimplementedCartServiceServer() {}
 
 // UnsafeCartServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -182,6 +348,96 @@ func _CartService_RemoveFromCart_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
+func _CartService_UpdateCartItemQuantity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateCartItemQuantityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CartServiceServer).UpdateCartItemQuantity(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cart.CartService/UpdateCartItemQuantity",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CartServiceServer).UpdateCartItemQuantity(ctx, req.(*UpdateCartItemQuantityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CartService_MergeCarts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MergeCartsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CartServiceServer).MergeCarts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cart.CartService/MergeCarts",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CartServiceServer).MergeCarts(ctx, req.(*MergeCartsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CartService_CreateCartList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateCartListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CartServiceServer).CreateCartList(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cart.CartService/CreateCartList",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CartServiceServer).CreateCartList(ctx, req.(*CreateCartListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CartService_DeleteCartList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteCartListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CartServiceServer).DeleteCartList(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cart.CartService/DeleteCartList",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CartServiceServer).DeleteCartList(ctx, req.(*DeleteCartListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CartService_MoveCartItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MoveCartItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CartServiceServer).MoveCartItem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cart.CartService/MoveCartItem",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CartServiceServer).MoveCartItem(ctx, req.(*MoveCartItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _CartService_ClearCart_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ClearCartRequest)
 	if err := dec(in); err != nil {
@@ -218,6 +474,132 @@ func _CartService_DeleteAllCartCache_Handler(srv interface{}, ctx context.Contex
 	return interceptor(ctx, in, info, handler)
 }
 
+func _CartService_ApplyCoupon_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ApplyCouponRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CartServiceServer).ApplyCoupon(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cart.CartService/ApplyCoupon",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CartServiceServer).ApplyCoupon(ctx, req.(*ApplyCouponRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CartService_RemoveCoupon_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveCouponRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CartServiceServer).RemoveCoupon(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cart.CartService/RemoveCoupon",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CartServiceServer).RemoveCoupon(ctx, req.(*RemoveCouponRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CartService_CreatePromotion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreatePromotionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CartServiceServer).CreatePromotion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cart.CartService/CreatePromotion",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CartServiceServer).CreatePromotion(ctx, req.(*CreatePromotionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CartService_GetPromotions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPromotionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CartServiceServer).GetPromotions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cart.CartService/GetPromotions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CartServiceServer).GetPromotions(ctx, req.(*GetPromotionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CartService_EvaluatePromotions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EvaluatePromotionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CartServiceServer).EvaluatePromotions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cart.CartService/EvaluatePromotions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CartServiceServer).EvaluatePromotions(ctx, req.(*EvaluatePromotionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CartService_RedeemPromotions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RedeemPromotionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CartServiceServer).RedeemPromotions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cart.CartService/RedeemPromotions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CartServiceServer).RedeemPromotions(ctx, req.(*RedeemPromotionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CartService_ReleasePromotions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReleasePromotionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CartServiceServer).ReleasePromotions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cart.CartService/ReleasePromotions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CartServiceServer).ReleasePromotions(ctx, req.(*ReleasePromotionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // CartService_ServiceDesc is the grpc.ServiceDesc for CartService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -237,6 +619,26 @@ var CartService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "RemoveFromCart",
 			Handler:    _CartService_RemoveFromCart_Handler,
 		},
+		{
+			MethodName: "UpdateCartItemQuantity",
+			Handler:    _CartService_UpdateCartItemQuantity_Handler,
+		},
+		{
+			MethodName: "MergeCarts",
+			Handler:    _CartService_MergeCarts_Handler,
+		},
+		{
+			MethodName: "CreateCartList",
+			Handler:    _CartService_CreateCartList_Handler,
+		},
+		{
+			MethodName: "DeleteCartList",
+			Handler:    _CartService_DeleteCartList_Handler,
+		},
+		{
+			MethodName: "MoveCartItem",
+			Handler:    _CartService_MoveCartItem_Handler,
+		},
 		{
 			MethodName: "ClearCart",
 			Handler:    _CartService_ClearCart_Handler,
@@ -245,6 +647,34 @@ var CartService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteAllCartCache",
 			Handler:    _CartService_DeleteAllCartCache_Handler,
 		},
+		{
+			MethodName: "ApplyCoupon",
+			Handler:    _CartService_ApplyCoupon_Handler,
+		},
+		{
+			MethodName: "RemoveCoupon",
+			Handler:    _CartService_RemoveCoupon_Handler,
+		},
+		{
+			MethodName: "CreatePromotion",
+			Handler:    _CartService_CreatePromotion_Handler,
+		},
+		{
+			MethodName: "GetPromotions",
+			Handler:    _CartService_GetPromotions_Handler,
+		},
+		{
+			MethodName: "EvaluatePromotions",
+			Handler:    _CartService_EvaluatePromotions_Handler,
+		},
+		{
+			MethodName: "RedeemPromotions",
+			Handler:    _CartService_RedeemPromotions_Handler,
+		},
+		{
+			MethodName: "ReleasePromotions",
+			Handler:    _CartService_ReleasePromotions_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/cart.proto",
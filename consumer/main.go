@@ -21,11 +21,26 @@ func main() {
 	}
 	defer ch.Close()
 
-	queues := []string{"user_registered", "order_created", "instrument_created"}
+	// события публикуются в fanout-обменники; у консоли свои очереди, чтобы
+	// не забирать сообщения у сервисов
+	events := []string{"user_registered", "order_created", "instrument_created"}
 
-	for _, queue := range queues {
-		_, err := ch.QueueDeclare(
-			queue,
+	for _, event := range events {
+		err := ch.ExchangeDeclare(
+			event,
+			amqp.ExchangeFanout,
+			true,  // durable
+			false, // autoDelete
+			false, // internal
+			false, // noWait
+			nil,   // arguments
+		)
+		if err != nil {
+			log.Fatalf("Ошибка создания обменника %s: %v", event, err)
+		}
+
+		_, err = ch.QueueDeclare(
+			"consumer."+event,
 			true,  // durable
 			false, // autoDelete
 			false, // exclusive
@@ -33,12 +48,16 @@ func main() {
 			nil,   // arguments
 		)
 		if err != nil {
-			log.Fatalf("Ошибка создания/подписки на очередь %s: %v", queue, err)
+			log.Fatalf("Ошибка создания/подписки на очередь %s: %v", event, err)
+		}
+
+		if err := ch.QueueBind("consumer."+event, "", event, false, nil); err != nil {
+			log.Fatalf("Ошибка привязки очереди к событию %s: %v", event, err)
 		}
 	}
 
 	msgsUser, _ := ch.Consume(
-		"user_registered",
+		"consumer.user_registered",
 		"",
 		true,  // auto-ack
 		false, // exclusive
@@ -48,7 +67,7 @@ func main() {
 	)
 
 	msgsOrder, _ := ch.Consume(
-		"order_created",
+		"consumer.order_created",
 		"",
 		true,
 		false,
@@ -57,8 +76,8 @@ func main() {
 		nil,
 	)
 
-	msgsUser, _ = ch.Consume(
-		"instrument_created",
+	msgsInstrument, _ := ch.Consume(
+		"consumer.instrument_created",
 		"",
 		true,  // auto-ack
 		false, // exclusive
@@ -86,7 +105,7 @@ func main() {
 	}()
 
 	go func() {
-		for d := range msgsOrder {
+		for d := range msgsInstrument {
 			var data map[string]string
 			_ = json.Unmarshal(d.Body, &data)
 			fmt.Printf("[Instrument_created] Новый инструмент: InstrumentId = %s", data["instrument_id"])
@@ -0,0 +1,86 @@
+package events
+
+import (
+	"encoding/json"
+	"log"
+
+	"github.com/streadway/amqp"
+)
+
+type EventConsumer struct {
+	channel *amqp.Channel
+}
+
+func NewEventConsumer(amqpURL string) *EventConsumer {
+	conn, err := amqp.Dial(amqpURL)
+	if err != nil {
+		log.Fatalf("Не удалось подключиться к RabbitMQ: %v", err)
+	}
+
+	ch, err := conn.Channel()
+	if err != nil {
+		log.Fatalf("Не удалось создать канал RabbitMQ: %v", err)
+	}
+
+	return &EventConsumer{channel: ch}
+}
+
+// Consume привязывает очередь подписчика к обменнику события и вызывает
+// handler для каждого сообщения. У каждого подписчика своя очередь, иначе
+// сервисы делили бы сообщения между собой. Сообщение подтверждается после
+// успешной обработки; при ошибке оно один раз возвращается в очередь, а
+// при повторной ошибке отбрасывается.
+func (c *EventConsumer) Consume(event, queue string, handler func(payload map[string]string) error) error {
+	if err := declareExchange(c.channel, event); err != nil {
+		return err
+	}
+
+	_, err := c.channel.QueueDeclare(
+		queue,
+		true,  // durable
+		false, // autoDelete
+		false, // exclusive
+		false, // noWait
+		nil,   // arguments
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := c.channel.QueueBind(queue, "", event, false, nil); err != nil {
+		return err
+	}
+
+	msgs, err := c.channel.Consume(
+		queue,
+		"",
+		false, // auto-ack
+		false, // exclusive
+		false, // no-local
+		false, // no-wait
+		nil,
+	)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		for d := range msgs {
+			var payload map[string]string
+			if err := json.Unmarshal(d.Body, &payload); err != nil {
+				log.Printf("Некорректное сообщение в очереди %s: %v", queue, err)
+				_ = d.Nack(false, false)
+				continue
+			}
+
+			if err := handler(payload); err != nil {
+				log.Printf("Ошибка обработки сообщения из очереди %s: %v", queue, err)
+				_ = d.Nack(false, !d.Redelivered)
+				continue
+			}
+			_ = d.Ack(false)
+		}
+	}()
+
+	return nil
+}
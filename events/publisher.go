@@ -25,27 +25,21 @@ func NewEventPublish(amqpURL string) *EventPublisher {
 	return &EventPublisher{channel: ch}
 }
 
-func (p *EventPublisher) Publish(queue string, event any) error {
+// Publish отправляет событие в fanout-обменник с именем события; каждый
+// подписчик получает копию в свою очередь.
+func (p *EventPublisher) Publish(name string, event any) error {
 	body, err := json.Marshal(event)
 	if err != nil {
 		return err
 	}
 
-	_, err = p.channel.QueueDeclare(
-		queue,
-		true,  // durable
-		false, // autoDelete
-		false, // exclusive
-		false, // noWait
-		nil,   // arguments
-	)
-	if err != nil {
+	if err := declareExchange(p.channel, name); err != nil {
 		return err
 	}
 
 	return p.channel.Publish(
-		"",    // default exchange
-		queue, // routing key
+		name, // exchange
+		"",   // routing key
 		false,
 		false,
 		amqp.Publishing{
@@ -54,3 +48,16 @@ func (p *EventPublisher) Publish(queue string, event any) error {
 		},
 	)
 }
+
+// declareExchange объявляет fanout-обменник события.
+func declareExchange(ch *amqp.Channel, name string) error {
+	return ch.ExchangeDeclare(
+		name,
+		amqp.ExchangeFanout,
+		true,  // durable
+		false, // autoDelete
+		false, // internal
+		false, // noWait
+		nil,   // arguments
+	)
+}
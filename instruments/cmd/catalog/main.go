@@ -0,0 +1,215 @@
+// Команда catalog загружает каталог инструментов из CSV или JSON Lines
+// и выгружает его обратно:
+//
+//	go run ./instruments/cmd/catalog import [-dry-run] [-format csv|jsonl] catalog.csv
+//	go run ./instruments/cmd/catalog export [-format csv|jsonl] [-category slug] [-o catalog.jsonl]
+//
+// Формат определяется по расширению файла, если не указан -format.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"google.golang.org/grpc"
+
+	"gotune/instruments/internal/catalogfile"
+	"gotune/instruments/proto"
+)
+
+const defaultAddr = "localhost:50052"
+
+func main() {
+	log.SetFlags(0)
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "import":
+		err = runImport(os.Args[2:])
+	case "export":
+		err = runExport(os.Args[2:])
+	default:
+		usage()
+	}
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "использование: catalog import|export [флаги] [файл]")
+	os.Exit(2)
+}
+
+func runImport(args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	addr := fs.String("addr", defaultAddr, "адрес сервиса инструментов")
+	format := fs.String("format", "", "csv или jsonl, по умолчанию по расширению файла")
+	dryRun := fs.Bool("dry-run", false, "только проверить строки, ничего не записывая")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return errors.New("укажите файл каталога")
+	}
+	path := fs.Arg(0)
+	if *format == "" {
+		f, err := catalogfile.DetectFormat(path)
+		if err != nil {
+			return err
+		}
+		*format = f
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	reader, err := catalogfile.NewReader(file, *format)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	client, conn, err := dial(*addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	stream, err := client.ImportInstruments(context.Background())
+	if err != nil {
+		return err
+	}
+
+	// строки, которые не удалось разобрать, в сервис не отправляются
+	var parseErrors []*catalogfile.RowError
+	first := true
+	for {
+		line, row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		var rowErr *catalogfile.RowError
+		if errors.As(err, &rowErr) {
+			parseErrors = append(parseErrors, rowErr)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		msg := &proto.ImportInstrumentRow{Line: int32(line), Instrument: row.ToProto()}
+		if first {
+			msg.DryRun = *dryRun
+			first = false
+		}
+		// при ошибке отправки сервис уже закрыл поток, причину вернёт CloseAndRecv
+		if err := stream.Send(msg); err != nil {
+			break
+		}
+	}
+	resp, err := stream.CloseAndRecv()
+	if err != nil {
+		return err
+	}
+
+	for _, e := range parseErrors {
+		log.Printf("строка %d: %v", e.Line, e.Err)
+	}
+	for _, e := range resp.Errors {
+		log.Printf("строка %d (%s): %s", e.Line, e.Name, e.Error)
+	}
+	if int(resp.Failed) > len(resp.Errors) {
+		log.Printf("... и ещё ошибок: %d", int(resp.Failed)-len(resp.Errors))
+	}
+
+	mode := ""
+	if *dryRun {
+		mode = " (проверка, ничего не записано)"
+	}
+	failed := int(resp.Failed) + len(parseErrors)
+	log.Printf("Создано: %d, обновлено: %d, с ошибками: %d%s", resp.Created, resp.Updated, failed, mode)
+	if failed > 0 {
+		return errors.New("импорт завершён с ошибками")
+	}
+	return nil
+}
+
+func runExport(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	addr := fs.String("addr", defaultAddr, "адрес сервиса инструментов")
+	format := fs.String("format", "", "csv или jsonl, по умолчанию по расширению файла или jsonl")
+	category := fs.String("category", "", "slug категории, пусто — весь каталог")
+	out := fs.String("o", "", "файл выгрузки, по умолчанию stdout")
+	fs.Parse(args)
+
+	if *format == "" {
+		*format = catalogfile.FormatJSONL
+		if *out != "" {
+			f, err := catalogfile.DetectFormat(*out)
+			if err != nil {
+				return err
+			}
+			*format = f
+		}
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		file, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		w = file
+	}
+	writer, err := catalogfile.NewWriter(w, *format)
+	if err != nil {
+		return err
+	}
+
+	client, conn, err := dial(*addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	stream, err := client.ExportInstruments(context.Background(), &proto.ExportInstrumentsRequest{Category: *category})
+	if err != nil {
+		return err
+	}
+	n := 0
+	for {
+		inst, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if err := writer.Write(catalogfile.RowFromProto(inst)); err != nil {
+			return err
+		}
+		n++
+	}
+	if err := writer.Flush(); err != nil {
+		return err
+	}
+	log.Printf("Выгружено инструментов: %d", n)
+	return nil
+}
+
+func dial(addr string) (proto.InstrumentServiceClient, *grpc.ClientConn, error) {
+	conn, err := grpc.Dial(addr, grpc.WithInsecure())
+	if err != nil {
+		return nil, nil, err
+	}
+	return proto.NewInstrumentServiceClient(conn), conn, nil
+}
@@ -0,0 +1,117 @@
+package catalogfile
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func readAll(t *testing.T, r Reader) ([]*Row, []*RowError) {
+	var rows []*Row
+	var rowErrors []*RowError
+	for {
+		_, row, err := r.Read()
+		if err == io.EOF {
+			return rows, rowErrors
+		}
+		var rowErr *RowError
+		if errors.As(err, &rowErr) {
+			rowErrors = append(rowErrors, rowErr)
+			continue
+		}
+		assert.NoError(t, err)
+		rows = append(rows, row)
+	}
+}
+
+func TestCSVReader(t *testing.T) {
+	data := "\ufeffName,price,stock,variants\n" +
+		"Fender Stratocaster,\"1299,5\",3,\n" +
+		"Yamaha P-45,abc,1,\n" +
+		"Ibanez RG,899,,\"[{\"\"sku\"\":\"\"RG-BK\"\",\"\"options\"\":{\"\"color\"\":\"\"black\"\"},\"\"stock\"\":2}]\"\n"
+
+	r, err := NewReader(strings.NewReader(data), FormatCSV)
+	assert.NoError(t, err)
+	rows, rowErrors := readAll(t, r)
+
+	assert.Len(t, rows, 2)
+	assert.Equal(t, "Fender Stratocaster", rows[0].Name)
+	assert.Equal(t, 1299.5, rows[0].Price)
+	assert.Equal(t, int32(3), rows[0].Stock)
+	assert.Equal(t, "RG-BK", rows[1].Variants[0].SKU)
+
+	assert.Len(t, rowErrors, 1)
+	assert.Equal(t, 3, rowErrors[0].Line)
+}
+
+func TestCSVReaderRejectsUnknownColumn(t *testing.T) {
+	_, err := NewReader(strings.NewReader("name,prise\n"), FormatCSV)
+	assert.Error(t, err)
+
+	_, err = NewReader(strings.NewReader("price,stock\n"), FormatCSV)
+	assert.Error(t, err)
+}
+
+func TestJSONLReader(t *testing.T) {
+	data := `{"name":"Roland TD-07","price":650,"stock":4,"attributes":{"pads":8,"mesh":true}}
+
+{"name":"Korg B2","prise":400}
+`
+	r, err := NewReader(strings.NewReader(data), FormatJSONL)
+	assert.NoError(t, err)
+	rows, rowErrors := readAll(t, r)
+
+	assert.Len(t, rows, 1)
+	attrs := rows[0].ToProto().Attributes
+	assert.Equal(t, "mesh", attrs[0].Key)
+	assert.True(t, attrs[0].BoolValue)
+	assert.Equal(t, 8.0, attrs[1].NumberValue)
+
+	assert.Len(t, rowErrors, 1)
+	assert.Equal(t, 3, rowErrors[0].Line)
+}
+
+func TestRoundTrip(t *testing.T) {
+	row := &Row{
+		Name:       "Gibson Les Paul, Standard",
+		Price:      2499,
+		Category:   "electric-guitars",
+		Stock:      5,
+		Attributes: map[string]interface{}{"strings": 6.0, "pickups": "humbucker"},
+		Variants: []Variant{
+			{SKU: "LP-RED", Options: map[string]string{"color": "red"}, Stock: 2},
+			{SKU: "LP-SUN", Options: map[string]string{"color": "sunburst"}, Price: 2599, Stock: 3},
+		},
+	}
+
+	for _, format := range []string{FormatCSV, FormatJSONL} {
+		var buf bytes.Buffer
+		w, err := NewWriter(&buf, format)
+		assert.NoError(t, err)
+		assert.NoError(t, w.Write(row))
+		assert.NoError(t, w.Flush())
+
+		r, err := NewReader(&buf, format)
+		assert.NoError(t, err)
+		rows, rowErrors := readAll(t, r)
+		assert.Empty(t, rowErrors)
+		assert.Equal(t, []*Row{row}, rows, format)
+	}
+}
+
+func TestDetectFormat(t *testing.T) {
+	f, err := DetectFormat("supplier.CSV")
+	assert.NoError(t, err)
+	assert.Equal(t, FormatCSV, f)
+
+	f, err = DetectFormat("catalog.ndjson")
+	assert.NoError(t, err)
+	assert.Equal(t, FormatJSONL, f)
+
+	_, err = DetectFormat("catalog.xlsx")
+	assert.Error(t, err)
+}
@@ -0,0 +1,197 @@
+package catalogfile
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// csvColumns — столбцы выгрузки. attributes и variants хранятся как JSON,
+// как в JSON Lines.
+var csvColumns = []string{
+	"name", "description", "price", "weight_kg", "length_cm", "width_cm", "height_cm",
+	"tax_category", "category", "brand", "max_cart_quantity", "stock", "attributes", "variants",
+}
+
+type csvReader struct {
+	r       *csv.Reader
+	columns []string
+}
+
+// newCSVReader читает заголовок. Столбцы могут идти в любом порядке,
+// кроме name все необязательны.
+func newCSVReader(r io.Reader) (*csvReader, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, errors.New("пустой файл")
+	}
+	if err != nil {
+		return nil, err
+	}
+	known := map[string]bool{}
+	for _, c := range csvColumns {
+		known[c] = true
+	}
+	seen := map[string]bool{}
+	for i, c := range header {
+		// Excel сохраняет CSV в UTF-8 с BOM
+		c = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(c, "\ufeff")))
+		if !known[c] {
+			return nil, fmt.Errorf("неизвестный столбец %q", header[i])
+		}
+		if seen[c] {
+			return nil, fmt.Errorf("столбец %q указан дважды", c)
+		}
+		seen[c] = true
+		header[i] = c
+	}
+	if !seen["name"] {
+		return nil, errors.New("нет столбца name")
+	}
+	return &csvReader{r: cr, columns: header}, nil
+}
+
+func (c *csvReader) Read() (int, *Row, error) {
+	record, err := c.r.Read()
+	if err == io.EOF {
+		return 0, nil, io.EOF
+	}
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return parseErr.StartLine, nil, &RowError{Line: parseErr.StartLine, Err: parseErr.Err}
+	}
+	if err != nil {
+		return 0, nil, err
+	}
+	line, _ := c.r.FieldPos(0)
+	if len(record) != len(c.columns) {
+		return line, nil, &RowError{Line: line, Err: fmt.Errorf("ожидалось столбцов: %d, получено: %d", len(c.columns), len(record))}
+	}
+
+	row := &Row{}
+	for i, value := range record {
+		if err := setCSVField(row, c.columns[i], strings.TrimSpace(value)); err != nil {
+			return line, nil, &RowError{Line: line, Err: fmt.Errorf("%s: %w", c.columns[i], err)}
+		}
+	}
+	return line, row, nil
+}
+
+func setCSVField(row *Row, column, value string) error {
+	var err error
+	switch column {
+	case "name":
+		row.Name = value
+	case "description":
+		row.Description = value
+	case "tax_category":
+		row.TaxCategory = value
+	case "category":
+		row.Category = value
+	case "brand":
+		row.Brand = value
+	case "price":
+		row.Price, err = parseFloat(value)
+	case "weight_kg":
+		row.WeightKg, err = parseFloat(value)
+	case "length_cm":
+		row.LengthCm, err = parseFloat(value)
+	case "width_cm":
+		row.WidthCm, err = parseFloat(value)
+	case "height_cm":
+		row.HeightCm, err = parseFloat(value)
+	case "max_cart_quantity":
+		row.MaxCartQuantity, err = parseInt32(value)
+	case "stock":
+		row.Stock, err = parseInt32(value)
+	case "attributes":
+		if value != "" {
+			err = json.Unmarshal([]byte(value), &row.Attributes)
+		}
+	case "variants":
+		if value != "" {
+			err = json.Unmarshal([]byte(value), &row.Variants)
+		}
+	}
+	return err
+}
+
+func parseFloat(value string) (float64, error) {
+	if value == "" {
+		return 0, nil
+	}
+	// в прайсах поставщиков встречается десятичная запятая
+	return strconv.ParseFloat(strings.Replace(value, ",", ".", 1), 64)
+}
+
+func parseInt32(value string) (int32, error) {
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.ParseInt(value, 10, 32)
+	return int32(n), err
+}
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func newCSVWriter(w io.Writer) (*csvWriter, error) {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvColumns); err != nil {
+		return nil, err
+	}
+	return &csvWriter{w: cw}, nil
+}
+
+func (c *csvWriter) Write(row *Row) error {
+	attributes, err := jsonCell(row.Attributes, len(row.Attributes) == 0)
+	if err != nil {
+		return err
+	}
+	variants, err := jsonCell(row.Variants, len(row.Variants) == 0)
+	if err != nil {
+		return err
+	}
+	return c.w.Write([]string{
+		row.Name,
+		row.Description,
+		formatFloat(row.Price),
+		formatFloat(row.WeightKg),
+		formatFloat(row.LengthCm),
+		formatFloat(row.WidthCm),
+		formatFloat(row.HeightCm),
+		row.TaxCategory,
+		row.Category,
+		row.Brand,
+		strconv.Itoa(int(row.MaxCartQuantity)),
+		strconv.Itoa(int(row.Stock)),
+		attributes,
+		variants,
+	})
+}
+
+func (c *csvWriter) Flush() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func jsonCell(v interface{}, empty bool) (string, error) {
+	if empty {
+		return "", nil
+	}
+	data, err := json.Marshal(v)
+	return string(data), err
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -0,0 +1,67 @@
+package catalogfile
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+)
+
+// maxJSONLLine — предельная длина строки JSON Lines, с запасом на
+// описание и варианты.
+const maxJSONLLine = 1 << 20
+
+type jsonlReader struct {
+	s    *bufio.Scanner
+	line int
+}
+
+func newJSONLReader(r io.Reader) *jsonlReader {
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 64<<10), maxJSONLLine)
+	return &jsonlReader{s: s}
+}
+
+// Read пропускает пустые строки. Неизвестные поля считаются ошибкой
+// строки, чтобы опечатка в названии поля не теряла данные молча.
+func (j *jsonlReader) Read() (int, *Row, error) {
+	for j.s.Scan() {
+		j.line++
+		data := bytes.TrimSpace(j.s.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		row := &Row{}
+		if err := dec.Decode(row); err != nil {
+			return j.line, nil, &RowError{Line: j.line, Err: err}
+		}
+		return j.line, row, nil
+	}
+	if err := j.s.Err(); err != nil {
+		return 0, nil, err
+	}
+	return 0, nil, io.EOF
+}
+
+type jsonlWriter struct {
+	buf *bufio.Writer
+	enc *json.Encoder
+}
+
+func newJSONLWriter(w io.Writer) *jsonlWriter {
+	buf := bufio.NewWriter(w)
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	return &jsonlWriter{buf: buf, enc: enc}
+}
+
+func (j *jsonlWriter) Write(row *Row) error {
+	return j.enc.Encode(row)
+}
+
+func (j *jsonlWriter) Flush() error {
+	return j.buf.Flush()
+}
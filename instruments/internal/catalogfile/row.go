@@ -0,0 +1,192 @@
+// Package catalogfile читает и пишет каталог инструментов в CSV и JSON
+// Lines. Оба формата содержат одни и те же поля, поэтому выгруженный
+// каталог можно загрузить обратно.
+package catalogfile
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gotune/instruments/proto"
+)
+
+const (
+	FormatCSV   = "csv"
+	FormatJSONL = "jsonl"
+)
+
+// Row — строка каталога. Атрибуты: строка — значение enum, число —
+// number, true/false — boolean.
+type Row struct {
+	Name            string                 `json:"name"`
+	Description     string                 `json:"description,omitempty"`
+	Price           float64                `json:"price"`
+	WeightKg        float64                `json:"weight_kg,omitempty"`
+	LengthCm        float64                `json:"length_cm,omitempty"`
+	WidthCm         float64                `json:"width_cm,omitempty"`
+	HeightCm        float64                `json:"height_cm,omitempty"`
+	TaxCategory     string                 `json:"tax_category,omitempty"`
+	Category        string                 `json:"category,omitempty"`
+	Brand           string                 `json:"brand,omitempty"`
+	MaxCartQuantity int32                  `json:"max_cart_quantity,omitempty"`
+	Stock           int32                  `json:"stock"`
+	Attributes      map[string]interface{} `json:"attributes,omitempty"`
+	Variants        []Variant              `json:"variants,omitempty"`
+}
+
+type Variant struct {
+	SKU     string            `json:"sku"`
+	Options map[string]string `json:"options"`
+	Price   float64           `json:"price,omitempty"`
+	Stock   int32             `json:"stock"`
+}
+
+// RowError — строка, которую не удалось разобрать.
+type RowError struct {
+	Line int
+	Err  error
+}
+
+func (e *RowError) Error() string {
+	return fmt.Sprintf("строка %d: %v", e.Line, e.Err)
+}
+
+// DetectFormat определяет формат по расширению файла.
+func DetectFormat(path string) (string, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return FormatCSV, nil
+	case ".jsonl", ".ndjson":
+		return FormatJSONL, nil
+	}
+	return "", fmt.Errorf("не удалось определить формат файла %s, укажите csv или jsonl", path)
+}
+
+func (r *Row) ToProto() *proto.CreateInstrumentRequest {
+	req := &proto.CreateInstrumentRequest{
+		Name:            r.Name,
+		Description:     r.Description,
+		Price:           r.Price,
+		WeightKg:        r.WeightKg,
+		LengthCm:        r.LengthCm,
+		WidthCm:         r.WidthCm,
+		HeightCm:        r.HeightCm,
+		TaxCategory:     r.TaxCategory,
+		Category:        r.Category,
+		Brand:           r.Brand,
+		MaxCartQuantity: r.MaxCartQuantity,
+		Stock:           r.Stock,
+		Attributes:      attributeValues(r.Attributes),
+	}
+	for _, v := range r.Variants {
+		req.Variants = append(req.Variants, &proto.Variant{
+			Sku:     v.SKU,
+			Options: v.Options,
+			Price:   v.Price,
+			Stock:   v.Stock,
+		})
+	}
+	return req
+}
+
+func RowFromProto(inst *proto.Instrument) *Row {
+	r := &Row{
+		Name:            inst.Name,
+		Description:     inst.Description,
+		Price:           inst.Price,
+		WeightKg:        inst.WeightKg,
+		LengthCm:        inst.LengthCm,
+		WidthCm:         inst.WidthCm,
+		HeightCm:        inst.HeightCm,
+		TaxCategory:     inst.TaxCategory,
+		Category:        inst.Category,
+		Brand:           inst.Brand,
+		MaxCartQuantity: inst.MaxCartQuantity,
+		Stock:           inst.Stock,
+	}
+	// тип атрибута в ответе не передаётся: нулевое число выгружается как
+	// false, и при загрузке сервис всё равно прочитает его как 0
+	if len(inst.Attributes) > 0 {
+		r.Attributes = map[string]interface{}{}
+		for _, a := range inst.Attributes {
+			switch {
+			case a.EnumValue != "":
+				r.Attributes[a.Key] = a.EnumValue
+			case a.NumberValue != 0:
+				r.Attributes[a.Key] = a.NumberValue
+			default:
+				r.Attributes[a.Key] = a.BoolValue
+			}
+		}
+	}
+	for _, v := range inst.Variants {
+		r.Variants = append(r.Variants, Variant{
+			SKU:     v.Sku,
+			Options: v.Options,
+			Price:   v.Price,
+			Stock:   v.Stock,
+		})
+	}
+	return r
+}
+
+func attributeValues(attrs map[string]interface{}) []*proto.AttributeValue {
+	var result []*proto.AttributeValue
+	for _, k := range sortedKeys(attrs) {
+		v := &proto.AttributeValue{Key: k}
+		switch value := attrs[k].(type) {
+		case string:
+			v.EnumValue = value
+		case float64:
+			v.NumberValue = value
+		case bool:
+			v.BoolValue = value
+		}
+		result = append(result, v)
+	}
+	return result
+}
+
+// Reader читает строки каталога. Read возвращает номер строки в файле;
+// io.EOF — конец файла, *RowError — строку не удалось разобрать, и
+// чтение можно продолжать.
+type Reader interface {
+	Read() (int, *Row, error)
+}
+
+type Writer interface {
+	Write(row *Row) error
+	Flush() error
+}
+
+func NewReader(r io.Reader, format string) (Reader, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVReader(r)
+	case FormatJSONL:
+		return newJSONLReader(r), nil
+	}
+	return nil, fmt.Errorf("неизвестный формат %q", format)
+}
+
+func NewWriter(w io.Writer, format string) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w)
+	case FormatJSONL:
+		return newJSONLWriter(w), nil
+	}
+	return nil, fmt.Errorf("неизвестный формат %q", format)
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,37 @@
+package entity
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	AttributeEnum    = "enum"
+	AttributeNumber  = "number"
+	AttributeBoolean = "boolean"
+)
+
+type Category struct {
+	ID     primitive.ObjectID `bson:"_id,omitempty"`
+	Slug   string             `bson:"slug"`
+	Name   string             `bson:"name"`
+	Parent string             `bson:"parent,omitempty"`
+	// slug всех предков от корня и самой категории
+	Path []string `bson:"path"`
+	// Собственные атрибуты; атрибуты предков наследуются
+	Attributes []AttributeDef `bson:"attributes,omitempty"`
+}
+
+type AttributeDef struct {
+	Key      string   `bson:"key"`
+	Name     string   `bson:"name"`
+	Type     string   `bson:"type"`
+	Values   []string `bson:"values,omitempty"`
+	Unit     string   `bson:"unit,omitempty"`
+	Required bool     `bson:"required,omitempty"`
+}
+
+type Brand struct {
+	ID   primitive.ObjectID `bson:"_id,omitempty"`
+	Slug string             `bson:"slug"`
+	Name string             `bson:"name"`
+}
@@ -1,6 +1,8 @@
 package entity
 
 import (
+	"time"
+
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -9,4 +11,78 @@ type Instrument struct {
 	Name        string             `bson:"name"`
 	Description string             `bson:"description"`
 	Price       float64            `bson:"price"`
+	Category    string             `bson:"category,omitempty"`
+	Brand       string             `bson:"brand,omitempty"`
+	// slug категории и всех её предков, по нему фильтруется поддерево
+	CategoryPath []string `bson:"category_path,omitempty"`
+	// Значения атрибутов категории: string для enum, float64 для number,
+	// bool для boolean
+	Attributes map[string]interface{} `bson:"attributes,omitempty"`
+	// Габариты упаковки нужны для расчёта стоимости доставки
+	WeightKg float64 `bson:"weight_kg"`
+	LengthCm float64 `bson:"length_cm"`
+	WidthCm  float64 `bson:"width_cm"`
+	HeightCm float64 `bson:"height_cm"`
+	// Налоговая категория товара, см. правила налогов в сервисе заказов
+	TaxCategory string `bson:"tax_category,omitempty"`
+	// Максимальное количество в одной корзине, 0 — без ограничения
+	MaxCartQuantity int32 `bson:"max_cart_quantity,omitempty"`
+	// Остаток на складе, 0 — нет в наличии. У товара с вариантами —
+	// сумма остатков вариантов
+	Stock    int32     `bson:"stock"`
+	Variants []Variant `bson:"variants,omitempty"`
+	Media    []Media   `bson:"media,omitempty"`
+	// Версия документа для оптимистической блокировки, растёт при
+	// каждом изменении
+	Version int64 `bson:"version"`
+	// Средняя оценка и число одобренных отзывов, пересчитываются при
+	// модерации и не меняют версию
+	RatingAverage float64 `bson:"rating_average,omitempty"`
+	RatingCount   int32   `bson:"rating_count,omitempty"`
+	// Б/у товар: единственный экземпляр из trade-in со своим состоянием
+	Used      bool                `bson:"used,omitempty"`
+	Condition string              `bson:"condition,omitempty"`
+	TradeInID *primitive.ObjectID `bson:"trade_in_id,omitempty"`
+	// Время мягкого удаления; удалённый инструмент скрыт из каталога,
+	// пока его не восстановят или не удалит задача очистки
+	DeletedAt *time.Time `bson:"deleted_at,omitempty"`
+}
+
+type Variant struct {
+	SKU     string            `bson:"sku"`
+	Options map[string]string `bson:"options"`
+	// 0 — цена инструмента
+	Price float64 `bson:"price,omitempty"`
+	Stock int32   `bson:"stock"`
+}
+
+const (
+	MediaImage = "image"
+	MediaAudio = "audio"
+)
+
+// Media — файл в хранилище; Key и ThumbnailKey нужны для удаления,
+// адреса сохраняются при загрузке.
+type Media struct {
+	ID              primitive.ObjectID `bson:"_id"`
+	Kind            string             `bson:"kind"`
+	Filename        string             `bson:"filename,omitempty"`
+	Key             string             `bson:"key"`
+	URL             string             `bson:"url"`
+	ThumbnailKey    string             `bson:"thumbnail_key,omitempty"`
+	ThumbnailURL    string             `bson:"thumbnail_url,omitempty"`
+	ContentType     string             `bson:"content_type"`
+	SizeBytes       int64              `bson:"size_bytes"`
+	Width           int32              `bson:"width,omitempty"`
+	Height          int32              `bson:"height,omitempty"`
+	DurationSeconds float64            `bson:"duration_seconds,omitempty"`
+	CreatedAt       time.Time          `bson:"created_at"`
+}
+
+// UnitPrice возвращает цену варианта или цену инструмента.
+func (i *Instrument) UnitPrice(v *Variant) float64 {
+	if v != nil && v.Price > 0 {
+		return v.Price
+	}
+	return i.Price
 }
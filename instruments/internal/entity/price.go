@@ -0,0 +1,53 @@
+package entity
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	PriceSourceManual   = "manual"
+	PriceSourceSchedule = "schedule"
+)
+
+// PriceChange — запись истории цен инструмента.
+type PriceChange struct {
+	ID           primitive.ObjectID  `bson:"_id,omitempty"`
+	InstrumentID primitive.ObjectID  `bson:"instrument_id"`
+	OldPrice     float64             `bson:"old_price"`
+	NewPrice     float64             `bson:"new_price"`
+	Source       string              `bson:"source"`
+	ScheduleID   *primitive.ObjectID `bson:"schedule_id,omitempty"`
+	ChangedAt    time.Time           `bson:"changed_at"`
+}
+
+const (
+	ScheduleStatusPending   = "pending"
+	ScheduleStatusActive    = "active"
+	ScheduleStatusCompleted = "completed"
+	ScheduleStatusCancelled = "cancelled"
+)
+
+// PriceSchedule — запланированное изменение цены. OriginalPrice
+// запоминается при начале и возвращается по окончании; EndsAt == nil —
+// изменение без окончания.
+type PriceSchedule struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty"`
+	InstrumentID  primitive.ObjectID `bson:"instrument_id"`
+	Price         float64            `bson:"price"`
+	StartsAt      time.Time          `bson:"starts_at"`
+	EndsAt        *time.Time         `bson:"ends_at,omitempty"`
+	Status        string             `bson:"status"`
+	OriginalPrice float64            `bson:"original_price,omitempty"`
+	CreatedAt     time.Time          `bson:"created_at"`
+}
+
+// Overlaps сообщает, пересекаются ли периоды действия двух расписаний.
+func (p *PriceSchedule) Overlaps(other *PriceSchedule) bool {
+	return endsAfter(p.EndsAt, other.StartsAt) && endsAfter(other.EndsAt, p.StartsAt)
+}
+
+func endsAfter(end *time.Time, t time.Time) bool {
+	return end == nil || end.After(t)
+}
@@ -0,0 +1,29 @@
+package entity
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	ReviewStatusPending  = "pending"
+	ReviewStatusApproved = "approved"
+	ReviewStatusRejected = "rejected"
+)
+
+// Review — отзыв покупателя на инструмент, один от пользователя.
+// HelpfulVoters хранит проголосовавших, чтобы голос учитывался один раз.
+type Review struct {
+	ID             primitive.ObjectID   `bson:"_id,omitempty"`
+	InstrumentID   primitive.ObjectID   `bson:"instrument_id"`
+	UserID         primitive.ObjectID   `bson:"user_id"`
+	Rating         int32                `bson:"rating"`
+	Text           string               `bson:"text"`
+	Status         string               `bson:"status"`
+	HelpfulVotes   int32                `bson:"helpful_votes"`
+	HelpfulVoters  []primitive.ObjectID `bson:"helpful_voters,omitempty"`
+	ModerationNote string               `bson:"moderation_note,omitempty"`
+	CreatedAt      time.Time            `bson:"created_at"`
+	ModeratedAt    *time.Time           `bson:"moderated_at,omitempty"`
+}
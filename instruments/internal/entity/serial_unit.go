@@ -0,0 +1,58 @@
+package entity
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	SerialStatusInStock  = "in_stock"
+	SerialStatusReserved = "reserved"
+	SerialStatusSold     = "sold"
+	SerialStatusRented   = "rented"
+)
+
+// Градации состояния экземпляра, от нового к изношенному.
+const (
+	ConditionNew       = "new"
+	ConditionMint      = "mint"
+	ConditionExcellent = "excellent"
+	ConditionVeryGood  = "very_good"
+	ConditionGood      = "good"
+	ConditionFair      = "fair"
+	ConditionPoor      = "poor"
+)
+
+func ValidCondition(c string) bool {
+	switch c {
+	case ConditionNew, ConditionMint, ConditionExcellent, ConditionVeryGood,
+		ConditionGood, ConditionFair, ConditionPoor:
+		return true
+	}
+	return false
+}
+
+// SerialUnit — экземпляр инструмента с серийным номером. OrderID, SoldTo
+// и SoldAt заполняются, когда экземпляр выделен заказу при отгрузке.
+type SerialUnit struct {
+	ID           primitive.ObjectID  `bson:"_id,omitempty"`
+	InstrumentID primitive.ObjectID  `bson:"instrument_id"`
+	SKU          string              `bson:"sku"`
+	SerialNumber string              `bson:"serial_number"`
+	Condition    string              `bson:"condition"`
+	Location     string              `bson:"location,omitempty"`
+	Status       string              `bson:"status"`
+	OrderID      *primitive.ObjectID `bson:"order_id,omitempty"`
+	SoldTo       *primitive.ObjectID `bson:"sold_to,omitempty"`
+	SoldAt       *time.Time          `bson:"sold_at,omitempty"`
+	Warranty     *Warranty           `bson:"warranty,omitempty"`
+	CreatedAt    time.Time           `bson:"created_at"`
+	UpdatedAt    time.Time           `bson:"updated_at"`
+}
+
+type Warranty struct {
+	UserID       primitive.ObjectID `bson:"user_id"`
+	RegisteredAt time.Time          `bson:"registered_at"`
+	ExpiresAt    time.Time          `bson:"expires_at"`
+}
@@ -0,0 +1,40 @@
+package entity
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	TradeInStatusSubmitted = "submitted"
+	TradeInStatusOffered   = "offered"
+	TradeInStatusRejected  = "rejected"
+	TradeInStatusAccepted  = "accepted"
+	TradeInStatusDeclined  = "declined"
+	TradeInStatusReceived  = "received"
+	TradeInStatusListed    = "listed"
+)
+
+// TradeIn — заявка покупателя на сдачу инструмента в зачёт. ListingID
+// назначается при получении инструмента, до создания товара, чтобы
+// повторное получение после сбоя не создало второй товар.
+type TradeIn struct {
+	ID                 primitive.ObjectID  `bson:"_id,omitempty"`
+	UserID             primitive.ObjectID  `bson:"user_id"`
+	Name               string              `bson:"name"`
+	Brand              string              `bson:"brand,omitempty"`
+	Description        string              `bson:"description,omitempty"`
+	SerialNumber       string              `bson:"serial_number,omitempty"`
+	Condition          string              `bson:"condition"`
+	Photos             []Media             `bson:"photos,omitempty"`
+	Status             string              `bson:"status"`
+	AppraisedCondition string              `bson:"appraised_condition,omitempty"`
+	OfferAmount        float64             `bson:"offer_amount,omitempty"`
+	OfferNote          string              `bson:"offer_note,omitempty"`
+	ListingID          *primitive.ObjectID `bson:"listing_id,omitempty"`
+	CreatedAt          time.Time           `bson:"created_at"`
+	OfferedAt          *time.Time          `bson:"offered_at,omitempty"`
+	RespondedAt        *time.Time          `bson:"responded_at,omitempty"`
+	ReceivedAt         *time.Time          `bson:"received_at,omitempty"`
+}
@@ -0,0 +1,156 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"time"
+)
+
+const (
+	AudioWAV  = "wav"
+	AudioMP3  = "mp3"
+	AudioFLAC = "flac"
+)
+
+var ErrUnsupportedAudio = errors.New("неподдерживаемый формат аудио, допустимы WAV, MP3 и FLAC")
+
+type AudioInfo struct {
+	Format      string
+	ContentType string
+	Duration    time.Duration
+	SampleRate  int
+}
+
+// ProbeAudio определяет формат и длительность аудиофайла по его
+// заголовкам, не декодируя звук.
+func ProbeAudio(data []byte) (*AudioInfo, error) {
+	switch {
+	case len(data) >= 12 && string(data[:4]) == "RIFF" && string(data[8:12]) == "WAVE":
+		return probeWAV(data)
+	case len(data) >= 4 && string(data[:4]) == "fLaC":
+		return probeFLAC(data)
+	default:
+		return probeMP3(data)
+	}
+}
+
+func probeWAV(data []byte) (*AudioInfo, error) {
+	var sampleRate, byteRate uint32
+	for pos := 12; pos+8 <= len(data); {
+		id := string(data[pos : pos+4])
+		size := binary.LittleEndian.Uint32(data[pos+4 : pos+8])
+		body := data[pos+8:]
+
+		switch id {
+		case "fmt ":
+			if len(body) < 16 {
+				return nil, ErrUnsupportedAudio
+			}
+			sampleRate = binary.LittleEndian.Uint32(body[4:8])
+			byteRate = binary.LittleEndian.Uint32(body[8:12])
+		case "data":
+			if byteRate == 0 {
+				return nil, ErrUnsupportedAudio
+			}
+			// размер data может быть больше файла у обрезанных записей
+			size = min(size, uint32(len(body)))
+			return &AudioInfo{
+				Format:      AudioWAV,
+				ContentType: "audio/wav",
+				Duration:    time.Duration(float64(size) / float64(byteRate) * float64(time.Second)),
+				SampleRate:  int(sampleRate),
+			}, nil
+		}
+		// чанки выравниваются по чётной границе
+		pos += 8 + int(size) + int(size%2)
+	}
+	return nil, ErrUnsupportedAudio
+}
+
+// probeFLAC читает блок STREAMINFO, который всегда идёт первым.
+func probeFLAC(data []byte) (*AudioInfo, error) {
+	if len(data) < 8+34 || data[4]&0x7f != 0 {
+		return nil, ErrUnsupportedAudio
+	}
+	info := data[8 : 8+34]
+	packed := binary.BigEndian.Uint64(info[10:18])
+	sampleRate := int(packed >> 44)
+	totalSamples := packed & (1<<36 - 1)
+	if sampleRate == 0 || totalSamples == 0 {
+		return nil, ErrUnsupportedAudio
+	}
+	return &AudioInfo{
+		Format:      AudioFLAC,
+		ContentType: "audio/flac",
+		Duration:    time.Duration(float64(totalSamples) / float64(sampleRate) * float64(time.Second)),
+		SampleRate:  sampleRate,
+	}, nil
+}
+
+var (
+	mp3BitratesV1 = [16]int{0, 32, 40, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320, 0}
+	mp3BitratesV2 = [16]int{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160, 0}
+	mp3Rates      = [3]int{44100, 48000, 32000}
+)
+
+// probeMP3 пропускает тег ID3v2 и суммирует длительность кадров
+// MPEG Layer III, поэтому работает и для VBR.
+func probeMP3(data []byte) (*AudioInfo, error) {
+	pos := 0
+	if len(data) >= 10 && bytes.Equal(data[:3], []byte("ID3")) {
+		size := int(data[6])<<21 | int(data[7])<<14 | int(data[8])<<7 | int(data[9])
+		pos = 10 + size
+		if data[5]&0x10 != 0 {
+			pos += 10
+		}
+	}
+
+	var seconds float64
+	var sampleRate, frames int
+	for pos+4 <= len(data) {
+		h := binary.BigEndian.Uint32(data[pos : pos+4])
+		if h>>21 != 0x7ff {
+			break
+		}
+		version := (h >> 19) & 3 // 3 — MPEG1, 2 — MPEG2, 0 — MPEG2.5
+		layer := (h >> 17) & 3   // 1 — Layer III
+		bitrateIdx := (h >> 12) & 0xf
+		rateIdx := (h >> 10) & 3
+		padding := int((h >> 9) & 1)
+		if version == 1 || layer != 1 || rateIdx == 3 {
+			break
+		}
+
+		rate := mp3Rates[rateIdx]
+		bitrate := mp3BitratesV1[bitrateIdx]
+		frameSamples, coeff := 1152, 144
+		switch version {
+		case 2:
+			rate /= 2
+			bitrate = mp3BitratesV2[bitrateIdx]
+			frameSamples, coeff = 576, 72
+		case 0:
+			rate /= 4
+			bitrate = mp3BitratesV2[bitrateIdx]
+			frameSamples, coeff = 576, 72
+		}
+		if bitrate == 0 {
+			break
+		}
+
+		sampleRate = rate
+		seconds += float64(frameSamples) / float64(rate)
+		frames++
+		pos += coeff*bitrate*1000/rate + padding
+	}
+	if frames == 0 {
+		return nil, ErrUnsupportedAudio
+	}
+	return &AudioInfo{
+		Format:      AudioMP3,
+		ContentType: "audio/mpeg",
+		Duration:    time.Duration(seconds * float64(time.Second)),
+		SampleRate:  sampleRate,
+	}, nil
+}
@@ -0,0 +1,97 @@
+package media
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+
+	// декодеры поддерживаемых форматов
+	_ "image/gif"
+	_ "image/png"
+)
+
+// ThumbnailSize — длина большей стороны миниатюры в пикселях.
+const ThumbnailSize = 320
+
+// MaxImagePixels ограничивает размер декодируемого изображения: сжатый
+// файл небольшого объёма может объявить размеры, на которые при
+// декодировании уйдут гигабайты памяти.
+const MaxImagePixels = 40_000_000
+
+var (
+	ErrUnsupportedImage = errors.New("неподдерживаемый формат изображения")
+	ErrImageTooLarge    = errors.New("изображение больше 40 мегапикселей")
+)
+
+type ImageInfo struct {
+	Format string
+	Width  int
+	Height int
+}
+
+// DecodeImage декодирует JPEG, PNG или GIF. Размеры проверяются по
+// заголовку до декодирования.
+func DecodeImage(data []byte) (image.Image, *ImageInfo, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, nil, ErrUnsupportedImage
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || int64(cfg.Width)*int64(cfg.Height) > MaxImagePixels {
+		return nil, nil, ErrImageTooLarge
+	}
+
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, nil, ErrUnsupportedImage
+	}
+	b := img.Bounds()
+	return img, &ImageInfo{Format: format, Width: b.Dx(), Height: b.Dy()}, nil
+}
+
+// Thumbnail уменьшает изображение так, чтобы большая сторона была не
+// больше maxSide, с сохранением пропорций. Каждый пиксель миниатюры —
+// среднее по соответствующей области исходника. Изображения меньше
+// maxSide не увеличиваются.
+func Thumbnail(src image.Image, maxSide int) image.Image {
+	b := src.Bounds()
+	sw, sh := b.Dx(), b.Dy()
+	dw, dh := sw, sh
+	if sw >= sh && sw > maxSide {
+		dw, dh = maxSide, max(1, sh*maxSide/sw)
+	} else if sh > sw && sh > maxSide {
+		dw, dh = max(1, sw*maxSide/sh), maxSide
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		y0, y1 := y*sh/dh, max((y+1)*sh/dh, y*sh/dh+1)
+		for x := 0; x < dw; x++ {
+			x0, x1 := x*sw/dw, max((x+1)*sw/dw, x*sw/dw+1)
+
+			var r, g, bl, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					c := color.RGBAModel.Convert(src.At(b.Min.X+sx, b.Min.Y+sy)).(color.RGBA)
+					r += uint64(c.R)
+					g += uint64(c.G)
+					bl += uint64(c.B)
+					a += uint64(c.A)
+					n++
+				}
+			}
+			dst.SetRGBA(x, y, color.RGBA{R: uint8(r / n), G: uint8(g / n), B: uint8(bl / n), A: uint8(a / n)})
+		}
+	}
+	return dst
+}
+
+// EncodeJPEG кодирует миниатюру; прозрачность не сохраняется.
+func EncodeJPEG(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 85}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
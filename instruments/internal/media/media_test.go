@@ -0,0 +1,114 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func wavFile(sampleRate, channels, seconds int) []byte {
+	byteRate := sampleRate * channels * 2
+	dataSize := byteRate * seconds
+
+	var buf bytes.Buffer
+	buf.WriteString("RIFF")
+	binary.Write(&buf, binary.LittleEndian, uint32(36+dataSize))
+	buf.WriteString("WAVEfmt ")
+	binary.Write(&buf, binary.LittleEndian, uint32(16))
+	binary.Write(&buf, binary.LittleEndian, uint16(1))
+	binary.Write(&buf, binary.LittleEndian, uint16(channels))
+	binary.Write(&buf, binary.LittleEndian, uint32(sampleRate))
+	binary.Write(&buf, binary.LittleEndian, uint32(byteRate))
+	binary.Write(&buf, binary.LittleEndian, uint16(channels*2))
+	binary.Write(&buf, binary.LittleEndian, uint16(16))
+	buf.WriteString("data")
+	binary.Write(&buf, binary.LittleEndian, uint32(dataSize))
+	buf.Write(make([]byte, dataSize))
+	return buf.Bytes()
+}
+
+func TestProbeWAV(t *testing.T) {
+	info, err := ProbeAudio(wavFile(8000, 2, 3))
+	assert.NoError(t, err)
+	assert.Equal(t, AudioWAV, info.Format)
+	assert.Equal(t, 3*time.Second, info.Duration)
+	assert.Equal(t, 8000, info.SampleRate)
+}
+
+func TestProbeFLAC(t *testing.T) {
+	data := []byte("fLaC")
+	data = append(data, 0x80, 0, 0, 34) // последний блок, STREAMINFO
+	info := make([]byte, 34)
+	// 44100 Гц, 2 канала, 16 бит, 441000 сэмплов
+	packed := uint64(44100)<<44 | uint64(1)<<41 | uint64(15)<<36 | 441000
+	binary.BigEndian.PutUint64(info[10:18], packed)
+	data = append(data, info...)
+
+	probed, err := ProbeAudio(data)
+	assert.NoError(t, err)
+	assert.Equal(t, AudioFLAC, probed.Format)
+	assert.Equal(t, 10*time.Second, probed.Duration)
+}
+
+func TestProbeMP3(t *testing.T) {
+	// ID3-тег и 100 кадров MPEG1 Layer III, 128 кбит/с, 44100 Гц
+	data := []byte{'I', 'D', '3', 4, 0, 0, 0, 0, 0, 5, 0, 0, 0, 0, 0}
+	frame := make([]byte, 417)
+	copy(frame, []byte{0xff, 0xfb, 0x90, 0x64})
+	for i := 0; i < 100; i++ {
+		data = append(data, frame...)
+	}
+
+	info, err := ProbeAudio(data)
+	assert.NoError(t, err)
+	assert.Equal(t, AudioMP3, info.Format)
+	assert.InDelta(t, 2.612, info.Duration.Seconds(), 0.001)
+}
+
+func TestProbeAudioRejectsOtherFiles(t *testing.T) {
+	_, err := ProbeAudio([]byte("definitely not audio"))
+	assert.ErrorIs(t, err, ErrUnsupportedAudio)
+}
+
+func TestThumbnail(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 1000, 500))
+	for x := 0; x < 1000; x++ {
+		for y := 0; y < 500; y++ {
+			src.Set(x, y, color.RGBA{R: 200, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	assert.NoError(t, png.Encode(&buf, src))
+
+	img, info, err := DecodeImage(buf.Bytes())
+	assert.NoError(t, err)
+	assert.Equal(t, "png", info.Format)
+
+	thumb := Thumbnail(img, ThumbnailSize)
+	assert.Equal(t, image.Rect(0, 0, 320, 160), thumb.Bounds())
+	assert.Equal(t, color.RGBA{R: 200, A: 255}, thumb.At(10, 10))
+
+	small := Thumbnail(image.NewRGBA(image.Rect(0, 0, 100, 200)), ThumbnailSize)
+	assert.Equal(t, image.Rect(0, 0, 100, 200), small.Bounds())
+
+	_, _, err = DecodeImage([]byte("not an image"))
+	assert.ErrorIs(t, err, ErrUnsupportedImage)
+}
+
+func TestDecodeImageRejectsHugeDimensions(t *testing.T) {
+	// заголовок GIF с логическим экраном 65535×65535 без данных
+	var buf bytes.Buffer
+	buf.WriteString("GIF89a")
+	binary.Write(&buf, binary.LittleEndian, uint16(65535))
+	binary.Write(&buf, binary.LittleEndian, uint16(65535))
+	buf.Write([]byte{0, 0, 0})
+
+	_, _, err := DecodeImage(buf.Bytes())
+	assert.ErrorIs(t, err, ErrImageTooLarge)
+}
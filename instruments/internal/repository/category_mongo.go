@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"gotune/instruments/internal/entity"
+)
+
+var (
+	ErrCategoryNotFound = errors.New("категория не найдена")
+	ErrBrandNotFound    = errors.New("бренд не найден")
+	ErrSlugTaken        = errors.New("slug уже занят")
+)
+
+type CategoryRepository interface {
+	Create(ctx context.Context, category *entity.Category) error
+	GetAll(ctx context.Context) ([]entity.Category, error)
+	FindBySlug(ctx context.Context, slug string) (*entity.Category, error)
+	FindBySlugs(ctx context.Context, slugs []string) ([]entity.Category, error)
+}
+
+type categoryRepository struct {
+	collection *mongo.Collection
+}
+
+func NewCategoryRepository(db *mongo.Database) CategoryRepository {
+	return &categoryRepository{
+		collection: db.Collection("categories"),
+	}
+}
+
+func (r *categoryRepository) Create(ctx context.Context, category *entity.Category) error {
+	_, err := r.collection.InsertOne(ctx, category)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrSlugTaken
+	}
+	return err
+}
+
+func (r *categoryRepository) GetAll(ctx context.Context) ([]entity.Category, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "path", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var categories []entity.Category
+	if err := cursor.All(ctx, &categories); err != nil {
+		return nil, err
+	}
+	return categories, nil
+}
+
+func (r *categoryRepository) FindBySlug(ctx context.Context, slug string) (*entity.Category, error) {
+	var category entity.Category
+	err := r.collection.FindOne(ctx, bson.M{"slug": slug}).Decode(&category)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrCategoryNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+func (r *categoryRepository) FindBySlugs(ctx context.Context, slugs []string) ([]entity.Category, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"slug": bson.M{"$in": slugs}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var categories []entity.Category
+	if err := cursor.All(ctx, &categories); err != nil {
+		return nil, err
+	}
+	return categories, nil
+}
+
+type BrandRepository interface {
+	Create(ctx context.Context, brand *entity.Brand) error
+	GetAll(ctx context.Context) ([]entity.Brand, error)
+	FindBySlug(ctx context.Context, slug string) (*entity.Brand, error)
+}
+
+type brandRepository struct {
+	collection *mongo.Collection
+}
+
+func NewBrandRepository(db *mongo.Database) BrandRepository {
+	return &brandRepository{
+		collection: db.Collection("brands"),
+	}
+}
+
+func (r *brandRepository) Create(ctx context.Context, brand *entity.Brand) error {
+	_, err := r.collection.InsertOne(ctx, brand)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrSlugTaken
+	}
+	return err
+}
+
+func (r *brandRepository) GetAll(ctx context.Context) ([]entity.Brand, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "name", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var brands []entity.Brand
+	if err := cursor.All(ctx, &brands); err != nil {
+		return nil, err
+	}
+	return brands, nil
+}
+
+func (r *brandRepository) FindBySlug(ctx context.Context, slug string) (*entity.Brand, error) {
+	var brand entity.Brand
+	err := r.collection.FindOne(ctx, bson.M{"slug": slug}).Decode(&brand)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrBrandNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &brand, nil
+}
@@ -2,18 +2,50 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"time"
+
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
 	"gotune/instruments/internal/entity"
 )
 
+// MaxMediaPerInstrument ограничивает число фото и аудио у инструмента.
+const MaxMediaPerInstrument = 20
+
+var (
+	ErrMediaLimit    = errors.New("превышено число файлов инструмента")
+	ErrMediaNotFound = errors.New("файл инструмента не найден")
+	// ErrVersionConflict — документ изменён после того, как его прочитал
+	// клиент
+	ErrVersionConflict = errors.New("версия инструмента устарела")
+	// ErrOutOfStock — остатка инструмента или варианта не хватает
+	ErrOutOfStock = errors.New("недостаточно товара на складе")
+)
+
 type InstrumentRepository interface {
 	Create(ctx context.Context, instrument *entity.Instrument) (primitive.ObjectID, error)
 	GetAll(ctx context.Context) ([]entity.Instrument, error)
 	FindByID(ctx context.Context, id primitive.ObjectID) (*entity.Instrument, error)
-	DeleteByID(ctx context.Context, id primitive.ObjectID) error
-	UpdateByID(ctx context.Context, id primitive.ObjectID, instrument *entity.Instrument) error
+	SoftDeleteByID(ctx context.Context, id primitive.ObjectID, deletedAt time.Time) error
+	RestoreByID(ctx context.Context, id primitive.ObjectID) error
+	FindDeletedBefore(ctx context.Context, before time.Time, afterID primitive.ObjectID, limit int64) ([]entity.Instrument, error)
+	PurgeByID(ctx context.Context, id primitive.ObjectID) error
+	UpdateByID(ctx context.Context, id primitive.ObjectID, instrument *entity.Instrument, fields ...string) error
+	FindBySKU(ctx context.Context, sku string) (*entity.Instrument, error)
+	FindByName(ctx context.Context, name string) (*entity.Instrument, error)
+	ForEach(ctx context.Context, category string, fn func(*entity.Instrument) error) error
+	SetPrice(ctx context.Context, id primitive.ObjectID, price float64, ifPrice *float64) (*entity.Instrument, error)
+	AdjustStock(ctx context.Context, id primitive.ObjectID, sku string, delta int32) (*entity.Instrument, error)
+	SetRating(ctx context.Context, id primitive.ObjectID, average float64, count int32) error
+	AddMedia(ctx context.Context, id primitive.ObjectID, media *entity.Media) error
+	RemoveMedia(ctx context.Context, id, mediaID primitive.ObjectID) (*entity.Media, error)
+	Search(ctx context.Context, q SearchQuery) ([]entity.Instrument, error)
+	Facets(ctx context.Context, q SearchQuery) (*SearchFacets, error)
 }
 
 type instrumentRepository struct {
@@ -34,8 +66,16 @@ func (r *instrumentRepository) Create(ctx context.Context, instrument *entity.In
 	return res.InsertedID.(primitive.ObjectID), nil
 }
 
+// notDeleted дополняет фильтр условием, исключающим удалённые инструменты.
+// Все методы, кроме методов для удалённых записей, работают только
+// с инструментами каталога.
+func notDeleted(filter bson.M) bson.M {
+	filter["deleted_at"] = bson.M{"$exists": false}
+	return filter
+}
+
 func (r *instrumentRepository) GetAll(ctx context.Context) ([]entity.Instrument, error) {
-	cursor, err := r.collection.Find(ctx, bson.M{})
+	cursor, err := r.collection.Find(ctx, notDeleted(bson.M{}))
 	if err != nil {
 		return nil, err
 	}
@@ -58,26 +98,292 @@ func (r *instrumentRepository) GetAll(ctx context.Context) ([]entity.Instrument,
 
 func (r *instrumentRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*entity.Instrument, error) {
 	var instrument entity.Instrument
-	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&instrument)
+	err := r.collection.FindOne(ctx, notDeleted(bson.M{"_id": id})).Decode(&instrument)
 	if err != nil {
 		return nil, err
 	}
 	return &instrument, nil
 }
 
-func (r *instrumentRepository) DeleteByID(ctx context.Context, id primitive.ObjectID) error {
-	_, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
-	return err
+// SoftDeleteByID помечает инструмент удалённым; документ и файлы остаются,
+// чтобы заказы могли ссылаться на него, а администратор — восстановить.
+func (r *instrumentRepository) SoftDeleteByID(ctx context.Context, id primitive.ObjectID, deletedAt time.Time) error {
+	res, err := r.collection.UpdateOne(ctx, notDeleted(bson.M{"_id": id}), bson.M{
+		"$set": bson.M{"deleted_at": deletedAt},
+		"$inc": bson.M{"version": 1},
+	})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+func (r *instrumentRepository) RestoreByID(ctx context.Context, id primitive.ObjectID) error {
+	res, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id, "deleted_at": bson.M{"$exists": true}},
+		bson.M{
+			"$unset": bson.M{"deleted_at": ""},
+			"$inc":   bson.M{"version": 1},
+		},
+	)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// FindDeletedBefore возвращает инструменты, удалённые раньше before,
+// по возрастанию _id начиная после afterID.
+func (r *instrumentRepository) FindDeletedBefore(ctx context.Context, before time.Time, afterID primitive.ObjectID, limit int64) ([]entity.Instrument, error) {
+	filter := bson.M{
+		"_id":        bson.M{"$gt": afterID},
+		"deleted_at": bson.M{"$lt": before},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}).SetLimit(limit)
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var instruments []entity.Instrument
+	if err := cursor.All(ctx, &instruments); err != nil {
+		return nil, err
+	}
+	return instruments, nil
+}
+
+// PurgeByID окончательно удаляет инструмент, только если он помечен
+// удалённым: восстановленный между проверкой и удалением останется.
+func (r *instrumentRepository) PurgeByID(ctx context.Context, id primitive.ObjectID) error {
+	res, err := r.collection.DeleteOne(ctx, bson.M{"_id": id, "deleted_at": bson.M{"$exists": true}})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// UpdateByID записывает перечисленные поля инструмента (имена полей
+// в MongoDB); без fields записываются все редактируемые поля. Запись
+// проходит, только если версия в базе равна instrument.Version, после
+// неё instrument.Version увеличивается.
+func (r *instrumentRepository) UpdateByID(ctx context.Context, id primitive.ObjectID, instrument *entity.Instrument, fields ...string) error {
+	values := bson.M{
+		"name":              instrument.Name,
+		"description":       instrument.Description,
+		"price":             instrument.Price,
+		"category":          instrument.Category,
+		"brand":             instrument.Brand,
+		"category_path":     instrument.CategoryPath,
+		"attributes":        instrument.Attributes,
+		"weight_kg":         instrument.WeightKg,
+		"length_cm":         instrument.LengthCm,
+		"width_cm":          instrument.WidthCm,
+		"height_cm":         instrument.HeightCm,
+		"tax_category":      instrument.TaxCategory,
+		"max_cart_quantity": instrument.MaxCartQuantity,
+		"stock":             instrument.Stock,
+		"variants":          instrument.Variants,
+	}
+	set := values
+	if len(fields) > 0 {
+		set = bson.M{}
+		for _, f := range fields {
+			v, ok := values[f]
+			if !ok {
+				return fmt.Errorf("поле %q нельзя обновить", f)
+			}
+			set[f] = v
+		}
+	}
+	res, err := r.collection.UpdateOne(ctx,
+		notDeleted(bson.M{"_id": id, "version": instrument.Version}),
+		bson.M{"$set": set, "$inc": bson.M{"version": 1}},
+	)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		n, err := r.collection.CountDocuments(ctx, notDeleted(bson.M{"_id": id}))
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return mongo.ErrNoDocuments
+		}
+		return ErrVersionConflict
+	}
+	instrument.Version++
+	return nil
+}
+
+func (r *instrumentRepository) FindBySKU(ctx context.Context, sku string) (*entity.Instrument, error) {
+	var instrument entity.Instrument
+	err := r.collection.FindOne(ctx, notDeleted(bson.M{"variants.sku": sku})).Decode(&instrument)
+	if err != nil {
+		return nil, err
+	}
+	return &instrument, nil
+}
+
+func (r *instrumentRepository) FindByName(ctx context.Context, name string) (*entity.Instrument, error) {
+	var instrument entity.Instrument
+	err := r.collection.FindOne(ctx, notDeleted(bson.M{"name": name})).Decode(&instrument)
+	if err != nil {
+		return nil, err
+	}
+	return &instrument, nil
+}
+
+// ForEach вызывает fn для каждого инструмента категории category с
+// подкатегориями (пусто — весь каталог) в порядке _id, не загружая
+// каталог в память целиком. Ошибка fn прерывает обход.
+func (r *instrumentRepository) ForEach(ctx context.Context, category string, fn func(*entity.Instrument) error) error {
+	filter := bson.M{}
+	if category != "" {
+		filter["category_path"] = category
+	}
+	cursor, err := r.collection.Find(ctx, notDeleted(filter), options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var inst entity.Instrument
+		if err := cursor.Decode(&inst); err != nil {
+			return err
+		}
+		if err := fn(&inst); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
 }
 
-func (r *instrumentRepository) UpdateByID(ctx context.Context, id primitive.ObjectID, instrument *entity.Instrument) error {
-	update := bson.M{
-		"$set": bson.M{
-			"name":        instrument.Name,
-			"description": instrument.Description,
-			"price":       instrument.Price,
+// SetPrice меняет цену инструмента и возвращает его состояние до
+// изменения. С ifPrice цена меняется, только если текущая равна *ifPrice,
+// иначе возвращается mongo.ErrNoDocuments.
+func (r *instrumentRepository) SetPrice(ctx context.Context, id primitive.ObjectID, price float64, ifPrice *float64) (*entity.Instrument, error) {
+	filter := notDeleted(bson.M{"_id": id})
+	if ifPrice != nil {
+		filter["price"] = *ifPrice
+	}
+	var before entity.Instrument
+	err := r.collection.FindOneAndUpdate(ctx, filter,
+		bson.M{
+			"$set": bson.M{"price": price},
+			"$inc": bson.M{"version": 1},
 		},
+		options.FindOneAndUpdate().SetReturnDocument(options.Before),
+	).Decode(&before)
+	if err != nil {
+		return nil, err
+	}
+	return &before, nil
+}
+
+// AdjustStock меняет остаток инструмента, а для sku — и остаток варианта,
+// на delta. Списание проходит, только если остатка хватает: проверка и
+// запись — одно обновление. Возврат остатка (delta > 0) не требует, чтобы
+// инструмент не был удалён.
+func (r *instrumentRepository) AdjustStock(ctx context.Context, id primitive.ObjectID, sku string, delta int32) (*entity.Instrument, error) {
+	filter := bson.M{"_id": id}
+	inc := bson.M{"stock": delta, "version": 1}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	if sku != "" {
+		variant := bson.M{"sku": sku}
+		if delta < 0 {
+			variant["stock"] = bson.M{"$gte": -delta}
+		}
+		filter["variants"] = bson.M{"$elemMatch": variant}
+		inc["variants.$[v].stock"] = delta
+		opts.SetArrayFilters(options.ArrayFilters{
+			Filters: []interface{}{bson.M{"v.sku": sku}},
+		})
+	} else {
+		// у товара с вариантами остаток меняется только через вариант
+		filter["variants.0"] = bson.M{"$exists": false}
+		if delta < 0 {
+			filter["stock"] = bson.M{"$gte": -delta}
+		}
 	}
-	_, err := r.collection.UpdateByID(ctx, id, update)
+	if delta < 0 {
+		filter = notDeleted(filter)
+	}
+
+	var instrument entity.Instrument
+	err := r.collection.FindOneAndUpdate(ctx, filter, bson.M{"$inc": inc}, opts).Decode(&instrument)
+	if err == mongo.ErrNoDocuments && delta < 0 {
+		return nil, ErrOutOfStock
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &instrument, nil
+}
+
+// SetRating сохраняет рейтинг по отзывам. Рейтинг не редактируется
+// вручную, поэтому версия не меняется.
+func (r *instrumentRepository) SetRating(ctx context.Context, id primitive.ObjectID, average float64, count int32) error {
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{
+		"$set": bson.M{"rating_average": average, "rating_count": count},
+	})
 	return err
 }
+
+// AddMedia добавляет файл, если у инструмента их меньше
+// MaxMediaPerInstrument; проверка и запись атомарны.
+func (r *instrumentRepository) AddMedia(ctx context.Context, id primitive.ObjectID, media *entity.Media) error {
+	filter := notDeleted(bson.M{
+		"_id": id,
+		fmt.Sprintf("media.%d", MaxMediaPerInstrument-1): bson.M{"$exists": false},
+	})
+	res, err := r.collection.UpdateOne(ctx, filter, bson.M{
+		"$push": bson.M{"media": media},
+		"$inc":  bson.M{"version": 1},
+	})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrMediaLimit
+	}
+	return nil
+}
+
+// RemoveMedia удаляет файл из инструмента и возвращает его, чтобы
+// вызывающий мог удалить его из хранилища.
+func (r *instrumentRepository) RemoveMedia(ctx context.Context, id, mediaID primitive.ObjectID) (*entity.Media, error) {
+	var before entity.Instrument
+	err := r.collection.FindOneAndUpdate(ctx,
+		notDeleted(bson.M{"_id": id, "media._id": mediaID}),
+		bson.M{
+			"$pull": bson.M{"media": bson.M{"_id": mediaID}},
+			"$inc":  bson.M{"version": 1},
+		},
+		options.FindOneAndUpdate().SetReturnDocument(options.Before).SetProjection(bson.M{"media": 1}),
+	).Decode(&before)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrMediaNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range before.Media {
+		if m.ID == mediaID {
+			return &m, nil
+		}
+	}
+	return nil, ErrMediaNotFound
+}
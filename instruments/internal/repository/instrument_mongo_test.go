@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+
+	"gotune/instruments/internal/entity"
+)
+
+func TestUpdateByIDVersionConflict(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	notMatched := mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 0}, bson.E{Key: "nModified", Value: 0})
+
+	mt.Run("версия устарела", func(mt *mtest.T) {
+		ns := mt.Coll.Database().Name() + "." + mt.Coll.Name()
+		mt.AddMockResponses(notMatched, mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, bson.D{{Key: "n", Value: 1}}))
+		repo := &instrumentRepository{collection: mt.Coll}
+		inst := &entity.Instrument{Name: "Fender Stratocaster", Version: 2}
+
+		err := repo.UpdateByID(context.Background(), primitive.NewObjectID(), inst, "price")
+		assert.ErrorIs(t, err, ErrVersionConflict)
+		assert.Equal(t, int64(2), inst.Version)
+	})
+
+	mt.Run("инструмента нет", func(mt *mtest.T) {
+		ns := mt.Coll.Database().Name() + "." + mt.Coll.Name()
+		mt.AddMockResponses(notMatched, mtest.CreateCursorResponse(0, ns, mtest.FirstBatch))
+		repo := &instrumentRepository{collection: mt.Coll}
+
+		err := repo.UpdateByID(context.Background(), primitive.NewObjectID(), &entity.Instrument{Version: 2}, "price")
+		assert.ErrorIs(t, err, mongo.ErrNoDocuments)
+	})
+
+	mt.Run("версия совпала", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}))
+		repo := &instrumentRepository{collection: mt.Coll}
+		inst := &entity.Instrument{Version: 2}
+
+		assert.NoError(t, repo.UpdateByID(context.Background(), primitive.NewObjectID(), inst, "price"))
+		assert.Equal(t, int64(3), inst.Version)
+	})
+}
+
+func TestAdjustStock(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	noDocument := mtest.CreateSuccessResponse(bson.E{Key: "value", Value: nil})
+
+	mt.Run("варианта не хватает", func(mt *mtest.T) {
+		mt.AddMockResponses(noDocument)
+		repo := &instrumentRepository{collection: mt.Coll}
+
+		_, err := repo.AdjustStock(context.Background(), primitive.NewObjectID(), "STRAT-SB", -3)
+		assert.ErrorIs(t, err, ErrOutOfStock)
+
+		cmd := mt.GetStartedEvent().Command
+		variant := cmd.Lookup("query", "variants", "$elemMatch")
+		assert.Equal(t, "STRAT-SB", variant.Document().Lookup("sku").StringValue())
+		assert.Equal(t, int32(3), variant.Document().Lookup("stock", "$gte").Int32())
+		assert.Equal(t, int32(-3), cmd.Lookup("update", "$inc", "variants.$[v].stock").Int32())
+		assert.Equal(t, int32(-3), cmd.Lookup("update", "$inc", "stock").Int32())
+	})
+
+	mt.Run("возврат удалённого инструмента", func(mt *mtest.T) {
+		mt.AddMockResponses(noDocument)
+		repo := &instrumentRepository{collection: mt.Coll}
+
+		_, err := repo.AdjustStock(context.Background(), primitive.NewObjectID(), "", 2)
+		assert.ErrorIs(t, err, mongo.ErrNoDocuments)
+
+		query := mt.GetStartedEvent().Command.Lookup("query").Document()
+		_, err = query.LookupErr("deleted_at")
+		assert.Error(t, err)
+		_, err = query.LookupErr("stock")
+		assert.Error(t, err)
+	})
+
+	mt.Run("списание без вариантов", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "value", Value: bson.D{{Key: "stock", Value: 4}}}))
+		repo := &instrumentRepository{collection: mt.Coll}
+
+		inst, err := repo.AdjustStock(context.Background(), primitive.NewObjectID(), "", -1)
+		assert.NoError(t, err)
+		assert.Equal(t, int32(4), inst.Stock)
+
+		query := mt.GetStartedEvent().Command.Lookup("query").Document()
+		assert.Equal(t, int32(1), query.Lookup("stock", "$gte").Int32())
+	})
+}
@@ -0,0 +1,248 @@
+package repository
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"gotune/instruments/internal/entity"
+)
+
+// Фильтр по состоянию товара в поиске.
+const (
+	ConditionFilterNew  = "new"
+	ConditionFilterUsed = "used"
+)
+
+const (
+	SortRelevance = "relevance"
+	SortPriceAsc  = "price_asc"
+	SortPriceDesc = "price_desc"
+	SortName      = "name"
+	SortNewest    = "newest"
+)
+
+type SearchQuery struct {
+	Text       string
+	MinPrice   float64 // 0 — без нижней границы
+	MaxPrice   float64 // 0 — без верхней границы
+	Category   string
+	Brand      string
+	InStock    bool
+	Condition  string // ConditionFilterNew, ConditionFilterUsed или пусто
+	Sort       string
+	After      *SearchCursor
+	Limit      int64
+	Attributes []AttributeFilter
+}
+
+// AttributeFilter отбирает товары по значению атрибута: Values — любое из
+// перечисленных значений, Min и Max — диапазон для числовых атрибутов.
+type AttributeFilter struct {
+	Key    string
+	Values []interface{}
+	Min    float64
+	Max    float64
+}
+
+// SearchCursor указывает на последний товар предыдущей страницы. Для
+// сортировки по релевантности используется смещение, для остальных —
+// значение поля сортировки и _id.
+type SearchCursor struct {
+	Sort   string             `json:"s"`
+	Offset int64              `json:"o,omitempty"`
+	Price  float64            `json:"p,omitempty"`
+	Name   string             `json:"n,omitempty"`
+	ID     primitive.ObjectID `json:"id"`
+}
+
+type FacetBucket struct {
+	Value string `bson:"_id"`
+	Count int64  `bson:"count"`
+}
+
+type SearchFacets struct {
+	Total        int64
+	Categories   []FacetBucket
+	Brands       []FacetBucket
+	Availability []FacetBucket
+}
+
+func (r *instrumentRepository) Search(ctx context.Context, q SearchQuery) ([]entity.Instrument, error) {
+	filter := bson.M{}
+	for k, v := range baseFilter(q) {
+		filter[k] = v
+	}
+	for k, v := range refinementFilter(q, "") {
+		filter[k] = v
+	}
+
+	opts := options.Find().SetLimit(q.Limit)
+	switch q.Sort {
+	case SortRelevance:
+		opts.SetSort(bson.D{{Key: "score", Value: bson.M{"$meta": "textScore"}}, {Key: "_id", Value: 1}})
+		if q.After != nil {
+			opts.SetSkip(q.After.Offset)
+		}
+	case SortPriceAsc:
+		opts.SetSort(bson.D{{Key: "price", Value: 1}, {Key: "_id", Value: 1}})
+		if q.After != nil {
+			filter["$or"] = keysetAfter("price", q.After.Price, q.After.ID, "$gt")
+		}
+	case SortPriceDesc:
+		opts.SetSort(bson.D{{Key: "price", Value: -1}, {Key: "_id", Value: -1}})
+		if q.After != nil {
+			filter["$or"] = keysetAfter("price", q.After.Price, q.After.ID, "$lt")
+		}
+	case SortName:
+		opts.SetSort(bson.D{{Key: "name", Value: 1}, {Key: "_id", Value: 1}})
+		if q.After != nil {
+			filter["$or"] = keysetAfter("name", q.After.Name, q.After.ID, "$gt")
+		}
+	default:
+		// ObjectID растёт со временем создания
+		opts.SetSort(bson.D{{Key: "_id", Value: -1}})
+		if q.After != nil {
+			filter["_id"] = bson.M{"$lt": q.After.ID}
+		}
+	}
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var instruments []entity.Instrument
+	if err := cursor.All(ctx, &instruments); err != nil {
+		return nil, err
+	}
+	return instruments, nil
+}
+
+// Facets считает товары по категориям, брендам и наличию. Для каждого поля
+// применяются все фильтры, кроме фильтра по нему самому, чтобы было видно,
+// сколько товаров найдётся при выборе другого значения.
+func (r *instrumentRepository) Facets(ctx context.Context, q SearchQuery) (*SearchFacets, error) {
+	countBy := func(field string, key interface{}) bson.A {
+		return bson.A{
+			bson.M{"$match": refinementFilter(q, field)},
+			bson.M{"$group": bson.M{"_id": key, "count": bson.M{"$sum": 1}}},
+			bson.M{"$match": bson.M{"_id": bson.M{"$nin": bson.A{nil, ""}}}},
+			bson.M{"$sort": bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}},
+		}
+	}
+
+	pipeline := bson.A{
+		// $text допускается только в первой стадии, поэтому общий фильтр
+		// применяется до $facet
+		bson.M{"$match": baseFilter(q)},
+		bson.M{"$facet": bson.M{
+			"category": countBy("category", "$category"),
+			"brand":    countBy("brand", "$brand"),
+			"availability": countBy("stock", bson.M{"$cond": bson.A{
+				bson.M{"$gt": bson.A{"$stock", 0}}, "in_stock", "out_of_stock",
+			}}),
+			"total": bson.A{
+				bson.M{"$match": refinementFilter(q, "")},
+				bson.M{"$count": "count"},
+			},
+		}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var result []struct {
+		Category     []FacetBucket `bson:"category"`
+		Brand        []FacetBucket `bson:"brand"`
+		Availability []FacetBucket `bson:"availability"`
+		Total        []struct {
+			Count int64 `bson:"count"`
+		} `bson:"total"`
+	}
+	if err := cursor.All(ctx, &result); err != nil {
+		return nil, err
+	}
+
+	facets := &SearchFacets{}
+	if len(result) > 0 {
+		facets.Categories = result[0].Category
+		facets.Brands = result[0].Brand
+		facets.Availability = result[0].Availability
+		if len(result[0].Total) > 0 {
+			facets.Total = result[0].Total[0].Count
+		}
+	}
+	return facets, nil
+}
+
+// baseFilter — текстовый поиск, состояние, диапазон цен и атрибуты.
+func baseFilter(q SearchQuery) bson.M {
+	filter := notDeleted(bson.M{})
+	if q.Text != "" {
+		filter["$text"] = bson.M{"$search": q.Text}
+	}
+	switch q.Condition {
+	case ConditionFilterNew:
+		filter["used"] = bson.M{"$ne": true}
+	case ConditionFilterUsed:
+		filter["used"] = true
+	}
+	price := bson.M{}
+	if q.MinPrice > 0 {
+		price["$gte"] = q.MinPrice
+	}
+	if q.MaxPrice > 0 {
+		price["$lte"] = q.MaxPrice
+	}
+	if len(price) > 0 {
+		filter["price"] = price
+	}
+	for _, a := range q.Attributes {
+		field := "attributes." + a.Key
+		if len(a.Values) > 0 {
+			filter[field] = bson.M{"$in": a.Values}
+			continue
+		}
+		bounds := bson.M{}
+		if a.Min > 0 {
+			bounds["$gte"] = a.Min
+		}
+		if a.Max > 0 {
+			bounds["$lte"] = a.Max
+		}
+		if len(bounds) > 0 {
+			filter[field] = bounds
+		}
+	}
+	return filter
+}
+
+// refinementFilter — фильтры по категории, бренду и наличию, кроме except.
+func refinementFilter(q SearchQuery, except string) bson.M {
+	filter := bson.M{}
+	if q.Category != "" && except != "category" {
+		// вместе с подкатегориями
+		filter["category_path"] = q.Category
+	}
+	if q.Brand != "" && except != "brand" {
+		filter["brand"] = q.Brand
+	}
+	if q.InStock && except != "stock" {
+		filter["stock"] = bson.M{"$gt": 0}
+	}
+	return filter
+}
+
+func keysetAfter(field string, value interface{}, id primitive.ObjectID, op string) bson.A {
+	return bson.A{
+		bson.M{field: bson.M{op: value}},
+		bson.M{field: value, "_id": bson.M{op: id}},
+	}
+}
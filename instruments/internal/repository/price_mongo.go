@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"gotune/instruments/internal/entity"
+)
+
+var ErrScheduleNotFound = errors.New("расписание цены не найдено")
+
+// PriceRepository хранит историю цен и запланированные изменения цен.
+type PriceRepository interface {
+	RecordChange(ctx context.Context, change *entity.PriceChange) error
+	History(ctx context.Context, instrumentID primitive.ObjectID, limit int64) ([]entity.PriceChange, error)
+	CreateSchedule(ctx context.Context, schedule *entity.PriceSchedule) error
+	FindSchedule(ctx context.Context, id primitive.ObjectID) (*entity.PriceSchedule, error)
+	OpenSchedules(ctx context.Context, instrumentID primitive.ObjectID) ([]entity.PriceSchedule, error)
+	SchedulesToStart(ctx context.Context, now time.Time, limit int64) ([]entity.PriceSchedule, error)
+	SchedulesToEnd(ctx context.Context, now time.Time, limit int64) ([]entity.PriceSchedule, error)
+	ActivateSchedule(ctx context.Context, id primitive.ObjectID, originalPrice float64) error
+	SetScheduleStatus(ctx context.Context, id primitive.ObjectID, from, to string) error
+}
+
+type priceRepository struct {
+	history   *mongo.Collection
+	schedules *mongo.Collection
+}
+
+func NewPriceRepository(db *mongo.Database) PriceRepository {
+	return &priceRepository{
+		history:   db.Collection("price_history"),
+		schedules: db.Collection("price_schedules"),
+	}
+}
+
+func (r *priceRepository) RecordChange(ctx context.Context, change *entity.PriceChange) error {
+	_, err := r.history.InsertOne(ctx, change)
+	return err
+}
+
+// History возвращает последние limit изменений цены, от новых к старым.
+func (r *priceRepository) History(ctx context.Context, instrumentID primitive.ObjectID, limit int64) ([]entity.PriceChange, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "changed_at", Value: -1}, {Key: "_id", Value: -1}}).
+		SetLimit(limit)
+	cursor, err := r.history.Find(ctx, bson.M{"instrument_id": instrumentID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var changes []entity.PriceChange
+	if err := cursor.All(ctx, &changes); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+func (r *priceRepository) CreateSchedule(ctx context.Context, schedule *entity.PriceSchedule) error {
+	res, err := r.schedules.InsertOne(ctx, schedule)
+	if err != nil {
+		return err
+	}
+	schedule.ID = res.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *priceRepository) FindSchedule(ctx context.Context, id primitive.ObjectID) (*entity.PriceSchedule, error) {
+	var schedule entity.PriceSchedule
+	err := r.schedules.FindOne(ctx, bson.M{"_id": id}).Decode(&schedule)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrScheduleNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+// OpenSchedules возвращает ожидающие и действующие расписания инструмента
+// по времени начала.
+func (r *priceRepository) OpenSchedules(ctx context.Context, instrumentID primitive.ObjectID) ([]entity.PriceSchedule, error) {
+	filter := bson.M{
+		"instrument_id": instrumentID,
+		"status":        bson.M{"$in": bson.A{entity.ScheduleStatusPending, entity.ScheduleStatusActive}},
+	}
+	return r.findSchedules(ctx, filter, options.Find().SetSort(bson.D{{Key: "starts_at", Value: 1}}))
+}
+
+func (r *priceRepository) SchedulesToStart(ctx context.Context, now time.Time, limit int64) ([]entity.PriceSchedule, error) {
+	filter := bson.M{
+		"status":    entity.ScheduleStatusPending,
+		"starts_at": bson.M{"$lte": now},
+	}
+	return r.findSchedules(ctx, filter, options.Find().SetSort(bson.D{{Key: "starts_at", Value: 1}}).SetLimit(limit))
+}
+
+func (r *priceRepository) SchedulesToEnd(ctx context.Context, now time.Time, limit int64) ([]entity.PriceSchedule, error) {
+	filter := bson.M{
+		"status":  entity.ScheduleStatusActive,
+		"ends_at": bson.M{"$lte": now},
+	}
+	return r.findSchedules(ctx, filter, options.Find().SetSort(bson.D{{Key: "ends_at", Value: 1}}).SetLimit(limit))
+}
+
+func (r *priceRepository) findSchedules(ctx context.Context, filter bson.M, opts *options.FindOptions) ([]entity.PriceSchedule, error) {
+	cursor, err := r.schedules.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var schedules []entity.PriceSchedule
+	if err := cursor.All(ctx, &schedules); err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
+
+// ActivateSchedule переводит ожидающее расписание в действующее
+// и запоминает цену, которую нужно вернуть по окончании.
+func (r *priceRepository) ActivateSchedule(ctx context.Context, id primitive.ObjectID, originalPrice float64) error {
+	res, err := r.schedules.UpdateOne(ctx,
+		bson.M{"_id": id, "status": entity.ScheduleStatusPending},
+		bson.M{"$set": bson.M{"status": entity.ScheduleStatusActive, "original_price": originalPrice}},
+	)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrScheduleNotFound
+	}
+	return nil
+}
+
+// SetScheduleStatus меняет статус расписания, если он равен from.
+func (r *priceRepository) SetScheduleStatus(ctx context.Context, id primitive.ObjectID, from, to string) error {
+	res, err := r.schedules.UpdateOne(ctx,
+		bson.M{"_id": id, "status": from},
+		bson.M{"$set": bson.M{"status": to}},
+	)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrScheduleNotFound
+	}
+	return nil
+}
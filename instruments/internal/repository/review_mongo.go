@@ -0,0 +1,196 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"gotune/instruments/internal/entity"
+)
+
+var ErrReviewNotFound = errors.New("отзыв не найден")
+
+const (
+	ReviewSortNewest     = "newest"
+	ReviewSortHelpful    = "helpful"
+	ReviewSortRatingDesc = "rating_desc"
+	ReviewSortRatingAsc  = "rating_asc"
+)
+
+// ReviewQuery — выборка отзывов; InstrumentID == NilObjectID — отзывы
+// всех инструментов.
+type ReviewQuery struct {
+	InstrumentID primitive.ObjectID
+	Status       string
+	Sort         string
+	Limit        int64
+	Offset       int64
+}
+
+type ReviewRepository interface {
+	Create(ctx context.Context, review *entity.Review) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*entity.Review, error)
+	List(ctx context.Context, q ReviewQuery) ([]entity.Review, int64, error)
+	SetStatus(ctx context.Context, id primitive.ObjectID, status, note string, at time.Time) (*entity.Review, error)
+	AddHelpfulVote(ctx context.Context, id, userID primitive.ObjectID) (int32, error)
+	RatingSummary(ctx context.Context, instrumentID primitive.ObjectID) (float64, int32, error)
+	HasReviews(ctx context.Context, instrumentID primitive.ObjectID) (bool, error)
+}
+
+type reviewRepository struct {
+	collection *mongo.Collection
+}
+
+func NewReviewRepository(db *mongo.Database) ReviewRepository {
+	return &reviewRepository{collection: db.Collection("reviews")}
+}
+
+// withoutVoters — списки проголосовавших нужны только для проверки
+// повторного голоса и в ответы не попадают.
+var withoutVoters = bson.M{"helpful_voters": 0}
+
+// Create сохраняет отзыв. Второй отзыв пользователя на тот же инструмент
+// отклоняет уникальный индекс, ошибку дубликата обрабатывает сервис.
+func (r *reviewRepository) Create(ctx context.Context, review *entity.Review) error {
+	res, err := r.collection.InsertOne(ctx, review)
+	if err != nil {
+		return err
+	}
+	review.ID = res.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *reviewRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*entity.Review, error) {
+	var review entity.Review
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}, options.FindOne().SetProjection(withoutVoters)).Decode(&review)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrReviewNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
+// List возвращает страницу отзывов и общее число подходящих.
+func (r *reviewRepository) List(ctx context.Context, q ReviewQuery) ([]entity.Review, int64, error) {
+	filter := bson.M{"status": q.Status}
+	if !q.InstrumentID.IsZero() {
+		filter["instrument_id"] = q.InstrumentID
+	}
+
+	total, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var sort bson.D
+	switch q.Sort {
+	case ReviewSortHelpful:
+		sort = bson.D{{Key: "helpful_votes", Value: -1}}
+	case ReviewSortRatingDesc:
+		sort = bson.D{{Key: "rating", Value: -1}}
+	case ReviewSortRatingAsc:
+		sort = bson.D{{Key: "rating", Value: 1}}
+	}
+	sort = append(sort, bson.E{Key: "created_at", Value: -1}, bson.E{Key: "_id", Value: -1})
+
+	opts := options.Find().
+		SetSort(sort).
+		SetSkip(q.Offset).
+		SetLimit(q.Limit).
+		SetProjection(withoutVoters)
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var reviews []entity.Review
+	if err := cursor.All(ctx, &reviews); err != nil {
+		return nil, 0, err
+	}
+	return reviews, total, nil
+}
+
+// SetStatus меняет статус отзыва и возвращает его состояние до изменения.
+func (r *reviewRepository) SetStatus(ctx context.Context, id primitive.ObjectID, status, note string, at time.Time) (*entity.Review, error) {
+	var before entity.Review
+	err := r.collection.FindOneAndUpdate(ctx,
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{"status": status, "moderation_note": note, "moderated_at": at}},
+		options.FindOneAndUpdate().SetReturnDocument(options.Before).SetProjection(withoutVoters),
+	).Decode(&before)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrReviewNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &before, nil
+}
+
+// AddHelpfulVote учитывает голос пользователя, если он ещё не голосовал,
+// и возвращает число голосов.
+func (r *reviewRepository) AddHelpfulVote(ctx context.Context, id, userID primitive.ObjectID) (int32, error) {
+	var review entity.Review
+	err := r.collection.FindOneAndUpdate(ctx,
+		bson.M{"_id": id, "helpful_voters": bson.M{"$ne": userID}},
+		bson.M{
+			"$push": bson.M{"helpful_voters": userID},
+			"$inc":  bson.M{"helpful_votes": 1},
+		},
+		options.FindOneAndUpdate().SetReturnDocument(options.After).SetProjection(withoutVoters),
+	).Decode(&review)
+	if err == mongo.ErrNoDocuments {
+		// уже голосовал
+		current, err := r.FindByID(ctx, id)
+		if err != nil {
+			return 0, err
+		}
+		return current.HelpfulVotes, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return review.HelpfulVotes, nil
+}
+
+// HasReviews сообщает, есть ли у инструмента отзывы в любом статусе.
+func (r *reviewRepository) HasReviews(ctx context.Context, instrumentID primitive.ObjectID) (bool, error) {
+	n, err := r.collection.CountDocuments(ctx, bson.M{"instrument_id": instrumentID}, options.Count().SetLimit(1))
+	return n > 0, err
+}
+
+// RatingSummary считает среднюю оценку и число одобренных отзывов.
+func (r *reviewRepository) RatingSummary(ctx context.Context, instrumentID primitive.ObjectID) (float64, int32, error) {
+	cursor, err := r.collection.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"instrument_id": instrumentID, "status": entity.ReviewStatusApproved}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":     nil,
+			"average": bson.M{"$avg": "$rating"},
+			"count":   bson.M{"$sum": 1},
+		}}},
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var summary []struct {
+		Average float64 `bson:"average"`
+		Count   int32   `bson:"count"`
+	}
+	if err := cursor.All(ctx, &summary); err != nil {
+		return 0, 0, err
+	}
+	if len(summary) == 0 {
+		return 0, 0, nil
+	}
+	return summary[0].Average, summary[0].Count, nil
+}
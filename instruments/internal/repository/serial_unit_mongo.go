@@ -0,0 +1,226 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"gotune/instruments/internal/entity"
+)
+
+var (
+	ErrSerialUnitNotFound = errors.New("экземпляр не найден")
+	ErrSerialUnitSold     = errors.New("экземпляр уже продан")
+	// ErrSerialUnitUnavailable — нет экземпляра, который можно продать
+	ErrSerialUnitUnavailable = errors.New("экземпляр недоступен для продажи")
+	ErrWarrantyNotAllowed    = errors.New("гарантию нельзя зарегистрировать")
+)
+
+// SerialUnitUpdate — пустые поля не меняются.
+type SerialUnitUpdate struct {
+	Condition string
+	Location  string
+	Status    string
+}
+
+type SerialUnitRepository interface {
+	Create(ctx context.Context, unit *entity.SerialUnit) error
+	FindBySerial(ctx context.Context, serial string) (*entity.SerialUnit, error)
+	List(ctx context.Context, instrumentID primitive.ObjectID, sku, status string) ([]entity.SerialUnit, error)
+	Update(ctx context.Context, serial string, upd SerialUnitUpdate, at time.Time) (*entity.SerialUnit, error)
+	IsTracked(ctx context.Context, instrumentID primitive.ObjectID) (bool, error)
+	FindByOrder(ctx context.Context, orderID primitive.ObjectID) ([]entity.SerialUnit, error)
+	Allocate(ctx context.Context, serial string, instrumentID primitive.ObjectID, sku string, orderID, userID primitive.ObjectID, at time.Time) (*entity.SerialUnit, error)
+	AllocateAny(ctx context.Context, instrumentID primitive.ObjectID, sku string, orderID, userID primitive.ObjectID, at time.Time) (*entity.SerialUnit, error)
+	ReleaseOrder(ctx context.Context, orderID primitive.ObjectID, at time.Time) error
+	RegisterWarranty(ctx context.Context, serial string, warranty entity.Warranty) (*entity.SerialUnit, error)
+}
+
+type serialUnitRepository struct {
+	collection *mongo.Collection
+}
+
+func NewSerialUnitRepository(db *mongo.Database) SerialUnitRepository {
+	return &serialUnitRepository{collection: db.Collection("serial_units")}
+}
+
+// Create сохраняет экземпляр. Повтор серийного номера отклоняет
+// уникальный индекс, ошибку дубликата обрабатывает сервис.
+func (r *serialUnitRepository) Create(ctx context.Context, unit *entity.SerialUnit) error {
+	res, err := r.collection.InsertOne(ctx, unit)
+	if err != nil {
+		return err
+	}
+	unit.ID = res.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *serialUnitRepository) FindBySerial(ctx context.Context, serial string) (*entity.SerialUnit, error) {
+	return r.findOne(ctx, bson.M{"serial_number": serial})
+}
+
+func (r *serialUnitRepository) List(ctx context.Context, instrumentID primitive.ObjectID, sku, status string) ([]entity.SerialUnit, error) {
+	filter := bson.M{"instrument_id": instrumentID}
+	if sku != "" {
+		filter["sku"] = sku
+	}
+	if status != "" {
+		filter["status"] = status
+	}
+	return r.find(ctx, filter, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+}
+
+// Update меняет состояние, место или статус экземпляра. Проданный
+// экземпляр не меняется.
+func (r *serialUnitRepository) Update(ctx context.Context, serial string, upd SerialUnitUpdate, at time.Time) (*entity.SerialUnit, error) {
+	set := bson.M{"updated_at": at}
+	if upd.Condition != "" {
+		set["condition"] = upd.Condition
+	}
+	if upd.Location != "" {
+		set["location"] = upd.Location
+	}
+	if upd.Status != "" {
+		set["status"] = upd.Status
+	}
+
+	unit, err := r.findOneAndUpdate(ctx,
+		bson.M{"serial_number": serial, "status": bson.M{"$ne": entity.SerialStatusSold}},
+		bson.M{"$set": set},
+	)
+	if errors.Is(err, ErrSerialUnitNotFound) {
+		if _, findErr := r.FindBySerial(ctx, serial); findErr == nil {
+			return nil, ErrSerialUnitSold
+		}
+	}
+	return unit, err
+}
+
+// IsTracked сообщает, ведётся ли по инструменту учёт экземпляров.
+func (r *serialUnitRepository) IsTracked(ctx context.Context, instrumentID primitive.ObjectID) (bool, error) {
+	n, err := r.collection.CountDocuments(ctx, bson.M{"instrument_id": instrumentID}, options.Count().SetLimit(1))
+	return n > 0, err
+}
+
+func (r *serialUnitRepository) FindByOrder(ctx context.Context, orderID primitive.ObjectID) ([]entity.SerialUnit, error) {
+	return r.find(ctx, bson.M{"order_id": orderID}, options.Find().SetSort(bson.D{{Key: "sold_at", Value: 1}}))
+}
+
+// Allocate продаёт конкретный экземпляр, если он на складе или в резерве.
+func (r *serialUnitRepository) Allocate(ctx context.Context, serial string, instrumentID primitive.ObjectID, sku string, orderID, userID primitive.ObjectID, at time.Time) (*entity.SerialUnit, error) {
+	unit, err := r.findOneAndUpdate(ctx,
+		bson.M{
+			"serial_number": serial,
+			"instrument_id": instrumentID,
+			"sku":           sku,
+			"status":        bson.M{"$in": bson.A{entity.SerialStatusInStock, entity.SerialStatusReserved}},
+		},
+		soldUpdate(orderID, userID, at),
+	)
+	if errors.Is(err, ErrSerialUnitNotFound) {
+		return nil, ErrSerialUnitUnavailable
+	}
+	return unit, err
+}
+
+// AllocateAny продаёт экземпляр со склада, первым — поступивший раньше.
+func (r *serialUnitRepository) AllocateAny(ctx context.Context, instrumentID primitive.ObjectID, sku string, orderID, userID primitive.ObjectID, at time.Time) (*entity.SerialUnit, error) {
+	unit, err := r.findOneAndUpdate(ctx,
+		bson.M{
+			"instrument_id": instrumentID,
+			"sku":           sku,
+			"status":        entity.SerialStatusInStock,
+		},
+		soldUpdate(orderID, userID, at),
+		options.FindOneAndUpdate().SetSort(bson.D{{Key: "created_at", Value: 1}}),
+	)
+	if errors.Is(err, ErrSerialUnitNotFound) {
+		return nil, ErrSerialUnitUnavailable
+	}
+	return unit, err
+}
+
+// ReleaseOrder возвращает экземпляры заказа на склад.
+func (r *serialUnitRepository) ReleaseOrder(ctx context.Context, orderID primitive.ObjectID, at time.Time) error {
+	_, err := r.collection.UpdateMany(ctx,
+		bson.M{"order_id": orderID, "warranty": bson.M{"$exists": false}},
+		bson.M{
+			"$set":   bson.M{"status": entity.SerialStatusInStock, "updated_at": at},
+			"$unset": bson.M{"order_id": "", "sold_to": "", "sold_at": ""},
+		},
+	)
+	return err
+}
+
+// RegisterWarranty записывает гарантию, если экземпляр продан этому
+// пользователю и гарантия ещё не зарегистрирована.
+func (r *serialUnitRepository) RegisterWarranty(ctx context.Context, serial string, warranty entity.Warranty) (*entity.SerialUnit, error) {
+	unit, err := r.findOneAndUpdate(ctx,
+		bson.M{
+			"serial_number": serial,
+			"status":        entity.SerialStatusSold,
+			"sold_to":       warranty.UserID,
+			"warranty":      bson.M{"$exists": false},
+		},
+		bson.M{"$set": bson.M{"warranty": warranty, "updated_at": warranty.RegisteredAt}},
+	)
+	if errors.Is(err, ErrSerialUnitNotFound) {
+		return nil, ErrWarrantyNotAllowed
+	}
+	return unit, err
+}
+
+func soldUpdate(orderID, userID primitive.ObjectID, at time.Time) bson.M {
+	return bson.M{"$set": bson.M{
+		"status":     entity.SerialStatusSold,
+		"order_id":   orderID,
+		"sold_to":    userID,
+		"sold_at":    at,
+		"updated_at": at,
+	}}
+}
+
+func (r *serialUnitRepository) findOne(ctx context.Context, filter bson.M) (*entity.SerialUnit, error) {
+	var unit entity.SerialUnit
+	err := r.collection.FindOne(ctx, filter).Decode(&unit)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrSerialUnitNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &unit, nil
+}
+
+func (r *serialUnitRepository) findOneAndUpdate(ctx context.Context, filter, update bson.M, opts ...*options.FindOneAndUpdateOptions) (*entity.SerialUnit, error) {
+	opts = append(opts, options.FindOneAndUpdate().SetReturnDocument(options.After))
+
+	var unit entity.SerialUnit
+	err := r.collection.FindOneAndUpdate(ctx, filter, update, opts...).Decode(&unit)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrSerialUnitNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &unit, nil
+}
+
+func (r *serialUnitRepository) find(ctx context.Context, filter bson.M, opts *options.FindOptions) ([]entity.SerialUnit, error) {
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var units []entity.SerialUnit
+	if err := cursor.All(ctx, &units); err != nil {
+		return nil, err
+	}
+	return units, nil
+}
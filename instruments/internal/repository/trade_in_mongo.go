@@ -0,0 +1,192 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"gotune/instruments/internal/entity"
+)
+
+var (
+	ErrTradeInNotFound = errors.New("заявка trade-in не найдена")
+	// ErrTradeInStatus — заявка уже в другом статусе
+	ErrTradeInStatus = errors.New("статус заявки изменился")
+	ErrPhotoLimit    = errors.New("превышено число фото заявки")
+)
+
+// MaxPhotosPerTradeIn — сколько фото можно приложить к заявке.
+const MaxPhotosPerTradeIn = 10
+
+type TradeInRepository interface {
+	Create(ctx context.Context, tradeIn *entity.TradeIn) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*entity.TradeIn, error)
+	List(ctx context.Context, userID primitive.ObjectID, status string) ([]entity.TradeIn, error)
+	AddPhoto(ctx context.Context, id, userID primitive.ObjectID, photo *entity.Media) error
+	SetOffer(ctx context.Context, id primitive.ObjectID, condition string, amount float64, note string, at time.Time) (*entity.TradeIn, error)
+	Reject(ctx context.Context, id primitive.ObjectID, note string, at time.Time) (*entity.TradeIn, error)
+	Respond(ctx context.Context, id, userID primitive.ObjectID, accept bool, at time.Time) (*entity.TradeIn, error)
+	MarkReceived(ctx context.Context, id, listingID primitive.ObjectID, at time.Time) (*entity.TradeIn, error)
+	MarkListed(ctx context.Context, id primitive.ObjectID) (*entity.TradeIn, error)
+	IsListing(ctx context.Context, instrumentID primitive.ObjectID) (bool, error)
+}
+
+type tradeInRepository struct {
+	collection *mongo.Collection
+}
+
+func NewTradeInRepository(db *mongo.Database) TradeInRepository {
+	return &tradeInRepository{collection: db.Collection("trade_ins")}
+}
+
+func (r *tradeInRepository) Create(ctx context.Context, tradeIn *entity.TradeIn) error {
+	res, err := r.collection.InsertOne(ctx, tradeIn)
+	if err != nil {
+		return err
+	}
+	tradeIn.ID = res.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *tradeInRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*entity.TradeIn, error) {
+	var tradeIn entity.TradeIn
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&tradeIn)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrTradeInNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &tradeIn, nil
+}
+
+// List возвращает заявки пользователя или, при NilObjectID, заявки всех
+// пользователей; старые первыми, чтобы очередь оценки шла по порядку.
+func (r *tradeInRepository) List(ctx context.Context, userID primitive.ObjectID, status string) ([]entity.TradeIn, error) {
+	filter := bson.M{}
+	if !userID.IsZero() {
+		filter["user_id"] = userID
+	}
+	if status != "" {
+		filter["status"] = status
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var tradeIns []entity.TradeIn
+	if err := cursor.All(ctx, &tradeIns); err != nil {
+		return nil, err
+	}
+	return tradeIns, nil
+}
+
+// AddPhoto добавляет фото к заявке владельца, пока она не оценена.
+func (r *tradeInRepository) AddPhoto(ctx context.Context, id, userID primitive.ObjectID, photo *entity.Media) error {
+	res, err := r.collection.UpdateOne(ctx,
+		bson.M{
+			"_id":     id,
+			"user_id": userID,
+			"status":  entity.TradeInStatusSubmitted,
+			fmt.Sprintf("photos.%d", MaxPhotosPerTradeIn-1): bson.M{"$exists": false},
+		},
+		bson.M{"$push": bson.M{"photos": photo}},
+	)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount > 0 {
+		return nil
+	}
+
+	tradeIn, err := r.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if tradeIn.UserID != userID {
+		return ErrTradeInNotFound
+	}
+	if tradeIn.Status != entity.TradeInStatusSubmitted {
+		return ErrTradeInStatus
+	}
+	return ErrPhotoLimit
+}
+
+func (r *tradeInRepository) SetOffer(ctx context.Context, id primitive.ObjectID, condition string, amount float64, note string, at time.Time) (*entity.TradeIn, error) {
+	return r.transition(ctx, bson.M{"_id": id}, entity.TradeInStatusSubmitted, entity.TradeInStatusOffered, bson.M{
+		"appraised_condition": condition,
+		"offer_amount":        amount,
+		"offer_note":          note,
+		"offered_at":          at,
+	})
+}
+
+func (r *tradeInRepository) Reject(ctx context.Context, id primitive.ObjectID, note string, at time.Time) (*entity.TradeIn, error) {
+	return r.transition(ctx, bson.M{"_id": id}, entity.TradeInStatusSubmitted, entity.TradeInStatusRejected, bson.M{
+		"offer_note": note,
+		"offered_at": at,
+	})
+}
+
+// Respond записывает ответ владельца заявки на предложение.
+func (r *tradeInRepository) Respond(ctx context.Context, id, userID primitive.ObjectID, accept bool, at time.Time) (*entity.TradeIn, error) {
+	to := entity.TradeInStatusDeclined
+	if accept {
+		to = entity.TradeInStatusAccepted
+	}
+	return r.transition(ctx, bson.M{"_id": id, "user_id": userID}, entity.TradeInStatusOffered, to, bson.M{
+		"responded_at": at,
+	})
+}
+
+func (r *tradeInRepository) MarkReceived(ctx context.Context, id, listingID primitive.ObjectID, at time.Time) (*entity.TradeIn, error) {
+	return r.transition(ctx, bson.M{"_id": id}, entity.TradeInStatusAccepted, entity.TradeInStatusReceived, bson.M{
+		"listing_id":  listingID,
+		"received_at": at,
+	})
+}
+
+func (r *tradeInRepository) MarkListed(ctx context.Context, id primitive.ObjectID) (*entity.TradeIn, error) {
+	return r.transition(ctx, bson.M{"_id": id}, entity.TradeInStatusReceived, entity.TradeInStatusListed, bson.M{})
+}
+
+// IsListing сообщает, выставлен ли инструмент по заявке trade-in.
+func (r *tradeInRepository) IsListing(ctx context.Context, instrumentID primitive.ObjectID) (bool, error) {
+	n, err := r.collection.CountDocuments(ctx, bson.M{"listing_id": instrumentID}, options.Count().SetLimit(1))
+	return n > 0, err
+}
+
+// transition переводит заявку из статуса from в to вместе с полями set.
+func (r *tradeInRepository) transition(ctx context.Context, filter bson.M, from, to string, set bson.M) (*entity.TradeIn, error) {
+	filter["status"] = from
+	set["status"] = to
+
+	var tradeIn entity.TradeIn
+	err := r.collection.FindOneAndUpdate(ctx, filter, bson.M{"$set": set},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&tradeIn)
+	if err == mongo.ErrNoDocuments {
+		current, findErr := r.FindByID(ctx, filter["_id"].(primitive.ObjectID))
+		if findErr != nil {
+			return nil, findErr
+		}
+		if userID, ok := filter["user_id"]; ok && current.UserID != userID {
+			return nil, ErrTradeInNotFound
+		}
+		return nil, ErrTradeInStatus
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &tradeIn, nil
+}
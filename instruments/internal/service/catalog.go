@@ -0,0 +1,374 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"gotune/instruments/internal/entity"
+	"gotune/instruments/internal/repository"
+	"gotune/instruments/proto"
+)
+
+var (
+	slugPattern         = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+	attributeKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+)
+
+func (s *InstrumentService) CreateCategory(ctx context.Context, req *proto.CreateCategoryRequest) (*proto.CreateCategoryResponse, error) {
+	if !slugPattern.MatchString(req.Slug) {
+		return nil, status.Errorf(codes.InvalidArgument, "slug может содержать только строчные латинские буквы, цифры и дефис")
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "не указано название категории")
+	}
+
+	category := &entity.Category{
+		Slug: req.Slug,
+		Name: name,
+		Path: []string{req.Slug},
+	}
+	var inherited []entity.AttributeDef
+	if req.Parent != "" {
+		parent, err := s.categoryRepo.FindBySlug(ctx, req.Parent)
+		if err != nil {
+			return nil, catalogError(err)
+		}
+		category.Parent = parent.Slug
+		category.Path = append(append([]string{}, parent.Path...), req.Slug)
+		if inherited, err = s.categoryAttributes(ctx, parent); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, d := range req.Attributes {
+		category.Attributes = append(category.Attributes, entity.AttributeDef{
+			Key:      d.Key,
+			Name:     strings.TrimSpace(d.Name),
+			Type:     d.Type,
+			Values:   d.Values,
+			Unit:     d.Unit,
+			Required: d.Required,
+		})
+	}
+	if err := validateAttributeDefs(category.Attributes, inherited); err != nil {
+		return nil, err
+	}
+
+	if err := s.categoryRepo.Create(ctx, category); err != nil {
+		return nil, catalogError(err)
+	}
+
+	return &proto.CreateCategoryResponse{
+		Category: categoryToProto(category, append(inherited, category.Attributes...)),
+	}, nil
+}
+
+func (s *InstrumentService) GetCategories(ctx context.Context, req *proto.GetCategoriesRequest) (*proto.GetCategoriesResponse, error) {
+	categories, err := s.categoryRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	bySlug := make(map[string]entity.Category, len(categories))
+	for _, c := range categories {
+		bySlug[c.Slug] = c
+	}
+
+	resp := &proto.GetCategoriesResponse{}
+	for i := range categories {
+		resp.Categories = append(resp.Categories, categoryToProto(&categories[i], effectiveAttributes(categories[i].Path, bySlug)))
+	}
+	return resp, nil
+}
+
+func (s *InstrumentService) CreateBrand(ctx context.Context, req *proto.CreateBrandRequest) (*proto.CreateBrandResponse, error) {
+	if !slugPattern.MatchString(req.Slug) {
+		return nil, status.Errorf(codes.InvalidArgument, "slug может содержать только строчные латинские буквы, цифры и дефис")
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "не указано название бренда")
+	}
+
+	brand := &entity.Brand{Slug: req.Slug, Name: name}
+	if err := s.brandRepo.Create(ctx, brand); err != nil {
+		return nil, catalogError(err)
+	}
+
+	return &proto.CreateBrandResponse{Brand: &proto.Brand{Slug: brand.Slug, Name: brand.Name}}, nil
+}
+
+func (s *InstrumentService) GetBrands(ctx context.Context, req *proto.GetBrandsRequest) (*proto.GetBrandsResponse, error) {
+	brands, err := s.brandRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &proto.GetBrandsResponse{}
+	for _, b := range brands {
+		resp.Brands = append(resp.Brands, &proto.Brand{Slug: b.Slug, Name: b.Name})
+	}
+	return resp, nil
+}
+
+// resolveCatalog проверяет бренд, категорию и атрибуты инструмента и
+// заполняет путь категории и значения атрибутов.
+func (s *InstrumentService) resolveCatalog(ctx context.Context, inst *entity.Instrument, values []*proto.AttributeValue) error {
+	if inst.Brand != "" {
+		if _, err := s.brandRepo.FindBySlug(ctx, inst.Brand); err != nil {
+			return catalogError(err)
+		}
+	}
+
+	inst.CategoryPath = nil
+	inst.Attributes = nil
+	if inst.Category == "" {
+		if len(values) > 0 {
+			return status.Errorf(codes.InvalidArgument, "атрибуты задаются только для товара с категорией")
+		}
+		return nil
+	}
+
+	category, err := s.categoryRepo.FindBySlug(ctx, inst.Category)
+	if err != nil {
+		return catalogError(err)
+	}
+	defs, err := s.categoryAttributes(ctx, category)
+	if err != nil {
+		return err
+	}
+
+	attrs, err := attributesFromProto(defs, values)
+	if err != nil {
+		return err
+	}
+	inst.CategoryPath = category.Path
+	if len(attrs) > 0 {
+		inst.Attributes = attrs
+	}
+	return nil
+}
+
+// categoryAttributes возвращает атрибуты категории вместе с унаследованными.
+func (s *InstrumentService) categoryAttributes(ctx context.Context, category *entity.Category) ([]entity.AttributeDef, error) {
+	ancestors, err := s.categoryRepo.FindBySlugs(ctx, category.Path)
+	if err != nil {
+		return nil, err
+	}
+	bySlug := make(map[string]entity.Category, len(ancestors))
+	for _, c := range ancestors {
+		bySlug[c.Slug] = c
+	}
+	return effectiveAttributes(category.Path, bySlug), nil
+}
+
+// searchAttributeDefs возвращает определения атрибутов, по которым можно
+// фильтровать: атрибуты выбранной категории или всех категорий.
+func (s *InstrumentService) searchAttributeDefs(ctx context.Context, categorySlug string) (map[string]entity.AttributeDef, error) {
+	var defs []entity.AttributeDef
+	if categorySlug != "" {
+		category, err := s.categoryRepo.FindBySlug(ctx, categorySlug)
+		if err != nil {
+			return nil, catalogError(err)
+		}
+		if defs, err = s.categoryAttributes(ctx, category); err != nil {
+			return nil, err
+		}
+	} else {
+		categories, err := s.categoryRepo.GetAll(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range categories {
+			defs = append(defs, c.Attributes...)
+		}
+	}
+
+	result := make(map[string]entity.AttributeDef, len(defs))
+	for _, d := range defs {
+		if _, ok := result[d.Key]; !ok {
+			result[d.Key] = d
+		}
+	}
+	return result, nil
+}
+
+func effectiveAttributes(path []string, bySlug map[string]entity.Category) []entity.AttributeDef {
+	var defs []entity.AttributeDef
+	for _, slug := range path {
+		defs = append(defs, bySlug[slug].Attributes...)
+	}
+	return defs
+}
+
+func validateAttributeDefs(defs, inherited []entity.AttributeDef) error {
+	seen := map[string]bool{}
+	for _, d := range inherited {
+		seen[d.Key] = true
+	}
+	for _, d := range defs {
+		if !attributeKeyPattern.MatchString(d.Key) {
+			return status.Errorf(codes.InvalidArgument, "некорректный ключ атрибута %q", d.Key)
+		}
+		if seen[d.Key] {
+			return status.Errorf(codes.InvalidArgument, "атрибут %q уже определён", d.Key)
+		}
+		seen[d.Key] = true
+
+		switch d.Type {
+		case entity.AttributeEnum:
+			if len(d.Values) == 0 {
+				return status.Errorf(codes.InvalidArgument, "для атрибута %q не указаны значения", d.Key)
+			}
+		case entity.AttributeNumber, entity.AttributeBoolean:
+			if len(d.Values) > 0 {
+				return status.Errorf(codes.InvalidArgument, "значения задаются только для enum-атрибута %q", d.Key)
+			}
+		default:
+			return status.Errorf(codes.InvalidArgument, "неизвестный тип атрибута %q: %q", d.Key, d.Type)
+		}
+	}
+	return nil
+}
+
+// attributesFromProto проверяет значения по определениям категории и
+// приводит их к типам атрибутов.
+func attributesFromProto(defs []entity.AttributeDef, values []*proto.AttributeValue) (map[string]interface{}, error) {
+	byKey := make(map[string]entity.AttributeDef, len(defs))
+	for _, d := range defs {
+		byKey[d.Key] = d
+	}
+
+	attrs := map[string]interface{}{}
+	for _, v := range values {
+		def, ok := byKey[v.Key]
+		if !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "атрибут %q не определён для категории", v.Key)
+		}
+		if _, dup := attrs[v.Key]; dup {
+			return nil, status.Errorf(codes.InvalidArgument, "атрибут %q указан дважды", v.Key)
+		}
+
+		switch def.Type {
+		case entity.AttributeEnum:
+			if !contains(def.Values, v.EnumValue) {
+				return nil, status.Errorf(codes.InvalidArgument, "недопустимое значение %q атрибута %q", v.EnumValue, v.Key)
+			}
+			attrs[v.Key] = v.EnumValue
+		case entity.AttributeNumber:
+			attrs[v.Key] = v.NumberValue
+		case entity.AttributeBoolean:
+			attrs[v.Key] = v.BoolValue
+		}
+	}
+
+	for _, d := range defs {
+		if _, ok := attrs[d.Key]; d.Required && !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "не указан обязательный атрибут %q", d.Key)
+		}
+	}
+	return attrs, nil
+}
+
+func attributesToProto(attrs map[string]interface{}) []*proto.AttributeValue {
+	keys := make([]string, 0, len(attrs))
+	for k := range attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var result []*proto.AttributeValue
+	for _, k := range keys {
+		v := &proto.AttributeValue{Key: k}
+		switch value := attrs[k].(type) {
+		case string:
+			v.EnumValue = value
+		case float64:
+			v.NumberValue = value
+		case bool:
+			v.BoolValue = value
+		}
+		result = append(result, v)
+	}
+	return result
+}
+
+// attributeFilters приводит значения фильтров из запроса к типам атрибутов.
+func attributeFilters(defs map[string]entity.AttributeDef, filters []*proto.AttributeFilter) ([]repository.AttributeFilter, error) {
+	var result []repository.AttributeFilter
+	for _, f := range filters {
+		def, ok := defs[f.Key]
+		if !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "неизвестный атрибут %q", f.Key)
+		}
+
+		filter := repository.AttributeFilter{Key: f.Key, Min: f.Min, Max: f.Max}
+		for _, raw := range f.Values {
+			switch def.Type {
+			case entity.AttributeEnum:
+				filter.Values = append(filter.Values, raw)
+			case entity.AttributeNumber:
+				n, err := strconv.ParseFloat(raw, 64)
+				if err != nil {
+					return nil, status.Errorf(codes.InvalidArgument, "атрибут %q числовой, получено %q", f.Key, raw)
+				}
+				filter.Values = append(filter.Values, n)
+			case entity.AttributeBoolean:
+				b, err := strconv.ParseBool(raw)
+				if err != nil {
+					return nil, status.Errorf(codes.InvalidArgument, "атрибут %q логический, получено %q", f.Key, raw)
+				}
+				filter.Values = append(filter.Values, b)
+			}
+		}
+		result = append(result, filter)
+	}
+	return result, nil
+}
+
+func categoryToProto(c *entity.Category, defs []entity.AttributeDef) *proto.Category {
+	result := &proto.Category{
+		Slug:   c.Slug,
+		Name:   c.Name,
+		Parent: c.Parent,
+		Path:   c.Path,
+	}
+	for _, d := range defs {
+		result.Attributes = append(result.Attributes, &proto.AttributeDefinition{
+			Key:      d.Key,
+			Name:     d.Name,
+			Type:     d.Type,
+			Values:   d.Values,
+			Unit:     d.Unit,
+			Required: d.Required,
+		})
+	}
+	return result
+}
+
+func catalogError(err error) error {
+	switch {
+	case errors.Is(err, repository.ErrCategoryNotFound), errors.Is(err, repository.ErrBrandNotFound):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, repository.ErrSlugTaken):
+		return status.Error(codes.AlreadyExists, err.Error())
+	}
+	return err
+}
+
+func contains(values []string, v string) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,223 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"gotune/instruments/internal/entity"
+	"gotune/instruments/internal/repository"
+	"gotune/instruments/proto"
+)
+
+const maxImportErrors = 1000
+
+// ImportInstruments создаёт и обновляет инструменты из потока строк
+// каталога. Ошибки строк попадают в отчёт и не прерывают импорт; поток
+// обрывает только ошибка базы. В режиме dry_run строки проверяются так
+// же, но ничего не записывается.
+func (s *InstrumentService) ImportInstruments(stream proto.InstrumentService_ImportInstrumentsServer) error {
+	ctx := stream.Context()
+	resp := &proto.ImportInstrumentsResponse{}
+	batch := newImportBatch()
+
+	for first := true; ; first = false {
+		row, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if first {
+			resp.DryRun = row.DryRun
+		}
+
+		created, err := s.importRow(ctx, row, batch, resp.DryRun)
+		if st, ok := status.FromError(err); err != nil && ok {
+			resp.Failed++
+			if len(resp.Errors) < maxImportErrors {
+				resp.Errors = append(resp.Errors, &proto.ImportRowError{
+					Line:  row.Line,
+					Name:  row.GetInstrument().GetName(),
+					Error: st.Message(),
+				})
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if created {
+			resp.Created++
+		} else {
+			resp.Updated++
+		}
+	}
+
+	if !resp.DryRun && resp.Created+resp.Updated > 0 {
+		s.cache.Del(ctx, allInstrumentsCacheKey)
+	}
+	return stream.SendAndClose(resp)
+}
+
+// importRow проверяет строку и записывает инструмент. Ошибки самой
+// строки возвращаются как gRPC-статус, остальные — ошибки базы.
+func (s *InstrumentService) importRow(ctx context.Context, row *proto.ImportInstrumentRow, batch *importBatch, dryRun bool) (bool, error) {
+	req := row.GetInstrument()
+	if err := validateImportRow(req); err != nil {
+		return false, err
+	}
+	instrument := &entity.Instrument{
+		Name:            strings.TrimSpace(req.Name),
+		Description:     req.Description,
+		Price:           req.Price,
+		WeightKg:        req.WeightKg,
+		LengthCm:        req.LengthCm,
+		WidthCm:         req.WidthCm,
+		HeightCm:        req.HeightCm,
+		TaxCategory:     req.TaxCategory,
+		Category:        req.Category,
+		Brand:           req.Brand,
+		MaxCartQuantity: req.MaxCartQuantity,
+		Stock:           req.Stock,
+	}
+	if err := s.resolveCatalog(ctx, instrument, req.Attributes); err != nil {
+		return false, err
+	}
+	if err := applyVariants(instrument, req.Variants); err != nil {
+		return false, err
+	}
+	if err := batch.add(row.Line, instrument); err != nil {
+		return false, err
+	}
+
+	existing, err := s.findImportTarget(ctx, instrument)
+	if err != nil {
+		return false, err
+	}
+	if dryRun {
+		return existing == nil, nil
+	}
+
+	if existing == nil {
+		instrument.Version = 1
+		id, err := s.repo.Create(ctx, instrument)
+		if mongo.IsDuplicateKeyError(err) {
+			return false, status.Errorf(codes.AlreadyExists, "инструмент с таким названием или SKU уже существует")
+		}
+		if err != nil {
+			return false, err
+		}
+		_ = s.eventPublisher.Publish("instrument_created", map[string]string{
+			"id": id.Hex(),
+		})
+		return true, nil
+	}
+
+	instrument.Version = existing.Version
+	err = s.repo.UpdateByID(ctx, existing.ID, instrument)
+	if mongo.IsDuplicateKeyError(err) {
+		return false, status.Errorf(codes.AlreadyExists, "инструмент с таким названием или SKU уже существует")
+	}
+	if err == mongo.ErrNoDocuments || errors.Is(err, repository.ErrVersionConflict) {
+		return false, status.Errorf(codes.Aborted, "инструмент %q изменён во время импорта", instrument.Name)
+	}
+	if err != nil {
+		return false, err
+	}
+	if instrument.Price != existing.Price {
+		s.recordPriceChange(ctx, existing.ID, existing.Price, instrument.Price, entity.PriceSourceManual, nil)
+	}
+	s.instrumentUpdated(ctx, existing.ID.Hex(), instrument)
+	return false, nil
+}
+
+// findImportTarget ищет инструмент, который обновит строка: сначала по
+// SKU вариантов, затем по названию. Совпадения с разными инструментами —
+// ошибка строки.
+func (s *InstrumentService) findImportTarget(ctx context.Context, instrument *entity.Instrument) (*entity.Instrument, error) {
+	var target *entity.Instrument
+	for _, v := range instrument.Variants {
+		found, err := s.repo.FindBySKU(ctx, v.SKU)
+		if err == mongo.ErrNoDocuments {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if target != nil && target.ID != found.ID {
+			return nil, status.Errorf(codes.InvalidArgument, "SKU %s принадлежит другому инструменту (%s)", v.SKU, found.Name)
+		}
+		target = found
+	}
+
+	byName, err := s.repo.FindByName(ctx, instrument.Name)
+	if err == mongo.ErrNoDocuments {
+		return target, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if target != nil && target.ID != byName.ID {
+		return nil, status.Errorf(codes.InvalidArgument, "название %q занято другим инструментом", instrument.Name)
+	}
+	return byName, nil
+}
+
+// ExportInstruments отдаёт инструменты каталога по одному в порядке
+// создания.
+func (s *InstrumentService) ExportInstruments(req *proto.ExportInstrumentsRequest, stream proto.InstrumentService_ExportInstrumentsServer) error {
+	return s.repo.ForEach(stream.Context(), req.Category, func(inst *entity.Instrument) error {
+		return stream.Send(instrumentToProto(inst))
+	})
+}
+
+func validateImportRow(req *proto.CreateInstrumentRequest) error {
+	if req == nil || strings.TrimSpace(req.Name) == "" {
+		return status.Errorf(codes.InvalidArgument, "не указано название")
+	}
+	if req.Price <= 0 {
+		return status.Errorf(codes.InvalidArgument, "цена должна быть больше нуля")
+	}
+	if req.Stock < 0 || req.MaxCartQuantity < 0 {
+		return status.Errorf(codes.InvalidArgument, "остаток и ограничение корзины не могут быть отрицательными")
+	}
+	if req.WeightKg < 0 || req.LengthCm < 0 || req.WidthCm < 0 || req.HeightCm < 0 {
+		return status.Errorf(codes.InvalidArgument, "вес и габариты не могут быть отрицательными")
+	}
+	return nil
+}
+
+// importBatch помнит названия и SKU уже принятых строк, чтобы строка,
+// повторяющая предыдущую, не перезаписала её молча.
+type importBatch struct {
+	names map[string]int32
+	skus  map[string]int32
+}
+
+func newImportBatch() *importBatch {
+	return &importBatch{names: map[string]int32{}, skus: map[string]int32{}}
+}
+
+func (b *importBatch) add(line int32, instrument *entity.Instrument) error {
+	if prev, ok := b.names[instrument.Name]; ok {
+		return status.Errorf(codes.InvalidArgument, "название %q уже встречалось в строке %d", instrument.Name, prev)
+	}
+	for _, v := range instrument.Variants {
+		if prev, ok := b.skus[v.SKU]; ok {
+			return status.Errorf(codes.InvalidArgument, "SKU %s уже встречался в строке %d", v.SKU, prev)
+		}
+	}
+
+	b.names[instrument.Name] = line
+	for _, v := range instrument.Variants {
+		b.skus[v.SKU] = line
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"gotune/instruments/internal/entity"
+	"gotune/instruments/internal/repository"
+	"gotune/instruments/proto"
+)
+
+const (
+	defaultSearchPageSize = 20
+	maxSearchPageSize     = 100
+)
+
+// SearchInstruments ищет инструменты с фильтрами и постраничной выдачей.
+// Итоговое количество и фасеты считаются только для первой страницы.
+func (s *InstrumentService) SearchInstruments(ctx context.Context, req *proto.SearchInstrumentsRequest) (*proto.SearchInstrumentsResponse, error) {
+	q, err := searchQueryFromProto(req)
+	if err != nil {
+		return nil, err
+	}
+	if len(req.Attributes) > 0 {
+		defs, err := s.searchAttributeDefs(ctx, req.Category)
+		if err != nil {
+			return nil, err
+		}
+		if q.Attributes, err = attributeFilters(defs, req.Attributes); err != nil {
+			return nil, err
+		}
+	}
+
+	instruments, err := s.repo.Search(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &proto.SearchInstrumentsResponse{}
+	pageSize := int(q.Limit - 1)
+	if len(instruments) > pageSize {
+		instruments = instruments[:pageSize]
+		resp.NextCursor = encodeCursor(nextCursor(q, instruments))
+	}
+	for i := range instruments {
+		resp.Instruments = append(resp.Instruments, instrumentToProto(&instruments[i]))
+	}
+
+	if q.After == nil {
+		facets, err := s.repo.Facets(ctx, q)
+		if err != nil {
+			return nil, err
+		}
+		resp.Total = facets.Total
+		resp.Facets = []*proto.Facet{
+			facetToProto("category", facets.Categories),
+			facetToProto("brand", facets.Brands),
+			facetToProto("availability", facets.Availability),
+		}
+	}
+
+	return resp, nil
+}
+
+func searchQueryFromProto(req *proto.SearchInstrumentsRequest) (repository.SearchQuery, error) {
+	q := repository.SearchQuery{
+		Text:      strings.TrimSpace(req.Query),
+		MinPrice:  req.MinPrice,
+		MaxPrice:  req.MaxPrice,
+		Category:  req.Category,
+		Brand:     req.Brand,
+		InStock:   req.InStock,
+		Condition: req.Condition,
+		Sort:      req.Sort,
+	}
+
+	switch q.Condition {
+	case "", repository.ConditionFilterNew, repository.ConditionFilterUsed:
+	default:
+		return q, status.Errorf(codes.InvalidArgument, "фильтр состояния должен быть new или used")
+	}
+
+	if q.MinPrice < 0 || q.MaxPrice < 0 || (q.MaxPrice > 0 && q.MinPrice > q.MaxPrice) {
+		return q, status.Errorf(codes.InvalidArgument, "некорректный диапазон цен")
+	}
+
+	switch q.Sort {
+	case "":
+		q.Sort = repository.SortRelevance
+	case repository.SortRelevance, repository.SortPriceAsc, repository.SortPriceDesc,
+		repository.SortName, repository.SortNewest:
+	default:
+		return q, status.Errorf(codes.InvalidArgument, "неизвестная сортировка %q", q.Sort)
+	}
+	// без поискового запроса релевантность не определена
+	if q.Sort == repository.SortRelevance && q.Text == "" {
+		q.Sort = repository.SortNewest
+	}
+
+	pageSize := int64(req.PageSize)
+	if pageSize <= 0 {
+		pageSize = defaultSearchPageSize
+	}
+	if pageSize > maxSearchPageSize {
+		pageSize = maxSearchPageSize
+	}
+	// лишний товар показывает, есть ли следующая страница
+	q.Limit = pageSize + 1
+
+	if req.Cursor != "" {
+		after, err := decodeCursor(req.Cursor)
+		if err != nil || after.Sort != q.Sort {
+			return q, status.Errorf(codes.InvalidArgument, "некорректный курсор")
+		}
+		q.After = after
+	}
+
+	return q, nil
+}
+
+func nextCursor(q repository.SearchQuery, page []entity.Instrument) *repository.SearchCursor {
+	last := page[len(page)-1]
+	c := &repository.SearchCursor{Sort: q.Sort, ID: last.ID}
+	switch q.Sort {
+	case repository.SortRelevance:
+		c.Offset = int64(len(page))
+		if q.After != nil {
+			c.Offset += q.After.Offset
+		}
+	case repository.SortPriceAsc, repository.SortPriceDesc:
+		c.Price = last.Price
+	case repository.SortName:
+		c.Name = last.Name
+	}
+	return c
+}
+
+func encodeCursor(c *repository.SearchCursor) string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(s string) (*repository.SearchCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	var c repository.SearchCursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func facetToProto(field string, buckets []repository.FacetBucket) *proto.Facet {
+	facet := &proto.Facet{Field: field}
+	for _, b := range buckets {
+		facet.Buckets = append(facet.Buckets, &proto.FacetBucket{Value: b.Value, Count: b.Count})
+	}
+	return facet
+}
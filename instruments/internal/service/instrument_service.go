@@ -3,16 +3,23 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"gotune/events"
 	"gotune/instruments/internal/entity"
 	"gotune/instruments/internal/repository"
+	"gotune/instruments/internal/storage"
 	"gotune/instruments/metrics"
 	"gotune/instruments/proto"
+	orderproto "gotune/order/proto"
+	usersproto "gotune/users/proto"
 )
 
 const (
@@ -24,14 +31,45 @@ const (
 
 type InstrumentService struct {
 	repo           repository.InstrumentRepository
+	categoryRepo   repository.CategoryRepository
+	brandRepo      repository.BrandRepository
+	blobs          storage.BlobStore
+	prices         repository.PriceRepository
+	reviews        repository.ReviewRepository
+	serials        repository.SerialUnitRepository
+	tradeIns       repository.TradeInRepository
+	orderClient    orderproto.OrderServiceClient
+	userClient     usersproto.UserServiceClient
 	eventPublisher *events.EventPublisher
 	cache          *redis.Client
 	proto.UnimplementedInstrumentServiceServer
 }
 
-func NewInstrumentService(repo repository.InstrumentRepository, publisher *events.EventPublisher, cache *redis.Client) *InstrumentService {
+func NewInstrumentService(
+	repo repository.InstrumentRepository,
+	categoryRepo repository.CategoryRepository,
+	brandRepo repository.BrandRepository,
+	blobs storage.BlobStore,
+	prices repository.PriceRepository,
+	reviews repository.ReviewRepository,
+	serials repository.SerialUnitRepository,
+	tradeIns repository.TradeInRepository,
+	orderClient orderproto.OrderServiceClient,
+	userClient usersproto.UserServiceClient,
+	publisher *events.EventPublisher,
+	cache *redis.Client,
+) *InstrumentService {
 	return &InstrumentService{
 		repo:           repo,
+		categoryRepo:   categoryRepo,
+		brandRepo:      brandRepo,
+		blobs:          blobs,
+		prices:         prices,
+		reviews:        reviews,
+		serials:        serials,
+		tradeIns:       tradeIns,
+		orderClient:    orderClient,
+		userClient:     userClient,
 		eventPublisher: publisher,
 		cache:          cache,
 	}
@@ -42,14 +80,33 @@ func (s *InstrumentService) CreateInstrument(ctx context.Context, req *proto.Cre
 	start := time.Now()
 
 	instrument := &entity.Instrument{
-		Name:        req.Name,
-		Description: req.Description,
-		Price:       req.Price,
+		Name:            req.Name,
+		Description:     req.Description,
+		Price:           req.Price,
+		WeightKg:        req.WeightKg,
+		LengthCm:        req.LengthCm,
+		WidthCm:         req.WidthCm,
+		HeightCm:        req.HeightCm,
+		TaxCategory:     req.TaxCategory,
+		Category:        req.Category,
+		Brand:           req.Brand,
+		MaxCartQuantity: req.MaxCartQuantity,
+		Stock:           req.Stock,
+		Version:         1,
+	}
+	if err := s.resolveCatalog(ctx, instrument, req.Attributes); err != nil {
+		return nil, err
+	}
+	if err := applyVariants(instrument, req.Variants); err != nil {
+		return nil, err
 	}
 	id, err := s.repo.Create(ctx, instrument)
 	duration := time.Since(start).Seconds()
 	metrics.InstrumentCreateDuration.Observe(duration)
 
+	if mongo.IsDuplicateKeyError(err) {
+		return nil, status.Errorf(codes.AlreadyExists, "инструмент с таким названием или SKU уже существует")
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -88,12 +145,7 @@ func (s *InstrumentService) GetInstrumentByID(ctx context.Context, req *proto.Ge
 		return nil, err
 	}
 
-	resp := &proto.Instrument{
-		Id:          inst.ID.Hex(),
-		Name:        inst.Name,
-		Description: inst.Description,
-		Price:       inst.Price,
-	}
+	resp := instrumentToProto(inst)
 
 	data, _ := json.Marshal(resp)
 	s.cache.Set(ctx, cacheKey, data, instrumentCacheExpTime)
@@ -117,12 +169,7 @@ func (s *InstrumentService) GetAllInstruments(ctx context.Context, req *proto.Ge
 
 	var protoInstruments []*proto.Instrument
 	for _, inst := range instruments {
-		protoInstruments = append(protoInstruments, &proto.Instrument{
-			Id:          inst.ID.Hex(),
-			Name:        inst.Name,
-			Description: inst.Description,
-			Price:       inst.Price,
-		})
+		protoInstruments = append(protoInstruments, instrumentToProto(&inst))
 	}
 
 	resp := &proto.GetAllInstrumentsResponse{
@@ -135,48 +182,207 @@ func (s *InstrumentService) GetAllInstruments(ctx context.Context, req *proto.Ge
 	return resp, nil
 }
 
+// UpdateInstrumentByID с маской обновляет только перечисленные поля,
+// без маски заменяет все поля инструмента значениями из запроса.
+// Запрос проходит, только если версия инструмента равна expected_version,
+// иначе ABORTED: клиент должен перечитать инструмент. Изменение цены
+// записывается в историю цен.
 func (s *InstrumentService) UpdateInstrumentByID(ctx context.Context, req *proto.UpdateInstrumentByIDRequest) (*proto.UpdateInstrumentByIDResponse, error) {
 	id, err := primitive.ObjectIDFromHex(req.Id)
 	if err != nil {
 		return nil, err
 	}
+	if req.ExpectedVersion <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "не указана версия инструмента")
+	}
 
-	instrument := &entity.Instrument{
-		Name:        req.Name,
-		Description: req.Description,
-		Price:       req.Price,
+	current, err := s.repo.FindByID(ctx, id)
+	if err == mongo.ErrNoDocuments {
+		return nil, status.Errorf(codes.NotFound, "инструмент %s не найден", req.Id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	// проверяем сразу, чтобы не проверять атрибуты устаревшей версии;
+	// окончательно версию сверяет запись в репозитории, поэтому старая
+	// цена для истории верна
+	if current.Version != req.ExpectedVersion {
+		return nil, versionConflict(req.Id)
+	}
+	oldPrice := current.Price
+
+	var instrument *entity.Instrument
+	var fields []string
+	if len(req.GetUpdateMask().GetPaths()) > 0 {
+		if instrument, fields, err = s.maskedUpdate(ctx, current, req); err != nil {
+			return nil, err
+		}
+	} else {
+		instrument = &entity.Instrument{
+			Name:            req.Name,
+			Description:     req.Description,
+			Price:           req.Price,
+			WeightKg:        req.WeightKg,
+			LengthCm:        req.LengthCm,
+			WidthCm:         req.WidthCm,
+			HeightCm:        req.HeightCm,
+			TaxCategory:     req.TaxCategory,
+			Category:        req.Category,
+			Brand:           req.Brand,
+			MaxCartQuantity: req.MaxCartQuantity,
+			Stock:           req.Stock,
+			Version:         req.ExpectedVersion,
+		}
+		if err := s.resolveCatalog(ctx, instrument, req.Attributes); err != nil {
+			return nil, err
+		}
+		if err := applyVariants(instrument, req.Variants); err != nil {
+			return nil, err
+		}
+	}
+
+	err = s.repo.UpdateByID(ctx, id, instrument, fields...)
+	if mongo.IsDuplicateKeyError(err) {
+		return nil, status.Errorf(codes.AlreadyExists, "инструмент с таким названием или SKU уже существует")
+	}
+	if errors.Is(err, repository.ErrVersionConflict) {
+		return nil, versionConflict(req.Id)
+	}
+	if err == mongo.ErrNoDocuments {
+		return nil, status.Errorf(codes.NotFound, "инструмент %s не найден", req.Id)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if instrument.Price != oldPrice {
+		s.recordPriceChange(ctx, id, oldPrice, instrument.Price, entity.PriceSourceManual, nil)
+	}
+	s.instrumentUpdated(ctx, req.Id, instrument)
+
+	return &proto.UpdateInstrumentByIDResponse{Success: true, Version: instrument.Version}, nil
+}
+
+// DeleteInstrumentByID скрывает инструмент из каталога. Документ и файлы
+// остаются для истории заказов и восстановления, окончательно их удаляет
+// PurgeJob.
+func (s *InstrumentService) DeleteInstrumentByID(ctx context.Context, req *proto.DeleteInstrumentByIDRequest) (*proto.DeleteInstrumentByIDResponse, error) {
+	id, err := primitive.ObjectIDFromHex(req.Id)
+	if err != nil {
+		return nil, err
 	}
 
-	if err := s.repo.UpdateByID(ctx, id, instrument); err != nil {
+	err = s.repo.SoftDeleteByID(ctx, id, time.Now())
+	if err == mongo.ErrNoDocuments {
+		return nil, status.Errorf(codes.NotFound, "инструмент %s не найден", req.Id)
+	}
+	if err != nil {
 		return nil, err
 	}
 
 	s.cache.Del(ctx, instrumentCacheKeyPrefix+req.Id)
 	s.cache.Del(ctx, allInstrumentsCacheKey)
 
-	_ = s.eventPublisher.Publish("instrument_updated", map[string]string{
+	_ = s.eventPublisher.Publish("instrument_deleted", map[string]string{
 		"id": req.Id,
 	})
 
-	return &proto.UpdateInstrumentByIDResponse{Success: true}, nil
+	return &proto.DeleteInstrumentByIDResponse{Success: true}, nil
 }
 
-func (s *InstrumentService) DeleteInstrumentByID(ctx context.Context, req *proto.DeleteInstrumentByIDRequest) (*proto.DeleteInstrumentByIDResponse, error) {
+func (s *InstrumentService) RestoreInstrument(ctx context.Context, req *proto.RestoreInstrumentRequest) (*proto.RestoreInstrumentResponse, error) {
 	id, err := primitive.ObjectIDFromHex(req.Id)
 	if err != nil {
-		return nil, err
+		return nil, status.Errorf(codes.InvalidArgument, "invalid instrument ID: %v", err)
 	}
 
-	if err := s.repo.DeleteByID(ctx, id); err != nil {
+	err = s.repo.RestoreByID(ctx, id)
+	if err == mongo.ErrNoDocuments {
+		return nil, status.Errorf(codes.NotFound, "удалённый инструмент %s не найден", req.Id)
+	}
+	if mongo.IsDuplicateKeyError(err) {
+		return nil, status.Errorf(codes.AlreadyExists, "название или SKU инструмента %s заняты другим инструментом", req.Id)
+	}
+	if err != nil {
 		return nil, err
 	}
 
 	s.cache.Del(ctx, instrumentCacheKeyPrefix+req.Id)
 	s.cache.Del(ctx, allInstrumentsCacheKey)
 
-	_ = s.eventPublisher.Publish("instrument_deleted", map[string]string{
+	_ = s.eventPublisher.Publish("instrument_restored", map[string]string{
 		"id": req.Id,
 	})
 
-	return &proto.DeleteInstrumentByIDResponse{Success: true}, nil
+	return &proto.RestoreInstrumentResponse{Success: true}, nil
+}
+
+// maskedUpdate применяет маску к загруженному инструменту и возвращает
+// его вместе с полями документа для записи. Атрибуты перепроверяются при
+// смене категории, остаток товара с вариантами задаётся только через
+// варианты.
+func (s *InstrumentService) maskedUpdate(ctx context.Context, current *entity.Instrument, req *proto.UpdateInstrumentByIDRequest) (*entity.Instrument, []string, error) {
+	paths, err := instrumentMaskPaths(req.UpdateMask)
+	if err != nil {
+		return nil, nil, err
+	}
+	instrument := current
+
+	applyInstrumentMask(instrument, req, paths)
+	if contains(paths, "category") || contains(paths, "brand") || contains(paths, "attributes") {
+		values := req.Attributes
+		if !contains(paths, "attributes") {
+			values = attributesToProto(instrument.Attributes)
+		}
+		if err := s.resolveCatalog(ctx, instrument, values); err != nil {
+			return nil, nil, err
+		}
+	}
+	if contains(paths, "variants") {
+		if err := applyVariants(instrument, req.Variants); err != nil {
+			return nil, nil, err
+		}
+	} else if contains(paths, "stock") && len(instrument.Variants) > 0 {
+		return nil, nil, status.Errorf(codes.InvalidArgument, "остаток товара с вариантами задаётся в вариантах")
+	}
+	return instrument, instrumentMaskColumns(paths), nil
+}
+
+func versionConflict(id string) error {
+	return status.Errorf(codes.Aborted, "инструмент %s изменён другим пользователем, обновите данные и повторите", id)
+}
+
+func instrumentToProto(inst *entity.Instrument) *proto.Instrument {
+	return &proto.Instrument{
+		Id:              inst.ID.Hex(),
+		Name:            inst.Name,
+		Description:     inst.Description,
+		Price:           inst.Price,
+		WeightKg:        inst.WeightKg,
+		LengthCm:        inst.LengthCm,
+		WidthCm:         inst.WidthCm,
+		HeightCm:        inst.HeightCm,
+		TaxCategory:     inst.TaxCategory,
+		Category:        inst.Category,
+		Brand:           inst.Brand,
+		MaxCartQuantity: inst.MaxCartQuantity,
+		Stock:           inst.Stock,
+		Attributes:      attributesToProto(inst.Attributes),
+		CategoryPath:    inst.CategoryPath,
+		Variants:        variantsToProto(inst.Variants),
+		Media:           mediaListToProto(inst.Media),
+		Version:         inst.Version,
+		RatingAverage:   inst.RatingAverage,
+		RatingCount:     inst.RatingCount,
+		Used:            inst.Used,
+		Condition:       inst.Condition,
+		TradeInId:       tradeInIDHex(inst.TradeInID),
+	}
+}
+
+func tradeInIDHex(id *primitive.ObjectID) string {
+	if id == nil {
+		return ""
+	}
+	return id.Hex()
 }
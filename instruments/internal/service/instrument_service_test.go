@@ -2,8 +2,17 @@ package service
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+
+	"gotune/instruments/internal/entity"
+	"gotune/instruments/internal/repository"
+	"gotune/instruments/proto"
 )
 
 type Instrument struct {
@@ -29,3 +38,321 @@ func TestValidateInstrumentData(t *testing.T) {
 	assert.True(t, instr.Price > 0)
 	assert.True(t, instr.Stock >= 0)
 }
+
+func TestSearchQueryDefaults(t *testing.T) {
+	q, err := searchQueryFromProto(&proto.SearchInstrumentsRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, repository.SortNewest, q.Sort)
+	assert.Equal(t, int64(defaultSearchPageSize+1), q.Limit)
+
+	q, err = searchQueryFromProto(&proto.SearchInstrumentsRequest{Query: " stratocaster ", PageSize: 500})
+	assert.NoError(t, err)
+	assert.Equal(t, repository.SortRelevance, q.Sort)
+	assert.Equal(t, "stratocaster", q.Text)
+	assert.Equal(t, int64(maxSearchPageSize+1), q.Limit)
+}
+
+func TestSearchQueryValidation(t *testing.T) {
+	_, err := searchQueryFromProto(&proto.SearchInstrumentsRequest{MinPrice: 500, MaxPrice: 100})
+	assert.Error(t, err)
+	_, err = searchQueryFromProto(&proto.SearchInstrumentsRequest{Sort: "popularity"})
+	assert.Error(t, err)
+	_, err = searchQueryFromProto(&proto.SearchInstrumentsRequest{Cursor: "not a cursor"})
+	assert.Error(t, err)
+	_, err = searchQueryFromProto(&proto.SearchInstrumentsRequest{Condition: entity.ConditionGood})
+	assert.Error(t, err)
+	_, err = searchQueryFromProto(&proto.SearchInstrumentsRequest{Condition: repository.ConditionFilterUsed})
+	assert.NoError(t, err)
+}
+
+func TestSearchCursorRoundTrip(t *testing.T) {
+	page := []entity.Instrument{
+		{ID: primitive.NewObjectID(), Name: "Gibson SG", Price: 1500},
+		{ID: primitive.NewObjectID(), Name: "Ibanez RG", Price: 900},
+	}
+	q := repository.SearchQuery{Sort: repository.SortPriceDesc}
+
+	cursor := encodeCursor(nextCursor(q, page))
+	next, err := searchQueryFromProto(&proto.SearchInstrumentsRequest{Sort: repository.SortPriceDesc, Cursor: cursor})
+	assert.NoError(t, err)
+	assert.Equal(t, 900.0, next.After.Price)
+	assert.Equal(t, page[1].ID, next.After.ID)
+
+	// курсор другой сортировки не принимается
+	_, err = searchQueryFromProto(&proto.SearchInstrumentsRequest{Sort: repository.SortName, Cursor: cursor})
+	assert.Error(t, err)
+}
+
+func TestRelevanceCursorAccumulatesOffset(t *testing.T) {
+	page := []entity.Instrument{{ID: primitive.NewObjectID()}, {ID: primitive.NewObjectID()}}
+	q := repository.SearchQuery{Sort: repository.SortRelevance, After: &repository.SearchCursor{Offset: 20}}
+
+	assert.Equal(t, int64(22), nextCursor(q, page).Offset)
+}
+
+func TestAttributesFromProto(t *testing.T) {
+	defs := []entity.AttributeDef{
+		{Key: "strings", Type: entity.AttributeNumber, Required: true},
+		{Key: "body_wood", Type: entity.AttributeEnum, Values: []string{"alder", "mahogany"}},
+		{Key: "right_handed", Type: entity.AttributeBoolean},
+	}
+
+	attrs, err := attributesFromProto(defs, []*proto.AttributeValue{
+		{Key: "strings", NumberValue: 6},
+		{Key: "body_wood", EnumValue: "mahogany"},
+		{Key: "right_handed", BoolValue: true},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"strings": 6.0, "body_wood": "mahogany", "right_handed": true}, attrs)
+	assert.Len(t, attributesToProto(attrs), 3)
+
+	_, err = attributesFromProto(defs, []*proto.AttributeValue{{Key: "body_wood", EnumValue: "mahogany"}})
+	assert.Error(t, err, "обязательный атрибут")
+	_, err = attributesFromProto(defs, []*proto.AttributeValue{{Key: "strings", NumberValue: 6}, {Key: "body_wood", EnumValue: "plastic"}})
+	assert.Error(t, err, "значение вне enum")
+	_, err = attributesFromProto(defs, []*proto.AttributeValue{{Key: "strings", NumberValue: 6}, {Key: "color"}})
+	assert.Error(t, err, "атрибут не из категории")
+}
+
+func TestValidateAttributeDefs(t *testing.T) {
+	inherited := []entity.AttributeDef{{Key: "strings", Type: entity.AttributeNumber}}
+
+	assert.NoError(t, validateAttributeDefs([]entity.AttributeDef{
+		{Key: "pickups", Type: entity.AttributeEnum, Values: []string{"SSS", "HH"}},
+	}, inherited))
+	assert.Error(t, validateAttributeDefs([]entity.AttributeDef{{Key: "strings", Type: entity.AttributeNumber}}, inherited))
+	assert.Error(t, validateAttributeDefs([]entity.AttributeDef{{Key: "pickups", Type: entity.AttributeEnum}}, nil))
+	assert.Error(t, validateAttributeDefs([]entity.AttributeDef{{Key: "Body Wood", Type: entity.AttributeEnum, Values: []string{"alder"}}}, nil))
+	assert.Error(t, validateAttributeDefs([]entity.AttributeDef{{Key: "color", Type: "text"}}, nil))
+}
+
+func TestEffectiveAttributesInherited(t *testing.T) {
+	bySlug := map[string]entity.Category{
+		"guitars":    {Slug: "guitars", Attributes: []entity.AttributeDef{{Key: "strings"}}},
+		"electric":   {Slug: "electric", Attributes: []entity.AttributeDef{{Key: "pickups"}}},
+		"solid-body": {Slug: "solid-body"},
+	}
+
+	defs := effectiveAttributes([]string{"guitars", "electric", "solid-body"}, bySlug)
+	assert.Equal(t, []entity.AttributeDef{{Key: "strings"}, {Key: "pickups"}}, defs)
+}
+
+func TestAttributeFilters(t *testing.T) {
+	defs := map[string]entity.AttributeDef{
+		"strings":      {Key: "strings", Type: entity.AttributeNumber},
+		"right_handed": {Key: "right_handed", Type: entity.AttributeBoolean},
+	}
+
+	filters, err := attributeFilters(defs, []*proto.AttributeFilter{
+		{Key: "strings", Values: []string{"6", "7"}},
+		{Key: "right_handed", Values: []string{"true"}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{6.0, 7.0}, filters[0].Values)
+	assert.Equal(t, []interface{}{true}, filters[1].Values)
+
+	_, err = attributeFilters(defs, []*proto.AttributeFilter{{Key: "strings", Values: []string{"six"}}})
+	assert.Error(t, err)
+	_, err = attributeFilters(defs, []*proto.AttributeFilter{{Key: "color", Values: []string{"red"}}})
+	assert.Error(t, err)
+}
+
+func TestApplyVariants(t *testing.T) {
+	inst := &entity.Instrument{Name: "Fender Stratocaster", Stock: 100}
+	err := applyVariants(inst, []*proto.Variant{
+		{Sku: "strat-sb", Options: map[string]string{"color": "sunburst", "handedness": "right"}, Stock: 3},
+		{Sku: "STRAT-LH", Options: map[string]string{"color": "sunburst", "handedness": "left"}, Price: 1100, Stock: 1},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "STRAT-SB", inst.Variants[0].SKU)
+	assert.Equal(t, int32(4), inst.Stock)
+	assert.Equal(t, 1100.0, inst.UnitPrice(&inst.Variants[1]))
+
+	err = applyVariants(inst, []*proto.Variant{
+		{Sku: "A", Options: map[string]string{"color": "red"}},
+		{Sku: "B", Options: map[string]string{"finish": "matte"}},
+	})
+	assert.Error(t, err)
+
+	err = applyVariants(inst, []*proto.Variant{
+		{Sku: "A", Options: map[string]string{"color": "red"}},
+		{Sku: "B", Options: map[string]string{"color": "red"}},
+	})
+	assert.Error(t, err)
+
+	err = applyVariants(inst, []*proto.Variant{{Sku: "bad sku", Options: map[string]string{"color": "red"}}})
+	assert.Error(t, err)
+}
+
+func TestVariantTitle(t *testing.T) {
+	assert.Equal(t, "color: sunburst, handedness: left", variantTitle(map[string]string{"handedness": "left", "color": "sunburst"}))
+}
+
+func TestInstrumentMaskPaths(t *testing.T) {
+	paths, err := instrumentMaskPaths(&fieldmaskpb.FieldMask{Paths: []string{"price", "category", "price"}})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"category", "price"}, paths)
+	assert.Equal(t, []string{"attributes", "category", "category_path", "price"}, instrumentMaskColumns(paths))
+
+	_, err = instrumentMaskPaths(&fieldmaskpb.FieldMask{Paths: []string{"id"}})
+	assert.Error(t, err)
+}
+
+func TestApplyInstrumentMaskKeepsOtherFields(t *testing.T) {
+	inst := &entity.Instrument{Name: "Gibson Les Paul", Description: "Standard", Price: 2500}
+	applyInstrumentMask(inst, &proto.UpdateInstrumentByIDRequest{Price: 2300}, []string{"price"})
+
+	assert.Equal(t, "Gibson Les Paul", inst.Name)
+	assert.Equal(t, "Standard", inst.Description)
+	assert.Equal(t, 2300.0, inst.Price)
+}
+
+func TestNewPriceScheduleValidation(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	id := primitive.NewObjectID()
+
+	_, err := newPriceSchedule(id, &proto.SchedulePriceChangeRequest{Price: 0, StartsAt: now.Unix()}, now)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = newPriceSchedule(id, &proto.SchedulePriceChangeRequest{Price: 90, StartsAt: now.Unix(), EndsAt: now.Unix()}, now)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	schedule, err := newPriceSchedule(id, &proto.SchedulePriceChangeRequest{Price: 90, StartsAt: now.Unix()}, now)
+	assert.NoError(t, err)
+	assert.Nil(t, schedule.EndsAt)
+	assert.Equal(t, entity.ScheduleStatusPending, schedule.Status)
+}
+
+func TestFindOverlap(t *testing.T) {
+	at := func(h int) *time.Time {
+		v := time.Unix(1_700_000_000, 0).Add(time.Duration(h) * time.Hour)
+		return &v
+	}
+	open := []entity.PriceSchedule{
+		{StartsAt: *at(0), EndsAt: at(10)},
+		{StartsAt: *at(20)},
+	}
+
+	assert.Nil(t, findOverlap(open, &entity.PriceSchedule{StartsAt: *at(10), EndsAt: at(20)}))
+	assert.Equal(t, &open[0], findOverlap(open, &entity.PriceSchedule{StartsAt: *at(5), EndsAt: at(15)}))
+	assert.Equal(t, &open[1], findOverlap(open, &entity.PriceSchedule{StartsAt: *at(30), EndsAt: at(40)}))
+	assert.Equal(t, &open[0], findOverlap(open, &entity.PriceSchedule{StartsAt: *at(-5)}))
+}
+
+func TestValidateImportRow(t *testing.T) {
+	assert.NoError(t, validateImportRow(&proto.CreateInstrumentRequest{Name: "Fender Jazz Bass", Price: 1100}))
+
+	for _, req := range []*proto.CreateInstrumentRequest{
+		nil,
+		{Name: " ", Price: 100},
+		{Name: "Fender Jazz Bass"},
+		{Name: "Fender Jazz Bass", Price: 100, Stock: -1},
+		{Name: "Fender Jazz Bass", Price: 100, WeightKg: -4},
+	} {
+		assert.Equal(t, codes.InvalidArgument, status.Code(validateImportRow(req)))
+	}
+}
+
+func TestImportBatchRejectsRepeatedRows(t *testing.T) {
+	batch := newImportBatch()
+	strat := &entity.Instrument{Name: "Stratocaster", Variants: []entity.Variant{{SKU: "STRAT-BK"}}}
+	assert.NoError(t, batch.add(2, strat))
+
+	err := batch.add(5, &entity.Instrument{Name: "Stratocaster"})
+	assert.Contains(t, status.Convert(err).Message(), "строке 2")
+
+	err = batch.add(6, &entity.Instrument{Name: "Telecaster", Variants: []entity.Variant{{SKU: "STRAT-BK"}}})
+	assert.Contains(t, status.Convert(err).Message(), "строке 2")
+
+	assert.NoError(t, batch.add(7, &entity.Instrument{Name: "Telecaster"}))
+}
+
+func TestValidateReview(t *testing.T) {
+	assert.NoError(t, validateReview(5, "Отличный звук"))
+	assert.Equal(t, codes.InvalidArgument, status.Code(validateReview(0, "Отличный звук")))
+	assert.Equal(t, codes.InvalidArgument, status.Code(validateReview(6, "Отличный звук")))
+	assert.Equal(t, codes.InvalidArgument, status.Code(validateReview(4, "")))
+}
+
+func TestReviewQueryDefaults(t *testing.T) {
+	q, err := reviewQueryFromProto(&proto.GetReviewsRequest{})
+	assert.NoError(t, err)
+	assert.True(t, q.InstrumentID.IsZero())
+	assert.Equal(t, entity.ReviewStatusApproved, q.Status)
+	assert.Equal(t, repository.ReviewSortNewest, q.Sort)
+	assert.Equal(t, int64(defaultReviewPageSize), q.Limit)
+
+	q, err = reviewQueryFromProto(&proto.GetReviewsRequest{Status: entity.ReviewStatusPending, Limit: 1000})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(maxReviewPageSize), q.Limit)
+
+	_, err = reviewQueryFromProto(&proto.GetReviewsRequest{Sort: "oldest"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = reviewQueryFromProto(&proto.GetReviewsRequest{Status: "hidden"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestValidateSerialUnitUpdate(t *testing.T) {
+	assert.NoError(t, validateSerialUnitUpdate(repository.SerialUnitUpdate{}))
+	assert.NoError(t, validateSerialUnitUpdate(repository.SerialUnitUpdate{Condition: entity.ConditionVeryGood, Status: entity.SerialStatusRented}))
+
+	assert.Equal(t, codes.InvalidArgument, status.Code(validateSerialUnitUpdate(repository.SerialUnitUpdate{Status: entity.SerialStatusSold})))
+	assert.Equal(t, codes.InvalidArgument, status.Code(validateSerialUnitUpdate(repository.SerialUnitUpdate{Status: "lost"})))
+	assert.Equal(t, codes.InvalidArgument, status.Code(validateSerialUnitUpdate(repository.SerialUnitUpdate{Condition: "like new"})))
+}
+
+func TestCheckUnitVariant(t *testing.T) {
+	plain := &entity.Instrument{}
+	assert.NoError(t, checkUnitVariant(plain, ""))
+	assert.Error(t, checkUnitVariant(plain, "STRAT-BK"))
+
+	withVariants := &entity.Instrument{Variants: []entity.Variant{{SKU: "STRAT-BK"}, {SKU: "STRAT-RD"}}}
+	assert.NoError(t, checkUnitVariant(withVariants, "STRAT-RD"))
+	assert.Error(t, checkUnitVariant(withVariants, ""))
+	assert.Error(t, checkUnitVariant(withVariants, "STRAT-WH"))
+}
+
+func TestSerialAllocationsGroupByItem(t *testing.T) {
+	guitar := primitive.NewObjectID()
+	amp := primitive.NewObjectID()
+	units := []entity.SerialUnit{
+		{InstrumentID: guitar, SKU: "STRAT-BK", SerialNumber: "US1"},
+		{InstrumentID: amp, SerialNumber: "AMP1"},
+		{InstrumentID: guitar, SKU: "STRAT-BK", SerialNumber: "US2"},
+	}
+
+	items := serialAllocations(units)
+	assert.Len(t, items, 2)
+	assert.Equal(t, guitar.Hex(), items[0].InstrumentId)
+	assert.Equal(t, int32(2), items[0].Quantity)
+	assert.Equal(t, []string{"US1", "US2"}, items[0].SerialNumbers)
+	assert.Equal(t, []string{"AMP1"}, items[1].SerialNumbers)
+}
+
+func TestValidateTradeIn(t *testing.T) {
+	assert.NoError(t, validateTradeIn(&proto.SubmitTradeInRequest{Name: "Fender Stratocaster", Condition: entity.ConditionGood}))
+
+	assert.Equal(t, codes.InvalidArgument, status.Code(validateTradeIn(&proto.SubmitTradeInRequest{Name: " ", Condition: entity.ConditionGood})))
+	assert.Equal(t, codes.InvalidArgument, status.Code(validateTradeIn(&proto.SubmitTradeInRequest{Name: "Fender Stratocaster", Condition: entity.ConditionNew})))
+	assert.Equal(t, codes.InvalidArgument, status.Code(validateTradeIn(&proto.SubmitTradeInRequest{Name: "Fender Stratocaster"})))
+}
+
+func TestValidateAppraisal(t *testing.T) {
+	assert.NoError(t, validateAppraisal(&proto.AppraiseTradeInRequest{Condition: entity.ConditionFair, OfferAmount: 150}))
+	assert.NoError(t, validateAppraisal(&proto.AppraiseTradeInRequest{Reject: true}))
+
+	assert.Equal(t, codes.InvalidArgument, status.Code(validateAppraisal(&proto.AppraiseTradeInRequest{Condition: entity.ConditionFair})))
+	assert.Equal(t, codes.InvalidArgument, status.Code(validateAppraisal(&proto.AppraiseTradeInRequest{Condition: "broken", OfferAmount: 150})))
+}
+
+func TestUsedListingNameIsUniquePerTradeIn(t *testing.T) {
+	first := &entity.TradeIn{ID: primitive.NewObjectID(), Name: "Gibson Les Paul"}
+	second := &entity.TradeIn{ID: primitive.NewObjectID(), Name: "Gibson Les Paul"}
+
+	assert.Contains(t, usedListingName(first), "Gibson Les Paul (б/у, ")
+	assert.NotEqual(t, usedListingName(first), usedListingName(second))
+}
+
+func TestVersionConflictIsAborted(t *testing.T) {
+	assert.Equal(t, codes.Aborted, status.Code(versionConflict("64b0c0ffee")))
+}
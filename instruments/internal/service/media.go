@@ -0,0 +1,260 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"gotune/instruments/internal/entity"
+	"gotune/instruments/internal/media"
+	"gotune/instruments/internal/repository"
+	"gotune/instruments/internal/storage"
+	"gotune/instruments/proto"
+)
+
+const (
+	maxImageBytes    = 10 << 20
+	maxAudioBytes    = 20 << 20
+	minAudioDuration = time.Second
+	maxAudioDuration = 5 * time.Minute
+)
+
+var imageExtensions = map[string]string{
+	"jpeg": ".jpg",
+	"png":  ".png",
+	"gif":  ".gif",
+}
+
+// UploadInstrumentMedia принимает файл частями. Изображение сохраняется
+// вместе с миниатюрой, у аудиопримера проверяются формат и длительность.
+func (s *InstrumentService) UploadInstrumentMedia(stream proto.InstrumentService_UploadInstrumentMediaServer) error {
+	ctx := stream.Context()
+
+	first, err := stream.Recv()
+	if err == io.EOF {
+		return status.Errorf(codes.InvalidArgument, "пустой файл")
+	}
+	if err != nil {
+		return err
+	}
+	id, err := primitive.ObjectIDFromHex(first.InstrumentId)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid instrument ID: %v", err)
+	}
+	limit := 0
+	switch first.Kind {
+	case entity.MediaImage:
+		limit = maxImageBytes
+	case entity.MediaAudio:
+		limit = maxAudioBytes
+	default:
+		return status.Errorf(codes.InvalidArgument, "неизвестный тип файла %q, допустимы image и audio", first.Kind)
+	}
+	if _, err := s.repo.FindByID(ctx, id); err != nil {
+		return status.Errorf(codes.NotFound, "инструмент %s не найден", first.InstrumentId)
+	}
+
+	data, err := readUpload(first, stream.Recv, limit)
+	if err != nil {
+		return err
+	}
+
+	m := newMedia(first.Kind, first.Filename, len(data))
+	var blobs map[string][]byte
+	if first.Kind == entity.MediaImage {
+		blobs, err = prepareImage(m, instrumentMediaDir(id), data)
+	} else {
+		blobs, err = prepareAudio(m, instrumentMediaDir(id), data)
+	}
+	if err != nil {
+		return err
+	}
+	if err := s.putBlobs(ctx, m, blobs); err != nil {
+		return err
+	}
+
+	if err := s.repo.AddMedia(ctx, id, m); err != nil {
+		deleteBlobs(ctx, s.blobs, m)
+		if errors.Is(err, repository.ErrMediaLimit) {
+			return status.Errorf(codes.FailedPrecondition, "у инструмента не может быть больше %d файлов", repository.MaxMediaPerInstrument)
+		}
+		return err
+	}
+
+	s.cache.Del(ctx, instrumentCacheKeyPrefix+first.InstrumentId)
+	s.cache.Del(ctx, allInstrumentsCacheKey)
+
+	return stream.SendAndClose(mediaToProto(m))
+}
+
+func (s *InstrumentService) DeleteInstrumentMedia(ctx context.Context, req *proto.DeleteInstrumentMediaRequest) (*proto.DeleteInstrumentMediaResponse, error) {
+	id, err := primitive.ObjectIDFromHex(req.InstrumentId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid instrument ID: %v", err)
+	}
+	mediaID, err := primitive.ObjectIDFromHex(req.MediaId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid media ID: %v", err)
+	}
+
+	m, err := s.repo.RemoveMedia(ctx, id, mediaID)
+	if errors.Is(err, repository.ErrMediaNotFound) {
+		return nil, status.Errorf(codes.NotFound, "файл %s не найден", req.MediaId)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	deleteBlobs(ctx, s.blobs, m)
+	s.cache.Del(ctx, instrumentCacheKeyPrefix+req.InstrumentId)
+	s.cache.Del(ctx, allInstrumentsCacheKey)
+
+	return &proto.DeleteInstrumentMediaResponse{Success: true}, nil
+}
+
+// uploadChunk — сообщение потоковой загрузки файла.
+type uploadChunk interface {
+	GetData() []byte
+}
+
+// readUpload собирает файл из первого сообщения и остальных сообщений
+// потока, не больше limit байт.
+func readUpload[T uploadChunk](first T, recv func() (T, error), limit int) ([]byte, error) {
+	var buf bytes.Buffer
+	for msg := first; ; {
+		if buf.Len()+len(msg.GetData()) > limit {
+			return nil, status.Errorf(codes.InvalidArgument, "файл больше %d МБ", limit>>20)
+		}
+		buf.Write(msg.GetData())
+
+		var err error
+		msg, err = recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if buf.Len() == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "пустой файл")
+	}
+	return buf.Bytes(), nil
+}
+
+func newMedia(kind, filename string, size int) *entity.Media {
+	return &entity.Media{
+		ID:        primitive.NewObjectID(),
+		Kind:      kind,
+		Filename:  strings.TrimPrefix(filepath.Base(filename), "."),
+		SizeBytes: int64(size),
+		CreatedAt: time.Now(),
+	}
+}
+
+// putBlobs записывает подготовленные файлы и заполняет адреса. При
+// ошибке уже записанные файлы удаляются.
+func (s *InstrumentService) putBlobs(ctx context.Context, m *entity.Media, blobs map[string][]byte) error {
+	for key, data := range blobs {
+		if err := s.blobs.Put(ctx, key, bytes.NewReader(data)); err != nil {
+			deleteBlobs(ctx, s.blobs, m)
+			return status.Errorf(codes.Internal, "ошибка сохранения файла: %v", err)
+		}
+	}
+	m.URL = s.blobs.URL(m.Key)
+	if m.ThumbnailKey != "" {
+		m.ThumbnailURL = s.blobs.URL(m.ThumbnailKey)
+	}
+	return nil
+}
+
+// prepareImage проверяет изображение и готовит к записи оригинал
+// и JPEG-миниатюру.
+func prepareImage(m *entity.Media, dir string, data []byte) (map[string][]byte, error) {
+	img, info, err := media.DecodeImage(data)
+	if errors.Is(err, media.ErrImageTooLarge) {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v, допустимы JPEG, PNG и GIF", err)
+	}
+	thumb, err := media.EncodeJPEG(media.Thumbnail(img, media.ThumbnailSize))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "ошибка создания миниатюры: %v", err)
+	}
+
+	m.Key = mediaKey(dir, m.ID, imageExtensions[info.Format])
+	m.ThumbnailKey = mediaKey(dir, m.ID, "_thumb.jpg")
+	m.ContentType = "image/" + info.Format
+	m.Width = int32(info.Width)
+	m.Height = int32(info.Height)
+	return map[string][]byte{m.Key: data, m.ThumbnailKey: thumb}, nil
+}
+
+func prepareAudio(m *entity.Media, dir string, data []byte) (map[string][]byte, error) {
+	info, err := media.ProbeAudio(data)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	if info.Duration < minAudioDuration || info.Duration > maxAudioDuration {
+		return nil, status.Errorf(codes.InvalidArgument, "длительность аудиопримера должна быть от %v до %v", minAudioDuration, maxAudioDuration)
+	}
+
+	m.Key = mediaKey(dir, m.ID, "."+info.Format)
+	m.ContentType = info.ContentType
+	m.DurationSeconds = info.Duration.Round(time.Millisecond).Seconds()
+	return map[string][]byte{m.Key: data}, nil
+}
+
+func instrumentMediaDir(instrumentID primitive.ObjectID) string {
+	return "instruments/" + instrumentID.Hex()
+}
+
+func mediaKey(dir string, mediaID primitive.ObjectID, suffix string) string {
+	return dir + "/" + mediaID.Hex() + suffix
+}
+
+// deleteBlobs удаляет файлы из хранилища; ошибки только логируются,
+// чтобы удаление записи не зависело от хранилища.
+func deleteBlobs(ctx context.Context, blobs storage.BlobStore, m *entity.Media) {
+	for _, key := range []string{m.Key, m.ThumbnailKey} {
+		if key == "" {
+			continue
+		}
+		if err := blobs.Delete(ctx, key); err != nil && !errors.Is(err, storage.ErrBlobNotFound) {
+			log.Printf("Ошибка удаления файла %s: %v", key, err)
+		}
+	}
+}
+
+func mediaToProto(m *entity.Media) *proto.Media {
+	return &proto.Media{
+		Id:              m.ID.Hex(),
+		Kind:            m.Kind,
+		Filename:        m.Filename,
+		Url:             m.URL,
+		ThumbnailUrl:    m.ThumbnailURL,
+		ContentType:     m.ContentType,
+		SizeBytes:       m.SizeBytes,
+		Width:           m.Width,
+		Height:          m.Height,
+		DurationSeconds: m.DurationSeconds,
+	}
+}
+
+func mediaListToProto(list []entity.Media) []*proto.Media {
+	var result []*proto.Media
+	for i := range list {
+		result = append(result, mediaToProto(&list[i]))
+	}
+	return result
+}
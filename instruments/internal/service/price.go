@@ -0,0 +1,311 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"gotune/instruments/internal/entity"
+	"gotune/instruments/internal/repository"
+	"gotune/instruments/proto"
+)
+
+const (
+	defaultPriceHistoryLimit = 100
+	maxPriceHistoryLimit     = 500
+	priceScheduleBatchSize   = 100
+)
+
+// SchedulePriceChange планирует цену инструмента на период. Без ends_at
+// цена остаётся навсегда. Периоды ожидающих и действующих расписаний
+// одного инструмента не должны пересекаться.
+func (s *InstrumentService) SchedulePriceChange(ctx context.Context, req *proto.SchedulePriceChangeRequest) (*proto.PriceSchedule, error) {
+	id, err := primitive.ObjectIDFromHex(req.InstrumentId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "неверный ID инструмента")
+	}
+	schedule, err := newPriceSchedule(id, req, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.repo.FindByID(ctx, id); err == mongo.ErrNoDocuments {
+		return nil, status.Errorf(codes.NotFound, "инструмент %s не найден", req.InstrumentId)
+	} else if err != nil {
+		return nil, err
+	}
+
+	open, err := s.prices.OpenSchedules(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if other := findOverlap(open, schedule); other != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "период пересекается с расписанием %s", other.ID.Hex())
+	}
+
+	if err := s.prices.CreateSchedule(ctx, schedule); err != nil {
+		return nil, err
+	}
+	return priceScheduleToProto(schedule), nil
+}
+
+// CancelPriceSchedule отменяет расписание. Если оно уже действует,
+// инструменту возвращается прежняя цена.
+func (s *InstrumentService) CancelPriceSchedule(ctx context.Context, req *proto.CancelPriceScheduleRequest) (*proto.CancelPriceScheduleResponse, error) {
+	id, err := primitive.ObjectIDFromHex(req.Id)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "неверный ID расписания")
+	}
+	schedule, err := s.prices.FindSchedule(ctx, id)
+	if err == nil && req.InstrumentId != "" && schedule.InstrumentID.Hex() != req.InstrumentId {
+		err = repository.ErrScheduleNotFound
+	}
+	if errors.Is(err, repository.ErrScheduleNotFound) {
+		return nil, status.Errorf(codes.NotFound, "расписание %s не найдено", req.Id)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch schedule.Status {
+	case entity.ScheduleStatusPending:
+		err = s.prices.SetScheduleStatus(ctx, id, entity.ScheduleStatusPending, entity.ScheduleStatusCancelled)
+	case entity.ScheduleStatusActive:
+		err = s.endPriceSchedule(ctx, schedule, entity.ScheduleStatusCancelled)
+	default:
+		return nil, status.Errorf(codes.FailedPrecondition, "расписание %s уже завершено", req.Id)
+	}
+	if errors.Is(err, repository.ErrScheduleNotFound) {
+		// планировщик успел начать или завершить расписание
+		return nil, status.Errorf(codes.Aborted, "расписание %s изменилось, повторите запрос", req.Id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &proto.CancelPriceScheduleResponse{Success: true}, nil
+}
+
+// GetPriceHistory возвращает последние изменения цены и открытые
+// расписания инструмента.
+func (s *InstrumentService) GetPriceHistory(ctx context.Context, req *proto.GetPriceHistoryRequest) (*proto.GetPriceHistoryResponse, error) {
+	id, err := primitive.ObjectIDFromHex(req.InstrumentId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "неверный ID инструмента")
+	}
+	limit := int64(req.Limit)
+	if limit <= 0 {
+		limit = defaultPriceHistoryLimit
+	}
+	if limit > maxPriceHistoryLimit {
+		limit = maxPriceHistoryLimit
+	}
+
+	changes, err := s.prices.History(ctx, id, limit)
+	if err != nil {
+		return nil, err
+	}
+	schedules, err := s.prices.OpenSchedules(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &proto.GetPriceHistoryResponse{}
+	for i := range changes {
+		resp.Changes = append(resp.Changes, priceChangeToProto(&changes[i]))
+	}
+	for i := range schedules {
+		resp.Schedules = append(resp.Schedules, priceScheduleToProto(&schedules[i]))
+	}
+	return resp, nil
+}
+
+// ApplyPriceSchedules завершает расписания, чей период истёк, и начинает
+// наступившие. Возвращает число обработанных расписаний.
+func (s *InstrumentService) ApplyPriceSchedules(ctx context.Context, now time.Time) (int, error) {
+	applied := 0
+
+	// сначала завершаем, чтобы следующее расписание того же инструмента
+	// запомнило обычную цену, а не цену предыдущей акции
+	ending, err := s.prices.SchedulesToEnd(ctx, now, priceScheduleBatchSize)
+	if err != nil {
+		return applied, err
+	}
+	for i := range ending {
+		err := s.endPriceSchedule(ctx, &ending[i], entity.ScheduleStatusCompleted)
+		if err != nil && !errors.Is(err, repository.ErrScheduleNotFound) {
+			return applied, err
+		}
+		applied++
+	}
+
+	starting, err := s.prices.SchedulesToStart(ctx, now, priceScheduleBatchSize)
+	if err != nil {
+		return applied, err
+	}
+	for i := range starting {
+		err := s.startPriceSchedule(ctx, &starting[i], now)
+		if err != nil && !errors.Is(err, repository.ErrScheduleNotFound) {
+			return applied, err
+		}
+		applied++
+	}
+	return applied, nil
+}
+
+func (s *InstrumentService) startPriceSchedule(ctx context.Context, schedule *entity.PriceSchedule, now time.Time) error {
+	if schedule.EndsAt != nil && !schedule.EndsAt.After(now) {
+		// период прошёл, пока планировщик не работал
+		return s.prices.SetScheduleStatus(ctx, schedule.ID, entity.ScheduleStatusPending, entity.ScheduleStatusCompleted)
+	}
+
+	current, err := s.repo.FindByID(ctx, schedule.InstrumentID)
+	if err == mongo.ErrNoDocuments {
+		// инструмент удалён
+		return s.prices.SetScheduleStatus(ctx, schedule.ID, entity.ScheduleStatusPending, entity.ScheduleStatusCancelled)
+	}
+	if err != nil {
+		return err
+	}
+
+	// расписание активируется до смены цены: если его отменили, цена не
+	// меняется, а активное расписание всегда знает цену для возврата
+	if err := s.prices.ActivateSchedule(ctx, schedule.ID, current.Price); err != nil {
+		return err
+	}
+
+	before, err := s.repo.SetPrice(ctx, schedule.InstrumentID, schedule.Price, &current.Price)
+	if err != nil {
+		// цену изменили вручную, инструмент удалили или запись не прошла —
+		// расписание снова ждёт и повторится на следующем проходе
+		if revertErr := s.prices.SetScheduleStatus(ctx, schedule.ID, entity.ScheduleStatusActive, entity.ScheduleStatusPending); revertErr != nil {
+			log.Printf("Ошибка возврата расписания цены %s в ожидание: %v", schedule.ID.Hex(), revertErr)
+		}
+		if err == mongo.ErrNoDocuments {
+			return nil
+		}
+		return err
+	}
+
+	s.recordPriceChange(ctx, schedule.InstrumentID, before.Price, schedule.Price, entity.PriceSourceSchedule, &schedule.ID)
+	before.Price = schedule.Price
+	s.instrumentUpdated(ctx, schedule.InstrumentID.Hex(), before)
+	return nil
+}
+
+// endPriceSchedule возвращает цену, действовавшую до расписания, и
+// переводит его в status. Если цену за время действия изменили вручную,
+// ручная цена остаётся.
+func (s *InstrumentService) endPriceSchedule(ctx context.Context, schedule *entity.PriceSchedule, to string) error {
+	before, err := s.repo.SetPrice(ctx, schedule.InstrumentID, schedule.OriginalPrice, &schedule.Price)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return err
+	}
+	if err == nil {
+		s.recordPriceChange(ctx, schedule.InstrumentID, schedule.Price, schedule.OriginalPrice, entity.PriceSourceSchedule, &schedule.ID)
+		before.Price = schedule.OriginalPrice
+		s.instrumentUpdated(ctx, schedule.InstrumentID.Hex(), before)
+	}
+	return s.prices.SetScheduleStatus(ctx, schedule.ID, entity.ScheduleStatusActive, to)
+}
+
+// recordPriceChange пишет изменение в историю цен. Цена к этому моменту
+// уже сохранена, поэтому ошибка только логируется.
+func (s *InstrumentService) recordPriceChange(ctx context.Context, id primitive.ObjectID, oldPrice, newPrice float64, source string, scheduleID *primitive.ObjectID) {
+	err := s.prices.RecordChange(ctx, &entity.PriceChange{
+		InstrumentID: id,
+		OldPrice:     oldPrice,
+		NewPrice:     newPrice,
+		Source:       source,
+		ScheduleID:   scheduleID,
+		ChangedAt:    time.Now(),
+	})
+	if err != nil {
+		log.Printf("Ошибка записи истории цены инструмента %s: %v", id.Hex(), err)
+	}
+}
+
+// instrumentUpdated сбрасывает кэш инструмента и публикует
+// instrument_updated с его текущими ценой и остатком.
+func (s *InstrumentService) instrumentUpdated(ctx context.Context, id string, inst *entity.Instrument) {
+	s.cache.Del(ctx, instrumentCacheKeyPrefix+id)
+	s.cache.Del(ctx, allInstrumentsCacheKey)
+
+	_ = s.eventPublisher.Publish("instrument_updated", map[string]string{
+		"id":    id,
+		"name":  inst.Name,
+		"price": strconv.FormatFloat(inst.Price, 'f', -1, 64),
+		"stock": strconv.Itoa(int(inst.Stock)),
+	})
+}
+
+func newPriceSchedule(instrumentID primitive.ObjectID, req *proto.SchedulePriceChangeRequest, now time.Time) (*entity.PriceSchedule, error) {
+	if req.Price <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "цена должна быть больше нуля")
+	}
+	if req.StartsAt <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "не указано время начала")
+	}
+	schedule := &entity.PriceSchedule{
+		InstrumentID: instrumentID,
+		Price:        req.Price,
+		StartsAt:     time.Unix(req.StartsAt, 0),
+		Status:       entity.ScheduleStatusPending,
+		CreatedAt:    now,
+	}
+	if req.EndsAt != 0 {
+		if req.EndsAt <= req.StartsAt {
+			return nil, status.Errorf(codes.InvalidArgument, "время окончания должно быть позже начала")
+		}
+		endsAt := time.Unix(req.EndsAt, 0)
+		if !endsAt.After(now) {
+			return nil, status.Errorf(codes.InvalidArgument, "время окончания уже прошло")
+		}
+		schedule.EndsAt = &endsAt
+	}
+	return schedule, nil
+}
+
+func findOverlap(schedules []entity.PriceSchedule, schedule *entity.PriceSchedule) *entity.PriceSchedule {
+	for i := range schedules {
+		if schedules[i].Overlaps(schedule) {
+			return &schedules[i]
+		}
+	}
+	return nil
+}
+
+func priceScheduleToProto(p *entity.PriceSchedule) *proto.PriceSchedule {
+	resp := &proto.PriceSchedule{
+		Id:            p.ID.Hex(),
+		InstrumentId:  p.InstrumentID.Hex(),
+		Price:         p.Price,
+		StartsAt:      p.StartsAt.Unix(),
+		Status:        p.Status,
+		OriginalPrice: p.OriginalPrice,
+		CreatedAt:     p.CreatedAt.Unix(),
+	}
+	if p.EndsAt != nil {
+		resp.EndsAt = p.EndsAt.Unix()
+	}
+	return resp
+}
+
+func priceChangeToProto(c *entity.PriceChange) *proto.PriceChange {
+	resp := &proto.PriceChange{
+		OldPrice:  c.OldPrice,
+		NewPrice:  c.NewPrice,
+		Source:    c.Source,
+		ChangedAt: c.ChangedAt.Unix(),
+	}
+	if c.ScheduleID != nil {
+		resp.ScheduleId = c.ScheduleID.Hex()
+	}
+	return resp
+}
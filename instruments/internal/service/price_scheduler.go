@@ -0,0 +1,40 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// PriceScheduler начинает и завершает запланированные изменения цен.
+type PriceScheduler struct {
+	service  *InstrumentService
+	interval time.Duration
+}
+
+func NewPriceScheduler(service *InstrumentService, interval time.Duration) *PriceScheduler {
+	return &PriceScheduler{service: service, interval: interval}
+}
+
+func (j *PriceScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		if n, err := j.RunOnce(ctx); err != nil {
+			log.Printf("Ошибка применения расписаний цен: %v", err)
+		} else if n > 0 {
+			log.Printf("Применено расписаний цен: %d", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (j *PriceScheduler) RunOnce(ctx context.Context) (int, error) {
+	return j.service.ApplyPriceSchedules(ctx, time.Now())
+}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"gotune/instruments/internal/repository"
+	"gotune/instruments/internal/storage"
+	orderproto "gotune/order/proto"
+	rentalproto "gotune/rental/proto"
+	wishlistproto "gotune/wishlist/proto"
+)
+
+const purgeBatchSize = 100
+
+// PurgeJob окончательно удаляет инструменты, удалённые дольше retention
+// назад, вместе с их файлами. Инструменты, на которые ссылаются заказы,
+// отзывы, экземпляры, заявки trade-in, прокат или списки желаний,
+// остаются в базе помеченными удалёнными.
+type PurgeJob struct {
+	repo           repository.InstrumentRepository
+	reviews        repository.ReviewRepository
+	serialUnits    repository.SerialUnitRepository
+	tradeIns       repository.TradeInRepository
+	blobs          storage.BlobStore
+	orderClient    orderproto.OrderServiceClient
+	rentalClient   rentalproto.RentalServiceClient
+	wishlistClient wishlistproto.WishlistServiceClient
+	retention      time.Duration
+	interval       time.Duration
+}
+
+func NewPurgeJob(
+	repo repository.InstrumentRepository,
+	reviews repository.ReviewRepository,
+	serialUnits repository.SerialUnitRepository,
+	tradeIns repository.TradeInRepository,
+	blobs storage.BlobStore,
+	orderClient orderproto.OrderServiceClient,
+	rentalClient rentalproto.RentalServiceClient,
+	wishlistClient wishlistproto.WishlistServiceClient,
+	retention, interval time.Duration,
+) *PurgeJob {
+	return &PurgeJob{
+		repo:           repo,
+		reviews:        reviews,
+		serialUnits:    serialUnits,
+		tradeIns:       tradeIns,
+		blobs:          blobs,
+		orderClient:    orderClient,
+		rentalClient:   rentalClient,
+		wishlistClient: wishlistClient,
+		retention:      retention,
+		interval:       interval,
+	}
+}
+
+func (j *PurgeJob) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		if n, err := j.RunOnce(ctx); err != nil {
+			log.Printf("Ошибка очистки удалённых инструментов: %v", err)
+		} else if n > 0 {
+			log.Printf("Удалено инструментов: %d", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (j *PurgeJob) RunOnce(ctx context.Context) (int, error) {
+	before := time.Now().Add(-j.retention)
+	purged := 0
+	// инструменты со ссылками остаются, поэтому проходим по _id, а не
+	// запрашиваем первую страницу заново
+	afterID := primitive.NilObjectID
+
+	for {
+		instruments, err := j.repo.FindDeletedBefore(ctx, before, afterID, purgeBatchSize)
+		if err != nil {
+			return purged, err
+		}
+
+		for i := range instruments {
+			inst := &instruments[i]
+			afterID = inst.ID
+
+			referenced, err := j.isReferenced(ctx, inst.ID)
+			if err != nil {
+				return purged, err
+			}
+			if referenced {
+				continue
+			}
+
+			err = j.repo.PurgeByID(ctx, inst.ID)
+			if err == mongo.ErrNoDocuments {
+				// восстановлен после выборки
+				continue
+			}
+			if err != nil {
+				return purged, err
+			}
+			for k := range inst.Media {
+				deleteBlobs(ctx, j.blobs, &inst.Media[k])
+			}
+			purged++
+		}
+
+		if len(instruments) < purgeBatchSize {
+			return purged, nil
+		}
+	}
+}
+
+// isReferenced проверяет ссылки на инструмент в каталоге и в сервисах
+// заказов, проката и списков желаний.
+func (j *PurgeJob) isReferenced(ctx context.Context, id primitive.ObjectID) (bool, error) {
+	if ok, err := j.reviews.HasReviews(ctx, id); err != nil || ok {
+		return ok, err
+	}
+	if ok, err := j.serialUnits.IsTracked(ctx, id); err != nil || ok {
+		return ok, err
+	}
+	if ok, err := j.tradeIns.IsListing(ctx, id); err != nil || ok {
+		return ok, err
+	}
+
+	orders, err := j.orderClient.CountOrderReferences(ctx, &orderproto.CountOrderReferencesRequest{
+		InstrumentId: id.Hex(),
+	})
+	if err != nil {
+		return false, err
+	}
+	if orders.Orders > 0 {
+		return true, nil
+	}
+
+	rentals, err := j.rentalClient.CountRentalReferences(ctx, &rentalproto.CountRentalReferencesRequest{
+		InstrumentId: id.Hex(),
+	})
+	if err != nil {
+		return false, err
+	}
+	if rentals.Units > 0 {
+		return true, nil
+	}
+
+	wishlists, err := j.wishlistClient.CountWishlistReferences(ctx, &wishlistproto.CountWishlistReferencesRequest{
+		InstrumentId: id.Hex(),
+	})
+	if err != nil {
+		return false, err
+	}
+	return wishlists.Wishlists > 0, nil
+}
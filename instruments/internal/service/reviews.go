@@ -0,0 +1,268 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"gotune/instruments/internal/entity"
+	"gotune/instruments/internal/repository"
+	"gotune/instruments/proto"
+	orderproto "gotune/order/proto"
+)
+
+const (
+	maxReviewTextLength   = 5000
+	defaultReviewPageSize = 20
+	maxReviewPageSize     = 100
+)
+
+// CreateReview сохраняет отзыв покупателя. Отзыв принимается только от
+// пользователя с доставленным заказом, где есть этот инструмент, и
+// попадает в каталог после модерации.
+func (s *InstrumentService) CreateReview(ctx context.Context, req *proto.CreateReviewRequest) (*proto.Review, error) {
+	instrumentID, err := primitive.ObjectIDFromHex(req.InstrumentId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "неверный ID инструмента")
+	}
+	userID, err := primitive.ObjectIDFromHex(req.UserId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "неверный ID пользователя")
+	}
+	text := strings.TrimSpace(req.Text)
+	if err := validateReview(req.Rating, text); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.repo.FindByID(ctx, instrumentID); err == mongo.ErrNoDocuments {
+		return nil, status.Errorf(codes.NotFound, "инструмент %s не найден", req.InstrumentId)
+	} else if err != nil {
+		return nil, err
+	}
+
+	purchase, err := s.orderClient.HasDeliveredOrder(ctx, &orderproto.HasDeliveredOrderRequest{
+		UserId:       req.UserId,
+		InstrumentId: req.InstrumentId,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !purchase.Delivered {
+		return nil, status.Errorf(codes.FailedPrecondition, "отзыв можно оставить только после получения заказа с этим инструментом")
+	}
+
+	review := &entity.Review{
+		InstrumentID: instrumentID,
+		UserID:       userID,
+		Rating:       req.Rating,
+		Text:         text,
+		Status:       entity.ReviewStatusPending,
+		CreatedAt:    time.Now(),
+	}
+	err = s.reviews.Create(ctx, review)
+	if mongo.IsDuplicateKeyError(err) {
+		return nil, status.Errorf(codes.AlreadyExists, "вы уже оставили отзыв на этот инструмент")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	_ = s.eventPublisher.Publish("review_created", map[string]string{
+		"id":            review.ID.Hex(),
+		"instrument_id": req.InstrumentId,
+		"user_id":       req.UserId,
+	})
+
+	return reviewToProto(review), nil
+}
+
+// GetReviews возвращает отзывы инструмента или, без instrument_id,
+// отзывы всех инструментов — так модераторы получают очередь pending.
+func (s *InstrumentService) GetReviews(ctx context.Context, req *proto.GetReviewsRequest) (*proto.GetReviewsResponse, error) {
+	q, err := reviewQueryFromProto(req)
+	if err != nil {
+		return nil, err
+	}
+
+	reviews, total, err := s.reviews.List(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &proto.GetReviewsResponse{Total: total}
+	for i := range reviews {
+		resp.Reviews = append(resp.Reviews, reviewToProto(&reviews[i]))
+	}
+	return resp, nil
+}
+
+// ModerateReview одобряет или отклоняет отзыв и пересчитывает рейтинг
+// инструмента, если изменился набор одобренных отзывов.
+func (s *InstrumentService) ModerateReview(ctx context.Context, req *proto.ModerateReviewRequest) (*proto.Review, error) {
+	id, err := primitive.ObjectIDFromHex(req.Id)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "неверный ID отзыва")
+	}
+	if req.Status != entity.ReviewStatusApproved && req.Status != entity.ReviewStatusRejected {
+		return nil, status.Errorf(codes.InvalidArgument, "статус модерации должен быть approved или rejected")
+	}
+
+	note := strings.TrimSpace(req.Note)
+	now := time.Now()
+	before, err := s.reviews.SetStatus(ctx, id, req.Status, note, now)
+	if errors.Is(err, repository.ErrReviewNotFound) {
+		return nil, status.Errorf(codes.NotFound, "отзыв %s не найден", req.Id)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if (before.Status == entity.ReviewStatusApproved) != (req.Status == entity.ReviewStatusApproved) {
+		if err := s.refreshRating(ctx, before.InstrumentID); err != nil {
+			return nil, err
+		}
+	}
+
+	_ = s.eventPublisher.Publish("review_moderated", map[string]string{
+		"id":            req.Id,
+		"instrument_id": before.InstrumentID.Hex(),
+		"user_id":       before.UserID.Hex(),
+		"status":        req.Status,
+	})
+
+	review := *before
+	review.Status = req.Status
+	review.ModerationNote = note
+	review.ModeratedAt = &now
+	return reviewToProto(&review), nil
+}
+
+// VoteReviewHelpful отмечает одобренный отзыв полезным. Повторный голос
+// пользователя не учитывается, за свой отзыв голосовать нельзя.
+func (s *InstrumentService) VoteReviewHelpful(ctx context.Context, req *proto.VoteReviewHelpfulRequest) (*proto.VoteReviewHelpfulResponse, error) {
+	id, err := primitive.ObjectIDFromHex(req.ReviewId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "неверный ID отзыва")
+	}
+	userID, err := primitive.ObjectIDFromHex(req.UserId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "неверный ID пользователя")
+	}
+
+	review, err := s.reviews.FindByID(ctx, id)
+	if err == nil && review.Status != entity.ReviewStatusApproved {
+		err = repository.ErrReviewNotFound
+	}
+	if errors.Is(err, repository.ErrReviewNotFound) {
+		return nil, status.Errorf(codes.NotFound, "отзыв %s не найден", req.ReviewId)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if review.UserID == userID {
+		return nil, status.Errorf(codes.FailedPrecondition, "нельзя голосовать за свой отзыв")
+	}
+
+	votes, err := s.reviews.AddHelpfulVote(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &proto.VoteReviewHelpfulResponse{HelpfulVotes: votes}, nil
+}
+
+// refreshRating пересчитывает рейтинг инструмента по одобренным отзывам.
+func (s *InstrumentService) refreshRating(ctx context.Context, instrumentID primitive.ObjectID) error {
+	average, count, err := s.reviews.RatingSummary(ctx, instrumentID)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.SetRating(ctx, instrumentID, average, count); err != nil {
+		return err
+	}
+
+	s.cache.Del(ctx, instrumentCacheKeyPrefix+instrumentID.Hex())
+	s.cache.Del(ctx, allInstrumentsCacheKey)
+	return nil
+}
+
+func validateReview(rating int32, text string) error {
+	if rating < 1 || rating > 5 {
+		return status.Errorf(codes.InvalidArgument, "оценка должна быть от 1 до 5")
+	}
+	if text == "" {
+		return status.Errorf(codes.InvalidArgument, "текст отзыва не может быть пустым")
+	}
+	if utf8.RuneCountInString(text) > maxReviewTextLength {
+		return status.Errorf(codes.InvalidArgument, "отзыв длиннее %d символов", maxReviewTextLength)
+	}
+	return nil
+}
+
+func reviewQueryFromProto(req *proto.GetReviewsRequest) (repository.ReviewQuery, error) {
+	q := repository.ReviewQuery{
+		Status: req.Status,
+		Sort:   req.Sort,
+		Limit:  int64(req.Limit),
+		Offset: int64(req.Offset),
+	}
+	if req.InstrumentId != "" {
+		id, err := primitive.ObjectIDFromHex(req.InstrumentId)
+		if err != nil {
+			return q, status.Errorf(codes.InvalidArgument, "неверный ID инструмента")
+		}
+		q.InstrumentID = id
+	}
+
+	switch q.Status {
+	case "":
+		q.Status = entity.ReviewStatusApproved
+	case entity.ReviewStatusApproved, entity.ReviewStatusPending, entity.ReviewStatusRejected:
+	default:
+		return q, status.Errorf(codes.InvalidArgument, "неизвестный статус отзыва %q", q.Status)
+	}
+
+	switch q.Sort {
+	case "":
+		q.Sort = repository.ReviewSortNewest
+	case repository.ReviewSortNewest, repository.ReviewSortHelpful,
+		repository.ReviewSortRatingDesc, repository.ReviewSortRatingAsc:
+	default:
+		return q, status.Errorf(codes.InvalidArgument, "неизвестная сортировка %q", q.Sort)
+	}
+
+	if q.Limit <= 0 {
+		q.Limit = defaultReviewPageSize
+	}
+	if q.Limit > maxReviewPageSize {
+		q.Limit = maxReviewPageSize
+	}
+	if q.Offset < 0 {
+		return q, status.Errorf(codes.InvalidArgument, "offset не может быть отрицательным")
+	}
+	return q, nil
+}
+
+func reviewToProto(r *entity.Review) *proto.Review {
+	resp := &proto.Review{
+		Id:             r.ID.Hex(),
+		InstrumentId:   r.InstrumentID.Hex(),
+		UserId:         r.UserID.Hex(),
+		Rating:         r.Rating,
+		Text:           r.Text,
+		Status:         r.Status,
+		HelpfulVotes:   r.HelpfulVotes,
+		ModerationNote: r.ModerationNote,
+		CreatedAt:      r.CreatedAt.Unix(),
+	}
+	if r.ModeratedAt != nil {
+		resp.ModeratedAt = r.ModeratedAt.Unix()
+	}
+	return resp
+}
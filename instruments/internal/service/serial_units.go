@@ -0,0 +1,341 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"gotune/instruments/internal/entity"
+	"gotune/instruments/internal/repository"
+	"gotune/instruments/proto"
+)
+
+// warrantyPeriod отсчитывается от продажи экземпляра.
+const warrantyPeriod = 365 * 24 * time.Hour
+
+// AddSerialUnit ставит на учёт экземпляр инструмента. У инструмента с
+// вариантами экземпляр относится к одному из них.
+func (s *InstrumentService) AddSerialUnit(ctx context.Context, req *proto.AddSerialUnitRequest) (*proto.SerialUnit, error) {
+	instrumentID, err := primitive.ObjectIDFromHex(req.InstrumentId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "неверный ID инструмента")
+	}
+	serial := normalizeSerial(req.SerialNumber)
+	if serial == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "не указан серийный номер")
+	}
+	condition := req.Condition
+	if condition == "" {
+		condition = entity.ConditionNew
+	}
+	if !entity.ValidCondition(condition) {
+		return nil, status.Errorf(codes.InvalidArgument, "неизвестное состояние экземпляра: %q", condition)
+	}
+
+	inst, err := s.repo.FindByID(ctx, instrumentID)
+	if err == mongo.ErrNoDocuments {
+		return nil, status.Errorf(codes.NotFound, "инструмент %s не найден", req.InstrumentId)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := checkUnitVariant(inst, req.Sku); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	unit := &entity.SerialUnit{
+		InstrumentID: instrumentID,
+		SKU:          req.Sku,
+		SerialNumber: serial,
+		Condition:    condition,
+		Location:     strings.TrimSpace(req.Location),
+		Status:       entity.SerialStatusInStock,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	err = s.serials.Create(ctx, unit)
+	if mongo.IsDuplicateKeyError(err) {
+		return nil, status.Errorf(codes.AlreadyExists, "экземпляр с серийным номером %s уже есть", serial)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return serialUnitToProto(unit), nil
+}
+
+func (s *InstrumentService) UpdateSerialUnit(ctx context.Context, req *proto.UpdateSerialUnitRequest) (*proto.SerialUnit, error) {
+	upd := repository.SerialUnitUpdate{
+		Condition: req.Condition,
+		Location:  strings.TrimSpace(req.Location),
+		Status:    req.Status,
+	}
+	if err := validateSerialUnitUpdate(upd); err != nil {
+		return nil, err
+	}
+
+	unit, err := s.serials.Update(ctx, normalizeSerial(req.SerialNumber), upd, time.Now())
+	if err != nil {
+		return nil, serialUnitError(err)
+	}
+	return serialUnitToProto(unit), nil
+}
+
+// GetSerialUnit ищет экземпляр по серийному номеру, например для
+// гарантийного обращения.
+func (s *InstrumentService) GetSerialUnit(ctx context.Context, req *proto.GetSerialUnitRequest) (*proto.SerialUnit, error) {
+	unit, err := s.serials.FindBySerial(ctx, normalizeSerial(req.SerialNumber))
+	if err != nil {
+		return nil, serialUnitError(err)
+	}
+	return serialUnitToProto(unit), nil
+}
+
+func (s *InstrumentService) GetSerialUnits(ctx context.Context, req *proto.GetSerialUnitsRequest) (*proto.GetSerialUnitsResponse, error) {
+	instrumentID, err := primitive.ObjectIDFromHex(req.InstrumentId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "неверный ID инструмента")
+	}
+
+	units, err := s.serials.List(ctx, instrumentID, req.Sku, req.Status)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &proto.GetSerialUnitsResponse{}
+	for i := range units {
+		resp.Units = append(resp.Units, serialUnitToProto(&units[i]))
+	}
+	return resp, nil
+}
+
+// AllocateSerialUnits выделяет заказу экземпляры при отгрузке: сначала
+// указанные, затем первые поступившие со склада. Если экземпляров не
+// хватает, выделенные в этом вызове возвращаются на склад.
+func (s *InstrumentService) AllocateSerialUnits(ctx context.Context, req *proto.AllocateSerialUnitsRequest) (*proto.AllocateSerialUnitsResponse, error) {
+	orderID, err := primitive.ObjectIDFromHex(req.OrderId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "неверный ID заказа")
+	}
+	userID, err := primitive.ObjectIDFromHex(req.UserId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "неверный ID пользователя")
+	}
+
+	allocated, err := s.serials.FindByOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if len(allocated) > 0 {
+		return &proto.AllocateSerialUnitsResponse{Items: serialAllocations(allocated)}, nil
+	}
+
+	now := time.Now()
+	tracked := map[primitive.ObjectID]bool{}
+	for _, item := range req.Items {
+		instrumentID, err := primitive.ObjectIDFromHex(item.InstrumentId)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "неверный ID инструмента")
+		}
+		if int(item.Quantity) < len(item.SerialNumbers) {
+			return nil, status.Errorf(codes.InvalidArgument, "экземпляров %s указано больше, чем в заказе", item.InstrumentId)
+		}
+
+		isTracked, ok := tracked[instrumentID]
+		if !ok {
+			if isTracked, err = s.serials.IsTracked(ctx, instrumentID); err != nil {
+				return nil, err
+			}
+			tracked[instrumentID] = isTracked
+		}
+		if !isTracked {
+			continue
+		}
+
+		if err := s.allocateItem(ctx, instrumentID, item, orderID, userID, now); err != nil {
+			if releaseErr := s.serials.ReleaseOrder(ctx, orderID, time.Now()); releaseErr != nil {
+				return nil, releaseErr
+			}
+			return nil, err
+		}
+	}
+
+	allocated, err = s.serials.FindByOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	return &proto.AllocateSerialUnitsResponse{Items: serialAllocations(allocated)}, nil
+}
+
+func (s *InstrumentService) allocateItem(ctx context.Context, instrumentID primitive.ObjectID, item *proto.SerialAllocation, orderID, userID primitive.ObjectID, at time.Time) error {
+	for _, serial := range item.SerialNumbers {
+		_, err := s.serials.Allocate(ctx, normalizeSerial(serial), instrumentID, item.Sku, orderID, userID, at)
+		if errors.Is(err, repository.ErrSerialUnitUnavailable) {
+			return status.Errorf(codes.FailedPrecondition, "экземпляр %s недоступен для продажи", serial)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	for n := len(item.SerialNumbers); n < int(item.Quantity); n++ {
+		_, err := s.serials.AllocateAny(ctx, instrumentID, item.Sku, orderID, userID, at)
+		if errors.Is(err, repository.ErrSerialUnitUnavailable) {
+			return status.Errorf(codes.FailedPrecondition, "на складе не хватает экземпляров %s", item.InstrumentId)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RegisterWarranty регистрирует гарантию на проданный экземпляр. Срок
+// гарантии считается от продажи, а не от регистрации.
+func (s *InstrumentService) RegisterWarranty(ctx context.Context, req *proto.RegisterWarrantyRequest) (*proto.SerialUnit, error) {
+	userID, err := primitive.ObjectIDFromHex(req.UserId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "неверный ID пользователя")
+	}
+	serial := normalizeSerial(req.SerialNumber)
+
+	unit, err := s.serials.FindBySerial(ctx, serial)
+	if err != nil {
+		return nil, serialUnitError(err)
+	}
+	if unit.Status != entity.SerialStatusSold || unit.SoldTo == nil || *unit.SoldTo != userID {
+		return nil, status.Errorf(codes.FailedPrecondition, "гарантию регистрирует покупатель экземпляра")
+	}
+	if unit.Warranty != nil {
+		return nil, status.Errorf(codes.AlreadyExists, "гарантия на экземпляр %s уже зарегистрирована", serial)
+	}
+	now := time.Now()
+	expiresAt := unit.SoldAt.Add(warrantyPeriod)
+	if now.After(expiresAt) {
+		return nil, status.Errorf(codes.FailedPrecondition, "гарантийный срок истёк")
+	}
+
+	unit, err = s.serials.RegisterWarranty(ctx, serial, entity.Warranty{
+		UserID:       userID,
+		RegisteredAt: now,
+		ExpiresAt:    expiresAt,
+	})
+	if err != nil {
+		return nil, serialUnitError(err)
+	}
+
+	_ = s.eventPublisher.Publish("warranty_registered", map[string]string{
+		"serial_number": serial,
+		"instrument_id": unit.InstrumentID.Hex(),
+		"user_id":       req.UserId,
+		"expires_at":    expiresAt.Format(time.RFC3339),
+	})
+
+	return serialUnitToProto(unit), nil
+}
+
+// checkUnitVariant проверяет, что sku экземпляра — вариант инструмента,
+// а у инструмента без вариантов sku пустой.
+func checkUnitVariant(inst *entity.Instrument, sku string) error {
+	if len(inst.Variants) == 0 {
+		if sku != "" {
+			return status.Errorf(codes.InvalidArgument, "у инструмента нет вариантов")
+		}
+		return nil
+	}
+	for _, v := range inst.Variants {
+		if v.SKU == sku {
+			return nil
+		}
+	}
+	if sku == "" {
+		return status.Errorf(codes.InvalidArgument, "у инструмента есть варианты, укажите sku")
+	}
+	return status.Errorf(codes.InvalidArgument, "вариант %s не найден", sku)
+}
+
+// validateSerialUnitUpdate — продажа экземпляра выставляется только при
+// отгрузке заказа.
+func validateSerialUnitUpdate(upd repository.SerialUnitUpdate) error {
+	if upd.Condition != "" && !entity.ValidCondition(upd.Condition) {
+		return status.Errorf(codes.InvalidArgument, "неизвестное состояние экземпляра: %q", upd.Condition)
+	}
+	switch upd.Status {
+	case "", entity.SerialStatusInStock, entity.SerialStatusReserved, entity.SerialStatusRented:
+	case entity.SerialStatusSold:
+		return status.Errorf(codes.InvalidArgument, "экземпляр продаётся только через заказ")
+	default:
+		return status.Errorf(codes.InvalidArgument, "неизвестный статус экземпляра: %q", upd.Status)
+	}
+	return nil
+}
+
+// serialAllocations группирует экземпляры заказа по позициям.
+func serialAllocations(units []entity.SerialUnit) []*proto.SerialAllocation {
+	type key struct {
+		instrumentID primitive.ObjectID
+		sku          string
+	}
+	index := map[key]*proto.SerialAllocation{}
+	var items []*proto.SerialAllocation
+	for _, u := range units {
+		k := key{u.InstrumentID, u.SKU}
+		item, ok := index[k]
+		if !ok {
+			item = &proto.SerialAllocation{InstrumentId: u.InstrumentID.Hex(), Sku: u.SKU}
+			index[k] = item
+			items = append(items, item)
+		}
+		item.Quantity++
+		item.SerialNumbers = append(item.SerialNumbers, u.SerialNumber)
+	}
+	return items
+}
+
+func normalizeSerial(serial string) string {
+	return strings.ToUpper(strings.TrimSpace(serial))
+}
+
+func serialUnitError(err error) error {
+	switch {
+	case errors.Is(err, repository.ErrSerialUnitNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, repository.ErrSerialUnitSold),
+		errors.Is(err, repository.ErrWarrantyNotAllowed):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+	return err
+}
+
+func serialUnitToProto(u *entity.SerialUnit) *proto.SerialUnit {
+	resp := &proto.SerialUnit{
+		Id:           u.ID.Hex(),
+		InstrumentId: u.InstrumentID.Hex(),
+		Sku:          u.SKU,
+		SerialNumber: u.SerialNumber,
+		Condition:    u.Condition,
+		Location:     u.Location,
+		Status:       u.Status,
+		CreatedAt:    u.CreatedAt.Unix(),
+		UpdatedAt:    u.UpdatedAt.Unix(),
+	}
+	if u.OrderID != nil {
+		resp.OrderId = u.OrderID.Hex()
+	}
+	if u.SoldAt != nil {
+		resp.SoldAt = u.SoldAt.Unix()
+	}
+	if u.Warranty != nil {
+		resp.Warranty = &proto.Warranty{
+			UserId:       u.Warranty.UserID.Hex(),
+			RegisteredAt: u.Warranty.RegisteredAt.Unix(),
+			ExpiresAt:    u.Warranty.ExpiresAt.Unix(),
+		}
+	}
+	return resp
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"gotune/instruments/internal/repository"
+	"gotune/instruments/proto"
+)
+
+// ReserveStock списывает остатки позиций заказа. Если какой-то позиции не
+// хватает, уже списанные возвращаются и заказ отклоняется целиком.
+func (s *InstrumentService) ReserveStock(ctx context.Context, req *proto.ReserveStockRequest) (*proto.ReserveStockResponse, error) {
+	items, err := parseStockItems(req.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	for idx, item := range items {
+		inst, err := s.repo.AdjustStock(ctx, item.instrumentID, item.sku, -item.quantity)
+		if err != nil {
+			s.releaseStock(context.Background(), items[:idx])
+			if errors.Is(err, repository.ErrOutOfStock) {
+				return nil, status.Errorf(codes.FailedPrecondition, "недостаточно товара %s на складе", item.ref())
+			}
+			return nil, err
+		}
+		s.instrumentUpdated(ctx, item.instrumentID.Hex(), inst)
+	}
+	return &proto.ReserveStockResponse{}, nil
+}
+
+// ReleaseStock возвращает остатки, списанные ReserveStock.
+func (s *InstrumentService) ReleaseStock(ctx context.Context, req *proto.ReleaseStockRequest) (*proto.ReleaseStockResponse, error) {
+	items, err := parseStockItems(req.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range items {
+		inst, err := s.repo.AdjustStock(ctx, item.instrumentID, item.sku, item.quantity)
+		if err == mongo.ErrNoDocuments {
+			// инструмент окончательно удалён или вариант убран
+			log.Printf("Остаток %s некуда вернуть", item.ref())
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		s.instrumentUpdated(ctx, item.instrumentID.Hex(), inst)
+	}
+	return &proto.ReleaseStockResponse{}, nil
+}
+
+// releaseStock возвращает остатки после неудачного списания; ошибки
+// только логируются, исходная ошибка важнее.
+func (s *InstrumentService) releaseStock(ctx context.Context, items []stockItem) {
+	for _, item := range items {
+		inst, err := s.repo.AdjustStock(ctx, item.instrumentID, item.sku, item.quantity)
+		if err != nil {
+			log.Printf("Не удалось вернуть остаток %s: %v", item.ref(), err)
+			continue
+		}
+		s.instrumentUpdated(ctx, item.instrumentID.Hex(), inst)
+	}
+}
+
+type stockItem struct {
+	instrumentID primitive.ObjectID
+	sku          string
+	quantity     int32
+}
+
+func (i stockItem) ref() string {
+	if i.sku != "" {
+		return i.sku
+	}
+	return i.instrumentID.Hex()
+}
+
+func parseStockItems(items []*proto.StockItem) ([]stockItem, error) {
+	if len(items) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "не указаны позиции")
+	}
+	parsed := make([]stockItem, 0, len(items))
+	for _, i := range items {
+		id, err := primitive.ObjectIDFromHex(i.InstrumentId)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "неверный ID инструмента")
+		}
+		if i.Quantity <= 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "количество должно быть положительным")
+		}
+		parsed = append(parsed, stockItem{
+			instrumentID: id,
+			sku:          strings.ToUpper(strings.TrimSpace(i.Sku)),
+			quantity:     i.Quantity,
+		})
+	}
+	return parsed, nil
+}
@@ -0,0 +1,386 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"gotune/instruments/internal/entity"
+	"gotune/instruments/internal/repository"
+	"gotune/instruments/proto"
+	usersproto "gotune/users/proto"
+)
+
+// tradeInCreditReason — причина начисления кредита в истории счёта.
+const tradeInCreditReason = "trade_in"
+
+// SubmitTradeIn создаёт заявку на сдачу инструмента в зачёт. Фото
+// прикладываются отдельно через UploadTradeInPhoto.
+func (s *InstrumentService) SubmitTradeIn(ctx context.Context, req *proto.SubmitTradeInRequest) (*proto.TradeIn, error) {
+	userID, err := primitive.ObjectIDFromHex(req.UserId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "неверный ID пользователя")
+	}
+	if err := validateTradeIn(req); err != nil {
+		return nil, err
+	}
+
+	tradeIn := &entity.TradeIn{
+		UserID:       userID,
+		Name:         strings.TrimSpace(req.Name),
+		Brand:        strings.TrimSpace(req.Brand),
+		Description:  strings.TrimSpace(req.Description),
+		SerialNumber: normalizeSerial(req.SerialNumber),
+		Condition:    req.Condition,
+		Status:       entity.TradeInStatusSubmitted,
+		CreatedAt:    time.Now(),
+	}
+	if err := s.tradeIns.Create(ctx, tradeIn); err != nil {
+		return nil, err
+	}
+
+	s.publishTradeIn(tradeIn)
+	return tradeInToProto(tradeIn), nil
+}
+
+// UploadTradeInPhoto принимает фото инструмента к заявке частями.
+func (s *InstrumentService) UploadTradeInPhoto(stream proto.InstrumentService_UploadTradeInPhotoServer) error {
+	ctx := stream.Context()
+
+	first, err := stream.Recv()
+	if err == io.EOF {
+		return status.Errorf(codes.InvalidArgument, "пустой файл")
+	}
+	if err != nil {
+		return err
+	}
+	id, err := primitive.ObjectIDFromHex(first.TradeInId)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "неверный ID заявки")
+	}
+	userID, err := primitive.ObjectIDFromHex(first.UserId)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "неверный ID пользователя")
+	}
+	tradeIn, err := s.tradeIns.FindByID(ctx, id)
+	if err != nil {
+		return tradeInError(err)
+	}
+	if tradeIn.UserID != userID {
+		return tradeInError(repository.ErrTradeInNotFound)
+	}
+
+	data, err := readUpload(first, stream.Recv, maxImageBytes)
+	if err != nil {
+		return err
+	}
+
+	m := newMedia(entity.MediaImage, first.Filename, len(data))
+	blobs, err := prepareImage(m, tradeInMediaDir(id), data)
+	if err != nil {
+		return err
+	}
+	if err := s.putBlobs(ctx, m, blobs); err != nil {
+		return err
+	}
+
+	if err := s.tradeIns.AddPhoto(ctx, id, userID, m); err != nil {
+		deleteBlobs(ctx, s.blobs, m)
+		return tradeInError(err)
+	}
+
+	return stream.SendAndClose(mediaToProto(m))
+}
+
+func (s *InstrumentService) GetTradeIns(ctx context.Context, req *proto.GetTradeInsRequest) (*proto.GetTradeInsResponse, error) {
+	userID := primitive.NilObjectID
+	if req.UserId != "" {
+		var err error
+		userID, err = primitive.ObjectIDFromHex(req.UserId)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "неверный ID пользователя")
+		}
+	}
+
+	tradeIns, err := s.tradeIns.List(ctx, userID, req.Status)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &proto.GetTradeInsResponse{}
+	for i := range tradeIns {
+		resp.TradeIns = append(resp.TradeIns, tradeInToProto(&tradeIns[i]))
+	}
+	return resp, nil
+}
+
+// AppraiseTradeIn записывает оценку магазина: состояние инструмента
+// и сумму кредита либо отказ.
+func (s *InstrumentService) AppraiseTradeIn(ctx context.Context, req *proto.AppraiseTradeInRequest) (*proto.TradeIn, error) {
+	id, err := primitive.ObjectIDFromHex(req.Id)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "неверный ID заявки")
+	}
+	if err := validateAppraisal(req); err != nil {
+		return nil, err
+	}
+
+	note := strings.TrimSpace(req.Note)
+	var tradeIn *entity.TradeIn
+	if req.Reject {
+		tradeIn, err = s.tradeIns.Reject(ctx, id, note, time.Now())
+	} else {
+		amount := math.Round(req.OfferAmount*100) / 100
+		tradeIn, err = s.tradeIns.SetOffer(ctx, id, req.Condition, amount, note, time.Now())
+	}
+	if err != nil {
+		return nil, tradeInError(err)
+	}
+
+	s.publishTradeIn(tradeIn)
+	return tradeInToProto(tradeIn), nil
+}
+
+func (s *InstrumentService) RespondTradeInOffer(ctx context.Context, req *proto.RespondTradeInOfferRequest) (*proto.TradeIn, error) {
+	id, err := primitive.ObjectIDFromHex(req.Id)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "неверный ID заявки")
+	}
+	userID, err := primitive.ObjectIDFromHex(req.UserId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "неверный ID пользователя")
+	}
+
+	tradeIn, err := s.tradeIns.Respond(ctx, id, userID, req.Accept, time.Now())
+	if err != nil {
+		return nil, tradeInError(err)
+	}
+
+	s.publishTradeIn(tradeIn)
+	return tradeInToProto(tradeIn), nil
+}
+
+// ReceiveTradeIn вызывается, когда магазин получил инструмент по
+// принятому предложению: пользователю начисляется кредит, а инструмент
+// выставляется в каталог как б/у товар. ID товара сохраняется в заявке
+// до его создания, поэтому повторный вызов после сбоя продолжает с
+// прерванного шага, а кредит начисляется один раз по reference заявки.
+// Фото заявки в товар не переносятся — фото товара загружаются через
+// UploadInstrumentMedia.
+func (s *InstrumentService) ReceiveTradeIn(ctx context.Context, req *proto.ReceiveTradeInRequest) (*proto.TradeIn, error) {
+	id, err := primitive.ObjectIDFromHex(req.Id)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "неверный ID заявки")
+	}
+	if req.Price <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "цена товара должна быть больше нуля")
+	}
+
+	tradeIn, err := s.tradeIns.FindByID(ctx, id)
+	if err != nil {
+		return nil, tradeInError(err)
+	}
+	switch tradeIn.Status {
+	case entity.TradeInStatusListed:
+		return tradeInToProto(tradeIn), nil
+	case entity.TradeInStatusAccepted, entity.TradeInStatusReceived:
+	default:
+		return nil, tradeInError(repository.ErrTradeInStatus)
+	}
+
+	listing := &entity.Instrument{
+		Name:        strings.TrimSpace(req.Name),
+		Description: strings.TrimSpace(req.Description),
+		Price:       req.Price,
+		Category:    req.Category,
+		Brand:       req.Brand,
+		Stock:       1,
+		Used:        true,
+		Condition:   tradeIn.AppraisedCondition,
+		TradeInID:   &tradeIn.ID,
+		Version:     1,
+	}
+	if listing.Name == "" {
+		listing.Name = usedListingName(tradeIn)
+	}
+	if listing.Description == "" {
+		listing.Description = tradeIn.Description
+	}
+	if err := s.resolveCatalog(ctx, listing, nil); err != nil {
+		return nil, err
+	}
+
+	if tradeIn.Status == entity.TradeInStatusAccepted {
+		tradeIn, err = s.tradeIns.MarkReceived(ctx, id, primitive.NewObjectID(), time.Now())
+		if err != nil {
+			return nil, tradeInError(err)
+		}
+		s.publishTradeIn(tradeIn)
+	}
+	listing.ID = *tradeIn.ListingID
+
+	_, err = s.userClient.AddStoreCredit(ctx, &usersproto.AddStoreCreditRequest{
+		UserId:    tradeIn.UserID.Hex(),
+		Amount:    tradeIn.OfferAmount,
+		Reason:    tradeInCreditReason,
+		Reference: "trade_in:" + tradeIn.ID.Hex(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := s.createUsedListing(ctx, listing)
+	if err != nil {
+		return nil, err
+	}
+	if tradeIn.SerialNumber != "" {
+		s.addTradeInUnit(ctx, tradeIn, strings.TrimSpace(req.Location))
+	}
+
+	tradeIn, err = s.tradeIns.MarkListed(ctx, id)
+	if err != nil {
+		return nil, tradeInError(err)
+	}
+
+	s.cache.Del(ctx, allInstrumentsCacheKey)
+	if created {
+		_ = s.eventPublisher.Publish("instrument_created", map[string]string{
+			"id": listing.ID.Hex(),
+		})
+	}
+	s.publishTradeIn(tradeIn)
+
+	return tradeInToProto(tradeIn), nil
+}
+
+// createUsedListing создаёт б/у товар с заранее назначенным ID. Если
+// товар уже создан прерванным вызовом, возвращает false.
+func (s *InstrumentService) createUsedListing(ctx context.Context, listing *entity.Instrument) (bool, error) {
+	_, err := s.repo.Create(ctx, listing)
+	if err == nil {
+		return true, nil
+	}
+	if !mongo.IsDuplicateKeyError(err) {
+		return false, err
+	}
+	if _, findErr := s.repo.FindByID(ctx, listing.ID); findErr == nil {
+		return false, nil
+	}
+	return false, status.Errorf(codes.AlreadyExists, "инструмент с названием %q уже существует", listing.Name)
+}
+
+// addTradeInUnit ставит полученный экземпляр на учёт. Экземпляр с тем же
+// серийным номером мог остаться от прошлой продажи — тогда учёт ведётся
+// вручную.
+func (s *InstrumentService) addTradeInUnit(ctx context.Context, tradeIn *entity.TradeIn, location string) {
+	now := time.Now()
+	err := s.serials.Create(ctx, &entity.SerialUnit{
+		InstrumentID: *tradeIn.ListingID,
+		SerialNumber: tradeIn.SerialNumber,
+		Condition:    tradeIn.AppraisedCondition,
+		Location:     location,
+		Status:       entity.SerialStatusInStock,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	})
+	if err != nil && !mongo.IsDuplicateKeyError(err) {
+		log.Printf("Ошибка учёта экземпляра %s заявки %s: %v", tradeIn.SerialNumber, tradeIn.ID.Hex(), err)
+	}
+}
+
+func (s *InstrumentService) publishTradeIn(tradeIn *entity.TradeIn) {
+	_ = s.eventPublisher.Publish("trade_in_updated", map[string]string{
+		"id":      tradeIn.ID.Hex(),
+		"user_id": tradeIn.UserID.Hex(),
+		"status":  tradeIn.Status,
+	})
+}
+
+// validateTradeIn — новый инструмент в зачёт не принимается, поэтому
+// состояние ConditionNew недопустимо.
+func validateTradeIn(req *proto.SubmitTradeInRequest) error {
+	if strings.TrimSpace(req.Name) == "" {
+		return status.Errorf(codes.InvalidArgument, "не указано название инструмента")
+	}
+	if req.Condition == entity.ConditionNew || !entity.ValidCondition(req.Condition) {
+		return status.Errorf(codes.InvalidArgument, "неизвестное состояние инструмента: %q", req.Condition)
+	}
+	return nil
+}
+
+func validateAppraisal(req *proto.AppraiseTradeInRequest) error {
+	if req.Reject {
+		return nil
+	}
+	if req.Condition == entity.ConditionNew || !entity.ValidCondition(req.Condition) {
+		return status.Errorf(codes.InvalidArgument, "неизвестное состояние инструмента: %q", req.Condition)
+	}
+	if req.OfferAmount <= 0 {
+		return status.Errorf(codes.InvalidArgument, "сумма предложения должна быть больше нуля")
+	}
+	return nil
+}
+
+// usedListingName добавляет к названию из заявки пометку и хвост ID
+// заявки: названия в каталоге уникальны, а одинаковых б/у инструментов
+// может быть несколько.
+func usedListingName(tradeIn *entity.TradeIn) string {
+	return fmt.Sprintf("%s (б/у, %s)", tradeIn.Name, tradeIn.ID.Hex()[18:])
+}
+
+func tradeInMediaDir(id primitive.ObjectID) string {
+	return "trade-ins/" + id.Hex()
+}
+
+func tradeInError(err error) error {
+	switch {
+	case errors.Is(err, repository.ErrTradeInNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, repository.ErrTradeInStatus):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, repository.ErrPhotoLimit):
+		return status.Errorf(codes.FailedPrecondition, "к заявке можно приложить не больше %d фото", repository.MaxPhotosPerTradeIn)
+	}
+	return err
+}
+
+func tradeInToProto(t *entity.TradeIn) *proto.TradeIn {
+	resp := &proto.TradeIn{
+		Id:                 t.ID.Hex(),
+		UserId:             t.UserID.Hex(),
+		Name:               t.Name,
+		Brand:              t.Brand,
+		Description:        t.Description,
+		SerialNumber:       t.SerialNumber,
+		Condition:          t.Condition,
+		Photos:             mediaListToProto(t.Photos),
+		Status:             t.Status,
+		AppraisedCondition: t.AppraisedCondition,
+		OfferAmount:        t.OfferAmount,
+		OfferNote:          t.OfferNote,
+		CreatedAt:          t.CreatedAt.Unix(),
+	}
+	if t.ListingID != nil {
+		resp.ListingId = t.ListingID.Hex()
+	}
+	if t.OfferedAt != nil {
+		resp.OfferedAt = t.OfferedAt.Unix()
+	}
+	if t.RespondedAt != nil {
+		resp.RespondedAt = t.RespondedAt.Unix()
+	}
+	if t.ReceivedAt != nil {
+		resp.ReceivedAt = t.ReceivedAt.Unix()
+	}
+	return resp
+}
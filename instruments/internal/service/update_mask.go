@@ -0,0 +1,98 @@
+package service
+
+import (
+	"sort"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+
+	"gotune/instruments/internal/entity"
+	"gotune/instruments/proto"
+)
+
+// instrumentMaskFields — поля UpdateInstrumentByIDRequest, допустимые
+// в маске, и поля документа, которые меняются вместе с ними: от категории
+// зависят путь и атрибуты, от вариантов — общий остаток.
+var instrumentMaskFields = map[string][]string{
+	"name":              {"name"},
+	"description":       {"description"},
+	"price":             {"price"},
+	"weight_kg":         {"weight_kg"},
+	"length_cm":         {"length_cm"},
+	"width_cm":          {"width_cm"},
+	"height_cm":         {"height_cm"},
+	"tax_category":      {"tax_category"},
+	"max_cart_quantity": {"max_cart_quantity"},
+	"stock":             {"stock"},
+	"brand":             {"brand"},
+	"category":          {"category", "category_path", "attributes"},
+	"attributes":        {"attributes"},
+	"variants":          {"variants", "stock"},
+}
+
+// instrumentMaskPaths проверяет маску и возвращает её пути без повторов.
+func instrumentMaskPaths(mask *fieldmaskpb.FieldMask) ([]string, error) {
+	seen := map[string]bool{}
+	var paths []string
+	for _, p := range mask.GetPaths() {
+		if _, ok := instrumentMaskFields[p]; !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "поле %q нельзя обновить", p)
+		}
+		if !seen[p] {
+			seen[p] = true
+			paths = append(paths, p)
+		}
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// applyInstrumentMask переносит в inst значения запроса для путей маски.
+// Атрибуты и варианты проверяются отдельно, см. UpdateInstrumentByID.
+func applyInstrumentMask(inst *entity.Instrument, req *proto.UpdateInstrumentByIDRequest, paths []string) {
+	for _, p := range paths {
+		switch p {
+		case "name":
+			inst.Name = req.Name
+		case "description":
+			inst.Description = req.Description
+		case "price":
+			inst.Price = req.Price
+		case "weight_kg":
+			inst.WeightKg = req.WeightKg
+		case "length_cm":
+			inst.LengthCm = req.LengthCm
+		case "width_cm":
+			inst.WidthCm = req.WidthCm
+		case "height_cm":
+			inst.HeightCm = req.HeightCm
+		case "tax_category":
+			inst.TaxCategory = req.TaxCategory
+		case "max_cart_quantity":
+			inst.MaxCartQuantity = req.MaxCartQuantity
+		case "stock":
+			inst.Stock = req.Stock
+		case "brand":
+			inst.Brand = req.Brand
+		case "category":
+			inst.Category = req.Category
+		}
+	}
+}
+
+// instrumentMaskColumns возвращает поля документа, которые нужно записать.
+func instrumentMaskColumns(paths []string) []string {
+	seen := map[string]bool{}
+	var columns []string
+	for _, p := range paths {
+		for _, c := range instrumentMaskFields[p] {
+			if !seen[c] {
+				seen[c] = true
+				columns = append(columns, c)
+			}
+		}
+	}
+	sort.Strings(columns)
+	return columns
+}
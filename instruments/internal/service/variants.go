@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"gotune/instruments/internal/entity"
+	"gotune/instruments/proto"
+)
+
+var skuPattern = regexp.MustCompile(`^[A-Z0-9][A-Z0-9-]*$`)
+
+func (s *InstrumentService) GetInstrumentBySKU(ctx context.Context, req *proto.GetInstrumentBySKURequest) (*proto.Instrument, error) {
+	inst, err := s.repo.FindBySKU(ctx, strings.ToUpper(req.Sku))
+	if err == mongo.ErrNoDocuments {
+		return nil, status.Errorf(codes.NotFound, "вариант %s не найден", req.Sku)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return instrumentToProto(inst), nil
+}
+
+// applyVariants проверяет варианты и пересчитывает общий остаток
+// инструмента. Все варианты должны задавать один и тот же набор опций,
+// а сочетания значений не должны повторяться.
+func applyVariants(inst *entity.Instrument, variants []*proto.Variant) error {
+	inst.Variants = nil
+	if len(variants) == 0 {
+		return nil
+	}
+
+	var optionNames []string
+	skus := map[string]bool{}
+	combinations := map[string]bool{}
+	var stock int32
+	for _, v := range variants {
+		sku := strings.ToUpper(strings.TrimSpace(v.Sku))
+		if !skuPattern.MatchString(sku) {
+			return status.Errorf(codes.InvalidArgument, "некорректный SKU %q", v.Sku)
+		}
+		if skus[sku] {
+			return status.Errorf(codes.InvalidArgument, "SKU %s указан дважды", sku)
+		}
+		skus[sku] = true
+
+		if len(v.Options) == 0 {
+			return status.Errorf(codes.InvalidArgument, "у варианта %s не указаны опции", sku)
+		}
+		names := sortedKeys(v.Options)
+		if optionNames == nil {
+			optionNames = names
+		} else if strings.Join(names, ",") != strings.Join(optionNames, ",") {
+			return status.Errorf(codes.InvalidArgument, "у всех вариантов должны быть опции %s", strings.Join(optionNames, ", "))
+		}
+		combination := variantTitle(v.Options)
+		if combinations[combination] {
+			return status.Errorf(codes.InvalidArgument, "вариант %s повторяется", combination)
+		}
+		combinations[combination] = true
+
+		if v.Price < 0 || v.Stock < 0 {
+			return status.Errorf(codes.InvalidArgument, "цена и остаток варианта %s не могут быть отрицательными", sku)
+		}
+		stock += v.Stock
+
+		inst.Variants = append(inst.Variants, entity.Variant{
+			SKU:     sku,
+			Options: v.Options,
+			Price:   v.Price,
+			Stock:   v.Stock,
+		})
+	}
+	inst.Stock = stock
+	return nil
+}
+
+func variantsToProto(variants []entity.Variant) []*proto.Variant {
+	var result []*proto.Variant
+	for _, v := range variants {
+		result = append(result, &proto.Variant{
+			Sku:     v.SKU,
+			Options: v.Options,
+			Price:   v.Price,
+			Stock:   v.Stock,
+		})
+	}
+	return result
+}
+
+// variantTitle описывает вариант в виде "finish: sunburst, handedness: left".
+func variantTitle(options map[string]string) string {
+	var parts []string
+	for _, name := range sortedKeys(options) {
+		parts = append(parts, name+": "+options[name])
+	}
+	return strings.Join(parts, ", ")
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
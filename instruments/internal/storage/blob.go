@@ -0,0 +1,17 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+var ErrBlobNotFound = errors.New("файл не найден")
+
+// BlobStore хранит загруженные файлы (фото, аудио) по ключу вида
+// "instruments/<id>/<file>" и выдаёт их публичные адреса.
+type BlobStore interface {
+	Put(ctx context.Context, key string, r io.Reader) error
+	Delete(ctx context.Context, key string) error
+	URL(key string) string
+}
@@ -0,0 +1,39 @@
+package migrations
+
+import (
+	"context"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func Migration002_AddInstrumentSearchIndexes(db *mongo.Database) error {
+	instruments := db.Collection("instruments")
+
+	_, err := instruments.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			// совпадение в названии важнее совпадения в описании
+			Keys: bson.D{{Key: "name", Value: "text"}, {Key: "description", Value: "text"}},
+			Options: options.Index().
+				SetName("instruments_text").
+				SetWeights(bson.D{{Key: "name", Value: 10}, {Key: "description", Value: 1}}).
+				SetDefaultLanguage("russian"),
+		},
+		{
+			Keys: bson.D{{Key: "category", Value: 1}, {Key: "price", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "brand", Value: 1}, {Key: "price", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "price", Value: 1}, {Key: "_id", Value: 1}},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	log.Println("✅ Migration002_AddInstrumentSearchIndexes applied")
+	return nil
+}
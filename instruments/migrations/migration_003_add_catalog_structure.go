@@ -0,0 +1,87 @@
+package migrations
+
+import (
+	"context"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func Migration003_AddCatalogStructure(db *mongo.Database) error {
+	ctx := context.Background()
+
+	for _, name := range []string{"categories", "brands"} {
+		_, err := db.Collection(name).Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys:    bson.D{{Key: "slug", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	instruments := db.Collection("instruments")
+	_, err := instruments.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "category_path", Value: 1}, {Key: "price", Value: 1}},
+		},
+		{
+			// фильтрация по произвольным атрибутам категорий
+			Keys: bson.D{{Key: "attributes.$**", Value: 1}},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	// у существующих товаров категория была плоской строкой
+	_, err = instruments.UpdateMany(ctx,
+		bson.M{"category": bson.M{"$exists": true, "$ne": ""}, "category_path": bson.M{"$exists": false}},
+		mongo.Pipeline{{{Key: "$set", Value: bson.M{"category_path": bson.A{"$category"}}}}},
+	)
+	if err != nil {
+		return err
+	}
+
+	// Для каждого прежнего значения категории и бренда создаётся корневая
+	// категория и бренд с тем же slug, иначе полное обновление и импорт
+	// существующих товаров не пройдут проверку каталога.
+	if err := seedLegacyValues(ctx, instruments, "category", db.Collection("categories"), true); err != nil {
+		return err
+	}
+	if err := seedLegacyValues(ctx, instruments, "brand", db.Collection("brands"), false); err != nil {
+		return err
+	}
+
+	log.Println("✅ Migration003_AddCatalogStructure applied")
+	return nil
+}
+
+func seedLegacyValues(ctx context.Context, instruments *mongo.Collection, field string, target *mongo.Collection, withPath bool) error {
+	values, err := instruments.Distinct(ctx, field, bson.M{field: bson.M{"$exists": true, "$ne": ""}})
+	if err != nil {
+		return err
+	}
+	for _, v := range values {
+		slug, ok := v.(string)
+		if !ok {
+			continue
+		}
+		// slug попадает в документ из фильтра
+		doc := bson.M{"name": slug}
+		if withPath {
+			doc["path"] = bson.A{slug}
+		}
+		_, err := target.UpdateOne(ctx,
+			bson.M{"slug": slug},
+			bson.M{"$setOnInsert": doc},
+			options.Update().SetUpsert(true),
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package migrations
+
+import (
+	"context"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func Migration004_AddVariantSKUIndex(db *mongo.Database) error {
+	instruments := db.Collection("instruments")
+
+	// SKU уникален во всём каталоге; товары без вариантов в индекс не попадают
+	_, err := instruments.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys: bson.D{{Key: "variants.sku", Value: 1}},
+		Options: options.Index().
+			SetUnique(true).
+			SetPartialFilterExpression(bson.M{"variants.sku": bson.M{"$exists": true}}),
+	})
+	if err != nil {
+		return err
+	}
+	log.Println("✅ Migration004_AddVariantSKUIndex applied")
+	return nil
+}
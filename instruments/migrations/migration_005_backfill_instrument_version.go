@@ -0,0 +1,24 @@
+package migrations
+
+import (
+	"context"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func Migration005_BackfillInstrumentVersion(db *mongo.Database) error {
+	instruments := db.Collection("instruments")
+
+	// без версии документ нельзя обновить: фильтр по version его не найдёт
+	res, err := instruments.UpdateMany(context.Background(),
+		bson.M{"version": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"version": 1}},
+	)
+	if err != nil {
+		return err
+	}
+	log.Printf("✅ Migration005_BackfillInstrumentVersion applied, обновлено %d инструментов", res.ModifiedCount)
+	return nil
+}
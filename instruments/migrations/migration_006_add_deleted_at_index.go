@@ -0,0 +1,48 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// indexNotFoundCode — код ошибки MongoDB IndexNotFound.
+const indexNotFoundCode = 27
+
+func Migration006_AddDeletedAtIndex(db *mongo.Database) error {
+	instruments := db.Collection("instruments")
+
+	// задача очистки ищет давно удалённые инструменты; в индекс попадают
+	// только они
+	_, err := instruments.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "deleted_at", Value: 1}},
+		Options: options.Index().SetSparse(true),
+	})
+	if err != nil {
+		return err
+	}
+
+	// уникальный name_1 из Migration001 учитывал удалённые инструменты, и
+	// имя удалённого нельзя было занять до очистки. Частичный индекс не
+	// поддерживает $exists: false, поэтому уникальность строится по паре
+	// (name, deleted_at): у активных deleted_at пуст и имена сталкиваются,
+	// у удалённых стоит время удаления.
+	_, err = instruments.Indexes().DropOne(context.Background(), "name_1")
+	var cmdErr mongo.CommandError
+	if err != nil && !(errors.As(err, &cmdErr) && cmdErr.Code == indexNotFoundCode) {
+		return err
+	}
+	_, err = instruments.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "name", Value: 1}, {Key: "deleted_at", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+	log.Println("✅ Migration006_AddDeletedAtIndex applied")
+	return nil
+}
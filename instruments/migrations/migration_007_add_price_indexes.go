@@ -0,0 +1,31 @@
+package migrations
+
+import (
+	"context"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func Migration007_AddPriceIndexes(db *mongo.Database) error {
+	_, err := db.Collection("price_history").Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys: bson.D{{Key: "instrument_id", Value: 1}, {Key: "changed_at", Value: -1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	// планировщик выбирает расписания по статусу и времени начала или
+	// окончания, карточка товара — открытые расписания инструмента
+	_, err = db.Collection("price_schedules").Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "starts_at", Value: 1}}},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "ends_at", Value: 1}}},
+		{Keys: bson.D{{Key: "instrument_id", Value: 1}, {Key: "status", Value: 1}}},
+	})
+	if err != nil {
+		return err
+	}
+	log.Println("✅ Migration007_AddPriceIndexes applied")
+	return nil
+}
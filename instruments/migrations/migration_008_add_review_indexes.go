@@ -0,0 +1,28 @@
+package migrations
+
+import (
+	"context"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func Migration008_AddReviewIndexes(db *mongo.Database) error {
+	_, err := db.Collection("reviews").Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		// один отзыв от пользователя на инструмент
+		{
+			Keys:    bson.D{{Key: "instrument_id", Value: 1}, {Key: "user_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{Keys: bson.D{{Key: "instrument_id", Value: 1}, {Key: "status", Value: 1}, {Key: "created_at", Value: -1}}},
+		// очередь модерации
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: -1}}},
+	})
+	if err != nil {
+		return err
+	}
+	log.Println("✅ Migration008_AddReviewIndexes applied")
+	return nil
+}
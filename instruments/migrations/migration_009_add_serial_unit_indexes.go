@@ -0,0 +1,30 @@
+package migrations
+
+import (
+	"context"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func Migration009_AddSerialUnitIndexes(db *mongo.Database) error {
+	_, err := db.Collection("serial_units").Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "serial_number", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		// подбор экземпляра со склада при отгрузке
+		{Keys: bson.D{{Key: "instrument_id", Value: 1}, {Key: "sku", Value: 1}, {Key: "status", Value: 1}, {Key: "created_at", Value: 1}}},
+		{
+			Keys:    bson.D{{Key: "order_id", Value: 1}},
+			Options: options.Index().SetSparse(true),
+		},
+	})
+	if err != nil {
+		return err
+	}
+	log.Println("✅ Migration009_AddSerialUnitIndexes applied")
+	return nil
+}
@@ -0,0 +1,25 @@
+package migrations
+
+import (
+	"context"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func Migration010_AddTradeInIndexes(db *mongo.Database) error {
+	_, err := db.Collection("trade_ins").Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: 1}}},
+		// очередь заявок для сотрудников
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: 1}}},
+		// задача очистки проверяет, не выставлен ли инструмент по заявке
+		{Keys: bson.D{{Key: "listing_id", Value: 1}}, Options: options.Index().SetSparse(true)},
+	})
+	if err != nil {
+		return err
+	}
+	log.Println("✅ Migration010_AddTradeInIndexes applied")
+	return nil
+}
@@ -0,0 +1,37 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Migration011 перестраивает уникальный индекс SKU из Migration004: он
+// учитывал удалённые инструменты, и их SKU нельзя было занять ни при
+// создании, ни при импорте до очистки. Как и название в Migration006,
+// SKU уникален в паре с deleted_at.
+func Migration011_ScopeVariantSKUIndex(db *mongo.Database) error {
+	instruments := db.Collection("instruments")
+
+	_, err := instruments.Indexes().DropOne(context.Background(), "variants.sku_1")
+	var cmdErr mongo.CommandError
+	if err != nil && !(errors.As(err, &cmdErr) && cmdErr.Code == indexNotFoundCode) {
+		return err
+	}
+
+	_, err = instruments.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys: bson.D{{Key: "variants.sku", Value: 1}, {Key: "deleted_at", Value: 1}},
+		Options: options.Index().
+			SetUnique(true).
+			SetPartialFilterExpression(bson.M{"variants.sku": bson.M{"$exists": true}}),
+	})
+	if err != nil {
+		return err
+	}
+	log.Println("✅ Migration011_ScopeVariantSKUIndex applied")
+	return nil
+}
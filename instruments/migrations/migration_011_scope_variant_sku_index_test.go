@@ -0,0 +1,46 @@
+package migrations
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestMigration011ScopesSKUIndexToActiveInstruments(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("индекс пересоздаётся с deleted_at", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateSuccessResponse(), mtest.CreateSuccessResponse())
+
+		assert.NoError(t, Migration011_ScopeVariantSKUIndex(mt.DB))
+
+		drop := mt.GetStartedEvent().Command
+		assert.Equal(t, "variants.sku_1", drop.Lookup("index").StringValue())
+
+		create := mt.GetStartedEvent().Command
+		index := create.Lookup("indexes").Array().Index(0).Value().Document()
+		keys, err := index.Lookup("key").Document().Elements()
+		assert.NoError(t, err)
+		if assert.Len(t, keys, 2) {
+			assert.Equal(t, "variants.sku", keys[0].Key())
+			assert.Equal(t, "deleted_at", keys[1].Key())
+		}
+		assert.True(t, index.Lookup("unique").Boolean())
+	})
+
+	mt.Run("старого индекса нет", func(mt *mtest.T) {
+		mt.AddMockResponses(
+			mtest.CreateCommandErrorResponse(mtest.CommandError{Code: indexNotFoundCode, Message: "index not found"}),
+			mtest.CreateSuccessResponse(),
+		)
+
+		assert.NoError(t, Migration011_ScopeVariantSKUIndex(mt.DB))
+	})
+
+	mt.Run("ошибка удаления", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 13, Message: "unauthorized"}))
+
+		assert.Error(t, Migration011_ScopeVariantSKUIndex(mt.DB))
+	})
+}
@@ -17,6 +17,16 @@ type Migration struct {
 func RunAll(db *mongo.Database) error {
 	migrations := []Migration{
 		{Name: "Migration001_AddInstrumentIndex", Func: Migration001_AddInstrumentIndex},
+		{Name: "Migration002_AddInstrumentSearchIndexes", Func: Migration002_AddInstrumentSearchIndexes},
+		{Name: "Migration003_AddCatalogStructure", Func: Migration003_AddCatalogStructure},
+		{Name: "Migration004_AddVariantSKUIndex", Func: Migration004_AddVariantSKUIndex},
+		{Name: "Migration005_BackfillInstrumentVersion", Func: Migration005_BackfillInstrumentVersion},
+		{Name: "Migration006_AddDeletedAtIndex", Func: Migration006_AddDeletedAtIndex},
+		{Name: "Migration007_AddPriceIndexes", Func: Migration007_AddPriceIndexes},
+		{Name: "Migration008_AddReviewIndexes", Func: Migration008_AddReviewIndexes},
+		{Name: "Migration009_AddSerialUnitIndexes", Func: Migration009_AddSerialUnitIndexes},
+		{Name: "Migration010_AddTradeInIndexes", Func: Migration010_AddTradeInIndexes},
+		{Name: "Migration011_ScopeVariantSKUIndex", Func: Migration011_ScopeVariantSKUIndex},
 	}
 
 	applied := db.Collection("migrations")
@@ -26,7 +26,39 @@ type InstrumentServiceClient interface {
 	GetAllInstruments(ctx context.Context, in *GetAllInstrumentsRequest, opts ...grpc.CallOption) (*GetAllInstrumentsResponse, error)
 	GetInstrumentByID(ctx context.Context, in *GetInstrumentByIDRequest, opts ...grpc.CallOption) (*Instrument, error)
 	DeleteInstrumentByID(ctx context.Context, in *DeleteInstrumentByIDRequest, opts ...grpc.CallOption) (*DeleteInstrumentByIDResponse, error)
+	RestoreInstrument(ctx context.Context, in *RestoreInstrumentRequest, opts ...grpc.CallOption) (*RestoreInstrumentResponse, error)
 	UpdateInstrumentByID(ctx context.Context, in *UpdateInstrumentByIDRequest, opts ...grpc.CallOption) (*UpdateInstrumentByIDResponse, error)
+	SearchInstruments(ctx context.Context, in *SearchInstrumentsRequest, opts ...grpc.CallOption) (*SearchInstrumentsResponse, error)
+	GetInstrumentBySKU(ctx context.Context, in *GetInstrumentBySKURequest, opts ...grpc.CallOption) (*Instrument, error)
+	ReserveStock(ctx context.Context, in *ReserveStockRequest, opts ...grpc.CallOption) (*ReserveStockResponse, error)
+	ReleaseStock(ctx context.Context, in *ReleaseStockRequest, opts ...grpc.CallOption) (*ReleaseStockResponse, error)
+	UploadInstrumentMedia(ctx context.Context, opts ...grpc.CallOption) (InstrumentService_UploadInstrumentMediaClient, error)
+	DeleteInstrumentMedia(ctx context.Context, in *DeleteInstrumentMediaRequest, opts ...grpc.CallOption) (*DeleteInstrumentMediaResponse, error)
+	CreateCategory(ctx context.Context, in *CreateCategoryRequest, opts ...grpc.CallOption) (*CreateCategoryResponse, error)
+	GetCategories(ctx context.Context, in *GetCategoriesRequest, opts ...grpc.CallOption) (*GetCategoriesResponse, error)
+	CreateBrand(ctx context.Context, in *CreateBrandRequest, opts ...grpc.CallOption) (*CreateBrandResponse, error)
+	GetBrands(ctx context.Context, in *GetBrandsRequest, opts ...grpc.CallOption) (*GetBrandsResponse, error)
+	SchedulePriceChange(ctx context.Context, in *SchedulePriceChangeRequest, opts ...grpc.CallOption) (*PriceSchedule, error)
+	CancelPriceSchedule(ctx context.Context, in *CancelPriceScheduleRequest, opts ...grpc.CallOption) (*CancelPriceScheduleResponse, error)
+	GetPriceHistory(ctx context.Context, in *GetPriceHistoryRequest, opts ...grpc.CallOption) (*GetPriceHistoryResponse, error)
+	ImportInstruments(ctx context.Context, opts ...grpc.CallOption) (InstrumentService_ImportInstrumentsClient, error)
+	ExportInstruments(ctx context.Context, in *ExportInstrumentsRequest, opts ...grpc.CallOption) (InstrumentService_ExportInstrumentsClient, error)
+	CreateReview(ctx context.Context, in *CreateReviewRequest, opts ...grpc.CallOption) (*Review, error)
+	GetReviews(ctx context.Context, in *GetReviewsRequest, opts ...grpc.CallOption) (*GetReviewsResponse, error)
+	ModerateReview(ctx context.Context, in *ModerateReviewRequest, opts ...grpc.CallOption) (*Review, error)
+	VoteReviewHelpful(ctx context.Context, in *VoteReviewHelpfulRequest, opts ...grpc.CallOption) (*VoteReviewHelpfulResponse, error)
+	AddSerialUnit(ctx context.Context, in *AddSerialUnitRequest, opts ...grpc.CallOption) (*SerialUnit, error)
+	UpdateSerialUnit(ctx context.Context, in *UpdateSerialUnitRequest, opts ...grpc.CallOption) (*SerialUnit, error)
+	GetSerialUnit(ctx context.Context, in *GetSerialUnitRequest, opts ...grpc.CallOption) (*SerialUnit, error)
+	GetSerialUnits(ctx context.Context, in *GetSerialUnitsRequest, opts ...grpc.CallOption) (*GetSerialUnitsResponse, error)
+	AllocateSerialUnits(ctx context.Context, in *AllocateSerialUnitsRequest, opts ...grpc.CallOption) (*AllocateSerialUnitsResponse, error)
+	RegisterWarranty(ctx context.Context, in *RegisterWarrantyRequest, opts ...grpc.CallOption) (*SerialUnit, error)
+	SubmitTradeIn(ctx context.Context, in *SubmitTradeInRequest, opts ...grpc.CallOption) (*TradeIn, error)
+	UploadTradeInPhoto(ctx context.Context, opts ...grpc.CallOption) (InstrumentService_UploadTradeInPhotoClient, error)
+	GetTradeIns(ctx context.Context, in *GetTradeInsRequest, opts ...grpc.CallOption) (*GetTradeInsResponse, error)
+	AppraiseTradeIn(ctx context.Context, in *AppraiseTradeInRequest, opts ...grpc.CallOption) (*TradeIn, error)
+	RespondTradeInOffer(ctx context.Context, in *RespondTradeInOfferRequest, opts ...grpc.CallOption) (*TradeIn, error)
+	ReceiveTradeIn(ctx context.Context, in *ReceiveTradeInRequest, opts ...grpc.CallOption) (*TradeIn, error)
 }
 
 type instrumentServiceClient struct {
@@ -73,145 +105,1262 @@ func (c *instrumentServiceClient) DeleteInstrumentByID(ctx context.Context, in *
 	return out, nil
 }
 
+func (c *instrumentServiceClient) RestoreInstrument(ctx context.Context, in *RestoreInstrumentRequest, opts ...grpc.CallOption) (*RestoreInstrumentResponse, error) {
+	out := new(RestoreInstrumentResponse)
+	err := c.cc.Invoke(ctx, "/instruments.InstrumentService/RestoreInstrument", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *instrumentServiceClient) UpdateInstrumentByID(ctx context.Context, in *UpdateInstrumentByIDRequest, opts ...grpc.CallOption) (*UpdateInstrumentByIDResponse, error) {
 	out := new(UpdateInstrumentByIDResponse)
 	err := c.cc.Invoke(ctx, "/instruments.InstrumentService/UpdateInstrumentByID", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
-	return out, nil
+	return out, nil
+}
+
+func (c *instrumentServiceClient) SearchInstruments(ctx context.Context, in *SearchInstrumentsRequest, opts ...grpc.CallOption) (*SearchInstrumentsResponse, error) {
+	out := new(SearchInstrumentsResponse)
+	err := c.cc.Invoke(ctx, "/instruments.InstrumentService/SearchInstruments", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *instrumentServiceClient) GetInstrumentBySKU(ctx context.Context, in *GetInstrumentBySKURequest, opts ...grpc.CallOption) (*Instrument, error) {
+	out := new(Instrument)
+	err := c.cc.Invoke(ctx, "/instruments.InstrumentService/GetInstrumentBySKU", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *instrumentServiceClient) ReserveStock(ctx context.Context, in *ReserveStockRequest, opts ...grpc.CallOption) (*ReserveStockResponse, error) {
+	out := new(ReserveStockResponse)
+	err := c.cc.Invoke(ctx, "/instruments.InstrumentService/ReserveStock", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *instrumentServiceClient) ReleaseStock(ctx context.Context, in *ReleaseStockRequest, opts ...grpc.CallOption) (*ReleaseStockResponse, error) {
+	out := new(ReleaseStockResponse)
+	err := c.cc.Invoke(ctx, "/instruments.InstrumentService/ReleaseStock", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *instrumentServiceClient) UploadInstrumentMedia(ctx context.Context, opts ...grpc.CallOption) (InstrumentService_UploadInstrumentMediaClient, error) {
+	stream, err := c.cc.NewStream(ctx, &InstrumentService_ServiceDesc.Streams[0], "/instruments.InstrumentService/UploadInstrumentMedia", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &instrumentServiceUploadInstrumentMediaClient{stream}
+	return x, nil
+}
+
+type InstrumentService_UploadInstrumentMediaClient interface {
+	Send(*UploadInstrumentMediaRequest) error
+	CloseAndRecv() (*Media, error)
+	grpc.ClientStream
+}
+
+type instrumentServiceUploadInstrumentMediaClient struct {
+	grpc.ClientStream
+}
+
+func (x *instrumentServiceUploadInstrumentMediaClient) Send(m *UploadInstrumentMediaRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *instrumentServiceUploadInstrumentMediaClient) CloseAndRecv() (*Media, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(Media)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *instrumentServiceClient) DeleteInstrumentMedia(ctx context.Context, in *DeleteInstrumentMediaRequest, opts ...grpc.CallOption) (*DeleteInstrumentMediaResponse, error) {
+	out := new(DeleteInstrumentMediaResponse)
+	err := c.cc.Invoke(ctx, "/instruments.InstrumentService/DeleteInstrumentMedia", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *instrumentServiceClient) CreateCategory(ctx context.Context, in *CreateCategoryRequest, opts ...grpc.CallOption) (*CreateCategoryResponse, error) {
+	out := new(CreateCategoryResponse)
+	err := c.cc.Invoke(ctx, "/instruments.InstrumentService/CreateCategory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *instrumentServiceClient) GetCategories(ctx context.Context, in *GetCategoriesRequest, opts ...grpc.CallOption) (*GetCategoriesResponse, error) {
+	out := new(GetCategoriesResponse)
+	err := c.cc.Invoke(ctx, "/instruments.InstrumentService/GetCategories", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *instrumentServiceClient) CreateBrand(ctx context.Context, in *CreateBrandRequest, opts ...grpc.CallOption) (*CreateBrandResponse, error) {
+	out := new(CreateBrandResponse)
+	err := c.cc.Invoke(ctx, "/instruments.InstrumentService/CreateBrand", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *instrumentServiceClient) GetBrands(ctx context.Context, in *GetBrandsRequest, opts ...grpc.CallOption) (*GetBrandsResponse, error) {
+	out := new(GetBrandsResponse)
+	err := c.cc.Invoke(ctx, "/instruments.InstrumentService/GetBrands", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *instrumentServiceClient) SchedulePriceChange(ctx context.Context, in *SchedulePriceChangeRequest, opts ...grpc.CallOption) (*PriceSchedule, error) {
+	out := new(PriceSchedule)
+	err := c.cc.Invoke(ctx, "/instruments.InstrumentService/SchedulePriceChange", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *instrumentServiceClient) CancelPriceSchedule(ctx context.Context, in *CancelPriceScheduleRequest, opts ...grpc.CallOption) (*CancelPriceScheduleResponse, error) {
+	out := new(CancelPriceScheduleResponse)
+	err := c.cc.Invoke(ctx, "/instruments.InstrumentService/CancelPriceSchedule", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *instrumentServiceClient) GetPriceHistory(ctx context.Context, in *GetPriceHistoryRequest, opts ...grpc.CallOption) (*GetPriceHistoryResponse, error) {
+	out := new(GetPriceHistoryResponse)
+	err := c.cc.Invoke(ctx, "/instruments.InstrumentService/GetPriceHistory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *instrumentServiceClient) ImportInstruments(ctx context.Context, opts ...grpc.CallOption) (InstrumentService_ImportInstrumentsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &InstrumentService_ServiceDesc.Streams[1], "/instruments.InstrumentService/ImportInstruments", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &instrumentServiceImportInstrumentsClient{stream}
+	return x, nil
+}
+
+type InstrumentService_ImportInstrumentsClient interface {
+	Send(*ImportInstrumentRow) error
+	CloseAndRecv() (*ImportInstrumentsResponse, error)
+	grpc.ClientStream
+}
+
+type instrumentServiceImportInstrumentsClient struct {
+	grpc.ClientStream
+}
+
+func (x *instrumentServiceImportInstrumentsClient) Send(m *ImportInstrumentRow) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *instrumentServiceImportInstrumentsClient) CloseAndRecv() (*ImportInstrumentsResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(ImportInstrumentsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *instrumentServiceClient) ExportInstruments(ctx context.Context, in *ExportInstrumentsRequest, opts ...grpc.CallOption) (InstrumentService_ExportInstrumentsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &InstrumentService_ServiceDesc.Streams[2], "/instruments.InstrumentService/ExportInstruments", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &instrumentServiceExportInstrumentsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type InstrumentService_ExportInstrumentsClient interface {
+	Recv() (*Instrument, error)
+	grpc.ClientStream
+}
+
+type instrumentServiceExportInstrumentsClient struct {
+	grpc.ClientStream
+}
+
+func (x *instrumentServiceExportInstrumentsClient) Recv() (*Instrument, error) {
+	m := new(Instrument)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *instrumentServiceClient) CreateReview(ctx context.Context, in *CreateReviewRequest, opts ...grpc.CallOption) (*Review, error) {
+	out := new(Review)
+	err := c.cc.Invoke(ctx, "/instruments.InstrumentService/CreateReview", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *instrumentServiceClient) GetReviews(ctx context.Context, in *GetReviewsRequest, opts ...grpc.CallOption) (*GetReviewsResponse, error) {
+	out := new(GetReviewsResponse)
+	err := c.cc.Invoke(ctx, "/instruments.InstrumentService/GetReviews", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *instrumentServiceClient) ModerateReview(ctx context.Context, in *ModerateReviewRequest, opts ...grpc.CallOption) (*Review, error) {
+	out := new(Review)
+	err := c.cc.Invoke(ctx, "/instruments.InstrumentService/ModerateReview", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *instrumentServiceClient) VoteReviewHelpful(ctx context.Context, in *VoteReviewHelpfulRequest, opts ...grpc.CallOption) (*VoteReviewHelpfulResponse, error) {
+	out := new(VoteReviewHelpfulResponse)
+	err := c.cc.Invoke(ctx, "/instruments.InstrumentService/VoteReviewHelpful", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *instrumentServiceClient) AddSerialUnit(ctx context.Context, in *AddSerialUnitRequest, opts ...grpc.CallOption) (*SerialUnit, error) {
+	out := new(SerialUnit)
+	err := c.cc.Invoke(ctx, "/instruments.InstrumentService/AddSerialUnit", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *instrumentServiceClient) UpdateSerialUnit(ctx context.Context, in *UpdateSerialUnitRequest, opts ...grpc.CallOption) (*SerialUnit, error) {
+	out := new(SerialUnit)
+	err := c.cc.Invoke(ctx, "/instruments.InstrumentService/UpdateSerialUnit", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *instrumentServiceClient) GetSerialUnit(ctx context.Context, in *GetSerialUnitRequest, opts ...grpc.CallOption) (*SerialUnit, error) {
+	out := new(SerialUnit)
+	err := c.cc.Invoke(ctx, "/instruments.InstrumentService/GetSerialUnit", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *instrumentServiceClient) GetSerialUnits(ctx context.Context, in *GetSerialUnitsRequest, opts ...grpc.CallOption) (*GetSerialUnitsResponse, error) {
+	out := new(GetSerialUnitsResponse)
+	err := c.cc.Invoke(ctx, "/instruments.InstrumentService/GetSerialUnits", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *instrumentServiceClient) AllocateSerialUnits(ctx context.Context, in *AllocateSerialUnitsRequest, opts ...grpc.CallOption) (*AllocateSerialUnitsResponse, error) {
+	out := new(AllocateSerialUnitsResponse)
+	err := c.cc.Invoke(ctx, "/instruments.InstrumentService/AllocateSerialUnits", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *instrumentServiceClient) RegisterWarranty(ctx context.Context, in *RegisterWarrantyRequest, opts ...grpc.CallOption) (*SerialUnit, error) {
+	out := new(SerialUnit)
+	err := c.cc.Invoke(ctx, "/instruments.InstrumentService/RegisterWarranty", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *instrumentServiceClient) SubmitTradeIn(ctx context.Context, in *SubmitTradeInRequest, opts ...grpc.CallOption) (*TradeIn, error) {
+	out := new(TradeIn)
+	err := c.cc.Invoke(ctx, "/instruments.InstrumentService/SubmitTradeIn", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *instrumentServiceClient) UploadTradeInPhoto(ctx context.Context, opts ...grpc.CallOption) (InstrumentService_UploadTradeInPhotoClient, error) {
+	stream, err := c.cc.NewStream(ctx, &InstrumentService_ServiceDesc.Streams[3], "/instruments.InstrumentService/UploadTradeInPhoto", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &instrumentServiceUploadTradeInPhotoClient{stream}
+	return x, nil
+}
+
+type InstrumentService_UploadTradeInPhotoClient interface {
+	Send(*UploadTradeInPhotoRequest) error
+	CloseAndRecv() (*Media, error)
+	grpc.ClientStream
+}
+
+type instrumentServiceUploadTradeInPhotoClient struct {
+	grpc.ClientStream
+}
+
+func (x *instrumentServiceUploadTradeInPhotoClient) Send(m *UploadTradeInPhotoRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *instrumentServiceUploadTradeInPhotoClient) CloseAndRecv() (*Media, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(Media)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *instrumentServiceClient) GetTradeIns(ctx context.Context, in *GetTradeInsRequest, opts ...grpc.CallOption) (*GetTradeInsResponse, error) {
+	out := new(GetTradeInsResponse)
+	err := c.cc.Invoke(ctx, "/instruments.InstrumentService/GetTradeIns", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *instrumentServiceClient) AppraiseTradeIn(ctx context.Context, in *AppraiseTradeInRequest, opts ...grpc.CallOption) (*TradeIn, error) {
+	out := new(TradeIn)
+	err := c.cc.Invoke(ctx, "/instruments.InstrumentService/AppraiseTradeIn", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *instrumentServiceClient) RespondTradeInOffer(ctx context.Context, in *RespondTradeInOfferRequest, opts ...grpc.CallOption) (*TradeIn, error) {
+	out := new(TradeIn)
+	err := c.cc.Invoke(ctx, "/instruments.InstrumentService/RespondTradeInOffer", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *instrumentServiceClient) ReceiveTradeIn(ctx context.Context, in *ReceiveTradeInRequest, opts ...grpc.CallOption) (*TradeIn, error) {
+	out := new(TradeIn)
+	err := c.cc.Invoke(ctx, "/instruments.InstrumentService/ReceiveTradeIn", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// InstrumentServiceServer is the server API for InstrumentService service.
+// All implementations must embed UnimplementedInstrumentServiceServer
+// for forward compatibility
+type InstrumentServiceServer interface {
+	CreateInstrument(context.Context, *CreateInstrumentRequest) (*CreateInstrumentResponse, error)
+	GetAllInstruments(context.Context, *GetAllInstrumentsRequest) (*GetAllInstrumentsResponse, error)
+	GetInstrumentByID(context.Context, *GetInstrumentByIDRequest) (*Instrument, error)
+	DeleteInstrumentByID(context.Context, *DeleteInstrumentByIDRequest) (*DeleteInstrumentByIDResponse, error)
+	RestoreInstrument(context.Context, *RestoreInstrumentRequest) (*RestoreInstrumentResponse, error)
+	UpdateInstrumentByID(context.Context, *UpdateInstrumentByIDRequest) (*UpdateInstrumentByIDResponse, error)
+	SearchInstruments(context.Context, *SearchInstrumentsRequest) (*SearchInstrumentsResponse, error)
+	GetInstrumentBySKU(context.Context, *GetInstrumentBySKURequest) (*Instrument, error)
+	ReserveStock(context.Context, *ReserveStockRequest) (*ReserveStockResponse, error)
+	ReleaseStock(context.Context, *ReleaseStockRequest) (*ReleaseStockResponse, error)
+	UploadInstrumentMedia(InstrumentService_UploadInstrumentMediaServer) error
+	DeleteInstrumentMedia(context.Context, *DeleteInstrumentMediaRequest) (*DeleteInstrumentMediaResponse, error)
+	CreateCategory(context.Context, *CreateCategoryRequest) (*CreateCategoryResponse, error)
+	GetCategories(context.Context, *GetCategoriesRequest) (*GetCategoriesResponse, error)
+	CreateBrand(context.Context, *CreateBrandRequest) (*CreateBrandResponse, error)
+	GetBrands(context.Context, *GetBrandsRequest) (*GetBrandsResponse, error)
+	SchedulePriceChange(context.Context, *SchedulePriceChangeRequest) (*PriceSchedule, error)
+	CancelPriceSchedule(context.Context, *CancelPriceScheduleRequest) (*CancelPriceScheduleResponse, error)
+	GetPriceHistory(context.Context, *GetPriceHistoryRequest) (*GetPriceHistoryResponse, error)
+	ImportInstruments(InstrumentService_ImportInstrumentsServer) error
+	ExportInstruments(*ExportInstrumentsRequest, InstrumentService_ExportInstrumentsServer) error
+	CreateReview(context.Context, *CreateReviewRequest) (*Review, error)
+	GetReviews(context.Context, *GetReviewsRequest) (*GetReviewsResponse, error)
+	ModerateReview(context.Context, *ModerateReviewRequest) (*Review, error)
+	VoteReviewHelpful(context.Context, *VoteReviewHelpfulRequest) (*VoteReviewHelpfulResponse, error)
+	AddSerialUnit(context.Context, *AddSerialUnitRequest) (*SerialUnit, error)
+	UpdateSerialUnit(context.Context, *UpdateSerialUnitRequest) (*SerialUnit, error)
+	GetSerialUnit(context.Context, *GetSerialUnitRequest) (*SerialUnit, error)
+	GetSerialUnits(context.Context, *GetSerialUnitsRequest) (*GetSerialUnitsResponse, error)
+	AllocateSerialUnits(context.Context, *AllocateSerialUnitsRequest) (*AllocateSerialUnitsResponse, error)
+	RegisterWarranty(context.Context, *RegisterWarrantyRequest) (*SerialUnit, error)
+	SubmitTradeIn(context.Context, *SubmitTradeInRequest) (*TradeIn, error)
+	UploadTradeInPhoto(InstrumentService_UploadTradeInPhotoServer) error
+	GetTradeIns(context.Context, *GetTradeInsRequest) (*GetTradeInsResponse, error)
+	AppraiseTradeIn(context.Context, *AppraiseTradeInRequest) (*TradeIn, error)
+	RespondTradeInOffer(context.Context, *RespondTradeInOfferRequest) (*TradeIn, error)
+	ReceiveTradeIn(context.Context, *ReceiveTradeInRequest) (*TradeIn, error)
+	mustEmbedUnimplementedInstrumentServiceServer()
+}
+
+// UnimplementedInstrumentServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedInstrumentServiceServer struct {
+}
+
+func (UnimplementedInstrumentServiceServer) CreateInstrument(context.Context, *CreateInstrumentRequest) (*CreateInstrumentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateInstrument not implemented")
+}
+func (UnimplementedInstrumentServiceServer) GetAllInstruments(context.Context, *GetAllInstrumentsRequest) (*GetAllInstrumentsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAllInstruments not implemented")
+}
+func (UnimplementedInstrumentServiceServer) GetInstrumentByID(context.Context, *GetInstrumentByIDRequest) (*Instrument, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetInstrumentByID not implemented")
+}
+func (UnimplementedInstrumentServiceServer) DeleteInstrumentByID(context.Context, *DeleteInstrumentByIDRequest) (*DeleteInstrumentByIDResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteInstrumentByID not implemented")
+}
+func (UnimplementedInstrumentServiceServer) RestoreInstrument(context.Context, *RestoreInstrumentRequest) (*RestoreInstrumentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreInstrument not implemented")
+}
+func (UnimplementedInstrumentServiceServer) UpdateInstrumentByID(context.Context, *UpdateInstrumentByIDRequest) (*UpdateInstrumentByIDResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateInstrumentByID not implemented")
+}
+func (UnimplementedInstrumentServiceServer) SearchInstruments(context.Context, *SearchInstrumentsRequest) (*SearchInstrumentsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchInstruments not implemented")
+}
+func (UnimplementedInstrumentServiceServer) GetInstrumentBySKU(context.Context, *GetInstrumentBySKURequest) (*Instrument, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetInstrumentBySKU not implemented")
+}
+func (UnimplementedInstrumentServiceServer) ReserveStock(context.Context, *ReserveStockRequest) (*ReserveStockResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReserveStock not implemented")
+}
+func (UnimplementedInstrumentServiceServer) ReleaseStock(context.Context, *ReleaseStockRequest) (*ReleaseStockResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReleaseStock not implemented")
+}
+func (UnimplementedInstrumentServiceServer) UploadInstrumentMedia(InstrumentService_UploadInstrumentMediaServer) error {
+	return status.Errorf(codes.Unimplemented, "method UploadInstrumentMedia not implemented")
+}
+func (UnimplementedInstrumentServiceServer) DeleteInstrumentMedia(context.Context, *DeleteInstrumentMediaRequest) (*DeleteInstrumentMediaResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteInstrumentMedia not implemented")
+}
+func (UnimplementedInstrumentServiceServer) CreateCategory(context.Context, *CreateCategoryRequest) (*CreateCategoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateCategory not implemented")
+}
+func (UnimplementedInstrumentServiceServer) GetCategories(context.Context, *GetCategoriesRequest) (*GetCategoriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCategories not implemented")
+}
+func (UnimplementedInstrumentServiceServer) CreateBrand(context.Context, *CreateBrandRequest) (*CreateBrandResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateBrand not implemented")
+}
+func (UnimplementedInstrumentServiceServer) GetBrands(context.Context, *GetBrandsRequest) (*GetBrandsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBrands not implemented")
+}
+func (UnimplementedInstrumentServiceServer) SchedulePriceChange(context.Context, *SchedulePriceChangeRequest) (*PriceSchedule, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SchedulePriceChange not implemented")
+}
+func (UnimplementedInstrumentServiceServer) CancelPriceSchedule(context.Context, *CancelPriceScheduleRequest) (*CancelPriceScheduleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelPriceSchedule not implemented")
+}
+func (UnimplementedInstrumentServiceServer) GetPriceHistory(context.Context, *GetPriceHistoryRequest) (*GetPriceHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPriceHistory not implemented")
+}
+func (UnimplementedInstrumentServiceServer) ImportInstruments(InstrumentService_ImportInstrumentsServer) error {
+	return status.Errorf(codes.Unimplemented, "method ImportInstruments not implemented")
+}
+func (UnimplementedInstrumentServiceServer) ExportInstruments(*ExportInstrumentsRequest, InstrumentService_ExportInstrumentsServer) error {
+	return status.Errorf(codes.Unimplemented, "method ExportInstruments not implemented")
+}
+func (UnimplementedInstrumentServiceServer) CreateReview(context.Context, *CreateReviewRequest) (*Review, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateReview not implemented")
+}
+func (UnimplementedInstrumentServiceServer) GetReviews(context.Context, *GetReviewsRequest) (*GetReviewsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetReviews not implemented")
+}
+func (UnimplementedInstrumentServiceServer) ModerateReview(context.Context, *ModerateReviewRequest) (*Review, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ModerateReview not implemented")
+}
+func (UnimplementedInstrumentServiceServer) VoteReviewHelpful(context.Context, *VoteReviewHelpfulRequest) (*VoteReviewHelpfulResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VoteReviewHelpful not implemented")
+}
+func (UnimplementedInstrumentServiceServer) AddSerialUnit(context.Context, *AddSerialUnitRequest) (*SerialUnit, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddSerialUnit not implemented")
+}
+func (UnimplementedInstrumentServiceServer) UpdateSerialUnit(context.Context, *UpdateSerialUnitRequest) (*SerialUnit, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateSerialUnit not implemented")
+}
+func (UnimplementedInstrumentServiceServer) GetSerialUnit(context.Context, *GetSerialUnitRequest) (*SerialUnit, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSerialUnit not implemented")
+}
+func (UnimplementedInstrumentServiceServer) GetSerialUnits(context.Context, *GetSerialUnitsRequest) (*GetSerialUnitsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSerialUnits not implemented")
+}
+func (UnimplementedInstrumentServiceServer) AllocateSerialUnits(context.Context, *AllocateSerialUnitsRequest) (*AllocateSerialUnitsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AllocateSerialUnits not implemented")
+}
+func (UnimplementedInstrumentServiceServer) RegisterWarranty(context.Context, *RegisterWarrantyRequest) (*SerialUnit, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegisterWarranty not implemented")
+}
+func (UnimplementedInstrumentServiceServer) SubmitTradeIn(context.Context, *SubmitTradeInRequest) (*TradeIn, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubmitTradeIn not implemented")
+}
+func (UnimplementedInstrumentServiceServer) UploadTradeInPhoto(InstrumentService_UploadTradeInPhotoServer) error {
+	return status.Errorf(codes.Unimplemented, "method UploadTradeInPhoto not implemented")
+}
+func (UnimplementedInstrumentServiceServer) GetTradeIns(context.Context, *GetTradeInsRequest) (*GetTradeInsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTradeIns not implemented")
+}
+func (UnimplementedInstrumentServiceServer) AppraiseTradeIn(context.Context, *AppraiseTradeInRequest) (*TradeIn, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AppraiseTradeIn not implemented")
+}
+func (UnimplementedInstrumentServiceServer) RespondTradeInOffer(context.Context, *RespondTradeInOfferRequest) (*TradeIn, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RespondTradeInOffer not implemented")
+}
+func (UnimplementedInstrumentServiceServer) ReceiveTradeIn(context.Context, *ReceiveTradeInRequest) (*TradeIn, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReceiveTradeIn not implemented")
+}
+func (UnimplementedInstrumentServiceServer) mustEmbedUnimplementedInstrumentServiceServer() {}
+
+// UnsafeInstrumentServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to InstrumentServiceServer will
+// result in compilation errors.
+type UnsafeInstrumentServiceServer interface {
+	mustEmbedUnimplementedInstrumentServiceServer()
+}
+
+func RegisterInstrumentServiceServer(s grpc.ServiceRegistrar, srv InstrumentServiceServer) {
+	s.RegisterService(&InstrumentService_ServiceDesc, srv)
+}
+
+func _InstrumentService_CreateInstrument_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateInstrumentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InstrumentServiceServer).CreateInstrument(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/instruments.InstrumentService/CreateInstrument",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InstrumentServiceServer).CreateInstrument(ctx, req.(*CreateInstrumentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InstrumentService_GetAllInstruments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAllInstrumentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InstrumentServiceServer).GetAllInstruments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/instruments.InstrumentService/GetAllInstruments",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InstrumentServiceServer).GetAllInstruments(ctx, req.(*GetAllInstrumentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InstrumentService_GetInstrumentByID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetInstrumentByIDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InstrumentServiceServer).GetInstrumentByID(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/instruments.InstrumentService/GetInstrumentByID",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InstrumentServiceServer).GetInstrumentByID(ctx, req.(*GetInstrumentByIDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InstrumentService_DeleteInstrumentByID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteInstrumentByIDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InstrumentServiceServer).DeleteInstrumentByID(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/instruments.InstrumentService/DeleteInstrumentByID",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InstrumentServiceServer).DeleteInstrumentByID(ctx, req.(*DeleteInstrumentByIDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InstrumentService_RestoreInstrument_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreInstrumentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InstrumentServiceServer).RestoreInstrument(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/instruments.InstrumentService/RestoreInstrument",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InstrumentServiceServer).RestoreInstrument(ctx, req.(*RestoreInstrumentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InstrumentService_UpdateInstrumentByID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateInstrumentByIDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InstrumentServiceServer).UpdateInstrumentByID(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/instruments.InstrumentService/UpdateInstrumentByID",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InstrumentServiceServer).UpdateInstrumentByID(ctx, req.(*UpdateInstrumentByIDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InstrumentService_SearchInstruments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchInstrumentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InstrumentServiceServer).SearchInstruments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/instruments.InstrumentService/SearchInstruments",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InstrumentServiceServer).SearchInstruments(ctx, req.(*SearchInstrumentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InstrumentService_GetInstrumentBySKU_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetInstrumentBySKURequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InstrumentServiceServer).GetInstrumentBySKU(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/instruments.InstrumentService/GetInstrumentBySKU",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InstrumentServiceServer).GetInstrumentBySKU(ctx, req.(*GetInstrumentBySKURequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InstrumentService_ReserveStock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReserveStockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InstrumentServiceServer).ReserveStock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/instruments.InstrumentService/ReserveStock",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InstrumentServiceServer).ReserveStock(ctx, req.(*ReserveStockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InstrumentService_ReleaseStock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReleaseStockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InstrumentServiceServer).ReleaseStock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/instruments.InstrumentService/ReleaseStock",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InstrumentServiceServer).ReleaseStock(ctx, req.(*ReleaseStockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InstrumentService_UploadInstrumentMedia_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(InstrumentServiceServer).UploadInstrumentMedia(&instrumentServiceUploadInstrumentMediaServer{stream})
+}
+
+type InstrumentService_UploadInstrumentMediaServer interface {
+	SendAndClose(*Media) error
+	Recv() (*UploadInstrumentMediaRequest, error)
+	grpc.ServerStream
+}
+
+type instrumentServiceUploadInstrumentMediaServer struct {
+	grpc.ServerStream
+}
+
+func (x *instrumentServiceUploadInstrumentMediaServer) SendAndClose(m *Media) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *instrumentServiceUploadInstrumentMediaServer) Recv() (*UploadInstrumentMediaRequest, error) {
+	m := new(UploadInstrumentMediaRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _InstrumentService_DeleteInstrumentMedia_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteInstrumentMediaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InstrumentServiceServer).DeleteInstrumentMedia(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/instruments.InstrumentService/DeleteInstrumentMedia",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InstrumentServiceServer).DeleteInstrumentMedia(ctx, req.(*DeleteInstrumentMediaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InstrumentService_CreateCategory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateCategoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InstrumentServiceServer).CreateCategory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/instruments.InstrumentService/CreateCategory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InstrumentServiceServer).CreateCategory(ctx, req.(*CreateCategoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InstrumentService_GetCategories_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCategoriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InstrumentServiceServer).GetCategories(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/instruments.InstrumentService/GetCategories",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InstrumentServiceServer).GetCategories(ctx, req.(*GetCategoriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InstrumentService_CreateBrand_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateBrandRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InstrumentServiceServer).CreateBrand(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/instruments.InstrumentService/CreateBrand",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InstrumentServiceServer).CreateBrand(ctx, req.(*CreateBrandRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InstrumentService_GetBrands_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBrandsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InstrumentServiceServer).GetBrands(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/instruments.InstrumentService/GetBrands",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InstrumentServiceServer).GetBrands(ctx, req.(*GetBrandsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InstrumentService_SchedulePriceChange_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SchedulePriceChangeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InstrumentServiceServer).SchedulePriceChange(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/instruments.InstrumentService/SchedulePriceChange",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InstrumentServiceServer).SchedulePriceChange(ctx, req.(*SchedulePriceChangeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InstrumentService_CancelPriceSchedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelPriceScheduleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InstrumentServiceServer).CancelPriceSchedule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/instruments.InstrumentService/CancelPriceSchedule",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InstrumentServiceServer).CancelPriceSchedule(ctx, req.(*CancelPriceScheduleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InstrumentService_GetPriceHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPriceHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InstrumentServiceServer).GetPriceHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/instruments.InstrumentService/GetPriceHistory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InstrumentServiceServer).GetPriceHistory(ctx, req.(*GetPriceHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InstrumentService_ImportInstruments_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(InstrumentServiceServer).ImportInstruments(&instrumentServiceImportInstrumentsServer{stream})
+}
+
+type InstrumentService_ImportInstrumentsServer interface {
+	SendAndClose(*ImportInstrumentsResponse) error
+	Recv() (*ImportInstrumentRow, error)
+	grpc.ServerStream
+}
+
+type instrumentServiceImportInstrumentsServer struct {
+	grpc.ServerStream
+}
+
+func (x *instrumentServiceImportInstrumentsServer) SendAndClose(m *ImportInstrumentsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *instrumentServiceImportInstrumentsServer) Recv() (*ImportInstrumentRow, error) {
+	m := new(ImportInstrumentRow)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _InstrumentService_ExportInstruments_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportInstrumentsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(InstrumentServiceServer).ExportInstruments(m, &instrumentServiceExportInstrumentsServer{stream})
+}
+
+type InstrumentService_ExportInstrumentsServer interface {
+	Send(*Instrument) error
+	grpc.ServerStream
+}
+
+type instrumentServiceExportInstrumentsServer struct {
+	grpc.ServerStream
+}
+
+func (x *instrumentServiceExportInstrumentsServer) Send(m *Instrument) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _InstrumentService_CreateReview_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateReviewRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InstrumentServiceServer).CreateReview(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/instruments.InstrumentService/CreateReview",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InstrumentServiceServer).CreateReview(ctx, req.(*CreateReviewRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-// InstrumentServiceServer is the server API for InstrumentService service.
-// All implementations must embed UnimplementedInstrumentServiceServer
-// for forward compatibility
-type InstrumentServiceServer interface {
-	CreateInstrument(context.Context, *CreateInstrumentRequest) (*CreateInstrumentResponse, error)
-	GetAllInstruments(context.Context, *GetAllInstrumentsRequest) (*GetAllInstrumentsResponse, error)
-	GetInstrumentByID(context.Context, *GetInstrumentByIDRequest) (*Instrument, error)
-	DeleteInstrumentByID(context.Context, *DeleteInstrumentByIDRequest) (*DeleteInstrumentByIDResponse, error)
-	UpdateInstrumentByID(context.Context, *UpdateInstrumentByIDRequest) (*UpdateInstrumentByIDResponse, error)
-	mustEmbedUnimplementedInstrumentServiceServer()
+func _InstrumentService_GetReviews_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetReviewsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InstrumentServiceServer).GetReviews(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/instruments.InstrumentService/GetReviews",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InstrumentServiceServer).GetReviews(ctx, req.(*GetReviewsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-// UnimplementedInstrumentServiceServer must be embedded to have forward compatible implementations.
-type UnimplementedInstrumentServiceServer struct {
+func _InstrumentService_ModerateReview_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ModerateReviewRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InstrumentServiceServer).ModerateReview(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/instruments.InstrumentService/ModerateReview",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InstrumentServiceServer).ModerateReview(ctx, req.(*ModerateReviewRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (UnimplementedInstrumentServiceServer) CreateInstrument(context.Context, *CreateInstrumentRequest) (*CreateInstrumentResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method CreateInstrument not implemented")
+func _InstrumentService_VoteReviewHelpful_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VoteReviewHelpfulRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InstrumentServiceServer).VoteReviewHelpful(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/instruments.InstrumentService/VoteReviewHelpful",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InstrumentServiceServer).VoteReviewHelpful(ctx, req.(*VoteReviewHelpfulRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedInstrumentServiceServer) GetAllInstruments(context.Context, *GetAllInstrumentsRequest) (*GetAllInstrumentsResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetAllInstruments not implemented")
+
+func _InstrumentService_AddSerialUnit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddSerialUnitRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InstrumentServiceServer).AddSerialUnit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/instruments.InstrumentService/AddSerialUnit",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InstrumentServiceServer).AddSerialUnit(ctx, req.(*AddSerialUnitRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedInstrumentServiceServer) GetInstrumentByID(context.Context, *GetInstrumentByIDRequest) (*Instrument, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetInstrumentByID not implemented")
+
+func _InstrumentService_UpdateSerialUnit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateSerialUnitRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InstrumentServiceServer).UpdateSerialUnit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/instruments.InstrumentService/UpdateSerialUnit",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InstrumentServiceServer).UpdateSerialUnit(ctx, req.(*UpdateSerialUnitRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedInstrumentServiceServer) DeleteInstrumentByID(context.Context, *DeleteInstrumentByIDRequest) (*DeleteInstrumentByIDResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method DeleteInstrumentByID not implemented")
+
+func _InstrumentService_GetSerialUnit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSerialUnitRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InstrumentServiceServer).GetSerialUnit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/instruments.InstrumentService/GetSerialUnit",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InstrumentServiceServer).GetSerialUnit(ctx, req.(*GetSerialUnitRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedInstrumentServiceServer) UpdateInstrumentByID(context.Context, *UpdateInstrumentByIDRequest) (*UpdateInstrumentByIDResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method UpdateInstrumentByID not implemented")
+
+func _InstrumentService_GetSerialUnits_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSerialUnitsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InstrumentServiceServer).GetSerialUnits(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/instruments.InstrumentService/GetSerialUnits",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InstrumentServiceServer).GetSerialUnits(ctx, req.(*GetSerialUnitsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedInstrumentServiceServer) mustEmbedUnimplementedInstrumentServiceServer() {}
 
-// UnsafeInstrumentServiceServer may be embedded to opt out of forward compatibility for this service.
-// Use of this interface is not recommended, as added methods to InstrumentServiceServer will
-// result in compilation errors.
-type UnsafeInstrumentServiceServer interface {
-	mustEmbedUnimplementedInstrumentServiceServer()
+func _InstrumentService_AllocateSerialUnits_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AllocateSerialUnitsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InstrumentServiceServer).AllocateSerialUnits(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/instruments.InstrumentService/AllocateSerialUnits",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InstrumentServiceServer).AllocateSerialUnits(ctx, req.(*AllocateSerialUnitsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func RegisterInstrumentServiceServer(s grpc.ServiceRegistrar, srv InstrumentServiceServer) {
-	s.RegisterService(&InstrumentService_ServiceDesc, srv)
+func _InstrumentService_RegisterWarranty_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterWarrantyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InstrumentServiceServer).RegisterWarranty(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/instruments.InstrumentService/RegisterWarranty",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InstrumentServiceServer).RegisterWarranty(ctx, req.(*RegisterWarrantyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _InstrumentService_CreateInstrument_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(CreateInstrumentRequest)
+func _InstrumentService_SubmitTradeIn_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubmitTradeInRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(InstrumentServiceServer).CreateInstrument(ctx, in)
+		return srv.(InstrumentServiceServer).SubmitTradeIn(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/instruments.InstrumentService/CreateInstrument",
+		FullMethod: "/instruments.InstrumentService/SubmitTradeIn",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(InstrumentServiceServer).CreateInstrument(ctx, req.(*CreateInstrumentRequest))
+		return srv.(InstrumentServiceServer).SubmitTradeIn(ctx, req.(*SubmitTradeInRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _InstrumentService_GetAllInstruments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetAllInstrumentsRequest)
+func _InstrumentService_UploadTradeInPhoto_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(InstrumentServiceServer).UploadTradeInPhoto(&instrumentServiceUploadTradeInPhotoServer{stream})
+}
+
+type InstrumentService_UploadTradeInPhotoServer interface {
+	SendAndClose(*Media) error
+	Recv() (*UploadTradeInPhotoRequest, error)
+	grpc.ServerStream
+}
+
+type instrumentServiceUploadTradeInPhotoServer struct {
+	grpc.ServerStream
+}
+
+func (x *instrumentServiceUploadTradeInPhotoServer) SendAndClose(m *Media) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *instrumentServiceUploadTradeInPhotoServer) Recv() (*UploadTradeInPhotoRequest, error) {
+	m := new(UploadTradeInPhotoRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _InstrumentService_GetTradeIns_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTradeInsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(InstrumentServiceServer).GetAllInstruments(ctx, in)
+		return srv.(InstrumentServiceServer).GetTradeIns(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/instruments.InstrumentService/GetAllInstruments",
+		FullMethod: "/instruments.InstrumentService/GetTradeIns",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(InstrumentServiceServer).GetAllInstruments(ctx, req.(*GetAllInstrumentsRequest))
+		return srv.(InstrumentServiceServer).GetTradeIns(ctx, req.(*GetTradeInsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _InstrumentService_GetInstrumentByID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetInstrumentByIDRequest)
+func _InstrumentService_AppraiseTradeIn_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AppraiseTradeInRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(InstrumentServiceServer).GetInstrumentByID(ctx, in)
+		return srv.(InstrumentServiceServer).AppraiseTradeIn(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/instruments.InstrumentService/GetInstrumentByID",
+		FullMethod: "/instruments.InstrumentService/AppraiseTradeIn",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(InstrumentServiceServer).GetInstrumentByID(ctx, req.(*GetInstrumentByIDRequest))
+		return srv.(InstrumentServiceServer).AppraiseTradeIn(ctx, req.(*AppraiseTradeInRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _InstrumentService_DeleteInstrumentByID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(DeleteInstrumentByIDRequest)
+func _InstrumentService_RespondTradeInOffer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RespondTradeInOfferRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(InstrumentServiceServer).DeleteInstrumentByID(ctx, in)
+		return srv.(InstrumentServiceServer).RespondTradeInOffer(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/instruments.InstrumentService/DeleteInstrumentByID",
+		FullMethod: "/instruments.InstrumentService/RespondTradeInOffer",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(InstrumentServiceServer).DeleteInstrumentByID(ctx, req.(*DeleteInstrumentByIDRequest))
+		return srv.(InstrumentServiceServer).RespondTradeInOffer(ctx, req.(*RespondTradeInOfferRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _InstrumentService_UpdateInstrumentByID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(UpdateInstrumentByIDRequest)
+func _InstrumentService_ReceiveTradeIn_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReceiveTradeInRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(InstrumentServiceServer).UpdateInstrumentByID(ctx, in)
+		return srv.(InstrumentServiceServer).ReceiveTradeIn(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/instruments.InstrumentService/UpdateInstrumentByID",
+		FullMethod: "/instruments.InstrumentService/ReceiveTradeIn",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(InstrumentServiceServer).UpdateInstrumentByID(ctx, req.(*UpdateInstrumentByIDRequest))
+		return srv.(InstrumentServiceServer).ReceiveTradeIn(ctx, req.(*ReceiveTradeInRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -239,11 +1388,144 @@ var InstrumentService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteInstrumentByID",
 			Handler:    _InstrumentService_DeleteInstrumentByID_Handler,
 		},
+		{
+			MethodName: "RestoreInstrument",
+			Handler:    _InstrumentService_RestoreInstrument_Handler,
+		},
 		{
 			MethodName: "UpdateInstrumentByID",
 			Handler:    _InstrumentService_UpdateInstrumentByID_Handler,
 		},
+		{
+			MethodName: "SearchInstruments",
+			Handler:    _InstrumentService_SearchInstruments_Handler,
+		},
+		{
+			MethodName: "GetInstrumentBySKU",
+			Handler:    _InstrumentService_GetInstrumentBySKU_Handler,
+		},
+		{
+			MethodName: "ReserveStock",
+			Handler:    _InstrumentService_ReserveStock_Handler,
+		},
+		{
+			MethodName: "ReleaseStock",
+			Handler:    _InstrumentService_ReleaseStock_Handler,
+		},
+		{
+			MethodName: "DeleteInstrumentMedia",
+			Handler:    _InstrumentService_DeleteInstrumentMedia_Handler,
+		},
+		{
+			MethodName: "CreateCategory",
+			Handler:    _InstrumentService_CreateCategory_Handler,
+		},
+		{
+			MethodName: "GetCategories",
+			Handler:    _InstrumentService_GetCategories_Handler,
+		},
+		{
+			MethodName: "CreateBrand",
+			Handler:    _InstrumentService_CreateBrand_Handler,
+		},
+		{
+			MethodName: "GetBrands",
+			Handler:    _InstrumentService_GetBrands_Handler,
+		},
+		{
+			MethodName: "SchedulePriceChange",
+			Handler:    _InstrumentService_SchedulePriceChange_Handler,
+		},
+		{
+			MethodName: "CancelPriceSchedule",
+			Handler:    _InstrumentService_CancelPriceSchedule_Handler,
+		},
+		{
+			MethodName: "GetPriceHistory",
+			Handler:    _InstrumentService_GetPriceHistory_Handler,
+		},
+		{
+			MethodName: "CreateReview",
+			Handler:    _InstrumentService_CreateReview_Handler,
+		},
+		{
+			MethodName: "GetReviews",
+			Handler:    _InstrumentService_GetReviews_Handler,
+		},
+		{
+			MethodName: "ModerateReview",
+			Handler:    _InstrumentService_ModerateReview_Handler,
+		},
+		{
+			MethodName: "VoteReviewHelpful",
+			Handler:    _InstrumentService_VoteReviewHelpful_Handler,
+		},
+		{
+			MethodName: "AddSerialUnit",
+			Handler:    _InstrumentService_AddSerialUnit_Handler,
+		},
+		{
+			MethodName: "UpdateSerialUnit",
+			Handler:    _InstrumentService_UpdateSerialUnit_Handler,
+		},
+		{
+			MethodName: "GetSerialUnit",
+			Handler:    _InstrumentService_GetSerialUnit_Handler,
+		},
+		{
+			MethodName: "GetSerialUnits",
+			Handler:    _InstrumentService_GetSerialUnits_Handler,
+		},
+		{
+			MethodName: "AllocateSerialUnits",
+			Handler:    _InstrumentService_AllocateSerialUnits_Handler,
+		},
+		{
+			MethodName: "RegisterWarranty",
+			Handler:    _InstrumentService_RegisterWarranty_Handler,
+		},
+		{
+			MethodName: "SubmitTradeIn",
+			Handler:    _InstrumentService_SubmitTradeIn_Handler,
+		},
+		{
+			MethodName: "GetTradeIns",
+			Handler:    _InstrumentService_GetTradeIns_Handler,
+		},
+		{
+			MethodName: "AppraiseTradeIn",
+			Handler:    _InstrumentService_AppraiseTradeIn_Handler,
+		},
+		{
+			MethodName: "RespondTradeInOffer",
+			Handler:    _InstrumentService_RespondTradeInOffer_Handler,
+		},
+		{
+			MethodName: "ReceiveTradeIn",
+			Handler:    _InstrumentService_ReceiveTradeIn_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "UploadInstrumentMedia",
+			Handler:       _InstrumentService_UploadInstrumentMedia_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "ImportInstruments",
+			Handler:       _InstrumentService_ImportInstruments_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "ExportInstruments",
+			Handler:       _InstrumentService_ExportInstruments_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "UploadTradeInPhoto",
+			Handler:       _InstrumentService_UploadTradeInPhoto_Handler,
+			ClientStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/instruments.proto",
 }
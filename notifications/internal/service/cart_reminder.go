@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	cartproto "gotune/cart/proto"
+	usersproto "gotune/users/proto"
+)
+
+const (
+	reminderKeyPrefix = "cart_reminder:"
+	reminderKeyTTL    = 30 * 24 * time.Hour
+)
+
+type EmailSender interface {
+	SendEmail(to, subject, body string) error
+}
+
+// CartReminder отправляет письмо о брошенной корзине по событию
+// cart_abandoned. Пользователи, отказавшиеся от напоминаний, писем не
+// получают; повторная доставка события для той же ревизии корзины
+// отсекается ключом в Redis.
+type CartReminder struct {
+	userClient  usersproto.UserServiceClient
+	cartClient  cartproto.CartServiceClient
+	emailSender EmailSender
+	cache       *redis.Client
+}
+
+func NewCartReminder(
+	userClient usersproto.UserServiceClient,
+	cartClient cartproto.CartServiceClient,
+	emailSender EmailSender,
+	cache *redis.Client,
+) *CartReminder {
+	return &CartReminder{
+		userClient:  userClient,
+		cartClient:  cartClient,
+		emailSender: emailSender,
+		cache:       cache,
+	}
+}
+
+func (r *CartReminder) HandleCartAbandoned(payload map[string]string) error {
+	ctx := context.Background()
+
+	userID := payload["user_id"]
+	if userID == "" {
+		return nil
+	}
+
+	user, err := r.userClient.GetUser(ctx, &usersproto.GetUserRequest{UserId: userID})
+	if err != nil {
+		return err
+	}
+	if user.CartRemindersOptOut {
+		return nil
+	}
+
+	cart, err := r.cartClient.GetCart(ctx, &cartproto.GetCartRequest{UserId: userID})
+	if err != nil {
+		return err
+	}
+	body, ok := reminderBody(user.Username, cart)
+	if !ok {
+		return nil
+	}
+
+	key := reminderKeyPrefix + payload["cart_id"] + ":" + payload["revision"]
+	first, err := r.cache.SetNX(ctx, key, 1, reminderKeyTTL).Result()
+	if err != nil {
+		return err
+	}
+	if !first {
+		return nil
+	}
+
+	if err := r.emailSender.SendEmail(user.Email, "GoTune: вы забыли товары в корзине", body); err != nil {
+		// письмо не ушло — разрешаем повторную попытку
+		r.cache.Del(ctx, key)
+		return err
+	}
+	return nil
+}
+
+// reminderBody составляет текст письма. false означает, что в корзине
+// не осталось доступных товаров и напоминать не о чем.
+func reminderBody(username string, cart *cartproto.GetCartResponse) (string, bool) {
+	var lines []string
+	for _, item := range cart.Items {
+		if !item.Available {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("- %s × %d — %.2f", item.Name, item.Quantity, item.LineTotal))
+	}
+	if len(lines) == 0 {
+		return "", false
+	}
+
+	return fmt.Sprintf("Здравствуйте, %s!\n\nВ вашей корзине остались товары:\n%s\n\nИтого: %.2f\n\n"+
+		"Чтобы не получать такие письма, отключите напоминания о корзине в настройках профиля.",
+		username, strings.Join(lines, "\n"), cart.Subtotal), true
+}
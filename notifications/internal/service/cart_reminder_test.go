@@ -0,0 +1,31 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	cartproto "gotune/cart/proto"
+)
+
+func TestReminderBodyListsAvailableItems(t *testing.T) {
+	cart := &cartproto.GetCartResponse{
+		Items: []*cartproto.CartItem{
+			{Name: "Yamaha C40", Quantity: 1, LineTotal: 120, Available: true},
+			{InstrumentId: "deleted", Quantity: 2},
+		},
+		Subtotal: 120,
+	}
+
+	body, ok := reminderBody("berik", cart)
+	assert.True(t, ok)
+	assert.Contains(t, body, "Здравствуйте, berik!")
+	assert.Contains(t, body, "- Yamaha C40 × 1 — 120.00")
+	assert.NotContains(t, body, "deleted")
+	assert.Contains(t, body, "Итого: 120.00")
+}
+
+func TestReminderBodySkipsEmptyCart(t *testing.T) {
+	_, ok := reminderBody("berik", &cartproto.GetCartResponse{})
+	assert.False(t, ok)
+}
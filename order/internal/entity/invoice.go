@@ -0,0 +1,24 @@
+package entity
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"time"
+)
+
+type Invoice struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty"`
+	Number        string             `bson:"number"`
+	OrderID       primitive.ObjectID `bson:"order_id"`
+	UserID        primitive.ObjectID `bson:"user_id"`
+	CustomerName  string             `bson:"customer_name"`
+	CustomerEmail string             `bson:"customer_email"`
+	Items         []OrderItem        `bson:"items"`
+	Shipping      *ShippingCharge    `bson:"shipping,omitempty"`
+	Tax           *TaxBreakdown      `bson:"tax,omitempty"`
+	Discounts     []Discount         `bson:"discounts,omitempty"`
+	DiscountTotal float64            `bson:"discount_total,omitempty"`
+	Total         float64            `bson:"total"`
+	HTML          string             `bson:"html"`
+	PDF           []byte             `bson:"pdf"`
+	IssuedAt      time.Time          `bson:"issued_at"`
+}
@@ -5,14 +5,70 @@ import (
 	"time"
 )
 
+const (
+	OrderStatusCreated   = "created"
+	OrderStatusPaid      = "paid"
+	OrderStatusShipped   = "shipped"
+	OrderStatusDelivered = "delivered"
+)
+
 type OrderItem struct {
 	InstrumentID primitive.ObjectID `bson:"instrument_id"`
+	SKU          string             `bson:"sku,omitempty"`
+	Name         string             `bson:"name"`
+	UnitPrice    float64            `bson:"unit_price"`
 	Quantity     int32              `bson:"quantity"`
+	// Серийные номера экземпляров, выделенных при первой отгрузке
+	SerialNumbers []string `bson:"serial_numbers,omitempty"`
+}
+
+// ShippingAddress — копия адреса пользователя на момент заказа, чтобы
+// правки адресной книги не меняли адрес уже оформленных заказов.
+type ShippingAddress struct {
+	FullName   string `bson:"full_name"`
+	Phone      string `bson:"phone"`
+	Line1      string `bson:"line1"`
+	Line2      string `bson:"line2,omitempty"`
+	City       string `bson:"city"`
+	Region     string `bson:"region,omitempty"`
+	PostalCode string `bson:"postal_code"`
+	Country    string `bson:"country"`
+}
+
+type ShippingCharge struct {
+	Code string  `bson:"code"`
+	Name string  `bson:"name"`
+	Cost float64 `bson:"cost"`
+}
+
+// Discount — скидка по акции или купону, зафиксированная при оформлении.
+type Discount struct {
+	PromotionID  string  `bson:"promotion_id"`
+	Name         string  `bson:"name"`
+	Code         string  `bson:"code,omitempty"`
+	Type         string  `bson:"type"`
+	Amount       float64 `bson:"amount"`
+	FreeShipping bool    `bson:"free_shipping,omitempty"`
 }
 
 type Order struct {
-	ID        primitive.ObjectID `bson:"_id,omitempty"`
-	UserID    primitive.ObjectID `bson:"user_id"`
-	Items     []OrderItem        `bson:"items"`
-	CreatedAt time.Time          `bson:"created_at"`
+	ID              primitive.ObjectID `bson:"_id,omitempty"`
+	UserID          primitive.ObjectID `bson:"user_id"`
+	Items           []OrderItem        `bson:"items"`
+	Status          string             `bson:"status"`
+	Total           float64            `bson:"total"`
+	ShippingAddress ShippingAddress    `bson:"shipping_address"`
+	Shipments       []Shipment         `bson:"shipments,omitempty"`
+	Shipping        *ShippingCharge    `bson:"shipping,omitempty"`
+	Tax             *TaxBreakdown      `bson:"tax,omitempty"`
+	Discounts       []Discount         `bson:"discounts,omitempty"`
+	DiscountTotal   float64            `bson:"discount_total,omitempty"`
+	CreatedAt       time.Time          `bson:"created_at"`
+	PaidAt          *time.Time         `bson:"paid_at,omitempty"`
+	// Номер счёта закрепляется за заказом до формирования счёта, чтобы
+	// повторное оформление после сбоя не заняло новый номер
+	InvoiceNumber string `bson:"invoice_number,omitempty"`
+	// Время выделения экземпляров с серийными номерами; до первой
+	// отгрузки пусто
+	SerialsAllocatedAt *time.Time `bson:"serials_allocated_at,omitempty"`
 }
@@ -0,0 +1,21 @@
+package entity
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RelatedItem — инструмент, который покупали вместе с другим, и число
+// таких заказов.
+type RelatedItem struct {
+	InstrumentID primitive.ObjectID `bson:"instrument_id"`
+	Orders       int64              `bson:"orders"`
+}
+
+// Recommendations — лучшие RelatedItem инструмента по убыванию Orders.
+type Recommendations struct {
+	InstrumentID primitive.ObjectID `bson:"_id"`
+	Items        []RelatedItem      `bson:"items"`
+	UpdatedAt    time.Time          `bson:"updated_at"`
+}
@@ -0,0 +1,28 @@
+package entity
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"time"
+)
+
+const (
+	ShipmentStatusCreated   = "created"
+	ShipmentStatusInTransit = "in_transit"
+	ShipmentStatusDelivered = "delivered"
+)
+
+type TrackingEvent struct {
+	Status      string    `bson:"status"`
+	Location    string    `bson:"location,omitempty"`
+	Description string    `bson:"description,omitempty"`
+	OccurredAt  time.Time `bson:"occurred_at"`
+}
+
+type Shipment struct {
+	ID             primitive.ObjectID `bson:"_id"`
+	Carrier        string             `bson:"carrier"`
+	TrackingNumber string             `bson:"tracking_number"`
+	Status         string             `bson:"status"`
+	Events         []TrackingEvent    `bson:"events"`
+	CreatedAt      time.Time          `bson:"created_at"`
+}
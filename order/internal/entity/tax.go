@@ -0,0 +1,27 @@
+package entity
+
+type TaxLine struct {
+	InstrumentID string  `bson:"instrument_id,omitempty"`
+	Category     string  `bson:"category,omitempty"`
+	Rate         float64 `bson:"rate"`
+	Net          float64 `bson:"net"`
+	Tax          float64 `bson:"tax"`
+}
+
+type TaxRate struct {
+	Rate    float64 `bson:"rate"`
+	Taxable float64 `bson:"taxable"`
+	Tax     float64 `bson:"tax"`
+}
+
+type TaxBreakdown struct {
+	Country          string    `bson:"country"`
+	Region           string    `bson:"region,omitempty"`
+	PricesIncludeTax bool      `bson:"prices_include_tax"`
+	Rounding         string    `bson:"rounding"`
+	Lines            []TaxLine `bson:"lines"`
+	Rates            []TaxRate `bson:"rates"`
+	NetTotal         float64   `bson:"net_total"`
+	TotalTax         float64   `bson:"total_tax"`
+	GrossTotal       float64   `bson:"gross_total"`
+}
@@ -0,0 +1,185 @@
+package invoice
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"strconv"
+
+	"github.com/go-pdf/fpdf"
+
+	"gotune/order/internal/entity"
+)
+
+const dateLayout = "02.01.2006"
+
+// Generator рендерит счёт в HTML и PDF. Встроенные шрифты PDF поддерживают
+// только латиницу, поэтому для кириллицы нужно указать FontPath к TTF-шрифту.
+type Generator struct {
+	SellerName string
+	FontPath   string
+}
+
+func NewGenerator(sellerName, fontPath string) *Generator {
+	return &Generator{
+		SellerName: sellerName,
+		FontPath:   fontPath,
+	}
+}
+
+var htmlTemplate = template.Must(template.New("invoice").Funcs(template.FuncMap{
+	"money":   formatMoney,
+	"percent": formatPercent,
+	"lineTotal": func(item entity.OrderItem) string {
+		return formatMoney(item.UnitPrice * float64(item.Quantity))
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="ru">
+<head>
+<meta charset="utf-8">
+<title>Счёт {{.Invoice.Number}}</title>
+</head>
+<body>
+<h1>Счёт № {{.Invoice.Number}}</h1>
+<p>Продавец: {{.Seller}}</p>
+<p>Дата: {{.Date}}</p>
+<p>Покупатель: {{.Invoice.CustomerName}} &lt;{{.Invoice.CustomerEmail}}&gt;</p>
+<p>Заказ: {{.Invoice.OrderID.Hex}}</p>
+<table border="1" cellpadding="4" cellspacing="0">
+<tr><th>Наименование</th><th>Кол-во</th><th>Цена</th><th>Сумма</th></tr>
+{{- range .Invoice.Items}}
+<tr><td>{{.Name}}</td><td>{{.Quantity}}</td><td>{{money .UnitPrice}}</td><td>{{lineTotal .}}</td></tr>
+{{- end}}
+{{- with .Invoice.Shipping}}
+<tr><td>Доставка: {{.Name}}</td><td>1</td><td>{{money .Cost}}</td><td>{{money .Cost}}</td></tr>
+{{- end}}
+{{- range .Invoice.Discounts}}
+<tr><td>Скидка: {{.Name}}{{with .Code}} ({{.}}){{end}}</td><td></td><td></td><td>-{{money .Amount}}</td></tr>
+{{- end}}
+</table>
+{{- with .Invoice.Tax}}
+<table border="1" cellpadding="4" cellspacing="0">
+<tr><th>Ставка налога</th><th>Облагаемая сумма</th><th>Налог</th></tr>
+{{- range .Rates}}
+<tr><td>{{percent .Rate}}</td><td>{{money .Taxable}}</td><td>{{money .Tax}}</td></tr>
+{{- end}}
+</table>
+<p>Сумма без налога: {{money .NetTotal}}</p>
+<p>{{if .PricesIncludeTax}}В том числе налог{{else}}Налог{{end}}: {{money .TotalTax}}</p>
+{{- end}}
+<p><strong>Итого: {{money .Invoice.Total}}</strong></p>
+</body>
+</html>
+`))
+
+func (g *Generator) RenderHTML(inv *entity.Invoice) ([]byte, error) {
+	var buf bytes.Buffer
+	err := htmlTemplate.Execute(&buf, map[string]interface{}{
+		"Invoice": inv,
+		"Seller":  g.SellerName,
+		"Date":    inv.IssuedAt.Format(dateLayout),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (g *Generator) RenderPDF(inv *entity.Invoice) ([]byte, error) {
+	pdf := fpdf.New("P", "mm", "A4", "")
+
+	family := "Helvetica"
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	if g.FontPath != "" {
+		family = "InvoiceFont"
+		pdf.AddUTF8Font(family, "", g.FontPath)
+		tr = func(s string) string { return s }
+	}
+
+	pdf.AddPage()
+	pdf.SetFont(family, "", 16)
+	pdf.Cell(0, 10, tr("Invoice "+inv.Number))
+	pdf.Ln(12)
+
+	pdf.SetFont(family, "", 10)
+	lines := []string{
+		"Seller: " + g.SellerName,
+		"Date: " + inv.IssuedAt.Format(dateLayout),
+		fmt.Sprintf("Customer: %s <%s>", inv.CustomerName, inv.CustomerEmail),
+		"Order: " + inv.OrderID.Hex(),
+	}
+	for _, line := range lines {
+		pdf.Cell(0, 6, tr(line))
+		pdf.Ln(6)
+	}
+	pdf.Ln(4)
+
+	widths := []float64{95, 20, 35, 35}
+	for i, h := range []string{"Item", "Qty", "Price", "Amount"} {
+		pdf.CellFormat(widths[i], 7, h, "1", 0, "C", false, 0, "")
+	}
+	pdf.Ln(-1)
+	for _, item := range inv.Items {
+		pdf.CellFormat(widths[0], 7, tr(item.Name), "1", 0, "L", false, 0, "")
+		pdf.CellFormat(widths[1], 7, fmt.Sprintf("%d", item.Quantity), "1", 0, "R", false, 0, "")
+		pdf.CellFormat(widths[2], 7, formatMoney(item.UnitPrice), "1", 0, "R", false, 0, "")
+		pdf.CellFormat(widths[3], 7, formatMoney(item.UnitPrice*float64(item.Quantity)), "1", 0, "R", false, 0, "")
+		pdf.Ln(-1)
+	}
+	if inv.Shipping != nil {
+		pdf.CellFormat(widths[0], 7, tr("Shipping: "+inv.Shipping.Name), "1", 0, "L", false, 0, "")
+		pdf.CellFormat(widths[1], 7, "1", "1", 0, "R", false, 0, "")
+		pdf.CellFormat(widths[2], 7, formatMoney(inv.Shipping.Cost), "1", 0, "R", false, 0, "")
+		pdf.CellFormat(widths[3], 7, formatMoney(inv.Shipping.Cost), "1", 0, "R", false, 0, "")
+		pdf.Ln(-1)
+	}
+	for _, d := range inv.Discounts {
+		name := "Discount: " + d.Name
+		if d.Code != "" {
+			name += " (" + d.Code + ")"
+		}
+		pdf.CellFormat(widths[0], 7, tr(name), "1", 0, "L", false, 0, "")
+		pdf.CellFormat(widths[1], 7, "", "1", 0, "R", false, 0, "")
+		pdf.CellFormat(widths[2], 7, "", "1", 0, "R", false, 0, "")
+		pdf.CellFormat(widths[3], 7, "-"+formatMoney(d.Amount), "1", 0, "R", false, 0, "")
+		pdf.Ln(-1)
+	}
+
+	if inv.Tax != nil {
+		pdf.Ln(4)
+		for _, r := range inv.Tax.Rates {
+			pdf.Cell(0, 6, fmt.Sprintf("Tax %s on %s: %s", formatPercent(r.Rate), formatMoney(r.Taxable), formatMoney(r.Tax)))
+			pdf.Ln(6)
+		}
+		label := "Tax"
+		if inv.Tax.PricesIncludeTax {
+			label = "Tax included"
+		}
+		pdf.Cell(0, 6, "Net: "+formatMoney(inv.Tax.NetTotal))
+		pdf.Ln(6)
+		pdf.Cell(0, 6, label+": "+formatMoney(inv.Tax.TotalTax))
+		pdf.Ln(6)
+	}
+
+	pdf.Ln(4)
+	pdf.SetFont(family, "", 12)
+	pdf.Cell(0, 8, "Total: "+formatMoney(inv.Total))
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func FormatNumber(seq int64) string {
+	return fmt.Sprintf("INV-%06d", seq)
+}
+
+func formatMoney(v float64) string {
+	return fmt.Sprintf("%.2f", v)
+}
+
+func formatPercent(rate float64) string {
+	return strconv.FormatFloat(rate*100, 'f', -1, 64) + "%"
+}
@@ -0,0 +1,64 @@
+package invoice
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"gotune/order/internal/entity"
+)
+
+func testInvoice() *entity.Invoice {
+	return &entity.Invoice{
+		Number:        FormatNumber(42),
+		OrderID:       primitive.NewObjectID(),
+		CustomerName:  "berik",
+		CustomerEmail: "berik@example.com",
+		Items: []entity.OrderItem{
+			{Name: "Fender Stratocaster", UnitPrice: 1200, Quantity: 1},
+			{Name: "Strings <10-46>", UnitPrice: 9.5, Quantity: 2},
+		},
+		Tax: &entity.TaxBreakdown{
+			PricesIncludeTax: true,
+			Rates:            []entity.TaxRate{{Rate: 0.12, Taxable: 1219, Tax: 130.61}},
+			NetTotal:         1088.39,
+			TotalTax:         130.61,
+			GrossTotal:       1219,
+		},
+		Total:    1219,
+		IssuedAt: time.Date(2025, 5, 20, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestFormatNumber(t *testing.T) {
+	assert.Equal(t, "INV-000042", FormatNumber(42))
+}
+
+func TestRenderHTML(t *testing.T) {
+	html, err := NewGenerator("GoTune", "").RenderHTML(testInvoice())
+	assert.NoError(t, err)
+	assert.Contains(t, string(html), "INV-000042")
+	assert.Contains(t, string(html), "1219.00")
+	assert.Contains(t, string(html), "Strings &lt;10-46&gt;")
+	assert.Contains(t, string(html), "12%")
+	assert.Contains(t, string(html), "130.61")
+}
+
+func TestRenderPDF(t *testing.T) {
+	pdf, err := NewGenerator("GoTune", "").RenderPDF(testInvoice())
+	assert.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(pdf, []byte("%PDF")))
+}
+
+func TestRenderHTMLDiscounts(t *testing.T) {
+	inv := testInvoice()
+	inv.Discounts = []entity.Discount{{Name: "Black Friday", Code: "BF25", Amount: 300}}
+
+	html, err := NewGenerator("GoTune", "").RenderHTML(inv)
+	assert.NoError(t, err)
+	assert.Contains(t, string(html), "Скидка: Black Friday (BF25)")
+	assert.Contains(t, string(html), "-300.00")
+}
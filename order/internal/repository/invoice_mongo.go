@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"gotune/order/internal/entity"
+)
+
+const invoiceCounterID = "invoice_number"
+
+type InvoiceRepository interface {
+	NextNumber(ctx context.Context) (int64, error)
+	Create(ctx context.Context, invoice *entity.Invoice) (primitive.ObjectID, error)
+	FindByOrderID(ctx context.Context, orderID primitive.ObjectID) (*entity.Invoice, error)
+}
+
+type invoiceRepository struct {
+	collection *mongo.Collection
+	counters   *mongo.Collection
+}
+
+func NewInvoiceRepository(db *mongo.Database) InvoiceRepository {
+	return &invoiceRepository{
+		collection: db.Collection("invoices"),
+		counters:   db.Collection("counters"),
+	}
+}
+
+// NextNumber атомарно увеличивает счётчик счетов, поэтому номера не
+// повторяются даже при параллельных оплатах. Номер закрепляется за заказом
+// до формирования счёта и переиспользуется при повторе после сбоя; пропуск
+// возможен, только если один заказ оформляют два вызова одновременно.
+func (r *invoiceRepository) NextNumber(ctx context.Context) (int64, error) {
+	opts := options.FindOneAndUpdate().
+		SetUpsert(true).
+		SetReturnDocument(options.After)
+
+	var counter struct {
+		Seq int64 `bson:"seq"`
+	}
+	err := r.counters.FindOneAndUpdate(ctx,
+		bson.M{"_id": invoiceCounterID},
+		bson.M{"$inc": bson.M{"seq": 1}},
+		opts,
+	).Decode(&counter)
+	if err != nil {
+		return 0, err
+	}
+	return counter.Seq, nil
+}
+
+func (r *invoiceRepository) Create(ctx context.Context, invoice *entity.Invoice) (primitive.ObjectID, error) {
+	res, err := r.collection.InsertOne(ctx, invoice)
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+	return res.InsertedID.(primitive.ObjectID), nil
+}
+
+func (r *invoiceRepository) FindByOrderID(ctx context.Context, orderID primitive.ObjectID) (*entity.Invoice, error) {
+	var invoice entity.Invoice
+	err := r.collection.FindOne(ctx, bson.M{"order_id": orderID}).Decode(&invoice)
+	if err != nil {
+		return nil, err
+	}
+	return &invoice, nil
+}
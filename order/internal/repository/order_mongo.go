@@ -2,9 +2,12 @@ package repository
 
 import (
 	"context"
+	"time"
+
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 	"gotune/order/internal/entity"
 )
 
@@ -12,6 +15,17 @@ type OrderRepository interface {
 	Create(ctx context.Context, order *entity.Order) (primitive.ObjectID, error)
 	GetByUserID(ctx context.Context, userID primitive.ObjectID) ([]entity.Order, error)
 	Delete(ctx context.Context, orderID primitive.ObjectID, userID primitive.ObjectID) error
+	FindByID(ctx context.Context, orderID primitive.ObjectID, userID primitive.ObjectID) (*entity.Order, error)
+	FindByOrderID(ctx context.Context, orderID primitive.ObjectID) (*entity.Order, error)
+	MarkPaid(ctx context.Context, orderID primitive.ObjectID, paidAt time.Time) error
+	ReserveInvoiceNumber(ctx context.Context, orderID primitive.ObjectID, number string) (string, error)
+	UpdateStatus(ctx context.Context, orderID primitive.ObjectID, status string) error
+	AddShipment(ctx context.Context, orderID primitive.ObjectID, shipment *entity.Shipment) error
+	SetItemSerials(ctx context.Context, orderID primitive.ObjectID, items []entity.OrderItem, at time.Time) error
+	AddTrackingEvent(ctx context.Context, orderID, shipmentID primitive.ObjectID, event *entity.TrackingEvent) error
+	CountByUserID(ctx context.Context, userID primitive.ObjectID) (int64, error)
+	CountByInstrumentID(ctx context.Context, instrumentID primitive.ObjectID) (int64, error)
+	HasDelivered(ctx context.Context, userID, instrumentID primitive.ObjectID) (bool, error)
 }
 
 type orderRepository struct {
@@ -62,3 +76,139 @@ func (r *orderRepository) Delete(ctx context.Context, orderID primitive.ObjectID
 	_, err := r.collection.DeleteOne(ctx, filter)
 	return err
 }
+
+func (r *orderRepository) FindByID(ctx context.Context, orderID primitive.ObjectID, userID primitive.ObjectID) (*entity.Order, error) {
+	var order entity.Order
+	err := r.collection.FindOne(ctx, bson.M{"_id": orderID, "user_id": userID}).Decode(&order)
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// FindByOrderID ищет заказ без проверки владельца — для служебных операций
+// (доставка, трекинг), которые выполняет персонал магазина.
+func (r *orderRepository) FindByOrderID(ctx context.Context, orderID primitive.ObjectID) (*entity.Order, error) {
+	var order entity.Order
+	err := r.collection.FindOne(ctx, bson.M{"_id": orderID}).Decode(&order)
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// MarkPaid переводит заказ в статус "paid" только из статуса "created",
+// чтобы повторная оплата не выставила второй счёт.
+func (r *orderRepository) MarkPaid(ctx context.Context, orderID primitive.ObjectID, paidAt time.Time) error {
+	filter := bson.M{
+		"_id":    orderID,
+		"status": entity.OrderStatusCreated,
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"status":  entity.OrderStatusPaid,
+			"paid_at": paidAt,
+		},
+	}
+	res, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// ReserveInvoiceNumber закрепляет номер счёта за заказом, если номер ещё
+// не назначен, и возвращает номер заказа.
+func (r *orderRepository) ReserveInvoiceNumber(ctx context.Context, orderID primitive.ObjectID, number string) (string, error) {
+	var order entity.Order
+	err := r.collection.FindOneAndUpdate(ctx,
+		bson.M{"_id": orderID, "invoice_number": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"invoice_number": number}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&order)
+	if err == mongo.ErrNoDocuments {
+		err = r.collection.FindOne(ctx, bson.M{"_id": orderID}).Decode(&order)
+	}
+	if err != nil {
+		return "", err
+	}
+	return order.InvoiceNumber, nil
+}
+
+func (r *orderRepository) UpdateStatus(ctx context.Context, orderID primitive.ObjectID, status string) error {
+	_, err := r.collection.UpdateByID(ctx, orderID, bson.M{"$set": bson.M{"status": status}})
+	return err
+}
+
+func (r *orderRepository) AddShipment(ctx context.Context, orderID primitive.ObjectID, shipment *entity.Shipment) error {
+	update := bson.M{
+		"$push": bson.M{"shipments": shipment},
+		"$set":  bson.M{"status": entity.OrderStatusShipped},
+	}
+	res, err := r.collection.UpdateByID(ctx, orderID, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// SetItemSerials сохраняет позиции заказа с выделенными экземплярами.
+func (r *orderRepository) SetItemSerials(ctx context.Context, orderID primitive.ObjectID, items []entity.OrderItem, at time.Time) error {
+	update := bson.M{
+		"$set": bson.M{"items": items, "serials_allocated_at": at},
+	}
+	res, err := r.collection.UpdateByID(ctx, orderID, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+func (r *orderRepository) AddTrackingEvent(ctx context.Context, orderID, shipmentID primitive.ObjectID, event *entity.TrackingEvent) error {
+	// доставленное отправление не меняется
+	filter := bson.M{
+		"_id": orderID,
+		"shipments": bson.M{"$elemMatch": bson.M{
+			"_id":    shipmentID,
+			"status": bson.M{"$ne": entity.ShipmentStatusDelivered},
+		}},
+	}
+	update := bson.M{
+		"$push": bson.M{"shipments.$.events": event},
+		"$set":  bson.M{"shipments.$.status": event.Status},
+	}
+	res, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+func (r *orderRepository) CountByUserID(ctx context.Context, userID primitive.ObjectID) (int64, error) {
+	return r.collection.CountDocuments(ctx, bson.M{"user_id": userID})
+}
+
+func (r *orderRepository) CountByInstrumentID(ctx context.Context, instrumentID primitive.ObjectID) (int64, error) {
+	return r.collection.CountDocuments(ctx, bson.M{"items.instrument_id": instrumentID})
+}
+
+func (r *orderRepository) HasDelivered(ctx context.Context, userID, instrumentID primitive.ObjectID) (bool, error) {
+	n, err := r.collection.CountDocuments(ctx, bson.M{
+		"user_id":             userID,
+		"items.instrument_id": instrumentID,
+		"status":              entity.OrderStatusDelivered,
+	}, options.Count().SetLimit(1))
+	return n > 0, err
+}
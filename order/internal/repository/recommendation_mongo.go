@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"gotune/order/internal/entity"
+)
+
+// RecommendationRepository хранит, сколько раз пары инструментов
+// встречались в одном заказе (item_pairs), и лучшие пары для каждого
+// инструмента (recommendations).
+type RecommendationRepository interface {
+	Rebuild(ctx context.Context, topN int64) (int64, error)
+	AddOrder(ctx context.Context, instrumentIDs []primitive.ObjectID, topN int64) error
+	FindByInstrumentID(ctx context.Context, instrumentID primitive.ObjectID) (*entity.Recommendations, error)
+}
+
+type recommendationRepository struct {
+	orders          *mongo.Collection
+	pairs           *mongo.Collection
+	recommendations *mongo.Collection
+}
+
+func NewRecommendationRepository(db *mongo.Database) RecommendationRepository {
+	return &recommendationRepository{
+		orders:          db.Collection("orders"),
+		pairs:           db.Collection("item_pairs"),
+		recommendations: db.Collection("recommendations"),
+	}
+}
+
+// pairID — ключ пары; порядок полей важен, поэтому bson.D.
+func pairID(a, b primitive.ObjectID) bson.D {
+	return bson.D{{Key: "a", Value: a}, {Key: "b", Value: b}}
+}
+
+// Rebuild пересчитывает пары по всем заказам и заменяет ими накопленные
+// инкрементально, затем строит списки рекомендаций. Возвращает число
+// инструментов с рекомендациями.
+func (r *recommendationRepository) Rebuild(ctx context.Context, topN int64) (int64, error) {
+	started := time.Now()
+
+	cursor, err := r.orders.Aggregate(ctx, mongo.Pipeline{
+		// инструмент учитывается в заказе один раз, даже в нескольких вариантах
+		{{Key: "$project", Value: bson.M{"_id": 0, "ids": bson.M{"$setUnion": bson.A{"$items.instrument_id", bson.A{}}}}}},
+		{{Key: "$match", Value: bson.M{"ids.1": bson.M{"$exists": true}}}},
+		{{Key: "$project", Value: bson.M{"a": "$ids", "b": "$ids"}}},
+		{{Key: "$unwind", Value: "$a"}},
+		{{Key: "$unwind", Value: "$b"}},
+		{{Key: "$match", Value: bson.M{"$expr": bson.M{"$ne": bson.A{"$a", "$b"}}}}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: bson.D{{Key: "a", Value: "$a"}, {Key: "b", Value: "$b"}}},
+			{Key: "count", Value: bson.M{"$sum": 1}},
+		}}},
+		// $out заменяет коллекцию целиком и сохраняет её индексы
+		{{Key: "$out", Value: "item_pairs"}},
+	})
+	if err != nil {
+		return 0, err
+	}
+	cursor.Close(ctx)
+
+	cursor, err = r.pairs.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$sort", Value: bson.D{{Key: "_id.a", Value: 1}, {Key: "count", Value: -1}, {Key: "_id.b", Value: 1}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   "$_id.a",
+			"items": bson.M{"$push": bson.M{"instrument_id": "$_id.b", "orders": "$count"}},
+		}}},
+		{{Key: "$project", Value: bson.M{
+			"items":      bson.M{"$slice": bson.A{"$items", topN}},
+			"updated_at": started,
+		}}},
+		{{Key: "$merge", Value: bson.M{"into": "recommendations", "whenMatched": "replace", "whenNotMatched": "insert"}}},
+	})
+	if err != nil {
+		return 0, err
+	}
+	cursor.Close(ctx)
+
+	// инструменты, чьи заказы удалены, остаются без рекомендаций
+	if _, err := r.recommendations.DeleteMany(ctx, bson.M{"updated_at": bson.M{"$lt": started}}); err != nil {
+		return 0, err
+	}
+	return r.recommendations.CountDocuments(ctx, bson.M{})
+}
+
+// AddOrder учитывает инструменты нового заказа и обновляет их списки
+// рекомендаций.
+func (r *recommendationRepository) AddOrder(ctx context.Context, instrumentIDs []primitive.ObjectID, topN int64) error {
+	var models []mongo.WriteModel
+	for _, a := range instrumentIDs {
+		for _, b := range instrumentIDs {
+			if a == b {
+				continue
+			}
+			models = append(models, mongo.NewUpdateOneModel().
+				SetFilter(bson.M{"_id": pairID(a, b)}).
+				SetUpdate(bson.M{"$inc": bson.M{"count": 1}}).
+				SetUpsert(true))
+		}
+	}
+	if len(models) == 0 {
+		return nil
+	}
+	if _, err := r.pairs.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false)); err != nil {
+		return err
+	}
+
+	for _, id := range instrumentIDs {
+		if err := r.refresh(ctx, id, topN); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *recommendationRepository) refresh(ctx context.Context, instrumentID primitive.ObjectID, topN int64) error {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "count", Value: -1}, {Key: "_id.b", Value: 1}}).
+		SetLimit(topN)
+	cursor, err := r.pairs.Find(ctx, bson.M{"_id.a": instrumentID}, opts)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	var pairs []struct {
+		ID struct {
+			B primitive.ObjectID `bson:"b"`
+		} `bson:"_id"`
+		Count int64 `bson:"count"`
+	}
+	if err := cursor.All(ctx, &pairs); err != nil {
+		return err
+	}
+
+	items := make([]entity.RelatedItem, 0, len(pairs))
+	for _, p := range pairs {
+		items = append(items, entity.RelatedItem{InstrumentID: p.ID.B, Orders: p.Count})
+	}
+	_, err = r.recommendations.ReplaceOne(ctx,
+		bson.M{"_id": instrumentID},
+		&entity.Recommendations{InstrumentID: instrumentID, Items: items, UpdatedAt: time.Now()},
+		options.Replace().SetUpsert(true),
+	)
+	return err
+}
+
+func (r *recommendationRepository) FindByInstrumentID(ctx context.Context, instrumentID primitive.ObjectID) (*entity.Recommendations, error) {
+	var recs entity.Recommendations
+	err := r.recommendations.FindOne(ctx, bson.M{"_id": instrumentID}).Decode(&recs)
+	if err != nil {
+		return nil, err
+	}
+	return &recs, nil
+}
@@ -0,0 +1,37 @@
+package service
+
+import (
+	cartproto "gotune/cart/proto"
+	"gotune/order/internal/entity"
+	"gotune/order/proto"
+)
+
+func newDiscounts(r *cartproto.PromotionResult) []entity.Discount {
+	var result []entity.Discount
+	for _, d := range r.Discounts {
+		result = append(result, entity.Discount{
+			PromotionID:  d.PromotionId,
+			Name:         d.Name,
+			Code:         d.Code,
+			Type:         d.Type,
+			Amount:       d.Amount,
+			FreeShipping: d.FreeShipping,
+		})
+	}
+	return result
+}
+
+func discountsToProto(discounts []entity.Discount) []*proto.Discount {
+	var result []*proto.Discount
+	for _, d := range discounts {
+		result = append(result, &proto.Discount{
+			PromotionId:  d.PromotionID,
+			Name:         d.Name,
+			Code:         d.Code,
+			Type:         d.Type,
+			Amount:       d.Amount,
+			FreeShipping: d.FreeShipping,
+		})
+	}
+	return result
+}
@@ -3,15 +3,27 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"strings"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/redis/go-redis/v9"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
+	cartproto "gotune/cart/proto"
 	"gotune/events"
+	instrumentsproto "gotune/instruments/proto"
 	"gotune/order/internal/entity"
+	"gotune/order/internal/invoice"
 	"gotune/order/internal/repository"
+	"gotune/order/internal/shipping"
+	"gotune/order/internal/tax"
 	"gotune/order/metrics"
 	"gotune/order/proto"
 	usersproto "gotune/users/proto"
@@ -22,20 +34,53 @@ const (
 	orderCacheExpiration = 30 * time.Minute
 )
 
+type EmailSender interface {
+	SendEmailWithAttachment(to, subject, body, filename, contentType string, attachment []byte) error
+}
+
 type OrderService struct {
-	repo           repository.OrderRepository
-	userClient     usersproto.UserServiceClient
-	eventPublisher *events.EventPublisher
-	cache          *redis.Client
+	repo                repository.OrderRepository
+	invoiceRepo         repository.InvoiceRepository
+	recommendations     repository.RecommendationRepository
+	userClient          usersproto.UserServiceClient
+	instrumentClient    instrumentsproto.InstrumentServiceClient
+	cartClient          cartproto.CartServiceClient
+	eventPublisher      *events.EventPublisher
+	cache               *redis.Client
+	invoiceGenerator    *invoice.Generator
+	emailSender         EmailSender
+	shippingCalculators []shipping.ShippingRateCalculator
+	taxCalculator       *tax.Calculator
 	proto.UnimplementedOrderServiceServer
 }
 
-func NewOrderService(repo repository.OrderRepository, userClient usersproto.UserServiceClient, publisher *events.EventPublisher, cache *redis.Client) *OrderService {
+func NewOrderService(
+	repo repository.OrderRepository,
+	invoiceRepo repository.InvoiceRepository,
+	recommendations repository.RecommendationRepository,
+	userClient usersproto.UserServiceClient,
+	instrumentClient instrumentsproto.InstrumentServiceClient,
+	cartClient cartproto.CartServiceClient,
+	publisher *events.EventPublisher,
+	cache *redis.Client,
+	invoiceGenerator *invoice.Generator,
+	emailSender EmailSender,
+	shippingCalculators []shipping.ShippingRateCalculator,
+	taxCalculator *tax.Calculator,
+) *OrderService {
 	return &OrderService{
-		repo:           repo,
-		userClient:     userClient,
-		eventPublisher: publisher,
-		cache:          cache,
+		repo:                repo,
+		invoiceRepo:         invoiceRepo,
+		recommendations:     recommendations,
+		userClient:          userClient,
+		instrumentClient:    instrumentClient,
+		cartClient:          cartClient,
+		eventPublisher:      publisher,
+		cache:               cache,
+		invoiceGenerator:    invoiceGenerator,
+		emailSender:         emailSender,
+		shippingCalculators: shippingCalculators,
+		taxCalculator:       taxCalculator,
 	}
 }
 
@@ -51,23 +96,104 @@ func (s *OrderService) CreateOrder(ctx context.Context, req *proto.CreateOrderRe
 
 	userID, _ := primitive.ObjectIDFromHex(req.UserId)
 
+	shippingAddress, err := s.resolveShippingAddress(ctx, req.UserId, req.AddressId)
+	if err != nil {
+		return nil, err
+	}
+
+	instruments, err := s.lookupInstruments(ctx, req.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	// Фиксируем название и цену на момент заказа: последующие изменения
+	// каталога не должны влиять на уже оформленные заказы и счета.
 	var items []entity.OrderItem
-	for _, i := range req.Items {
+	var pricedLines []*cartproto.PricedLine
+	for idx, i := range req.Items {
 		instrumentID, _ := primitive.ObjectIDFromHex(i.InstrumentId)
+		inst := instruments[idx]
 		items = append(items, entity.OrderItem{
 			InstrumentID: instrumentID,
+			SKU:          i.Sku,
+			Name:         itemName(inst, i.Sku),
+			UnitPrice:    unitPrice(inst, i.Sku),
+			Quantity:     i.Quantity,
+		})
+		pricedLines = append(pricedLines, &cartproto.PricedLine{
+			InstrumentId: i.InstrumentId,
+			Sku:          i.Sku,
+			Category:     inst.Category,
+			CategoryPath: inst.CategoryPath,
+			UnitPrice:    unitPrice(inst, i.Sku),
 			Quantity:     i.Quantity,
 		})
 	}
 
+	var shippingCharge *entity.ShippingCharge
+	if req.ShippingOption != "" {
+		shippingReq := newShippingRequest(req.Items, instruments, shippingAddress.Country)
+		opt, err := shipping.Find(ctx, s.shippingCalculators, shippingReq, req.ShippingOption)
+		if err != nil {
+			return nil, status.Errorf(codes.FailedPrecondition, "способ доставки %q недоступен", req.ShippingOption)
+		}
+		shippingCharge = &entity.ShippingCharge{
+			Code: opt.Code,
+			Name: opt.Name,
+			Cost: opt.Cost,
+		}
+	}
+
+	// Остатки и использования акций списываются до сохранения заказа,
+	// поэтому идентификатор заказа генерируется заранее; если заказ не
+	// сохранится, списание откатывается.
+	stockItems := newStockItems(req.Items)
+	if _, err := s.instrumentClient.ReserveStock(ctx, &instrumentsproto.ReserveStockRequest{Items: stockItems}); err != nil {
+		return nil, err
+	}
+
+	orderID := primitive.NewObjectID()
+	promotions, err := s.cartClient.RedeemPromotions(ctx, &cartproto.RedeemPromotionsRequest{
+		UserId:     req.UserId,
+		OrderId:    orderID.Hex(),
+		CouponCode: req.CouponCode,
+		Lines:      pricedLines,
+	})
+	if err != nil {
+		s.releaseStock(orderID, stockItems)
+		return nil, err
+	}
+
+	var shippingCost float64
+	if shippingCharge != nil {
+		if promotions.FreeShipping {
+			shippingCharge.Cost = 0
+		}
+		shippingCost = shippingCharge.Cost
+	}
+	taxLines := discountedTaxLines(req.Items, instruments, promotions.LineDiscounts)
+	taxBreakdown := newTaxBreakdown(s.taxCalculator.Calculate(shippingAddress.Country, shippingAddress.Region, taxLines, shippingCost))
+
 	order := &entity.Order{
-		UserID:    userID,
-		Items:     items,
-		CreatedAt: time.Now(),
+		ID:              orderID,
+		UserID:          userID,
+		Items:           items,
+		Status:          entity.OrderStatusCreated,
+		Total:           taxBreakdown.GrossTotal,
+		ShippingAddress: *shippingAddress,
+		Shipping:        shippingCharge,
+		Tax:             taxBreakdown,
+		Discounts:       newDiscounts(promotions),
+		DiscountTotal:   promotions.DiscountTotal,
+		CreatedAt:       time.Now(),
 	}
 
 	id, err := s.repo.Create(ctx, order)
 	if err != nil {
+		if len(order.Discounts) > 0 {
+			s.releasePromotions(req.UserId, orderID)
+		}
+		s.releaseStock(orderID, stockItems)
 		return nil, err
 	}
 
@@ -85,6 +211,45 @@ func (s *OrderService) CreateOrder(ctx context.Context, req *proto.CreateOrderRe
 	}, nil
 }
 
+// releasePromotions возвращает использования акций несохранённого заказа.
+// Запрос клиента мог быть уже отменён, поэтому используется отдельный
+// контекст.
+func (s *OrderService) releasePromotions(userID string, orderID primitive.ObjectID) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := s.cartClient.ReleasePromotions(ctx, &cartproto.ReleasePromotionsRequest{
+		UserId:  userID,
+		OrderId: orderID.Hex(),
+	})
+	if err != nil {
+		log.Printf("Не удалось вернуть использования акций заказа %s: %v", orderID.Hex(), err)
+	}
+}
+
+// releaseStock возвращает остатки несохранённого заказа.
+func (s *OrderService) releaseStock(orderID primitive.ObjectID, items []*instrumentsproto.StockItem) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := s.instrumentClient.ReleaseStock(ctx, &instrumentsproto.ReleaseStockRequest{Items: items})
+	if err != nil {
+		log.Printf("Не удалось вернуть остатки заказа %s: %v", orderID.Hex(), err)
+	}
+}
+
+func newStockItems(items []*proto.OrderItem) []*instrumentsproto.StockItem {
+	stock := make([]*instrumentsproto.StockItem, 0, len(items))
+	for _, i := range items {
+		stock = append(stock, &instrumentsproto.StockItem{
+			InstrumentId: i.InstrumentId,
+			Sku:          i.Sku,
+			Quantity:     i.Quantity,
+		})
+	}
+	return stock
+}
+
 func (s *OrderService) GetOrders(ctx context.Context, req *proto.GetOrdersRequest) (*proto.GetOrdersResponse, error) {
 	_, err := s.userClient.GetUser(ctx, &usersproto.GetUserRequest{UserId: req.UserId})
 	if err != nil {
@@ -112,14 +277,26 @@ func (s *OrderService) GetOrders(ctx context.Context, req *proto.GetOrdersReques
 		var protoItems []*proto.OrderItem
 		for _, i := range o.Items {
 			protoItems = append(protoItems, &proto.OrderItem{
-				InstrumentId: i.InstrumentID.Hex(),
-				Quantity:     i.Quantity,
+				InstrumentId:  i.InstrumentID.Hex(),
+				Quantity:      i.Quantity,
+				Name:          i.Name,
+				UnitPrice:     i.UnitPrice,
+				Sku:           i.SKU,
+				SerialNumbers: i.SerialNumbers,
 			})
 		}
 		protoOrders = append(protoOrders, &proto.Order{
-			OrderId:   o.ID.Hex(),
-			Items:     protoItems,
-			CreatedAt: o.CreatedAt.Unix(),
+			OrderId:         o.ID.Hex(),
+			Items:           protoItems,
+			CreatedAt:       o.CreatedAt.Unix(),
+			Status:          o.Status,
+			Total:           o.Total,
+			ShippingAddress: shippingAddressToProto(o.ShippingAddress),
+			Shipments:       shipmentsToProto(o.Shipments),
+			Shipping:        shippingChargeToProto(o.Shipping),
+			Tax:             taxBreakdownToProto(o.Tax),
+			Discounts:       discountsToProto(o.Discounts),
+			DiscountTotal:   o.DiscountTotal,
 		})
 	}
 
@@ -151,6 +328,252 @@ func (s *OrderService) DeleteOrder(ctx context.Context, req *proto.DeleteOrderRe
 	return &proto.DeleteOrderResponse{Success: true}, nil
 }
 
+func (s *OrderService) PayOrder(ctx context.Context, req *proto.PayOrderRequest) (*proto.PayOrderResponse, error) {
+	user, err := s.userClient.GetUser(ctx, &usersproto.GetUserRequest{UserId: req.UserId})
+	if err != nil {
+		return nil, err
+	}
+
+	orderID, err := primitive.ObjectIDFromHex(req.OrderId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid order ID: %v", err)
+	}
+	userID, _ := primitive.ObjectIDFromHex(req.UserId)
+
+	order, err := s.repo.FindByID(ctx, orderID, userID)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "заказ не найден")
+	}
+	if order.PaidAt != nil {
+		// Оплата прошла, но счёт мог не сформироваться из-за сбоя —
+		// повторный вызов формирует его.
+		_, err := s.invoiceRepo.FindByOrderID(ctx, orderID)
+		if err == nil {
+			return nil, status.Errorf(codes.FailedPrecondition, "заказ уже оплачен")
+		}
+		if err != mongo.ErrNoDocuments {
+			return nil, err
+		}
+	} else {
+		if order.Status != entity.OrderStatusCreated {
+			return nil, status.Errorf(codes.FailedPrecondition, "заказ уже оплачен")
+		}
+		paidAt := time.Now()
+		if err := s.repo.MarkPaid(ctx, orderID, paidAt); err != nil {
+			if err == mongo.ErrNoDocuments {
+				return nil, status.Errorf(codes.FailedPrecondition, "заказ уже оплачен")
+			}
+			return nil, err
+		}
+		order.Status = entity.OrderStatusPaid
+		order.PaidAt = &paidAt
+	}
+
+	inv, err := s.issueInvoice(ctx, order, user)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "не удалось сформировать счёт: %v", err)
+	}
+
+	s.sendInvoiceEmail(inv)
+
+	_ = s.eventPublisher.Publish("order_paid", map[string]string{
+		"order_id":       req.OrderId,
+		"user_id":        req.UserId,
+		"invoice_number": inv.Number,
+	})
+
+	s.invalidateUserOrdersCache(ctx, req.UserId)
+
+	return &proto.PayOrderResponse{
+		Success:       true,
+		InvoiceNumber: inv.Number,
+	}, nil
+}
+
+func (s *OrderService) GetInvoice(ctx context.Context, req *proto.GetInvoiceRequest) (*proto.GetInvoiceResponse, error) {
+	orderID, err := primitive.ObjectIDFromHex(req.OrderId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid order ID: %v", err)
+	}
+	userID, err := primitive.ObjectIDFromHex(req.UserId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid user ID: %v", err)
+	}
+
+	if _, err := s.repo.FindByID(ctx, orderID, userID); err != nil {
+		return nil, status.Errorf(codes.NotFound, "заказ не найден")
+	}
+
+	inv, err := s.invoiceRepo.FindByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "счёт для заказа не найден")
+	}
+
+	switch req.Format {
+	case "", "html":
+		return &proto.GetInvoiceResponse{
+			InvoiceNumber: inv.Number,
+			ContentType:   "text/html; charset=utf-8",
+			Content:       []byte(inv.HTML),
+		}, nil
+	case "pdf":
+		return &proto.GetInvoiceResponse{
+			InvoiceNumber: inv.Number,
+			ContentType:   "application/pdf",
+			Content:       inv.PDF,
+		}, nil
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "неизвестный формат счёта: %s", req.Format)
+	}
+}
+
+// issueInvoice формирует счёт оплаченного заказа. Номер берётся из заказа,
+// если уже был закреплён прерванным вызовом; если счёт уже сохранён
+// параллельным вызовом, возвращается он.
+func (s *OrderService) issueInvoice(ctx context.Context, order *entity.Order, user *usersproto.GetUserResponse) (*entity.Invoice, error) {
+	number := order.InvoiceNumber
+	if number == "" {
+		seq, err := s.invoiceRepo.NextNumber(ctx)
+		if err != nil {
+			return nil, err
+		}
+		number, err = s.repo.ReserveInvoiceNumber(ctx, order.ID, invoice.FormatNumber(seq))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	inv := &entity.Invoice{
+		Number:        number,
+		OrderID:       order.ID,
+		UserID:        order.UserID,
+		CustomerName:  user.Username,
+		CustomerEmail: user.Email,
+		Items:         order.Items,
+		Shipping:      order.Shipping,
+		Tax:           order.Tax,
+		Discounts:     order.Discounts,
+		DiscountTotal: order.DiscountTotal,
+		Total:         order.Total,
+		IssuedAt:      *order.PaidAt,
+	}
+
+	html, err := s.invoiceGenerator.RenderHTML(inv)
+	if err != nil {
+		return nil, err
+	}
+	pdf, err := s.invoiceGenerator.RenderPDF(inv)
+	if err != nil {
+		return nil, err
+	}
+	inv.HTML = string(html)
+	inv.PDF = pdf
+
+	id, err := s.invoiceRepo.Create(ctx, inv)
+	if mongo.IsDuplicateKeyError(err) {
+		return s.invoiceRepo.FindByOrderID(ctx, order.ID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	inv.ID = id
+
+	return inv, nil
+}
+
+func (s *OrderService) sendInvoiceEmail(inv *entity.Invoice) {
+	subject := fmt.Sprintf("GoTune: счёт %s", inv.Number)
+	body := fmt.Sprintf("Здравствуйте, %s!\n\nСпасибо за оплату заказа %s. Счёт %s на сумму %.2f во вложении.",
+		inv.CustomerName, inv.OrderID.Hex(), inv.Number, inv.Total)
+	if err := s.emailSender.SendEmailWithAttachment(inv.CustomerEmail, subject, body, inv.Number+".pdf", "application/pdf", inv.PDF); err != nil {
+		log.Printf("Ошибка отправки счёта %s: %v", inv.Number, err)
+	}
+}
+
+// lookupInstruments загружает карточки инструментов в порядке позиций заказа.
+func (s *OrderService) lookupInstruments(ctx context.Context, items []*proto.OrderItem) ([]*instrumentsproto.Instrument, error) {
+	instruments := make([]*instrumentsproto.Instrument, 0, len(items))
+	for _, i := range items {
+		if i.Quantity <= 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "количество должно быть положительным")
+		}
+		inst, err := s.instrumentClient.GetInstrumentByID(ctx, &instrumentsproto.GetInstrumentByIDRequest{Id: i.InstrumentId})
+		if err != nil {
+			return nil, status.Errorf(codes.NotFound, "инструмент %s не найден", i.InstrumentId)
+		}
+		i.Sku = strings.ToUpper(strings.TrimSpace(i.Sku))
+		variant, err := resolveVariant(inst, i.Sku)
+		if err != nil {
+			return nil, err
+		}
+		stock := inst.Stock
+		if variant != nil {
+			stock = variant.Stock
+		}
+		if i.Quantity > stock {
+			return nil, status.Errorf(codes.FailedPrecondition, "недостаточно товара %s: доступно %d", itemName(inst, i.Sku), stock)
+		}
+		instruments = append(instruments, inst)
+	}
+	return instruments, nil
+}
+
+// discountedTaxLines строит строки для налога с учётом скидок: налог
+// считается с суммы, которую покупатель фактически платит. Скидка
+// позиции (инструмента или его варианта), встречающейся в заказе
+// несколько раз, распределяется по её строкам по порядку.
+func discountedTaxLines(items []*proto.OrderItem, instruments []*instrumentsproto.Instrument, lineDiscounts []*cartproto.LineDiscount) []tax.Line {
+	type lineKey struct{ instrumentID, sku string }
+	discounts := map[lineKey]float64{}
+	for _, d := range lineDiscounts {
+		discounts[lineKey{d.InstrumentId, d.Sku}] += d.Amount
+	}
+
+	var lines []tax.Line
+	for idx, i := range items {
+		inst := instruments[idx]
+		key := lineKey{i.InstrumentId, i.Sku}
+		amount := unitPrice(inst, i.Sku) * float64(i.Quantity)
+		discount := math.Min(discounts[key], amount)
+		discounts[key] -= discount
+		lines = append(lines, tax.Line{
+			InstrumentID: i.InstrumentId,
+			Category:     inst.TaxCategory,
+			Amount:       amount - discount,
+		})
+	}
+	return lines
+}
+
+// resolveShippingAddress берёт адрес из адресной книги пользователя:
+// указанный в запросе или адрес по умолчанию.
+func (s *OrderService) resolveShippingAddress(ctx context.Context, userID, addressID string) (*entity.ShippingAddress, error) {
+	resp, err := s.userClient.GetAddresses(ctx, &usersproto.GetAddressesRequest{UserId: userID})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, a := range resp.Addresses {
+		if (addressID != "" && a.Id == addressID) || (addressID == "" && a.IsDefault) {
+			return &entity.ShippingAddress{
+				FullName:   a.FullName,
+				Phone:      a.Phone,
+				Line1:      a.Line1,
+				Line2:      a.Line2,
+				City:       a.City,
+				Region:     a.Region,
+				PostalCode: a.PostalCode,
+				Country:    a.Country,
+			}, nil
+		}
+	}
+
+	if addressID != "" {
+		return nil, status.Errorf(codes.NotFound, "адрес доставки %s не найден", addressID)
+	}
+	return nil, status.Errorf(codes.FailedPrecondition, "не указан адрес доставки")
+}
+
 func (s *OrderService) invalidateUserOrdersCache(ctx context.Context, userId string) {
 	cacheKey := orderCacheKeyPrefix + userId
 	s.cache.Del(ctx, cacheKey)
@@ -1,9 +1,19 @@
 package service
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	cartproto "gotune/cart/proto"
+	instrumentsproto "gotune/instruments/proto"
+	"gotune/order/internal/entity"
+	"gotune/order/proto"
 )
 
 type DummyOrder struct {
@@ -23,3 +33,139 @@ func TestValidateDummyOrder(t *testing.T) {
 	assert.NotEmpty(t, order.UserID)
 	assert.True(t, order.Amount > 0)
 }
+
+func TestDiscountedTaxLinesByVariant(t *testing.T) {
+	strat := &instrumentsproto.Instrument{Id: "s", Name: "Fender Stratocaster", Price: 1000, Variants: []*instrumentsproto.Variant{
+		{Sku: "STRAT-SB", Options: map[string]string{"color": "sunburst", "handedness": "right"}},
+		{Sku: "STRAT-LH", Options: map[string]string{"color": "sunburst", "handedness": "left"}, Price: 1100},
+	}}
+	items := []*proto.OrderItem{
+		{InstrumentId: "s", Sku: "STRAT-SB", Quantity: 1},
+		{InstrumentId: "s", Sku: "STRAT-LH", Quantity: 1},
+	}
+
+	lines := discountedTaxLines(items, []*instrumentsproto.Instrument{strat, strat}, []*cartproto.LineDiscount{
+		{InstrumentId: "s", Sku: "STRAT-LH", Amount: 100},
+	})
+	assert.Equal(t, 1000.0, lines[0].Amount)
+	assert.Equal(t, 1000.0, lines[1].Amount)
+	assert.Equal(t, "Fender Stratocaster (color: sunburst, handedness: left)", itemName(strat, "STRAT-LH"))
+}
+
+// catalogStub отдаёт карточки инструментов из памяти.
+type catalogStub struct {
+	instrumentsproto.InstrumentServiceClient
+	instruments map[string]*instrumentsproto.Instrument
+}
+
+func (c *catalogStub) GetInstrumentByID(_ context.Context, req *instrumentsproto.GetInstrumentByIDRequest, _ ...grpc.CallOption) (*instrumentsproto.Instrument, error) {
+	if inst, ok := c.instruments[req.Id]; ok {
+		return inst, nil
+	}
+	return nil, status.Error(codes.NotFound, "not found")
+}
+
+func TestLookupInstrumentsChecksStock(t *testing.T) {
+	s := &OrderService{instrumentClient: &catalogStub{instruments: map[string]*instrumentsproto.Instrument{
+		"s": {Id: "s", Name: "Fender Stratocaster", Stock: 10, Variants: []*instrumentsproto.Variant{
+			{Sku: "STRAT-SB", Stock: 9},
+			{Sku: "STRAT-LH", Stock: 1},
+		}},
+		"p": {Id: "p", Name: "Yamaha P-45", Stock: 2},
+	}}}
+
+	_, err := s.lookupInstruments(context.Background(), []*proto.OrderItem{{InstrumentId: "s", Sku: "strat-lh", Quantity: 10}})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	_, err = s.lookupInstruments(context.Background(), []*proto.OrderItem{{InstrumentId: "p", Quantity: 3}})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	instruments, err := s.lookupInstruments(context.Background(), []*proto.OrderItem{
+		{InstrumentId: "s", Sku: "STRAT-LH", Quantity: 1},
+		{InstrumentId: "p", Quantity: 2},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, instruments, 2)
+}
+
+func TestCountOrderReferencesRequiresOneID(t *testing.T) {
+	s := &OrderService{}
+
+	_, err := s.CountOrderReferences(context.Background(), &proto.CountOrderReferencesRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = s.CountOrderReferences(context.Background(), &proto.CountOrderReferencesRequest{
+		UserId:       "64b7f0c2e1a4b2c3d4e5f601",
+		InstrumentId: "64b7f0c2e1a4b2c3d4e5f602",
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = s.CountOrderReferences(context.Background(), &proto.CountOrderReferencesRequest{InstrumentId: "bad"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestOrderInstrumentIDsDeduplicatesVariants(t *testing.T) {
+	guitar := primitive.NewObjectID()
+	capo := primitive.NewObjectID()
+	order := &entity.Order{Items: []entity.OrderItem{
+		{InstrumentID: guitar, SKU: "STRAT-BK"},
+		{InstrumentID: capo},
+		{InstrumentID: guitar, SKU: "STRAT-RD"},
+	}}
+
+	assert.Equal(t, []primitive.ObjectID{guitar, capo}, orderInstrumentIDs(order))
+}
+
+func TestSerialAllocationItems(t *testing.T) {
+	guitar := primitive.NewObjectID()
+	items := []entity.OrderItem{
+		{InstrumentID: guitar, SKU: "STRAT-BK", Quantity: 1},
+		{InstrumentID: guitar, SKU: "STRAT-BK", Quantity: 1},
+	}
+
+	req, err := serialAllocationItems(items, []*proto.ShipmentSerials{
+		{InstrumentId: guitar.Hex(), Sku: "STRAT-BK", SerialNumbers: []string{"US1"}},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, req, 1)
+	assert.Equal(t, int32(2), req[0].Quantity)
+	assert.Equal(t, []string{"US1"}, req[0].SerialNumbers)
+
+	_, err = serialAllocationItems(items, []*proto.ShipmentSerials{
+		{InstrumentId: guitar.Hex(), Sku: "STRAT-BK", SerialNumbers: []string{"US1", "US2", "US3"}},
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = serialAllocationItems(items, []*proto.ShipmentSerials{{InstrumentId: guitar.Hex()}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestAssignSerials(t *testing.T) {
+	guitar := primitive.NewObjectID()
+	capo := primitive.NewObjectID()
+	items := []entity.OrderItem{
+		{InstrumentID: guitar, Quantity: 1},
+		{InstrumentID: capo, Quantity: 3},
+		{InstrumentID: guitar, Quantity: 2},
+	}
+
+	result := assignSerials(items, []*instrumentsproto.SerialAllocation{
+		{InstrumentId: guitar.Hex(), Quantity: 3, SerialNumbers: []string{"US1", "US2", "US3"}},
+	})
+	assert.Equal(t, []string{"US1"}, result[0].SerialNumbers)
+	assert.Nil(t, result[1].SerialNumbers)
+	assert.Equal(t, []string{"US2", "US3"}, result[2].SerialNumbers)
+	assert.Nil(t, items[0].SerialNumbers)
+}
+
+func TestCheckTrackingTransition(t *testing.T) {
+	assert.NoError(t, checkTrackingTransition(entity.ShipmentStatusCreated, entity.ShipmentStatusInTransit))
+	assert.NoError(t, checkTrackingTransition(entity.ShipmentStatusInTransit, entity.ShipmentStatusInTransit))
+	assert.NoError(t, checkTrackingTransition(entity.ShipmentStatusInTransit, entity.ShipmentStatusDelivered))
+	assert.NoError(t, checkTrackingTransition(entity.ShipmentStatusCreated, entity.ShipmentStatusDelivered))
+
+	assert.Equal(t, codes.InvalidArgument, status.Code(checkTrackingTransition(entity.ShipmentStatusInTransit, "Delivered")))
+	assert.Equal(t, codes.InvalidArgument, status.Code(checkTrackingTransition(entity.ShipmentStatusInTransit, "")))
+	assert.Equal(t, codes.InvalidArgument, status.Code(checkTrackingTransition(entity.ShipmentStatusInTransit, entity.ShipmentStatusCreated)))
+	assert.Equal(t, codes.FailedPrecondition, status.Code(checkTrackingTransition(entity.ShipmentStatusDelivered, entity.ShipmentStatusDelivered)))
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	instrumentsproto "gotune/instruments/proto"
+	"gotune/order/internal/entity"
+	"gotune/order/internal/repository"
+	"gotune/order/proto"
+)
+
+// GetRecommendations возвращает инструменты, которые чаще всего покупали
+// вместе с запрошенным. Удалённые из каталога пропускаются.
+func (s *OrderService) GetRecommendations(ctx context.Context, req *proto.GetRecommendationsRequest) (*proto.GetRecommendationsResponse, error) {
+	instrumentID, err := primitive.ObjectIDFromHex(req.InstrumentId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "неверный instrument_id: %v", err)
+	}
+
+	resp := &proto.GetRecommendationsResponse{}
+	recs, err := s.recommendations.FindByInstrumentID(ctx, instrumentID)
+	if err == mongo.ErrNoDocuments {
+		return resp, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range recs.Items {
+		if req.Limit > 0 && len(resp.Recommendations) >= int(req.Limit) {
+			break
+		}
+		inst, err := s.instrumentClient.GetInstrumentByID(ctx, &instrumentsproto.GetInstrumentByIDRequest{Id: item.InstrumentID.Hex()})
+		if status.Code(err) == codes.NotFound {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		resp.Recommendations = append(resp.Recommendations, &proto.Recommendation{
+			InstrumentId: inst.Id,
+			Name:         inst.Name,
+			Price:        inst.Price,
+			Stock:        inst.Stock,
+			Orders:       item.Orders,
+		})
+	}
+	return resp, nil
+}
+
+// RecommendationJob периодически пересчитывает рекомендации «покупают
+// вместе» по всем заказам, а между пересчётами учитывает новые заказы
+// по событию order_created. Пересчёт заменяет инкрементальные счётчики,
+// поэтому расхождения из-за потерянных или повторных событий не копятся.
+type RecommendationJob struct {
+	orders   repository.OrderRepository
+	repo     repository.RecommendationRepository
+	topN     int64
+	interval time.Duration
+}
+
+func NewRecommendationJob(orders repository.OrderRepository, repo repository.RecommendationRepository, topN int64, interval time.Duration) *RecommendationJob {
+	return &RecommendationJob{
+		orders:   orders,
+		repo:     repo,
+		topN:     topN,
+		interval: interval,
+	}
+}
+
+func (j *RecommendationJob) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		if n, err := j.RunOnce(ctx); err != nil {
+			log.Printf("Ошибка пересчёта рекомендаций: %v", err)
+		} else {
+			log.Printf("Рекомендации пересчитаны, инструментов: %d", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (j *RecommendationJob) RunOnce(ctx context.Context) (int64, error) {
+	return j.repo.Rebuild(ctx, j.topN)
+}
+
+// HandleOrderCreated учитывает инструменты нового заказа.
+func (j *RecommendationJob) HandleOrderCreated(payload map[string]string) error {
+	orderID, err := primitive.ObjectIDFromHex(payload["order_id"])
+	if err != nil {
+		log.Printf("Некорректный order_id в событии order_created: %q", payload["order_id"])
+		return nil
+	}
+
+	ctx := context.Background()
+	order, err := j.orders.FindByOrderID(ctx, orderID)
+	if err == mongo.ErrNoDocuments {
+		// заказ уже удалён
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return j.repo.AddOrder(ctx, orderInstrumentIDs(order), j.topN)
+}
+
+// orderInstrumentIDs возвращает инструменты заказа без повторов: разные
+// варианты одного инструмента считаются одной покупкой.
+func orderInstrumentIDs(order *entity.Order) []primitive.ObjectID {
+	seen := map[primitive.ObjectID]bool{}
+	var ids []primitive.ObjectID
+	for _, item := range order.Items {
+		if !seen[item.InstrumentID] {
+			seen[item.InstrumentID] = true
+			ids = append(ids, item.InstrumentID)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"gotune/order/proto"
+)
+
+// CountOrderReferences считает заказы пользователя или заказы, в которых
+// есть инструмент. Вызывается задачами очистки сервисов пользователей и
+// каталога перед окончательным удалением записи.
+func (s *OrderService) CountOrderReferences(ctx context.Context, req *proto.CountOrderReferencesRequest) (*proto.CountOrderReferencesResponse, error) {
+	if (req.UserId == "") == (req.InstrumentId == "") {
+		return nil, status.Errorf(codes.InvalidArgument, "укажите user_id или instrument_id")
+	}
+
+	var orders int64
+	if req.UserId != "" {
+		userID, err := primitive.ObjectIDFromHex(req.UserId)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "неверный user_id: %v", err)
+		}
+		if orders, err = s.repo.CountByUserID(ctx, userID); err != nil {
+			return nil, err
+		}
+	} else {
+		instrumentID, err := primitive.ObjectIDFromHex(req.InstrumentId)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "неверный instrument_id: %v", err)
+		}
+		if orders, err = s.repo.CountByInstrumentID(ctx, instrumentID); err != nil {
+			return nil, err
+		}
+	}
+
+	return &proto.CountOrderReferencesResponse{Orders: orders}, nil
+}
+
+func (s *OrderService) HasDeliveredOrder(ctx context.Context, req *proto.HasDeliveredOrderRequest) (*proto.HasDeliveredOrderResponse, error) {
+	userID, err := primitive.ObjectIDFromHex(req.UserId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "неверный user_id: %v", err)
+	}
+	instrumentID, err := primitive.ObjectIDFromHex(req.InstrumentId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "неверный instrument_id: %v", err)
+	}
+
+	delivered, err := s.repo.HasDelivered(ctx, userID, instrumentID)
+	if err != nil {
+		return nil, err
+	}
+	return &proto.HasDeliveredOrderResponse{Delivered: delivered}, nil
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	instrumentsproto "gotune/instruments/proto"
+	"gotune/order/internal/entity"
+	"gotune/order/proto"
+)
+
+// allocateSerials выделяет заказу экземпляры с серийными номерами при
+// первой отгрузке. Инструменты без учёта экземпляров сервис
+// инструментов пропускает.
+func (s *OrderService) allocateSerials(ctx context.Context, order *entity.Order, requested []*proto.ShipmentSerials) error {
+	items, err := serialAllocationItems(order.Items, requested)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.instrumentClient.AllocateSerialUnits(ctx, &instrumentsproto.AllocateSerialUnitsRequest{
+		OrderId: order.ID.Hex(),
+		UserId:  order.UserID.Hex(),
+		Items:   items,
+	})
+	if err != nil {
+		return err
+	}
+
+	order.Items = assignSerials(order.Items, resp.Items)
+	return s.repo.SetItemSerials(ctx, order.ID, order.Items, time.Now())
+}
+
+// serialAllocationItems собирает запрос на выделение: позиции заказа с
+// серийными номерами, которые указал сотрудник склада.
+func serialAllocationItems(items []entity.OrderItem, requested []*proto.ShipmentSerials) ([]*instrumentsproto.SerialAllocation, error) {
+	var result []*instrumentsproto.SerialAllocation
+	byKey := map[string]*instrumentsproto.SerialAllocation{}
+	for _, item := range items {
+		key := item.InstrumentID.Hex() + "/" + item.SKU
+		if a, ok := byKey[key]; ok {
+			a.Quantity += item.Quantity
+			continue
+		}
+		a := &instrumentsproto.SerialAllocation{
+			InstrumentId: item.InstrumentID.Hex(),
+			Sku:          item.SKU,
+			Quantity:     item.Quantity,
+		}
+		byKey[key] = a
+		result = append(result, a)
+	}
+
+	for _, r := range requested {
+		a, ok := byKey[r.InstrumentId+"/"+r.Sku]
+		if !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "инструмента %s нет в заказе", r.InstrumentId)
+		}
+		a.SerialNumbers = append(a.SerialNumbers, r.SerialNumbers...)
+		if len(a.SerialNumbers) > int(a.Quantity) {
+			return nil, status.Errorf(codes.InvalidArgument, "экземпляров %s указано больше, чем в заказе", r.InstrumentId)
+		}
+	}
+	return result, nil
+}
+
+// assignSerials раскладывает выделенные экземпляры по позициям заказа, не
+// больше количества в позиции.
+func assignSerials(items []entity.OrderItem, allocations []*instrumentsproto.SerialAllocation) []entity.OrderItem {
+	pool := map[string][]string{}
+	for _, a := range allocations {
+		key := a.InstrumentId + "/" + a.Sku
+		pool[key] = append(pool[key], a.SerialNumbers...)
+	}
+
+	result := make([]entity.OrderItem, len(items))
+	for i, item := range items {
+		key := item.InstrumentID.Hex() + "/" + item.SKU
+		if n := min(int(item.Quantity), len(pool[key])); n > 0 {
+			item.SerialNumbers = pool[key][:n:n]
+			pool[key] = pool[key][n:]
+		}
+		result[i] = item
+	}
+	return result
+}
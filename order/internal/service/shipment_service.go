@@ -0,0 +1,232 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"gotune/order/internal/entity"
+	"gotune/order/proto"
+)
+
+func (s *OrderService) CreateShipment(ctx context.Context, req *proto.CreateShipmentRequest) (*proto.CreateShipmentResponse, error) {
+	if req.Carrier == "" || req.TrackingNumber == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "carrier и tracking_number обязательны")
+	}
+
+	orderID, err := primitive.ObjectIDFromHex(req.OrderId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid order ID: %v", err)
+	}
+
+	order, err := s.repo.FindByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "заказ не найден")
+	}
+	if order.Status != entity.OrderStatusPaid && order.Status != entity.OrderStatusShipped {
+		return nil, status.Errorf(codes.FailedPrecondition, "заказ в статусе %q нельзя отправить", order.Status)
+	}
+
+	if order.SerialsAllocatedAt == nil {
+		if err := s.allocateSerials(ctx, order, req.Serials); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	shipment := &entity.Shipment{
+		ID:             primitive.NewObjectID(),
+		Carrier:        req.Carrier,
+		TrackingNumber: req.TrackingNumber,
+		Status:         entity.ShipmentStatusCreated,
+		Events: []entity.TrackingEvent{
+			{Status: entity.ShipmentStatusCreated, OccurredAt: now},
+		},
+		CreatedAt: now,
+	}
+
+	if err := s.repo.AddShipment(ctx, orderID, shipment); err != nil {
+		return nil, err
+	}
+
+	s.invalidateUserOrdersCache(ctx, order.UserID.Hex())
+
+	_ = s.eventPublisher.Publish("shipment_created", map[string]string{
+		"order_id":        req.OrderId,
+		"user_id":         order.UserID.Hex(),
+		"shipment_id":     shipment.ID.Hex(),
+		"carrier":         shipment.Carrier,
+		"tracking_number": shipment.TrackingNumber,
+	})
+
+	return &proto.CreateShipmentResponse{ShipmentId: shipment.ID.Hex()}, nil
+}
+
+func (s *OrderService) AddTrackingEvent(ctx context.Context, req *proto.AddTrackingEventRequest) (*proto.AddTrackingEventResponse, error) {
+	if req.Event == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "статус события обязателен")
+	}
+
+	orderID, err := primitive.ObjectIDFromHex(req.OrderId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid order ID: %v", err)
+	}
+	shipmentID, err := primitive.ObjectIDFromHex(req.ShipmentId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid shipment ID: %v", err)
+	}
+
+	occurredAt := time.Now()
+	if req.Event.OccurredAt > 0 {
+		occurredAt = time.Unix(req.Event.OccurredAt, 0)
+	}
+
+	order, err := s.repo.FindByOrderID(ctx, orderID)
+	if err == mongo.ErrNoDocuments {
+		return nil, status.Errorf(codes.NotFound, "отправление не найдено")
+	}
+	if err != nil {
+		return nil, err
+	}
+	shipment := findShipment(order.Shipments, shipmentID)
+	if shipment == nil {
+		return nil, status.Errorf(codes.NotFound, "отправление не найдено")
+	}
+	if err := checkTrackingTransition(shipment.Status, req.Event.Status); err != nil {
+		return nil, err
+	}
+
+	event := &entity.TrackingEvent{
+		Status:      req.Event.Status,
+		Location:    req.Event.Location,
+		Description: req.Event.Description,
+		OccurredAt:  occurredAt,
+	}
+
+	if err := s.repo.AddTrackingEvent(ctx, orderID, shipmentID, event); err != nil {
+		if err == mongo.ErrNoDocuments {
+			// отправление доставлено параллельным событием
+			return nil, status.Errorf(codes.FailedPrecondition, "отправление уже доставлено")
+		}
+		return nil, err
+	}
+	shipment.Status = event.Status
+
+	// Заказ доставлен, когда доставлены все его отправления
+	if allShipmentsDelivered(order.Shipments) && order.Status != entity.OrderStatusDelivered {
+		if err := s.repo.UpdateStatus(ctx, orderID, entity.OrderStatusDelivered); err != nil {
+			return nil, err
+		}
+	}
+
+	s.invalidateUserOrdersCache(ctx, order.UserID.Hex())
+
+	_ = s.eventPublisher.Publish("shipment_updated", map[string]string{
+		"order_id":    req.OrderId,
+		"user_id":     order.UserID.Hex(),
+		"shipment_id": req.ShipmentId,
+		"status":      event.Status,
+	})
+
+	return &proto.AddTrackingEventResponse{Success: true}, nil
+}
+
+func (s *OrderService) GetTracking(ctx context.Context, req *proto.GetTrackingRequest) (*proto.GetTrackingResponse, error) {
+	orderID, err := primitive.ObjectIDFromHex(req.OrderId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid order ID: %v", err)
+	}
+	userID, err := primitive.ObjectIDFromHex(req.UserId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid user ID: %v", err)
+	}
+
+	order, err := s.repo.FindByID(ctx, orderID, userID)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "заказ не найден")
+	}
+
+	return &proto.GetTrackingResponse{
+		OrderId:         order.ID.Hex(),
+		Status:          order.Status,
+		ShippingAddress: shippingAddressToProto(order.ShippingAddress),
+		Shipments:       shipmentsToProto(order.Shipments),
+	}, nil
+}
+
+// checkTrackingTransition — событие перевозчика переводит отправление
+// в пути или доставляет его; после доставки событий не принимается.
+func checkTrackingTransition(from, to string) error {
+	switch to {
+	case entity.ShipmentStatusInTransit, entity.ShipmentStatusDelivered:
+	default:
+		return status.Errorf(codes.InvalidArgument, "неизвестный статус отправления %q, допустимы %s и %s",
+			to, entity.ShipmentStatusInTransit, entity.ShipmentStatusDelivered)
+	}
+	if from == entity.ShipmentStatusDelivered {
+		return status.Errorf(codes.FailedPrecondition, "отправление уже доставлено")
+	}
+	return nil
+}
+
+func findShipment(shipments []entity.Shipment, id primitive.ObjectID) *entity.Shipment {
+	for i := range shipments {
+		if shipments[i].ID == id {
+			return &shipments[i]
+		}
+	}
+	return nil
+}
+
+func allShipmentsDelivered(shipments []entity.Shipment) bool {
+	if len(shipments) == 0 {
+		return false
+	}
+	for _, sh := range shipments {
+		if sh.Status != entity.ShipmentStatusDelivered {
+			return false
+		}
+	}
+	return true
+}
+
+func shippingAddressToProto(a entity.ShippingAddress) *proto.ShippingAddress {
+	return &proto.ShippingAddress{
+		FullName:   a.FullName,
+		Phone:      a.Phone,
+		Line1:      a.Line1,
+		Line2:      a.Line2,
+		City:       a.City,
+		Region:     a.Region,
+		PostalCode: a.PostalCode,
+		Country:    a.Country,
+	}
+}
+
+func shipmentsToProto(shipments []entity.Shipment) []*proto.Shipment {
+	var result []*proto.Shipment
+	for _, sh := range shipments {
+		var events []*proto.TrackingEvent
+		for _, e := range sh.Events {
+			events = append(events, &proto.TrackingEvent{
+				Status:      e.Status,
+				Location:    e.Location,
+				Description: e.Description,
+				OccurredAt:  e.OccurredAt.Unix(),
+			})
+		}
+		result = append(result, &proto.Shipment{
+			ShipmentId:     sh.ID.Hex(),
+			Carrier:        sh.Carrier,
+			TrackingNumber: sh.TrackingNumber,
+			Status:         sh.Status,
+			Events:         events,
+			CreatedAt:      sh.CreatedAt.Unix(),
+		})
+	}
+	return result
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	instrumentsproto "gotune/instruments/proto"
+	"gotune/order/internal/entity"
+	"gotune/order/internal/shipping"
+	"gotune/order/proto"
+)
+
+func (s *OrderService) QuoteShipping(ctx context.Context, req *proto.QuoteShippingRequest) (*proto.QuoteShippingResponse, error) {
+	if len(req.Items) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "корзина пуста")
+	}
+
+	var country string
+	switch {
+	case req.Address != nil:
+		country = req.Address.Country
+	case req.UserId != "":
+		address, err := s.resolveShippingAddress(ctx, req.UserId, req.AddressId)
+		if err != nil {
+			return nil, err
+		}
+		country = address.Country
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "не указан адрес доставки")
+	}
+
+	instruments, err := s.lookupInstruments(ctx, req.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	options, err := shipping.Quote(ctx, s.shippingCalculators, newShippingRequest(req.Items, instruments, country))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "ошибка расчёта доставки: %v", err)
+	}
+
+	var protoOptions []*proto.ShippingOption
+	for _, opt := range options {
+		protoOptions = append(protoOptions, &proto.ShippingOption{
+			Code:          opt.Code,
+			Name:          opt.Name,
+			Cost:          opt.Cost,
+			EstimatedDays: opt.EstimatedDays,
+		})
+	}
+
+	return &proto.QuoteShippingResponse{Options: protoOptions}, nil
+}
+
+func newShippingRequest(items []*proto.OrderItem, instruments []*instrumentsproto.Instrument, country string) *shipping.Request {
+	req := &shipping.Request{Country: country}
+	for idx, i := range items {
+		inst := instruments[idx]
+		req.Items = append(req.Items, shipping.Item{
+			InstrumentID: i.InstrumentId,
+			Quantity:     i.Quantity,
+			UnitPrice:    unitPrice(inst, i.Sku),
+			WeightKg:     inst.WeightKg,
+			LengthCm:     inst.LengthCm,
+			WidthCm:      inst.WidthCm,
+			HeightCm:     inst.HeightCm,
+		})
+	}
+	return req
+}
+
+func shippingChargeToProto(c *entity.ShippingCharge) *proto.ShippingOption {
+	if c == nil {
+		return nil
+	}
+	return &proto.ShippingOption{
+		Code: c.Code,
+		Name: c.Name,
+		Cost: c.Cost,
+	}
+}
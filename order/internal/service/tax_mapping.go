@@ -0,0 +1,66 @@
+package service
+
+import (
+	"gotune/order/internal/entity"
+	"gotune/order/internal/tax"
+	"gotune/order/proto"
+)
+
+func newTaxBreakdown(b *tax.Breakdown) *entity.TaxBreakdown {
+	result := &entity.TaxBreakdown{
+		Country:          b.Country,
+		Region:           b.Region,
+		PricesIncludeTax: b.PricesIncludeTax,
+		Rounding:         b.Rounding,
+		NetTotal:         b.NetTotal,
+		TotalTax:         b.TotalTax,
+		GrossTotal:       b.GrossTotal,
+	}
+	for _, l := range b.Lines {
+		result.Lines = append(result.Lines, entity.TaxLine{
+			InstrumentID: l.InstrumentID,
+			Category:     l.Category,
+			Rate:         l.Rate,
+			Net:          l.Net,
+			Tax:          l.Tax,
+		})
+	}
+	for _, r := range b.Rates {
+		result.Rates = append(result.Rates, entity.TaxRate{
+			Rate:    r.Rate,
+			Taxable: r.Taxable,
+			Tax:     r.Tax,
+		})
+	}
+	return result
+}
+
+func taxBreakdownToProto(b *entity.TaxBreakdown) *proto.TaxBreakdown {
+	if b == nil {
+		return nil
+	}
+	result := &proto.TaxBreakdown{
+		PricesIncludeTax: b.PricesIncludeTax,
+		Rounding:         b.Rounding,
+		NetTotal:         b.NetTotal,
+		TotalTax:         b.TotalTax,
+		GrossTotal:       b.GrossTotal,
+	}
+	for _, l := range b.Lines {
+		result.Lines = append(result.Lines, &proto.TaxLine{
+			InstrumentId: l.InstrumentID,
+			Category:     l.Category,
+			Rate:         l.Rate,
+			Net:          l.Net,
+			Tax:          l.Tax,
+		})
+	}
+	for _, r := range b.Rates {
+		result.Rates = append(result.Rates, &proto.TaxRate{
+			Rate:    r.Rate,
+			Taxable: r.Taxable,
+			Tax:     r.Tax,
+		})
+	}
+	return result
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"sort"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	instrumentsproto "gotune/instruments/proto"
+)
+
+// resolveVariant находит выбранный вариант инструмента. Для инструмента
+// без вариантов возвращает nil, а SKU указывать нельзя.
+func resolveVariant(inst *instrumentsproto.Instrument, sku string) (*instrumentsproto.Variant, error) {
+	if len(inst.Variants) == 0 {
+		if sku != "" {
+			return nil, status.Errorf(codes.InvalidArgument, "у инструмента %s нет вариантов", inst.Name)
+		}
+		return nil, nil
+	}
+	if sku == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "выберите вариант инструмента %s", inst.Name)
+	}
+	for _, v := range inst.Variants {
+		if v.Sku == sku {
+			return v, nil
+		}
+	}
+	return nil, status.Errorf(codes.NotFound, "вариант %s не найден", sku)
+}
+
+// unitPrice — цена позиции: у варианта может быть своя цена, 0 означает
+// цену инструмента.
+func unitPrice(inst *instrumentsproto.Instrument, sku string) float64 {
+	v, _ := resolveVariant(inst, sku)
+	if v != nil && v.Price > 0 {
+		return v.Price
+	}
+	return inst.Price
+}
+
+// itemName — название позиции для заказа и счёта, например
+// "Fender Stratocaster (color: sunburst)".
+func itemName(inst *instrumentsproto.Instrument, sku string) string {
+	v, _ := resolveVariant(inst, sku)
+	if v == nil || len(v.Options) == 0 {
+		return inst.Name
+	}
+
+	names := make([]string, 0, len(v.Options))
+	for name := range v.Options {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, name+": "+v.Options[name])
+	}
+	return inst.Name + " (" + strings.Join(parts, ", ") + ")"
+}
@@ -0,0 +1,98 @@
+package shipping
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"strings"
+)
+
+// ErrNotApplicable возвращается калькулятором, если способ доставки
+// недоступен для данной посылки или адреса.
+var ErrNotApplicable = errors.New("способ доставки недоступен")
+
+type Item struct {
+	InstrumentID string
+	Quantity     int32
+	UnitPrice    float64
+	WeightKg     float64
+	LengthCm     float64
+	WidthCm      float64
+	HeightCm     float64
+}
+
+type Request struct {
+	Items   []Item
+	Country string
+}
+
+func (r *Request) Subtotal() float64 {
+	var subtotal float64
+	for _, i := range r.Items {
+		subtotal += i.UnitPrice * float64(i.Quantity)
+	}
+	return subtotal
+}
+
+type Option struct {
+	Code          string
+	Name          string
+	Cost          float64
+	EstimatedDays int32
+}
+
+type ShippingRateCalculator interface {
+	Calculate(ctx context.Context, req *Request) (*Option, error)
+}
+
+// Quote опрашивает все калькуляторы и возвращает доступные варианты,
+// отсортированные по стоимости.
+func Quote(ctx context.Context, calculators []ShippingRateCalculator, req *Request) ([]*Option, error) {
+	var options []*Option
+	for _, c := range calculators {
+		opt, err := c.Calculate(ctx, req)
+		if errors.Is(err, ErrNotApplicable) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, opt)
+	}
+	sort.SliceStable(options, func(i, j int) bool {
+		return options[i].Cost < options[j].Cost
+	})
+	return options, nil
+}
+
+// Find возвращает вариант доставки с указанным кодом.
+func Find(ctx context.Context, calculators []ShippingRateCalculator, req *Request, code string) (*Option, error) {
+	options, err := Quote(ctx, calculators, req)
+	if err != nil {
+		return nil, err
+	}
+	for _, opt := range options {
+		if opt.Code == code {
+			return opt, nil
+		}
+	}
+	return nil, ErrNotApplicable
+}
+
+// servesCountry — пустой список стран означает доставку в любую страну.
+func servesCountry(countries []string, country string) bool {
+	if len(countries) == 0 {
+		return true
+	}
+	for _, c := range countries {
+		if strings.EqualFold(c, country) {
+			return true
+		}
+	}
+	return false
+}
+
+func roundMoney(v float64) float64 {
+	return math.Round(v*100) / 100
+}
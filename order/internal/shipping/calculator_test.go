@@ -0,0 +1,53 @@
+package shipping
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWeightBasedUsesVolumetricWeight(t *testing.T) {
+	calc := &WeightBased{Code: "courier", BaseCost: 5, CostPerKg: 2, VolumetricDivisor: 5000}
+
+	capo := &Request{Items: []Item{{Quantity: 1, WeightKg: 0.1, LengthCm: 10, WidthCm: 5, HeightCm: 3}}}
+	opt, err := calc.Calculate(context.Background(), capo)
+	assert.NoError(t, err)
+	assert.Equal(t, 7.0, opt.Cost)
+
+	// 100×80×60 / 5000 = 96 кг объёмного веса против 30 кг фактического
+	drums := &Request{Items: []Item{{Quantity: 1, WeightKg: 30, LengthCm: 100, WidthCm: 80, HeightCm: 60}}}
+	opt, err = calc.Calculate(context.Background(), drums)
+	assert.NoError(t, err)
+	assert.Equal(t, 5+96*2.0, opt.Cost)
+}
+
+func TestWeightBasedRequiresWeight(t *testing.T) {
+	calc := &WeightBased{Code: "courier", BaseCost: 5, CostPerKg: 2}
+	_, err := calc.Calculate(context.Background(), &Request{Items: []Item{{Quantity: 1}}})
+	assert.ErrorIs(t, err, ErrNotApplicable)
+}
+
+func TestQuoteSortsAndFiltersOptions(t *testing.T) {
+	calculators := []ShippingRateCalculator{
+		&FlatRate{Code: "standard", Cost: 15, Countries: []string{"KZ"}},
+		&FreeShippingThreshold{Code: "free", Threshold: 500},
+		&FlatRate{Code: "express", Cost: 40},
+	}
+
+	req := &Request{Country: "kz", Items: []Item{{Quantity: 2, UnitPrice: 300, WeightKg: 1}}}
+	options, err := Quote(context.Background(), calculators, req)
+	assert.NoError(t, err)
+	if assert.Len(t, options, 3) {
+		assert.Equal(t, "free", options[0].Code)
+		assert.Equal(t, "standard", options[1].Code)
+		assert.Equal(t, "express", options[2].Code)
+	}
+
+	req = &Request{Country: "DE", Items: []Item{{Quantity: 1, UnitPrice: 100, WeightKg: 1}}}
+	options, err = Quote(context.Background(), calculators, req)
+	assert.NoError(t, err)
+	if assert.Len(t, options, 1) {
+		assert.Equal(t, "express", options[0].Code)
+	}
+}
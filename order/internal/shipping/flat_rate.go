@@ -0,0 +1,24 @@
+package shipping
+
+import "context"
+
+// FlatRate — фиксированная стоимость доставки независимо от состава заказа.
+type FlatRate struct {
+	Code          string
+	Name          string
+	Cost          float64
+	EstimatedDays int32
+	Countries     []string
+}
+
+func (f *FlatRate) Calculate(ctx context.Context, req *Request) (*Option, error) {
+	if !servesCountry(f.Countries, req.Country) {
+		return nil, ErrNotApplicable
+	}
+	return &Option{
+		Code:          f.Code,
+		Name:          f.Name,
+		Cost:          f.Cost,
+		EstimatedDays: f.EstimatedDays,
+	}, nil
+}
@@ -0,0 +1,24 @@
+package shipping
+
+import "context"
+
+// FreeShippingThreshold — бесплатная доставка для заказов от Threshold.
+type FreeShippingThreshold struct {
+	Code          string
+	Name          string
+	Threshold     float64
+	EstimatedDays int32
+	Countries     []string
+}
+
+func (f *FreeShippingThreshold) Calculate(ctx context.Context, req *Request) (*Option, error) {
+	if !servesCountry(f.Countries, req.Country) || req.Subtotal() < f.Threshold {
+		return nil, ErrNotApplicable
+	}
+	return &Option{
+		Code:          f.Code,
+		Name:          f.Name,
+		Cost:          0,
+		EstimatedDays: f.EstimatedDays,
+	}, nil
+}
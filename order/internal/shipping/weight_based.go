@@ -0,0 +1,52 @@
+package shipping
+
+import (
+	"context"
+	"math"
+)
+
+// WeightBased считает стоимость по оплачиваемому весу: большему из
+// фактического и объёмного (Д×Ш×В / VolumetricDivisor). Барабанная
+// установка лёгкая, но объёмная, поэтому без объёмного веса доставка
+// стоила бы как для каподастра.
+type WeightBased struct {
+	Code              string
+	Name              string
+	BaseCost          float64
+	CostPerKg         float64
+	VolumetricDivisor float64 // см³ на кг, у большинства перевозчиков 5000
+	MaxWeightKg       float64 // 0 — без ограничения
+	EstimatedDays     int32
+	Countries         []string
+}
+
+func (wb *WeightBased) Calculate(ctx context.Context, req *Request) (*Option, error) {
+	if !servesCountry(wb.Countries, req.Country) {
+		return nil, ErrNotApplicable
+	}
+
+	var billable float64
+	for _, i := range req.Items {
+		// Без веса посчитать доставку нельзя — предлагаем другие способы
+		if i.WeightKg <= 0 {
+			return nil, ErrNotApplicable
+		}
+		weight := i.WeightKg
+		if wb.VolumetricDivisor > 0 {
+			volumetric := i.LengthCm * i.WidthCm * i.HeightCm / wb.VolumetricDivisor
+			weight = math.Max(weight, volumetric)
+		}
+		billable += weight * float64(i.Quantity)
+	}
+
+	if wb.MaxWeightKg > 0 && billable > wb.MaxWeightKg {
+		return nil, ErrNotApplicable
+	}
+
+	return &Option{
+		Code:          wb.Code,
+		Name:          wb.Name,
+		Cost:          roundMoney(wb.BaseCost + math.Ceil(billable)*wb.CostPerKg),
+		EstimatedDays: wb.EstimatedDays,
+	}, nil
+}
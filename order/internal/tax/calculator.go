@@ -0,0 +1,137 @@
+package tax
+
+import (
+	"math"
+	"sort"
+)
+
+type Line struct {
+	InstrumentID string
+	Category     string
+	Amount       float64 // цена × количество в том виде, как она указана в каталоге
+}
+
+type LineTax struct {
+	InstrumentID string
+	Category     string
+	Rate         float64
+	Net          float64
+	Tax          float64
+}
+
+type RateTotal struct {
+	Rate    float64
+	Taxable float64
+	Tax     float64
+}
+
+type Breakdown struct {
+	Country          string
+	Region           string
+	PricesIncludeTax bool
+	Rounding         string
+	Lines            []LineTax
+	Rates            []RateTotal
+	NetTotal         float64
+	TotalTax         float64
+	GrossTotal       float64
+}
+
+type Calculator struct {
+	rules *Rules
+}
+
+func NewCalculator(rules *Rules) *Calculator {
+	return &Calculator{rules: rules}
+}
+
+// Calculate считает налог по позициям заказа и доставке. Если для адреса
+// нет правила, налог равен нулю.
+//
+// При округлении per_line налог каждой позиции округляется до копеек и
+// суммируется; при per_order налог считается один раз с суммы каждой
+// ставки, что избавляет от накопления ошибок округления.
+func (c *Calculator) Calculate(country, region string, lines []Line, shippingCost float64) *Breakdown {
+	rule := c.rules.Match(country, region)
+	if rule == nil {
+		rule = &Rule{Rounding: RoundingPerLine}
+	}
+
+	if shippingCost > 0 {
+		lines = append(lines, Line{Category: ShippingCategory, Amount: shippingCost})
+	}
+
+	b := &Breakdown{
+		Country:          country,
+		Region:           region,
+		PricesIncludeTax: rule.PricesIncludeTax,
+		Rounding:         rule.Rounding,
+	}
+
+	byRate := map[float64]*RateTotal{}
+	for _, l := range lines {
+		rate := rule.rateFor(l.Category)
+		if l.Category == ShippingCategory && !rule.ShippingTaxable {
+			rate = 0
+		}
+
+		net, tax := split(l.Amount, rate, rule.PricesIncludeTax)
+		tax = round(tax)
+		net = round(net)
+
+		b.Lines = append(b.Lines, LineTax{
+			InstrumentID: l.InstrumentID,
+			Category:     l.Category,
+			Rate:         rate,
+			Net:          net,
+			Tax:          tax,
+		})
+
+		rt, ok := byRate[rate]
+		if !ok {
+			rt = &RateTotal{Rate: rate}
+			byRate[rate] = rt
+		}
+		rt.Taxable += l.Amount
+		rt.Tax += tax
+	}
+
+	for _, rt := range byRate {
+		if rule.Rounding == RoundingPerOrder {
+			_, rt.Tax = split(rt.Taxable, rt.Rate, rule.PricesIncludeTax)
+		}
+		rt.Tax = round(rt.Tax)
+		rt.Taxable = round(rt.Taxable)
+		b.Rates = append(b.Rates, *rt)
+		b.TotalTax += rt.Tax
+	}
+	sort.Slice(b.Rates, func(i, j int) bool { return b.Rates[i].Rate < b.Rates[j].Rate })
+
+	var amount float64
+	for _, l := range lines {
+		amount += l.Amount
+	}
+	b.TotalTax = round(b.TotalTax)
+	if rule.PricesIncludeTax {
+		b.GrossTotal = round(amount)
+		b.NetTotal = round(b.GrossTotal - b.TotalTax)
+	} else {
+		b.NetTotal = round(amount)
+		b.GrossTotal = round(b.NetTotal + b.TotalTax)
+	}
+
+	return b
+}
+
+// split делит сумму на нетто и налог без округления.
+func split(amount, rate float64, inclusive bool) (net, tax float64) {
+	if inclusive {
+		net = amount / (1 + rate)
+		return net, amount - net
+	}
+	return amount, amount * rate
+}
+
+func round(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,71 @@
+package tax
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func loadFixture(t *testing.T) *Calculator {
+	rules, err := LoadRules("testdata/rules.json")
+	if err != nil {
+		t.Fatalf("не удалось загрузить правила: %v", err)
+	}
+	return NewCalculator(rules)
+}
+
+func TestMatchPrefersRegion(t *testing.T) {
+	rules, err := LoadRules("testdata/rules.json")
+	assert.NoError(t, err)
+
+	assert.Equal(t, 0.0725, rules.Match("US", "CA").DefaultRate)
+	assert.Equal(t, 0.05, rules.Match("us", "NY").DefaultRate)
+	assert.Equal(t, RoundingPerLine, rules.Match("US", "NY").Rounding)
+	assert.Nil(t, rules.Match("FR", ""))
+}
+
+func TestInclusivePricing(t *testing.T) {
+	b := loadFixture(t).Calculate("KZ", "", []Line{
+		{InstrumentID: "guitar", Amount: 1120},
+		{InstrumentID: "songbook", Category: "sheet_music", Amount: 50},
+	}, 0)
+
+	assert.True(t, b.PricesIncludeTax)
+	assert.Equal(t, 120.0, b.TotalTax)
+	assert.Equal(t, 1170.0, b.GrossTotal)
+	assert.Equal(t, 1050.0, b.NetTotal)
+	assert.Len(t, b.Rates, 2)
+}
+
+func TestExclusivePricingWithShipping(t *testing.T) {
+	b := loadFixture(t).Calculate("US", "CA", []Line{
+		{InstrumentID: "pedal", Amount: 200},
+	}, 20)
+
+	// доставка в Калифорнии налогом не облагается
+	assert.Equal(t, 14.5, b.TotalTax)
+	assert.Equal(t, 220.0, b.NetTotal)
+	assert.Equal(t, 234.5, b.GrossTotal)
+}
+
+func TestRoundingPerLineVsPerOrder(t *testing.T) {
+	lines := []Line{
+		{InstrumentID: "pick-1", Amount: 0.15},
+		{InstrumentID: "pick-2", Amount: 0.15},
+		{InstrumentID: "pick-3", Amount: 0.15},
+	}
+	calc := NewCalculator(&Rules{Rules: []Rule{
+		{Country: "A", DefaultRate: 0.1, Rounding: RoundingPerLine},
+		{Country: "B", DefaultRate: 0.1, Rounding: RoundingPerOrder},
+	}})
+
+	// 0.015 округляется до 0.02 на каждой строке, а с суммы 0.45 — до 0.05
+	assert.Equal(t, 0.06, calc.Calculate("A", "", lines, 0).TotalTax)
+	assert.Equal(t, 0.05, calc.Calculate("B", "", lines, 0).TotalTax)
+}
+
+func TestNoRuleMeansNoTax(t *testing.T) {
+	b := loadFixture(t).Calculate("FR", "", []Line{{InstrumentID: "violin", Amount: 300}}, 10)
+	assert.Equal(t, 0.0, b.TotalTax)
+	assert.Equal(t, 310.0, b.GrossTotal)
+}
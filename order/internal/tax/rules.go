@@ -0,0 +1,85 @@
+package tax
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+const (
+	RoundingPerLine  = "per_line"
+	RoundingPerOrder = "per_order"
+
+	// ShippingCategory — категория, по которой облагается доставка.
+	ShippingCategory = "shipping"
+)
+
+// Rule — налоговое правило для страны или региона внутри страны.
+// Пустой Region означает правило по умолчанию для всей страны.
+type Rule struct {
+	Country          string             `json:"country"`
+	Region           string             `json:"region"`
+	DefaultRate      float64            `json:"default_rate"`
+	CategoryRates    map[string]float64 `json:"category_rates"`
+	PricesIncludeTax bool               `json:"prices_include_tax"`
+	Rounding         string             `json:"rounding"`
+	ShippingTaxable  bool               `json:"shipping_taxable"`
+}
+
+func (r *Rule) rateFor(category string) float64 {
+	if rate, ok := r.CategoryRates[category]; ok {
+		return rate
+	}
+	return r.DefaultRate
+}
+
+type Rules struct {
+	Rules []Rule `json:"rules"`
+}
+
+// LoadRules читает правила из JSON-файла.
+func LoadRules(path string) (*Rules, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var rules Rules
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("ошибка разбора налоговых правил %s: %w", path, err)
+	}
+
+	for i, r := range rules.Rules {
+		if r.Country == "" {
+			return nil, fmt.Errorf("налоговое правило #%d: не указана страна", i)
+		}
+		switch r.Rounding {
+		case "":
+			rules.Rules[i].Rounding = RoundingPerLine
+		case RoundingPerLine, RoundingPerOrder:
+		default:
+			return nil, fmt.Errorf("налоговое правило #%d: неизвестный режим округления %q", i, r.Rounding)
+		}
+	}
+
+	return &rules, nil
+}
+
+// Match ищет правило сначала по стране и региону, затем по стране.
+func (rs *Rules) Match(country, region string) *Rule {
+	var countryRule *Rule
+	for i := range rs.Rules {
+		r := &rs.Rules[i]
+		if !strings.EqualFold(r.Country, country) {
+			continue
+		}
+		if r.Region != "" && strings.EqualFold(r.Region, region) {
+			return r
+		}
+		if r.Region == "" {
+			countryRule = r
+		}
+	}
+	return countryRule
+}
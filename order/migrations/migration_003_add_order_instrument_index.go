@@ -0,0 +1,23 @@
+package migrations
+
+import (
+	"context"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func Migration003_AddOrderInstrumentIndex(db *mongo.Database) error {
+	orders := db.Collection("orders")
+
+	// по нему очистка каталога проверяет, есть ли заказы с инструментом
+	_, err := orders.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys: bson.D{{Key: "items.instrument_id", Value: 1}},
+	})
+	if err != nil {
+		return err
+	}
+	log.Println("✅ Migration003_AddOrderInstrumentIndex applied")
+	return nil
+}
@@ -0,0 +1,21 @@
+package migrations
+
+import (
+	"context"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func Migration004_AddItemPairsIndex(db *mongo.Database) error {
+	// по нему выбираются лучшие пары инструмента при обновлении рекомендаций
+	_, err := db.Collection("item_pairs").Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys: bson.D{{Key: "_id.a", Value: 1}, {Key: "count", Value: -1}},
+	})
+	if err != nil {
+		return err
+	}
+	log.Println("✅ Migration004_AddItemPairsIndex applied")
+	return nil
+}
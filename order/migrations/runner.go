@@ -17,6 +17,9 @@ type Migration struct {
 func RunAll(db *mongo.Database) error {
 	migrations := []Migration{
 		{Name: "Migration001_AddOrderUserIndex", Func: Migration001_AddOrderUserIndex},
+		{Name: "Migration002_AddInvoiceIndexes", Func: Migration002_AddInvoiceIndexes},
+		{Name: "Migration003_AddOrderInstrumentIndex", Func: Migration003_AddOrderInstrumentIndex},
+		{Name: "Migration004_AddItemPairsIndex", Func: Migration004_AddItemPairsIndex},
 	}
 
 	applied := db.Collection("migrations")
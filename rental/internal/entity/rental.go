@@ -0,0 +1,83 @@
+package entity
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RentalPlan — тарифы аренды инструмента, _id — ID инструмента.
+type RentalPlan struct {
+	InstrumentID  primitive.ObjectID `bson:"_id"`
+	DailyRate     float64            `bson:"daily_rate"`
+	MonthlyRate   float64            `bson:"monthly_rate"`
+	Deposit       float64            `bson:"deposit"`
+	LateFeePerDay float64            `bson:"late_fee_per_day"`
+	UpdatedAt     time.Time          `bson:"updated_at"`
+}
+
+const (
+	UnitStatusAvailable   = "available"
+	UnitStatusMaintenance = "maintenance"
+	UnitStatusRetired     = "retired"
+)
+
+// RentalUnit — экземпляр инструмента из прокатного фонда. Bookings —
+// календарь экземпляра: периоды действующих аренд. Он хранится в самом
+// документе, чтобы проверка пересечения и бронь были одной атомарной
+// операцией.
+type RentalUnit struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty"`
+	InstrumentID primitive.ObjectID `bson:"instrument_id"`
+	SerialNumber string             `bson:"serial_number"`
+	Status       string             `bson:"status"`
+	Bookings     []Booking          `bson:"bookings"`
+	CreatedAt    time.Time          `bson:"created_at"`
+}
+
+// Booking занимает экземпляр с StartDate по EndDate включительно: в день
+// возврата экземпляр ещё не выдаётся другому.
+type Booking struct {
+	RentalID  primitive.ObjectID `bson:"rental_id"`
+	StartDate time.Time          `bson:"start_date"`
+	EndDate   time.Time          `bson:"end_date"`
+}
+
+const (
+	RentalStatusActive   = "active"
+	RentalStatusReturned = "returned"
+)
+
+// Rental — договор аренды. Тарифы копируются из плана при оформлении,
+// чтобы продление и пени считались по условиям договора.
+type Rental struct {
+	ID            primitive.ObjectID `bson:"_id"`
+	UserID        primitive.ObjectID `bson:"user_id"`
+	InstrumentID  primitive.ObjectID `bson:"instrument_id"`
+	UnitID        primitive.ObjectID `bson:"unit_id"`
+	SerialNumber  string             `bson:"serial_number"`
+	StartDate     time.Time          `bson:"start_date"`
+	EndDate       time.Time          `bson:"end_date"`
+	Status        string             `bson:"status"`
+	DailyRate     float64            `bson:"daily_rate"`
+	MonthlyRate   float64            `bson:"monthly_rate"`
+	Price         float64            `bson:"price"`
+	Deposit       float64            `bson:"deposit"`
+	LateFeePerDay float64            `bson:"late_fee_per_day"`
+	LateDays      int32              `bson:"late_days,omitempty"`
+	LateFee       float64            `bson:"late_fee,omitempty"`
+	Extensions    []Extension        `bson:"extensions,omitempty"`
+	CreatedAt     time.Time          `bson:"created_at"`
+	ReturnedAt    *time.Time         `bson:"returned_at,omitempty"`
+	// EndDate, о котором уже отправлены напоминание и уведомление о
+	// просрочке; после продления срок другой, и напоминание уйдёт снова
+	RemindedFor *time.Time `bson:"reminded_for,omitempty"`
+	OverdueFor  *time.Time `bson:"overdue_for,omitempty"`
+}
+
+type Extension struct {
+	PreviousEndDate time.Time `bson:"previous_end_date"`
+	EndDate         time.Time `bson:"end_date"`
+	Price           float64   `bson:"price"`
+	CreatedAt       time.Time `bson:"created_at"`
+}
@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"gotune/rental/internal/entity"
+)
+
+var (
+	ErrPlanNotFound   = errors.New("тариф аренды не найден")
+	ErrRentalNotFound = errors.New("аренда не найдена")
+	// ErrRentalChanged — договор вернули или продлили параллельно
+	ErrRentalChanged = errors.New("аренда изменена")
+)
+
+type RentalRepository interface {
+	SavePlan(ctx context.Context, plan *entity.RentalPlan) error
+	FindPlan(ctx context.Context, instrumentID primitive.ObjectID) (*entity.RentalPlan, error)
+	Create(ctx context.Context, rental *entity.Rental) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*entity.Rental, error)
+	ListByUser(ctx context.Context, userID primitive.ObjectID) ([]entity.Rental, error)
+	Extend(ctx context.Context, id primitive.ObjectID, ext entity.Extension) error
+	MarkReturned(ctx context.Context, id primitive.ObjectID, returnedAt time.Time, lateDays int32, lateFee float64) error
+	FindDueBefore(ctx context.Context, before time.Time, limit int64) ([]entity.Rental, error)
+	FindOverdue(ctx context.Context, now time.Time, limit int64) ([]entity.Rental, error)
+	MarkReminded(ctx context.Context, id primitive.ObjectID, endDate time.Time) error
+	MarkOverdueNotified(ctx context.Context, id primitive.ObjectID, endDate time.Time) error
+}
+
+type rentalRepository struct {
+	plans   *mongo.Collection
+	rentals *mongo.Collection
+}
+
+func NewRentalRepository(db *mongo.Database) RentalRepository {
+	return &rentalRepository{
+		plans:   db.Collection("rental_plans"),
+		rentals: db.Collection("rentals"),
+	}
+}
+
+func (r *rentalRepository) SavePlan(ctx context.Context, plan *entity.RentalPlan) error {
+	_, err := r.plans.ReplaceOne(ctx, bson.M{"_id": plan.InstrumentID}, plan, options.Replace().SetUpsert(true))
+	return err
+}
+
+func (r *rentalRepository) FindPlan(ctx context.Context, instrumentID primitive.ObjectID) (*entity.RentalPlan, error) {
+	var plan entity.RentalPlan
+	err := r.plans.FindOne(ctx, bson.M{"_id": instrumentID}).Decode(&plan)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrPlanNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+func (r *rentalRepository) Create(ctx context.Context, rental *entity.Rental) error {
+	_, err := r.rentals.InsertOne(ctx, rental)
+	return err
+}
+
+func (r *rentalRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*entity.Rental, error) {
+	var rental entity.Rental
+	err := r.rentals.FindOne(ctx, bson.M{"_id": id}).Decode(&rental)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrRentalNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &rental, nil
+}
+
+func (r *rentalRepository) ListByUser(ctx context.Context, userID primitive.ObjectID) ([]entity.Rental, error) {
+	return r.find(ctx, bson.M{"user_id": userID}, options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}))
+}
+
+// Extend записывает продление, если договор действует и срок не менялся
+// с момента чтения.
+func (r *rentalRepository) Extend(ctx context.Context, id primitive.ObjectID, ext entity.Extension) error {
+	res, err := r.rentals.UpdateOne(ctx,
+		bson.M{"_id": id, "status": entity.RentalStatusActive, "end_date": ext.PreviousEndDate},
+		bson.M{
+			"$set":  bson.M{"end_date": ext.EndDate},
+			"$inc":  bson.M{"price": ext.Price},
+			"$push": bson.M{"extensions": ext},
+		},
+	)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrRentalChanged
+	}
+	return nil
+}
+
+func (r *rentalRepository) MarkReturned(ctx context.Context, id primitive.ObjectID, returnedAt time.Time, lateDays int32, lateFee float64) error {
+	res, err := r.rentals.UpdateOne(ctx,
+		bson.M{"_id": id, "status": entity.RentalStatusActive},
+		bson.M{"$set": bson.M{
+			"status":      entity.RentalStatusReturned,
+			"returned_at": returnedAt,
+			"late_days":   lateDays,
+			"late_fee":    lateFee,
+		}},
+	)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrRentalChanged
+	}
+	return nil
+}
+
+// FindDueBefore — действующие аренды со сроком возврата до before, о
+// сроке которых ещё не напоминали.
+func (r *rentalRepository) FindDueBefore(ctx context.Context, before time.Time, limit int64) ([]entity.Rental, error) {
+	filter := bson.M{
+		"status":   entity.RentalStatusActive,
+		"end_date": bson.M{"$lt": before},
+		"$expr":    bson.M{"$ne": bson.A{"$reminded_for", "$end_date"}},
+	}
+	return r.find(ctx, filter, options.Find().SetSort(bson.D{{Key: "end_date", Value: 1}}).SetLimit(limit))
+}
+
+// FindOverdue — аренды, не возвращённые в день возврата, о просрочке
+// которых ещё не сообщали.
+func (r *rentalRepository) FindOverdue(ctx context.Context, now time.Time, limit int64) ([]entity.Rental, error) {
+	filter := bson.M{
+		"status":   entity.RentalStatusActive,
+		"end_date": bson.M{"$lte": now.Add(-24 * time.Hour)},
+		"$expr":    bson.M{"$ne": bson.A{"$overdue_for", "$end_date"}},
+	}
+	return r.find(ctx, filter, options.Find().SetSort(bson.D{{Key: "end_date", Value: 1}}).SetLimit(limit))
+}
+
+func (r *rentalRepository) MarkReminded(ctx context.Context, id primitive.ObjectID, endDate time.Time) error {
+	_, err := r.rentals.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"reminded_for": endDate}})
+	return err
+}
+
+func (r *rentalRepository) MarkOverdueNotified(ctx context.Context, id primitive.ObjectID, endDate time.Time) error {
+	_, err := r.rentals.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"overdue_for": endDate}})
+	return err
+}
+
+func (r *rentalRepository) find(ctx context.Context, filter bson.M, opts *options.FindOptions) ([]entity.Rental, error) {
+	cursor, err := r.rentals.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var rentals []entity.Rental
+	if err := cursor.All(ctx, &rentals); err != nil {
+		return nil, err
+	}
+	return rentals, nil
+}
@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"gotune/rental/internal/entity"
+)
+
+var (
+	ErrUnitNotFound = errors.New("экземпляр не найден")
+	// ErrUnitBusy — экземпляр занят в эти даты или не выдаётся
+	ErrUnitBusy = errors.New("экземпляр занят")
+)
+
+type UnitRepository interface {
+	Create(ctx context.Context, unit *entity.RentalUnit) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*entity.RentalUnit, error)
+	ListByInstrument(ctx context.Context, instrumentID primitive.ObjectID) ([]entity.RentalUnit, error)
+	CountByInstrument(ctx context.Context, instrumentID primitive.ObjectID) (int64, error)
+	SetStatus(ctx context.Context, id primitive.ObjectID, status string) (*entity.RentalUnit, error)
+	Book(ctx context.Context, unitID primitive.ObjectID, booking entity.Booking, now time.Time) error
+	ExtendBooking(ctx context.Context, unitID, rentalID primitive.ObjectID, from, to time.Time) error
+	Release(ctx context.Context, unitID, rentalID primitive.ObjectID) error
+}
+
+type unitRepository struct {
+	collection *mongo.Collection
+}
+
+func NewUnitRepository(db *mongo.Database) UnitRepository {
+	return &unitRepository{collection: db.Collection("rental_units")}
+}
+
+// overlapping — брони, пересекающиеся с периодом start..end включительно.
+func overlapping(start, end time.Time) bson.M {
+	return bson.M{
+		"start_date": bson.M{"$lte": end},
+		"end_date":   bson.M{"$gte": start},
+	}
+}
+
+func (r *unitRepository) Create(ctx context.Context, unit *entity.RentalUnit) error {
+	res, err := r.collection.InsertOne(ctx, unit)
+	if err != nil {
+		return err
+	}
+	unit.ID = res.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *unitRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*entity.RentalUnit, error) {
+	var unit entity.RentalUnit
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&unit)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrUnitNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &unit, nil
+}
+
+func (r *unitRepository) ListByInstrument(ctx context.Context, instrumentID primitive.ObjectID) ([]entity.RentalUnit, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "serial_number", Value: 1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"instrument_id": instrumentID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var units []entity.RentalUnit
+	if err := cursor.All(ctx, &units); err != nil {
+		return nil, err
+	}
+	return units, nil
+}
+
+func (r *unitRepository) CountByInstrument(ctx context.Context, instrumentID primitive.ObjectID) (int64, error) {
+	return r.collection.CountDocuments(ctx, bson.M{"instrument_id": instrumentID})
+}
+
+func (r *unitRepository) SetStatus(ctx context.Context, id primitive.ObjectID, status string) (*entity.RentalUnit, error) {
+	var unit entity.RentalUnit
+	err := r.collection.FindOneAndUpdate(ctx,
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{"status": status}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&unit)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrUnitNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &unit, nil
+}
+
+// Book занимает экземпляр, если он выдаётся и свободен весь период.
+// Проверка и запись — одно обновление документа, поэтому две
+// одновременные аренды не займут один период. Бронь снимается при
+// возврате, поэтому бронь с истёкшим на now днём возврата — просроченная
+// аренда, и экземпляр занят до её возврата.
+func (r *unitRepository) Book(ctx context.Context, unitID primitive.ObjectID, booking entity.Booking, now time.Time) error {
+	busy := bson.M{"$or": bson.A{
+		overlapping(booking.StartDate, booking.EndDate),
+		bson.M{"end_date": bson.M{"$lt": now.Add(-24 * time.Hour)}},
+	}}
+
+	res, err := r.collection.UpdateOne(ctx,
+		bson.M{
+			"_id":      unitID,
+			"status":   entity.UnitStatusAvailable,
+			"bookings": bson.M{"$not": bson.M{"$elemMatch": busy}},
+		},
+		bson.M{"$push": bson.M{"bookings": booking}},
+	)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrUnitBusy
+	}
+	return nil
+}
+
+// ExtendBooking переносит конец брони аренды с from на to, если
+// экземпляр свободен в добавляемые дни.
+func (r *unitRepository) ExtendBooking(ctx context.Context, unitID, rentalID primitive.ObjectID, from, to time.Time) error {
+	others := overlapping(from.Add(24*time.Hour), to)
+	others["rental_id"] = bson.M{"$ne": rentalID}
+
+	res, err := r.collection.UpdateOne(ctx,
+		bson.M{
+			"_id":      unitID,
+			"bookings": bson.M{"$elemMatch": bson.M{"rental_id": rentalID, "end_date": from}},
+			"$nor":     bson.A{bson.M{"bookings": bson.M{"$elemMatch": others}}},
+		},
+		bson.M{"$set": bson.M{"bookings.$[b].end_date": to}},
+		options.Update().SetArrayFilters(options.ArrayFilters{
+			Filters: []interface{}{bson.M{"b.rental_id": rentalID}},
+		}),
+	)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrUnitBusy
+	}
+	return nil
+}
+
+// Release освобождает календарь экземпляра после возврата.
+func (r *unitRepository) Release(ctx context.Context, unitID, rentalID primitive.ObjectID) error {
+	_, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": unitID},
+		bson.M{"$pull": bson.M{"bookings": bson.M{"rental_id": rentalID}}},
+	)
+	return err
+}
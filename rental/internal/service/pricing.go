@@ -0,0 +1,74 @@
+package service
+
+import (
+	"math"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	day             = 24 * time.Hour
+	billingMonth    = 30 // дней в расчётном месяце
+	maxRentalDays   = 365
+	maxRentalPeriod = maxRentalDays * day
+)
+
+// normalizeDay округляет Unix-время до начала дня UTC.
+func normalizeDay(unix int64) time.Time {
+	return time.Unix(unix, 0).UTC().Truncate(day)
+}
+
+// validatePeriod проверяет период аренды: начало не в прошлом, возврат
+// позже начала, не дольше года.
+func validatePeriod(start, end, today time.Time) error {
+	if start.Before(today) {
+		return status.Error(codes.InvalidArgument, "начало аренды не может быть в прошлом")
+	}
+	if !end.After(start) {
+		return status.Error(codes.InvalidArgument, "дата возврата должна быть позже начала аренды")
+	}
+	if end.Sub(start) > maxRentalPeriod {
+		return status.Errorf(codes.InvalidArgument, "аренда не может быть дольше %d дней", maxRentalDays)
+	}
+	return nil
+}
+
+// rentalDays — число оплачиваемых дней: день возврата не оплачивается.
+func rentalDays(start, end time.Time) int {
+	return int(end.Sub(start) / day)
+}
+
+// rentalPrice считает стоимость аренды на days дней. Каждые полные 30
+// дней — по месячному тарифу, остаток — посуточно, но не дороже месяца.
+// Без суточного тарифа каждый начатый месяц оплачивается целиком.
+func rentalPrice(days int, dailyRate, monthlyRate float64) float64 {
+	if monthlyRate <= 0 {
+		return roundCents(float64(days) * dailyRate)
+	}
+
+	months, rest := days/billingMonth, days%billingMonth
+	price := float64(months) * monthlyRate
+	if rest > 0 {
+		restPrice := monthlyRate
+		if dailyRate > 0 {
+			restPrice = math.Min(float64(rest)*dailyRate, monthlyRate)
+		}
+		price += restPrice
+	}
+	return roundCents(price)
+}
+
+// lateDays — число полных суток просрочки после дня возврата.
+func lateDays(end, returnedAt time.Time) int32 {
+	deadline := end.Add(day)
+	if !returnedAt.After(deadline) {
+		return 0
+	}
+	return int32(returnedAt.Sub(deadline)/day) + 1
+}
+
+func roundCents(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,47 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRentalPrice(t *testing.T) {
+	// посуточно, пока дешевле месяца
+	assert.Equal(t, 50.0, rentalPrice(5, 10, 200))
+	// остаток дороже месяца оплачивается как месяц
+	assert.Equal(t, 200.0, rentalPrice(25, 10, 200))
+	assert.Equal(t, 230.0, rentalPrice(33, 10, 200))
+	assert.Equal(t, 400.0, rentalPrice(60, 10, 200))
+
+	// без месячного тарифа — только посуточно
+	assert.Equal(t, 330.0, rentalPrice(33, 10, 0))
+	// без суточного — каждый начатый месяц целиком
+	assert.Equal(t, 400.0, rentalPrice(31, 0, 200))
+
+	assert.Equal(t, 0.3, rentalPrice(3, 0.1, 0))
+}
+
+func TestLateDays(t *testing.T) {
+	end := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, int32(0), lateDays(end, end.Add(-2*time.Hour)))
+	assert.Equal(t, int32(0), lateDays(end, end.Add(23*time.Hour)))
+	assert.Equal(t, int32(1), lateDays(end, end.Add(25*time.Hour)))
+	assert.Equal(t, int32(3), lateDays(end, end.Add(3*24*time.Hour+time.Minute)))
+}
+
+func TestValidatePeriod(t *testing.T) {
+	today := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+
+	assert.NoError(t, validatePeriod(today, today.AddDate(0, 0, 30), today))
+	assert.Error(t, validatePeriod(today.AddDate(0, 0, -1), today.AddDate(0, 0, 5), today))
+	assert.Error(t, validatePeriod(today, today, today))
+	assert.Error(t, validatePeriod(today, today.AddDate(0, 0, maxRentalDays+1), today))
+}
+
+func TestNormalizeDay(t *testing.T) {
+	ts := time.Date(2026, 3, 10, 17, 45, 0, 0, time.UTC).Unix()
+	assert.Equal(t, time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC), normalizeDay(ts))
+}
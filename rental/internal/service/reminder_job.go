@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"gotune/events"
+	"gotune/rental/internal/repository"
+	"gotune/rental/metrics"
+)
+
+const reminderBatchSize = 100
+
+// RentalReminderJob публикует rental_due_soon за lead до дня возврата и
+// rental_overdue, когда день возврата прошёл. Каждое событие уходит один
+// раз на срок договора; после продления напоминание придёт снова.
+type RentalReminderJob struct {
+	repo      repository.RentalRepository
+	publisher *events.EventPublisher
+	lead      time.Duration
+	interval  time.Duration
+}
+
+func NewRentalReminderJob(repo repository.RentalRepository, publisher *events.EventPublisher, lead, interval time.Duration) *RentalReminderJob {
+	return &RentalReminderJob{
+		repo:      repo,
+		publisher: publisher,
+		lead:      lead,
+		interval:  interval,
+	}
+}
+
+func (j *RentalReminderJob) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		if due, overdue, err := j.RunOnce(ctx); err != nil {
+			log.Printf("Ошибка напоминаний об аренде: %v", err)
+		} else if due+overdue > 0 {
+			log.Printf("Напоминания об аренде: срок подходит — %d, просрочено — %d", due, overdue)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (j *RentalReminderJob) RunOnce(ctx context.Context) (int, int, error) {
+	now := time.Now()
+
+	dueSoon, err := j.repo.FindDueBefore(ctx, now.Add(j.lead), reminderBatchSize)
+	if err != nil {
+		return 0, 0, err
+	}
+	due := 0
+	for i := range dueSoon {
+		rental := &dueSoon[i]
+		if err := j.publisher.Publish("rental_due_soon", rentalEvent(rental)); err != nil {
+			return due, 0, err
+		}
+		if err := j.repo.MarkReminded(ctx, rental.ID, rental.EndDate); err != nil {
+			return due, 0, err
+		}
+		due++
+	}
+
+	overdueRentals, err := j.repo.FindOverdue(ctx, now, reminderBatchSize)
+	if err != nil {
+		return due, 0, err
+	}
+	overdue := 0
+	for i := range overdueRentals {
+		rental := &overdueRentals[i]
+		if err := j.publisher.Publish("rental_overdue", rentalEvent(rental)); err != nil {
+			return due, overdue, err
+		}
+		if err := j.repo.MarkOverdueNotified(ctx, rental.ID, rental.EndDate); err != nil {
+			return due, overdue, err
+		}
+		metrics.RentalsOverdueTotal.Inc()
+		overdue++
+	}
+	return due, overdue, nil
+}
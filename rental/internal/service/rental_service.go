@@ -0,0 +1,569 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"gotune/events"
+	instrumentsproto "gotune/instruments/proto"
+	"gotune/rental/internal/entity"
+	"gotune/rental/internal/repository"
+	"gotune/rental/metrics"
+	"gotune/rental/proto"
+	usersproto "gotune/users/proto"
+)
+
+// Статусы экземпляра в складском учёте сервиса инструментов.
+const (
+	instrumentsInStockStatus = "in_stock"
+	instrumentsRentedStatus  = "rented"
+)
+
+type RentalService struct {
+	units            repository.UnitRepository
+	rentals          repository.RentalRepository
+	userClient       usersproto.UserServiceClient
+	instrumentClient instrumentsproto.InstrumentServiceClient
+	eventPublisher   *events.EventPublisher
+	proto.UnimplementedRentalServiceServer
+}
+
+func NewRentalService(
+	units repository.UnitRepository,
+	rentals repository.RentalRepository,
+	userClient usersproto.UserServiceClient,
+	instrumentClient instrumentsproto.InstrumentServiceClient,
+	publisher *events.EventPublisher,
+) *RentalService {
+	return &RentalService{
+		units:            units,
+		rentals:          rentals,
+		userClient:       userClient,
+		instrumentClient: instrumentClient,
+		eventPublisher:   publisher,
+	}
+}
+
+func (s *RentalService) SetRentalPlan(ctx context.Context, req *proto.SetRentalPlanRequest) (*proto.RentalPlan, error) {
+	instrumentID, err := s.instrumentRef(ctx, req.InstrumentId)
+	if err != nil {
+		return nil, err
+	}
+	if req.DailyRate < 0 || req.MonthlyRate < 0 || req.Deposit < 0 || req.LateFeePerDay < 0 {
+		return nil, status.Error(codes.InvalidArgument, "тарифы не могут быть отрицательными")
+	}
+	if req.DailyRate == 0 && req.MonthlyRate == 0 {
+		return nil, status.Error(codes.InvalidArgument, "нужен суточный или месячный тариф")
+	}
+
+	plan := &entity.RentalPlan{
+		InstrumentID:  instrumentID,
+		DailyRate:     req.DailyRate,
+		MonthlyRate:   req.MonthlyRate,
+		Deposit:       req.Deposit,
+		LateFeePerDay: req.LateFeePerDay,
+		UpdatedAt:     time.Now(),
+	}
+	if err := s.rentals.SavePlan(ctx, plan); err != nil {
+		return nil, err
+	}
+	return planToProto(plan), nil
+}
+
+func (s *RentalService) GetRentalPlan(ctx context.Context, req *proto.GetRentalPlanRequest) (*proto.RentalPlan, error) {
+	instrumentID, err := primitive.ObjectIDFromHex(req.InstrumentId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid instrument ID: %v", err)
+	}
+
+	plan, err := s.rentals.FindPlan(ctx, instrumentID)
+	if err != nil {
+		return nil, rentalError(err)
+	}
+	return planToProto(plan), nil
+}
+
+func (s *RentalService) AddRentalUnit(ctx context.Context, req *proto.AddRentalUnitRequest) (*proto.RentalUnit, error) {
+	instrumentID, err := s.instrumentRef(ctx, req.InstrumentId)
+	if err != nil {
+		return nil, err
+	}
+	serial := strings.TrimSpace(req.SerialNumber)
+	if serial == "" {
+		return nil, status.Error(codes.InvalidArgument, "не указан серийный номер")
+	}
+
+	tracked, err := s.takeSerialUnit(ctx, req.InstrumentId, serial)
+	if err != nil {
+		return nil, err
+	}
+
+	unit := &entity.RentalUnit{
+		InstrumentID: instrumentID,
+		SerialNumber: serial,
+		Status:       entity.UnitStatusAvailable,
+		Bookings:     []entity.Booking{},
+		CreatedAt:    time.Now(),
+	}
+	if err := s.units.Create(ctx, unit); err != nil {
+		if tracked {
+			s.returnSerialUnit(serial)
+		}
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, status.Errorf(codes.AlreadyExists, "экземпляр с серийным номером %s уже есть", serial)
+		}
+		return nil, err
+	}
+
+	return unitToProto(unit), nil
+}
+
+// takeSerialUnit переводит экземпляр из складского учёта в прокатный
+// фонд. Экземпляры, которых нет в учёте, принимаются как есть; проданный
+// или зарезервированный под заказ экземпляр в прокат не передаётся.
+// Возвращает true, если экземпляр есть в учёте.
+func (s *RentalService) takeSerialUnit(ctx context.Context, instrumentIDHex, serial string) (bool, error) {
+	stock, err := s.instrumentClient.GetSerialUnit(ctx, &instrumentsproto.GetSerialUnitRequest{SerialNumber: serial})
+	if status.Code(err) == codes.NotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if stock.InstrumentId != instrumentIDHex {
+		return false, status.Error(codes.InvalidArgument, "экземпляр относится к другому инструменту")
+	}
+	if stock.Status != instrumentsInStockStatus {
+		return false, status.Errorf(codes.FailedPrecondition, "экземпляр %s в статусе %s, в прокат передаётся только экземпляр со склада", serial, stock.Status)
+	}
+
+	_, err = s.instrumentClient.UpdateSerialUnit(ctx, &instrumentsproto.UpdateSerialUnitRequest{
+		SerialNumber: serial,
+		Status:       instrumentsRentedStatus,
+	})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// returnSerialUnit возвращает экземпляр на склад, если прокатный
+// экземпляр не удалось сохранить.
+func (s *RentalService) returnSerialUnit(serial string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := s.instrumentClient.UpdateSerialUnit(ctx, &instrumentsproto.UpdateSerialUnitRequest{
+		SerialNumber: serial,
+		Status:       instrumentsInStockStatus,
+	})
+	if err != nil {
+		log.Printf("Не удалось вернуть экземпляр %s на склад: %v", serial, err)
+	}
+}
+
+// SetRentalUnitStatus выводит экземпляр в ремонт или из фонда. Действующие
+// аренды это не отменяет: новые брони экземпляр просто не принимает.
+func (s *RentalService) SetRentalUnitStatus(ctx context.Context, req *proto.SetRentalUnitStatusRequest) (*proto.RentalUnit, error) {
+	unitID, err := primitive.ObjectIDFromHex(req.UnitId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid unit ID: %v", err)
+	}
+	switch req.Status {
+	case entity.UnitStatusAvailable, entity.UnitStatusMaintenance, entity.UnitStatusRetired:
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "неизвестный статус экземпляра: %q", req.Status)
+	}
+
+	unit, err := s.units.SetStatus(ctx, unitID, req.Status)
+	if err != nil {
+		return nil, rentalError(err)
+	}
+	return unitToProto(unit), nil
+}
+
+// GetAvailability возвращает календарь экземпляров инструмента: занятые
+// периоды, пересекающиеся с интервалом from..to. Не возвращённый в срок
+// экземпляр занят до конца интервала.
+func (s *RentalService) GetAvailability(ctx context.Context, req *proto.GetAvailabilityRequest) (*proto.GetAvailabilityResponse, error) {
+	instrumentID, err := primitive.ObjectIDFromHex(req.InstrumentId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid instrument ID: %v", err)
+	}
+	from := normalizeDay(req.From)
+	if req.From == 0 {
+		from = time.Now().UTC().Truncate(day)
+	}
+	to := normalizeDay(req.To)
+	if req.To == 0 {
+		to = from.Add(billingMonth * day)
+	}
+	if to.Before(from) {
+		return nil, status.Error(codes.InvalidArgument, "конец интервала раньше начала")
+	}
+
+	units, err := s.units.ListByInstrument(ctx, instrumentID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	resp := &proto.GetAvailabilityResponse{}
+	for i := range units {
+		if units[i].Status == entity.UnitStatusRetired {
+			continue
+		}
+		item := &proto.UnitAvailability{Unit: unitToProto(&units[i])}
+		for _, b := range units[i].Bookings {
+			end := b.EndDate
+			if lateDays(end, now) > 0 && end.Before(to) {
+				end = to
+			}
+			if b.StartDate.After(to) || end.Before(from) {
+				continue
+			}
+			item.Busy = append(item.Busy, &proto.BusyPeriod{
+				RentalId:  b.RentalID.Hex(),
+				StartDate: b.StartDate.Unix(),
+				EndDate:   end.Unix(),
+			})
+		}
+		resp.Units = append(resp.Units, item)
+	}
+	return resp, nil
+}
+
+// CreateRental оформляет аренду. Если экземпляр не указан, берётся первый
+// свободный на весь период.
+func (s *RentalService) CreateRental(ctx context.Context, req *proto.CreateRentalRequest) (*proto.Rental, error) {
+	userID, err := primitive.ObjectIDFromHex(req.UserId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid user ID: %v", err)
+	}
+	instrumentID, err := primitive.ObjectIDFromHex(req.InstrumentId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid instrument ID: %v", err)
+	}
+
+	start, end := normalizeDay(req.StartDate), normalizeDay(req.EndDate)
+	if err := validatePeriod(start, end, time.Now().UTC().Truncate(day)); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.userClient.GetUser(ctx, &usersproto.GetUserRequest{UserId: req.UserId}); err != nil {
+		return nil, status.Errorf(codes.NotFound, "пользователь %s не найден", req.UserId)
+	}
+	plan, err := s.rentals.FindPlan(ctx, instrumentID)
+	if err != nil {
+		if errors.Is(err, repository.ErrPlanNotFound) {
+			return nil, status.Error(codes.FailedPrecondition, "инструмент не сдаётся в аренду")
+		}
+		return nil, err
+	}
+
+	rental := &entity.Rental{
+		ID:            primitive.NewObjectID(),
+		UserID:        userID,
+		InstrumentID:  instrumentID,
+		StartDate:     start,
+		EndDate:       end,
+		Status:        entity.RentalStatusActive,
+		DailyRate:     plan.DailyRate,
+		MonthlyRate:   plan.MonthlyRate,
+		Price:         rentalPrice(rentalDays(start, end), plan.DailyRate, plan.MonthlyRate),
+		Deposit:       plan.Deposit,
+		LateFeePerDay: plan.LateFeePerDay,
+		CreatedAt:     time.Now(),
+	}
+
+	unit, err := s.bookUnit(ctx, instrumentID, req.UnitId, entity.Booking{
+		RentalID:  rental.ID,
+		StartDate: start,
+		EndDate:   end,
+	})
+	if err != nil {
+		return nil, err
+	}
+	rental.UnitID = unit.ID
+	rental.SerialNumber = unit.SerialNumber
+
+	if err := s.rentals.Create(ctx, rental); err != nil {
+		if releaseErr := s.units.Release(ctx, unit.ID, rental.ID); releaseErr != nil {
+			log.Printf("Не удалось освободить экземпляр %s после ошибки аренды: %v", unit.ID.Hex(), releaseErr)
+		}
+		return nil, err
+	}
+
+	metrics.RentalsCreatedTotal.Inc()
+	_ = s.eventPublisher.Publish("rental_created", rentalEvent(rental))
+	return rentalToProto(rental), nil
+}
+
+// bookUnit бронирует указанный экземпляр или перебирает экземпляры
+// инструмента, пока один не окажется свободен.
+func (s *RentalService) bookUnit(ctx context.Context, instrumentID primitive.ObjectID, unitIDHex string, booking entity.Booking) (*entity.RentalUnit, error) {
+	if unitIDHex != "" {
+		unitID, err := primitive.ObjectIDFromHex(unitIDHex)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid unit ID: %v", err)
+		}
+		unit, err := s.units.FindByID(ctx, unitID)
+		if err != nil {
+			return nil, rentalError(err)
+		}
+		if unit.InstrumentID != instrumentID {
+			return nil, status.Error(codes.InvalidArgument, "экземпляр относится к другому инструменту")
+		}
+		if err := s.units.Book(ctx, unit.ID, booking, time.Now()); err != nil {
+			return nil, rentalError(err)
+		}
+		return unit, nil
+	}
+
+	units, err := s.units.ListByInstrument(ctx, instrumentID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range units {
+		if units[i].Status != entity.UnitStatusAvailable {
+			continue
+		}
+		err := s.units.Book(ctx, units[i].ID, booking, time.Now())
+		if err == nil {
+			return &units[i], nil
+		}
+		if !errors.Is(err, repository.ErrUnitBusy) {
+			return nil, err
+		}
+	}
+	return nil, status.Error(codes.FailedPrecondition, "нет свободных экземпляров на эти даты")
+}
+
+// ExtendRental переносит дату возврата. Доплата — разница стоимости
+// аренды на новый и старый срок по тарифам договора.
+func (s *RentalService) ExtendRental(ctx context.Context, req *proto.ExtendRentalRequest) (*proto.Rental, error) {
+	rental, err := s.findRental(ctx, req.RentalId)
+	if err != nil {
+		return nil, err
+	}
+	if rental.Status != entity.RentalStatusActive {
+		return nil, status.Error(codes.FailedPrecondition, "аренда уже завершена")
+	}
+
+	end := normalizeDay(req.EndDate)
+	if !end.After(rental.EndDate) {
+		return nil, status.Error(codes.InvalidArgument, "новая дата возврата должна быть позже текущей")
+	}
+	if end.Sub(rental.StartDate) > maxRentalPeriod {
+		return nil, status.Errorf(codes.InvalidArgument, "аренда не может быть дольше %d дней", maxRentalDays)
+	}
+
+	ext := entity.Extension{
+		PreviousEndDate: rental.EndDate,
+		EndDate:         end,
+		Price: roundCents(rentalPrice(rentalDays(rental.StartDate, end), rental.DailyRate, rental.MonthlyRate) -
+			rentalPrice(rentalDays(rental.StartDate, rental.EndDate), rental.DailyRate, rental.MonthlyRate)),
+		CreatedAt: time.Now(),
+	}
+
+	if err := s.units.ExtendBooking(ctx, rental.UnitID, rental.ID, rental.EndDate, end); err != nil {
+		if errors.Is(err, repository.ErrUnitBusy) {
+			return nil, status.Error(codes.FailedPrecondition, "экземпляр занят в эти даты")
+		}
+		return nil, err
+	}
+	if err := s.rentals.Extend(ctx, rental.ID, ext); err != nil {
+		// договор изменился параллельно — возвращаем календарь к прежнему сроку
+		if rollbackErr := s.units.ExtendBooking(ctx, rental.UnitID, rental.ID, end, rental.EndDate); rollbackErr != nil {
+			log.Printf("Не удалось вернуть бронь аренды %s: %v", rental.ID.Hex(), rollbackErr)
+		}
+		return nil, rentalError(err)
+	}
+
+	rental.EndDate = end
+	rental.Price = roundCents(rental.Price + ext.Price)
+	rental.Extensions = append(rental.Extensions, ext)
+
+	event := rentalEvent(rental)
+	event["price"] = fmt.Sprintf("%.2f", ext.Price)
+	_ = s.eventPublisher.Publish("rental_extended", event)
+	return rentalToProto(rental), nil
+}
+
+// ReturnRental закрывает аренду, начисляет пени за просрочку и
+// освобождает экземпляр.
+func (s *RentalService) ReturnRental(ctx context.Context, req *proto.ReturnRentalRequest) (*proto.Rental, error) {
+	rental, err := s.findRental(ctx, req.RentalId)
+	if err != nil {
+		return nil, err
+	}
+	if rental.Status != entity.RentalStatusActive {
+		return nil, status.Error(codes.FailedPrecondition, "аренда уже завершена")
+	}
+
+	now := time.Now()
+	late := lateDays(rental.EndDate, now)
+	fee := roundCents(float64(late) * rental.LateFeePerDay)
+
+	if err := s.rentals.MarkReturned(ctx, rental.ID, now, late, fee); err != nil {
+		return nil, rentalError(err)
+	}
+	if err := s.units.Release(ctx, rental.UnitID, rental.ID); err != nil {
+		log.Printf("Не удалось освободить экземпляр %s: %v", rental.UnitID.Hex(), err)
+	}
+
+	rental.Status = entity.RentalStatusReturned
+	rental.ReturnedAt = &now
+	rental.LateDays = late
+	rental.LateFee = fee
+
+	if fee > 0 {
+		metrics.RentalLateFeesTotal.Add(fee)
+	}
+	event := rentalEvent(rental)
+	event["late_days"] = fmt.Sprint(late)
+	event["late_fee"] = fmt.Sprintf("%.2f", fee)
+	_ = s.eventPublisher.Publish("rental_returned", event)
+	return rentalToProto(rental), nil
+}
+
+func (s *RentalService) GetRentals(ctx context.Context, req *proto.GetRentalsRequest) (*proto.GetRentalsResponse, error) {
+	userID, err := primitive.ObjectIDFromHex(req.UserId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid user ID: %v", err)
+	}
+
+	rentals, err := s.rentals.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &proto.GetRentalsResponse{}
+	for i := range rentals {
+		resp.Rentals = append(resp.Rentals, rentalToProto(&rentals[i]))
+	}
+	return resp, nil
+}
+
+// CountRentalReferences считает экземпляры проката инструмента.
+// Экземпляры не удаляются, а договоры всегда ссылаются на экземпляр,
+// поэтому задаче очистки каталога достаточно этого числа.
+func (s *RentalService) CountRentalReferences(ctx context.Context, req *proto.CountRentalReferencesRequest) (*proto.CountRentalReferencesResponse, error) {
+	instrumentID, err := primitive.ObjectIDFromHex(req.InstrumentId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid instrument ID: %v", err)
+	}
+
+	units, err := s.units.CountByInstrument(ctx, instrumentID)
+	if err != nil {
+		return nil, err
+	}
+	return &proto.CountRentalReferencesResponse{Units: units}, nil
+}
+
+// instrumentRef проверяет, что инструмент есть в каталоге.
+func (s *RentalService) instrumentRef(ctx context.Context, idHex string) (primitive.ObjectID, error) {
+	id, err := primitive.ObjectIDFromHex(idHex)
+	if err != nil {
+		return primitive.NilObjectID, status.Errorf(codes.InvalidArgument, "invalid instrument ID: %v", err)
+	}
+	if _, err := s.instrumentClient.GetInstrumentByID(ctx, &instrumentsproto.GetInstrumentByIDRequest{Id: idHex}); err != nil {
+		return primitive.NilObjectID, status.Errorf(codes.NotFound, "инструмент %s не найден", idHex)
+	}
+	return id, nil
+}
+
+func (s *RentalService) findRental(ctx context.Context, idHex string) (*entity.Rental, error) {
+	id, err := primitive.ObjectIDFromHex(idHex)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid rental ID: %v", err)
+	}
+	rental, err := s.rentals.FindByID(ctx, id)
+	if err != nil {
+		return nil, rentalError(err)
+	}
+	return rental, nil
+}
+
+func rentalError(err error) error {
+	switch {
+	case errors.Is(err, repository.ErrPlanNotFound),
+		errors.Is(err, repository.ErrRentalNotFound),
+		errors.Is(err, repository.ErrUnitNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, repository.ErrUnitBusy):
+		return status.Error(codes.FailedPrecondition, "экземпляр занят в эти даты или не выдаётся")
+	case errors.Is(err, repository.ErrRentalChanged):
+		return status.Error(codes.Aborted, "аренда изменена, повторите запрос")
+	}
+	return err
+}
+
+func rentalEvent(rental *entity.Rental) map[string]string {
+	return map[string]string{
+		"rental_id":     rental.ID.Hex(),
+		"user_id":       rental.UserID.Hex(),
+		"instrument_id": rental.InstrumentID.Hex(),
+		"serial_number": rental.SerialNumber,
+		"end_date":      rental.EndDate.Format("2006-01-02"),
+	}
+}
+
+func planToProto(plan *entity.RentalPlan) *proto.RentalPlan {
+	return &proto.RentalPlan{
+		InstrumentId:  plan.InstrumentID.Hex(),
+		DailyRate:     plan.DailyRate,
+		MonthlyRate:   plan.MonthlyRate,
+		Deposit:       plan.Deposit,
+		LateFeePerDay: plan.LateFeePerDay,
+	}
+}
+
+func unitToProto(unit *entity.RentalUnit) *proto.RentalUnit {
+	return &proto.RentalUnit{
+		Id:           unit.ID.Hex(),
+		InstrumentId: unit.InstrumentID.Hex(),
+		SerialNumber: unit.SerialNumber,
+		Status:       unit.Status,
+	}
+}
+
+func rentalToProto(rental *entity.Rental) *proto.Rental {
+	resp := &proto.Rental{
+		Id:            rental.ID.Hex(),
+		UserId:        rental.UserID.Hex(),
+		InstrumentId:  rental.InstrumentID.Hex(),
+		UnitId:        rental.UnitID.Hex(),
+		SerialNumber:  rental.SerialNumber,
+		StartDate:     rental.StartDate.Unix(),
+		EndDate:       rental.EndDate.Unix(),
+		Status:        rental.Status,
+		Price:         rental.Price,
+		Deposit:       rental.Deposit,
+		LateFeePerDay: rental.LateFeePerDay,
+		LateFee:       rental.LateFee,
+		LateDays:      rental.LateDays,
+		CreatedAt:     rental.CreatedAt.Unix(),
+	}
+	if rental.ReturnedAt != nil {
+		resp.ReturnedAt = rental.ReturnedAt.Unix()
+	}
+	for _, ext := range rental.Extensions {
+		resp.Extensions = append(resp.Extensions, &proto.RentalExtension{
+			PreviousEndDate: ext.PreviousEndDate.Unix(),
+			EndDate:         ext.EndDate.Unix(),
+			Price:           ext.Price,
+			CreatedAt:       ext.CreatedAt.Unix(),
+		})
+	}
+	return resp
+}
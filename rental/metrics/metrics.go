@@ -0,0 +1,34 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	RentalsCreatedTotal = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "rentals_created_total",
+			Help: "Total number of rental agreements created",
+		},
+	)
+	RentalsOverdueTotal = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "rentals_overdue_total",
+			Help: "Total number of rentals that passed their return date",
+		},
+	)
+	RentalLateFeesTotal = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "rental_late_fees_total",
+			Help: "Total amount of late fees charged on returns",
+		},
+	)
+)
+
+func Register() {
+	prometheus.MustRegister(
+		RentalsCreatedTotal,
+		RentalsOverdueTotal,
+		RentalLateFeesTotal,
+	)
+}
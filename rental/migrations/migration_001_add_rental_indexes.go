@@ -0,0 +1,42 @@
+package migrations
+
+import (
+	"context"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func Migration001_AddRentalIndexes(db *mongo.Database) error {
+	ctx := context.Background()
+
+	_, err := db.Collection("rental_units").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "serial_number", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "instrument_id", Value: 1}},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Collection("rentals").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}},
+		},
+		{
+			// по нему задача напоминаний ищет подходящие сроки возврата
+			Keys: bson.D{{Key: "status", Value: 1}, {Key: "end_date", Value: 1}},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	log.Println("✅ Migration001_AddRentalIndexes applied")
+	return nil
+}
@@ -0,0 +1,429 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             v6.30.2
+// source: proto/rental.proto
+
+package proto
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// RentalServiceClient is the client API for RentalService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type RentalServiceClient interface {
+	SetRentalPlan(ctx context.Context, in *SetRentalPlanRequest, opts ...grpc.CallOption) (*RentalPlan, error)
+	GetRentalPlan(ctx context.Context, in *GetRentalPlanRequest, opts ...grpc.CallOption) (*RentalPlan, error)
+	AddRentalUnit(ctx context.Context, in *AddRentalUnitRequest, opts ...grpc.CallOption) (*RentalUnit, error)
+	SetRentalUnitStatus(ctx context.Context, in *SetRentalUnitStatusRequest, opts ...grpc.CallOption) (*RentalUnit, error)
+	GetAvailability(ctx context.Context, in *GetAvailabilityRequest, opts ...grpc.CallOption) (*GetAvailabilityResponse, error)
+	CreateRental(ctx context.Context, in *CreateRentalRequest, opts ...grpc.CallOption) (*Rental, error)
+	ExtendRental(ctx context.Context, in *ExtendRentalRequest, opts ...grpc.CallOption) (*Rental, error)
+	ReturnRental(ctx context.Context, in *ReturnRentalRequest, opts ...grpc.CallOption) (*Rental, error)
+	GetRentals(ctx context.Context, in *GetRentalsRequest, opts ...grpc.CallOption) (*GetRentalsResponse, error)
+	CountRentalReferences(ctx context.Context, in *CountRentalReferencesRequest, opts ...grpc.CallOption) (*CountRentalReferencesResponse, error)
+}
+
+type rentalServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewRentalServiceClient(cc grpc.ClientConnInterface) RentalServiceClient {
+	return &rentalServiceClient{cc}
+}
+
+func (c *rentalServiceClient) SetRentalPlan(ctx context.Context, in *SetRentalPlanRequest, opts ...grpc.CallOption) (*RentalPlan, error) {
+	out := new(RentalPlan)
+	err := c.cc.Invoke(ctx, "/rental.RentalService/SetRentalPlan", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rentalServiceClient) GetRentalPlan(ctx context.Context, in *GetRentalPlanRequest, opts ...grpc.CallOption) (*RentalPlan, error) {
+	out := new(RentalPlan)
+	err := c.cc.Invoke(ctx, "/rental.RentalService/GetRentalPlan", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rentalServiceClient) AddRentalUnit(ctx context.Context, in *AddRentalUnitRequest, opts ...grpc.CallOption) (*RentalUnit, error) {
+	out := new(RentalUnit)
+	err := c.cc.Invoke(ctx, "/rental.RentalService/AddRentalUnit", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rentalServiceClient) SetRentalUnitStatus(ctx context.Context, in *SetRentalUnitStatusRequest, opts ...grpc.CallOption) (*RentalUnit, error) {
+	out := new(RentalUnit)
+	err := c.cc.Invoke(ctx, "/rental.RentalService/SetRentalUnitStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rentalServiceClient) GetAvailability(ctx context.Context, in *GetAvailabilityRequest, opts ...grpc.CallOption) (*GetAvailabilityResponse, error) {
+	out := new(GetAvailabilityResponse)
+	err := c.cc.Invoke(ctx, "/rental.RentalService/GetAvailability", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rentalServiceClient) CreateRental(ctx context.Context, in *CreateRentalRequest, opts ...grpc.CallOption) (*Rental, error) {
+	out := new(Rental)
+	err := c.cc.Invoke(ctx, "/rental.RentalService/CreateRental", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rentalServiceClient) ExtendRental(ctx context.Context, in *ExtendRentalRequest, opts ...grpc.CallOption) (*Rental, error) {
+	out := new(Rental)
+	err := c.cc.Invoke(ctx, "/rental.RentalService/ExtendRental", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rentalServiceClient) ReturnRental(ctx context.Context, in *ReturnRentalRequest, opts ...grpc.CallOption) (*Rental, error) {
+	out := new(Rental)
+	err := c.cc.Invoke(ctx, "/rental.RentalService/ReturnRental", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rentalServiceClient) GetRentals(ctx context.Context, in *GetRentalsRequest, opts ...grpc.CallOption) (*GetRentalsResponse, error) {
+	out := new(GetRentalsResponse)
+	err := c.cc.Invoke(ctx, "/rental.RentalService/GetRentals", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rentalServiceClient) CountRentalReferences(ctx context.Context, in *CountRentalReferencesRequest, opts ...grpc.CallOption) (*CountRentalReferencesResponse, error) {
+	out := new(CountRentalReferencesResponse)
+	err := c.cc.Invoke(ctx, "/rental.RentalService/CountRentalReferences", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RentalServiceServer is the server API for RentalService service.
+// All implementations must embed UnimplementedRentalServiceServer
+// for forward compatibility
+type RentalServiceServer interface {
+	SetRentalPlan(context.Context, *SetRentalPlanRequest) (*RentalPlan, error)
+	GetRentalPlan(context.Context, *GetRentalPlanRequest) (*RentalPlan, error)
+	AddRentalUnit(context.Context, *AddRentalUnitRequest) (*RentalUnit, error)
+	SetRentalUnitStatus(context.Context, *SetRentalUnitStatusRequest) (*RentalUnit, error)
+	GetAvailability(context.Context, *GetAvailabilityRequest) (*GetAvailabilityResponse, error)
+	CreateRental(context.Context, *CreateRentalRequest) (*Rental, error)
+	ExtendRental(context.Context, *ExtendRentalRequest) (*Rental, error)
+	ReturnRental(context.Context, *ReturnRentalRequest) (*Rental, error)
+	GetRentals(context.Context, *GetRentalsRequest) (*GetRentalsResponse, error)
+	CountRentalReferences(context.Context, *CountRentalReferencesRequest) (*CountRentalReferencesResponse, error)
+	mustEmbedUnimplementedRentalServiceServer()
+}
+
+// UnimplementedRentalServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedRentalServiceServer struct {
+}
+
+func (UnimplementedRentalServiceServer) SetRentalPlan(context.Context, *SetRentalPlanRequest) (*RentalPlan, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetRentalPlan not implemented")
+}
+func (UnimplementedRentalServiceServer) GetRentalPlan(context.Context, *GetRentalPlanRequest) (*RentalPlan, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRentalPlan not implemented")
+}
+func (UnimplementedRentalServiceServer) AddRentalUnit(context.Context, *AddRentalUnitRequest) (*RentalUnit, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddRentalUnit not implemented")
+}
+func (UnimplementedRentalServiceServer) SetRentalUnitStatus(context.Context, *SetRentalUnitStatusRequest) (*RentalUnit, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetRentalUnitStatus not implemented")
+}
+func (UnimplementedRentalServiceServer) GetAvailability(context.Context, *GetAvailabilityRequest) (*GetAvailabilityResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAvailability not implemented")
+}
+func (UnimplementedRentalServiceServer) CreateRental(context.Context, *CreateRentalRequest) (*Rental, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateRental not implemented")
+}
+func (UnimplementedRentalServiceServer) ExtendRental(context.Context, *ExtendRentalRequest) (*Rental, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExtendRental not implemented")
+}
+func (UnimplementedRentalServiceServer) ReturnRental(context.Context, *ReturnRentalRequest) (*Rental, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReturnRental not implemented")
+}
+func (UnimplementedRentalServiceServer) GetRentals(context.Context, *GetRentalsRequest) (*GetRentalsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRentals not implemented")
+}
+func (UnimplementedRentalServiceServer) CountRentalReferences(context.Context, *CountRentalReferencesRequest) (*CountRentalReferencesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CountRentalReferences not implemented")
+}
+func (UnimplementedRentalServiceServer) mustEmbedUnimplementedRentalServiceServer() {}
+
+// UnsafeRentalServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to RentalServiceServer will
+// result in compilation errors.
+type UnsafeRentalServiceServer interface {
+	mustEmbedUnimplementedRentalServiceServer()
+}
+
+func RegisterRentalServiceServer(s grpc.ServiceRegistrar, srv RentalServiceServer) {
+	s.RegisterService(&RentalService_ServiceDesc, srv)
+}
+
+func _RentalService_SetRentalPlan_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetRentalPlanRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RentalServiceServer).SetRentalPlan(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rental.RentalService/SetRentalPlan",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RentalServiceServer).SetRentalPlan(ctx, req.(*SetRentalPlanRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RentalService_GetRentalPlan_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRentalPlanRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RentalServiceServer).GetRentalPlan(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rental.RentalService/GetRentalPlan",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RentalServiceServer).GetRentalPlan(ctx, req.(*GetRentalPlanRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RentalService_AddRentalUnit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddRentalUnitRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RentalServiceServer).AddRentalUnit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rental.RentalService/AddRentalUnit",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RentalServiceServer).AddRentalUnit(ctx, req.(*AddRentalUnitRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RentalService_SetRentalUnitStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetRentalUnitStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RentalServiceServer).SetRentalUnitStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rental.RentalService/SetRentalUnitStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RentalServiceServer).SetRentalUnitStatus(ctx, req.(*SetRentalUnitStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RentalService_GetAvailability_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAvailabilityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RentalServiceServer).GetAvailability(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rental.RentalService/GetAvailability",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RentalServiceServer).GetAvailability(ctx, req.(*GetAvailabilityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RentalService_CreateRental_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateRentalRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RentalServiceServer).CreateRental(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rental.RentalService/CreateRental",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RentalServiceServer).CreateRental(ctx, req.(*CreateRentalRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RentalService_ExtendRental_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExtendRentalRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RentalServiceServer).ExtendRental(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rental.RentalService/ExtendRental",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RentalServiceServer).ExtendRental(ctx, req.(*ExtendRentalRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RentalService_ReturnRental_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReturnRentalRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RentalServiceServer).ReturnRental(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rental.RentalService/ReturnRental",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RentalServiceServer).ReturnRental(ctx, req.(*ReturnRentalRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RentalService_GetRentals_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRentalsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RentalServiceServer).GetRentals(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rental.RentalService/GetRentals",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RentalServiceServer).GetRentals(ctx, req.(*GetRentalsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RentalService_CountRentalReferences_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CountRentalReferencesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RentalServiceServer).CountRentalReferences(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rental.RentalService/CountRentalReferences",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RentalServiceServer).CountRentalReferences(ctx, req.(*CountRentalReferencesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// RentalService_ServiceDesc is the grpc.ServiceDesc for RentalService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var RentalService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "rental.RentalService",
+	HandlerType: (*RentalServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SetRentalPlan",
+			Handler:    _RentalService_SetRentalPlan_Handler,
+		},
+		{
+			MethodName: "GetRentalPlan",
+			Handler:    _RentalService_GetRentalPlan_Handler,
+		},
+		{
+			MethodName: "AddRentalUnit",
+			Handler:    _RentalService_AddRentalUnit_Handler,
+		},
+		{
+			MethodName: "SetRentalUnitStatus",
+			Handler:    _RentalService_SetRentalUnitStatus_Handler,
+		},
+		{
+			MethodName: "GetAvailability",
+			Handler:    _RentalService_GetAvailability_Handler,
+		},
+		{
+			MethodName: "CreateRental",
+			Handler:    _RentalService_CreateRental_Handler,
+		},
+		{
+			MethodName: "ExtendRental",
+			Handler:    _RentalService_ExtendRental_Handler,
+		},
+		{
+			MethodName: "ReturnRental",
+			Handler:    _RentalService_ReturnRental_Handler,
+		},
+		{
+			MethodName: "GetRentals",
+			Handler:    _RentalService_GetRentals_Handler,
+		},
+		{
+			MethodName: "CountRentalReferences",
+			Handler:    _RentalService_CountRentalReferences_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proto/rental.proto",
+}
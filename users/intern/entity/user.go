@@ -2,6 +2,19 @@ package entity
 
 import "go.mongodb.org/mongo-driver/bson/primitive"
 
+type Address struct {
+	ID         primitive.ObjectID `bson:"_id"`
+	FullName   string             `bson:"full_name"`
+	Phone      string             `bson:"phone"`
+	Line1      string             `bson:"line1"`
+	Line2      string             `bson:"line2,omitempty"`
+	City       string             `bson:"city"`
+	Region     string             `bson:"region,omitempty"`
+	PostalCode string             `bson:"postal_code"`
+	Country    string             `bson:"country"`
+	IsDefault  bool               `bson:"is_default"`
+}
+
 type User struct {
 	ID        primitive.ObjectID `bson:"_id,omitempty"`
 	Username  string             `bson:"username"`
@@ -10,4 +23,27 @@ type User struct {
 	CreatedAt int64              `bson:"created_at"`
 	UpdatedAt int64              `bson:"updated_at,omitempty"`
 	Confirmed bool               `bson:"confirmed"`
+	Addresses []Address          `bson:"addresses,omitempty"`
+	// Отказ от писем о брошенной корзине
+	CartRemindersOptOut bool `bson:"cart_reminders_opt_out,omitempty"`
+	// Версия профиля для оптимистической блокировки, растёт при
+	// каждом изменении имени, email или пароля
+	Version int64 `bson:"version"`
+	// Время мягкого удаления (Unix); удалённый пользователь не может
+	// войти, пока его не восстановят или не удалит задача очистки
+	DeletedAt int64 `bson:"deleted_at,omitempty"`
+	// Баланс счёта в магазине; меняется только вместе с записью в
+	// store_credit_transactions
+	StoreCredit float64 `bson:"store_credit,omitempty"`
+}
+
+// StoreCreditTransaction — начисление на счёт пользователя. Reference
+// уникален для пользователя, поэтому одно основание начисляется один раз.
+type StoreCreditTransaction struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	UserID    primitive.ObjectID `bson:"user_id"`
+	Amount    float64            `bson:"amount"`
+	Reason    string             `bson:"reason"`
+	Reference string             `bson:"reference"`
+	CreatedAt int64              `bson:"created_at"`
 }
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"gotune/users/intern/entity"
+)
+
+// ErrCreditApplied — начисление с этим reference уже было.
+var ErrCreditApplied = errors.New("начисление уже выполнено")
+
+type StoreCreditRepository interface {
+	Add(ctx context.Context, tx *entity.StoreCreditTransaction) (float64, error)
+	Balance(ctx context.Context, userID primitive.ObjectID) (float64, error)
+	List(ctx context.Context, userID primitive.ObjectID, limit int64) ([]entity.StoreCreditTransaction, error)
+}
+
+type storeCreditRepository struct {
+	users        *mongo.Collection
+	transactions *mongo.Collection
+}
+
+func NewStoreCreditRepository(db *mongo.Database) StoreCreditRepository {
+	return &storeCreditRepository{
+		users:        db.Collection("users"),
+		transactions: db.Collection("store_credit_transactions"),
+	}
+}
+
+// Add записывает начисление и увеличивает баланс пользователя в одной
+// транзакции: запись в журнале и баланс не расходятся, если одна из
+// операций не прошла. Повтор с тем же reference отклоняет уникальный
+// индекс, и баланс не меняется дважды.
+func (r *storeCreditRepository) Add(ctx context.Context, tx *entity.StoreCreditTransaction) (float64, error) {
+	session, err := r.users.Database().Client().StartSession()
+	if err != nil {
+		return 0, err
+	}
+	defer session.EndSession(ctx)
+
+	if tx.ID.IsZero() {
+		tx.ID = primitive.NewObjectID()
+	}
+
+	balance, err := session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		_, err := r.transactions.InsertOne(sessCtx, tx)
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, ErrCreditApplied
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var user entity.User
+		err = r.users.FindOneAndUpdate(sessCtx,
+			notDeleted(bson.M{"_id": tx.UserID}),
+			bson.M{"$inc": bson.M{"store_credit": tx.Amount}},
+			options.FindOneAndUpdate().SetReturnDocument(options.After).SetProjection(bson.M{"store_credit": 1}),
+		).Decode(&user)
+		if err != nil {
+			// пользователь удалён между проверкой и начислением; запись в
+			// журнале откатывается вместе с транзакцией
+			return nil, err
+		}
+		return user.StoreCredit, nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return balance.(float64), nil
+}
+
+func (r *storeCreditRepository) Balance(ctx context.Context, userID primitive.ObjectID) (float64, error) {
+	var user entity.User
+	err := r.users.FindOne(ctx,
+		notDeleted(bson.M{"_id": userID}),
+		options.FindOne().SetProjection(bson.M{"store_credit": 1}),
+	).Decode(&user)
+	if err != nil {
+		return 0, err
+	}
+	return user.StoreCredit, nil
+}
+
+func (r *storeCreditRepository) List(ctx context.Context, userID primitive.ObjectID, limit int64) ([]entity.StoreCreditTransaction, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetLimit(limit)
+	cursor, err := r.transactions.Find(ctx, bson.M{"user_id": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var txs []entity.StoreCreditTransaction
+	if err := cursor.All(ctx, &txs); err != nil {
+		return nil, err
+	}
+	return txs, nil
+}
@@ -2,27 +2,48 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"gotune/users/intern/entity"
 )
 
+// ErrVersionConflict — профиль изменён после того, как его прочитал клиент.
+var ErrVersionConflict = errors.New("версия пользователя устарела")
+
 type UserRepository interface {
 	Create(ctx mongo.SessionContext, user *entity.User) error
 	FindByEmail(ctx context.Context, email string) (*entity.User, error)
 	GetAll(ctx context.Context) ([]entity.User, error)
 	FindByID(ctx context.Context, id string) (*entity.User, error)
-	Update(ctx context.Context, user *entity.User) error
-	Delete(ctx context.Context, id string) error
+	Update(ctx context.Context, user *entity.User, fields ...string) error
+	SoftDelete(ctx context.Context, id string, deletedAt time.Time) error
+	Restore(ctx context.Context, id string) error
+	FindDeletedBefore(ctx context.Context, before time.Time, afterID primitive.ObjectID, limit int64) ([]entity.User, error)
+	Purge(ctx context.Context, id primitive.ObjectID) error
 	DeleteAll(ctx context.Context) error // Новый метод
 	ConfirmUser(ctx context.Context, id string) error
+	AddAddress(ctx context.Context, id string, address *entity.Address) error
+	UpdateAddress(ctx context.Context, id string, address *entity.Address) error
+	DeleteAddress(ctx context.Context, id string, addressID primitive.ObjectID) error
+	SetDefaultAddress(ctx context.Context, id string, addressID primitive.ObjectID) error
+	SetCartRemindersOptOut(ctx context.Context, id string, optOut bool) error
 	GetDatabase() *mongo.Database
 }
 
+// notDeleted дополняет фильтр условием, исключающим удалённых
+// пользователей.
+func notDeleted(filter bson.M) bson.M {
+	filter["deleted_at"] = bson.M{"$exists": false}
+	return filter
+}
+
 type userRepository struct {
 	collection *mongo.Collection
 	db         *mongo.Database
@@ -45,7 +66,7 @@ func (r *userRepository) Create(ctx mongo.SessionContext, user *entity.User) err
 
 func (r *userRepository) FindByEmail(ctx context.Context, email string) (*entity.User, error) {
 	var user entity.User
-	err := r.collection.FindOne(ctx, bson.M{"email": email}).Decode(&user)
+	err := r.collection.FindOne(ctx, notDeleted(bson.M{"email": email})).Decode(&user)
 	if err != nil {
 		return nil, err
 	}
@@ -53,7 +74,7 @@ func (r *userRepository) FindByEmail(ctx context.Context, email string) (*entity
 }
 
 func (r *userRepository) GetAll(ctx context.Context) ([]entity.User, error) {
-	cursor, err := r.collection.Find(ctx, bson.M{})
+	cursor, err := r.collection.Find(ctx, notDeleted(bson.M{}))
 	if err != nil {
 		return nil, err
 	}
@@ -81,40 +102,130 @@ func (r *userRepository) FindByID(ctx context.Context, id string) (*entity.User,
 	}
 
 	var user entity.User
-	err = r.collection.FindOne(ctx, bson.M{"_id": objID}).Decode(&user)
+	err = r.collection.FindOne(ctx, notDeleted(bson.M{"_id": objID})).Decode(&user)
 	if err != nil {
 		return nil, err
 	}
 	return &user, nil
 }
 
-func (r *userRepository) Update(ctx context.Context, user *entity.User) error {
+// Update записывает перечисленные поля пользователя (username, email,
+// password); без fields записываются все три. Запись проходит, только
+// если версия в базе равна user.Version, после неё user.Version
+// увеличивается.
+func (r *userRepository) Update(ctx context.Context, user *entity.User, fields ...string) error {
 	objID, err := primitive.ObjectIDFromHex(user.ID.Hex())
 	if err != nil {
 		return err
 	}
 
-	update := bson.M{
-		"$set": bson.M{
-			"username":   user.Username,
-			"email":      user.Email,
-			"password":   user.Password,
-			"updated_at": time.Now().Unix(),
-		},
+	values := bson.M{
+		"username": user.Username,
+		"email":    user.Email,
+		"password": user.Password,
+	}
+	set := bson.M{"updated_at": time.Now().Unix()}
+	if len(fields) == 0 {
+		fields = []string{"username", "email", "password"}
+	}
+	for _, f := range fields {
+		v, ok := values[f]
+		if !ok {
+			return fmt.Errorf("поле %q нельзя обновить", f)
+		}
+		set[f] = v
 	}
 
-	_, err = r.collection.UpdateOne(ctx, bson.M{"_id": objID}, update)
-	return err
+	res, err := r.collection.UpdateOne(ctx,
+		notDeleted(bson.M{"_id": objID, "version": user.Version}),
+		bson.M{"$set": set, "$inc": bson.M{"version": 1}},
+	)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrVersionConflict
+	}
+	user.Version++
+	return nil
 }
 
-func (r *userRepository) Delete(ctx context.Context, id string) error {
+// SoftDelete помечает пользователя удалённым: он не может войти и не
+// находится обычными запросами, но его заказы продолжают на него
+// ссылаться.
+func (r *userRepository) SoftDelete(ctx context.Context, id string, deletedAt time.Time) error {
 	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
 		return err
 	}
 
-	_, err = r.collection.DeleteOne(ctx, bson.M{"_id": objID})
-	return err
+	update := bson.M{
+		"$set": bson.M{"deleted_at": deletedAt.Unix()},
+		"$inc": bson.M{"version": 1},
+	}
+	res, err := r.collection.UpdateOne(ctx, notDeleted(bson.M{"_id": objID}), update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+func (r *userRepository) Restore(ctx context.Context, id string) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	update := bson.M{
+		"$unset": bson.M{"deleted_at": ""},
+		"$set":   bson.M{"updated_at": time.Now().Unix()},
+		"$inc":   bson.M{"version": 1},
+	}
+	res, err := r.collection.UpdateOne(ctx, bson.M{"_id": objID, "deleted_at": bson.M{"$exists": true}}, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// FindDeletedBefore возвращает пользователей, удалённых раньше before,
+// по возрастанию _id начиная после afterID.
+func (r *userRepository) FindDeletedBefore(ctx context.Context, before time.Time, afterID primitive.ObjectID, limit int64) ([]entity.User, error) {
+	filter := bson.M{
+		"_id":        bson.M{"$gt": afterID},
+		"deleted_at": bson.M{"$lt": before.Unix()},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}).SetLimit(limit)
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var users []entity.User
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// Purge окончательно удаляет пользователя, только если он помечен
+// удалённым.
+func (r *userRepository) Purge(ctx context.Context, id primitive.ObjectID) error {
+	res, err := r.collection.DeleteOne(ctx, bson.M{"_id": id, "deleted_at": bson.M{"$exists": true}})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
 }
 func (r *userRepository) DeleteAll(ctx context.Context) error {
 	_, err := r.collection.DeleteMany(ctx, bson.M{})
@@ -133,6 +244,135 @@ func (r *userRepository) ConfirmUser(ctx context.Context, id string) error {
 		},
 	}
 
-	_, err = r.collection.UpdateOne(ctx, bson.M{"_id": objID}, update)
+	_, err = r.collection.UpdateOne(ctx, notDeleted(bson.M{"_id": objID}), update)
 	return err
 }
+
+func (r *userRepository) SetCartRemindersOptOut(ctx context.Context, id string, optOut bool) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"cart_reminders_opt_out": optOut,
+			"updated_at":             time.Now().Unix(),
+		},
+	}
+
+	res, err := r.collection.UpdateOne(ctx, notDeleted(bson.M{"_id": objID}), update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+func (r *userRepository) AddAddress(ctx context.Context, id string, address *entity.Address) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	update := bson.M{
+		"$push": bson.M{"addresses": address},
+		"$set":  bson.M{"updated_at": time.Now().Unix()},
+	}
+
+	res, err := r.collection.UpdateOne(ctx, notDeleted(bson.M{"_id": objID}), update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// UpdateAddress заменяет поля адреса, не трогая признак адреса по умолчанию —
+// он меняется только через SetDefaultAddress.
+func (r *userRepository) UpdateAddress(ctx context.Context, id string, address *entity.Address) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	filter := notDeleted(bson.M{"_id": objID, "addresses._id": address.ID})
+	update := bson.M{
+		"$set": bson.M{
+			"addresses.$.full_name":   address.FullName,
+			"addresses.$.phone":       address.Phone,
+			"addresses.$.line1":       address.Line1,
+			"addresses.$.line2":       address.Line2,
+			"addresses.$.city":        address.City,
+			"addresses.$.region":      address.Region,
+			"addresses.$.postal_code": address.PostalCode,
+			"addresses.$.country":     address.Country,
+			"updated_at":              time.Now().Unix(),
+		},
+	}
+
+	res, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+func (r *userRepository) DeleteAddress(ctx context.Context, id string, addressID primitive.ObjectID) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	filter := notDeleted(bson.M{"_id": objID, "addresses._id": addressID})
+	update := bson.M{
+		"$pull": bson.M{"addresses": bson.M{"_id": addressID}},
+		"$set":  bson.M{"updated_at": time.Now().Unix()},
+	}
+
+	res, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// SetDefaultAddress одним обновлением снимает признак по умолчанию
+// с остальных адресов и ставит его выбранному.
+func (r *userRepository) SetDefaultAddress(ctx context.Context, id string, addressID primitive.ObjectID) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	filter := notDeleted(bson.M{"_id": objID, "addresses._id": addressID})
+	update := bson.M{
+		"$set": bson.M{
+			"addresses.$[other].is_default":  false,
+			"addresses.$[target].is_default": true,
+			"updated_at":                     time.Now().Unix(),
+		},
+	}
+	opts := options.Update().SetArrayFilters(options.ArrayFilters{Filters: []interface{}{
+		bson.M{"other._id": bson.M{"$ne": addressID}},
+		bson.M{"target._id": addressID},
+	}})
+	res, err := r.collection.UpdateOne(ctx, filter, update, opts)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"gotune/users/intern/entity"
+	"gotune/users/proto"
+)
+
+func (s *UserService) AddAddress(ctx context.Context, req *proto.AddAddressRequest) (*proto.AddAddressResponse, error) {
+	if err := validateAddress(req.Address); err != nil {
+		return nil, err
+	}
+
+	user, err := s.repo.FindByID(ctx, req.UserId)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "User not found")
+	}
+
+	address := addressFromProto(req.Address)
+	address.ID = primitive.NewObjectID()
+	// Первый адрес в книге автоматически становится адресом по умолчанию
+	address.IsDefault = len(user.Addresses) == 0
+
+	if err := s.repo.AddAddress(ctx, req.UserId, address); err != nil {
+		return nil, err
+	}
+
+	if req.Address.IsDefault && !address.IsDefault {
+		if err := s.repo.SetDefaultAddress(ctx, req.UserId, address.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	return &proto.AddAddressResponse{AddressId: address.ID.Hex()}, nil
+}
+
+func (s *UserService) GetAddresses(ctx context.Context, req *proto.GetAddressesRequest) (*proto.GetAddressesResponse, error) {
+	user, err := s.repo.FindByID(ctx, req.UserId)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "User not found")
+	}
+
+	var addresses []*proto.Address
+	for _, a := range user.Addresses {
+		addresses = append(addresses, addressToProto(a))
+	}
+
+	return &proto.GetAddressesResponse{Addresses: addresses}, nil
+}
+
+func (s *UserService) UpdateAddress(ctx context.Context, req *proto.UpdateAddressRequest) (*proto.UpdateAddressResponse, error) {
+	if err := validateAddress(req.Address); err != nil {
+		return nil, err
+	}
+
+	addressID, err := primitive.ObjectIDFromHex(req.Address.Id)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid address ID")
+	}
+
+	address := addressFromProto(req.Address)
+	address.ID = addressID
+
+	if err := s.repo.UpdateAddress(ctx, req.UserId, address); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, status.Errorf(codes.NotFound, "Address not found")
+		}
+		return nil, err
+	}
+
+	// Снять признак нельзя — адрес по умолчанию должен быть всегда,
+	// поэтому is_default=false означает «не менять».
+	if req.Address.IsDefault {
+		if err := s.repo.SetDefaultAddress(ctx, req.UserId, addressID); err != nil {
+			return nil, err
+		}
+	}
+
+	return &proto.UpdateAddressResponse{Success: true}, nil
+}
+
+func (s *UserService) DeleteAddress(ctx context.Context, req *proto.DeleteAddressRequest) (*proto.DeleteAddressResponse, error) {
+	addressID, err := primitive.ObjectIDFromHex(req.AddressId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid address ID")
+	}
+
+	user, err := s.repo.FindByID(ctx, req.UserId)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "User not found")
+	}
+
+	if err := s.repo.DeleteAddress(ctx, req.UserId, addressID); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, status.Errorf(codes.NotFound, "Address not found")
+		}
+		return nil, err
+	}
+
+	// Если удалили адрес по умолчанию, им становится первый из оставшихся
+	if wasDefault(user.Addresses, addressID) {
+		for _, a := range user.Addresses {
+			if a.ID == addressID {
+				continue
+			}
+			if err := s.repo.SetDefaultAddress(ctx, req.UserId, a.ID); err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
+
+	return &proto.DeleteAddressResponse{Success: true}, nil
+}
+
+func (s *UserService) SetDefaultAddress(ctx context.Context, req *proto.SetDefaultAddressRequest) (*proto.SetDefaultAddressResponse, error) {
+	addressID, err := primitive.ObjectIDFromHex(req.AddressId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid address ID")
+	}
+
+	if err := s.repo.SetDefaultAddress(ctx, req.UserId, addressID); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, status.Errorf(codes.NotFound, "Address not found")
+		}
+		return nil, err
+	}
+
+	return &proto.SetDefaultAddressResponse{Success: true}, nil
+}
+
+func wasDefault(addresses []entity.Address, id primitive.ObjectID) bool {
+	for _, a := range addresses {
+		if a.ID == id {
+			return a.IsDefault
+		}
+	}
+	return false
+}
+
+func validateAddress(a *proto.Address) error {
+	if a == nil {
+		return status.Errorf(codes.InvalidArgument, "address is required")
+	}
+	if a.FullName == "" || a.Line1 == "" || a.City == "" || a.PostalCode == "" || a.Country == "" {
+		return status.Errorf(codes.InvalidArgument, "full_name, line1, city, postal_code and country are required")
+	}
+	return nil
+}
+
+func addressFromProto(a *proto.Address) *entity.Address {
+	return &entity.Address{
+		FullName:   a.FullName,
+		Phone:      a.Phone,
+		Line1:      a.Line1,
+		Line2:      a.Line2,
+		City:       a.City,
+		Region:     a.Region,
+		PostalCode: a.PostalCode,
+		Country:    a.Country,
+	}
+}
+
+func addressToProto(a entity.Address) *proto.Address {
+	return &proto.Address{
+		Id:         a.ID.Hex(),
+		FullName:   a.FullName,
+		Phone:      a.Phone,
+		Line1:      a.Line1,
+		Line2:      a.Line2,
+		City:       a.City,
+		Region:     a.Region,
+		PostalCode: a.PostalCode,
+		Country:    a.Country,
+		IsDefault:  a.IsDefault,
+	}
+}
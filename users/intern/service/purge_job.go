@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	orderproto "gotune/order/proto"
+	"gotune/users/intern/repository"
+)
+
+const purgeBatchSize = 100
+
+// PurgeJob окончательно удаляет пользователей, удалённых дольше retention
+// назад. Пользователи с заказами остаются помеченными удалёнными: на них
+// ссылаются заказы и счета.
+type PurgeJob struct {
+	repo        repository.UserRepository
+	orderClient orderproto.OrderServiceClient
+	retention   time.Duration
+	interval    time.Duration
+}
+
+func NewPurgeJob(repo repository.UserRepository, orderClient orderproto.OrderServiceClient, retention, interval time.Duration) *PurgeJob {
+	return &PurgeJob{
+		repo:        repo,
+		orderClient: orderClient,
+		retention:   retention,
+		interval:    interval,
+	}
+}
+
+func (j *PurgeJob) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		if n, err := j.RunOnce(ctx); err != nil {
+			log.Printf("Ошибка очистки удалённых пользователей: %v", err)
+		} else if n > 0 {
+			log.Printf("Удалено пользователей: %d", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (j *PurgeJob) RunOnce(ctx context.Context) (int, error) {
+	before := time.Now().Add(-j.retention)
+	purged := 0
+	afterID := primitive.NilObjectID
+
+	for {
+		users, err := j.repo.FindDeletedBefore(ctx, before, afterID, purgeBatchSize)
+		if err != nil {
+			return purged, err
+		}
+
+		for _, user := range users {
+			afterID = user.ID
+
+			refs, err := j.orderClient.CountOrderReferences(ctx, &orderproto.CountOrderReferencesRequest{
+				UserId: user.ID.Hex(),
+			})
+			if err != nil {
+				return purged, err
+			}
+			if refs.Orders > 0 {
+				continue
+			}
+
+			err = j.repo.Purge(ctx, user.ID)
+			if err == mongo.ErrNoDocuments {
+				// восстановлен после выборки
+				continue
+			}
+			if err != nil {
+				return purged, err
+			}
+			purged++
+		}
+
+		if len(users) < purgeBatchSize {
+			return purged, nil
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"gotune/users/intern/entity"
+	"gotune/users/intern/repository"
+	"gotune/users/proto"
+)
+
+const storeCreditHistoryLimit = 100
+
+// AddStoreCredit начисляет сумму на счёт пользователя в магазине.
+// Повторный запрос с тем же reference возвращает текущий баланс без
+// начисления.
+func (s *UserService) AddStoreCredit(ctx context.Context, req *proto.AddStoreCreditRequest) (*proto.AddStoreCreditResponse, error) {
+	userID, err := primitive.ObjectIDFromHex(req.UserId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid user ID")
+	}
+	if err := validateStoreCredit(req); err != nil {
+		return nil, err
+	}
+
+	balance, err := s.credits.Add(ctx, &entity.StoreCreditTransaction{
+		UserID:    userID,
+		Amount:    math.Round(req.Amount*100) / 100,
+		Reason:    strings.TrimSpace(req.Reason),
+		Reference: strings.TrimSpace(req.Reference),
+		CreatedAt: time.Now().Unix(),
+	})
+	if errors.Is(err, repository.ErrCreditApplied) {
+		balance, err = s.credits.Balance(ctx, userID)
+		if err != nil {
+			return nil, storeCreditError(err)
+		}
+		return &proto.AddStoreCreditResponse{Balance: balance}, nil
+	}
+	if err != nil {
+		return nil, storeCreditError(err)
+	}
+
+	_ = s.eventPublisher.Publish("store_credit_added", map[string]string{
+		"user_id":   req.UserId,
+		"amount":    fmt.Sprintf("%.2f", req.Amount),
+		"reason":    req.Reason,
+		"reference": req.Reference,
+		"balance":   fmt.Sprintf("%.2f", balance),
+	})
+
+	return &proto.AddStoreCreditResponse{Balance: balance, Applied: true}, nil
+}
+
+func (s *UserService) GetStoreCredit(ctx context.Context, req *proto.GetStoreCreditRequest) (*proto.GetStoreCreditResponse, error) {
+	userID, err := primitive.ObjectIDFromHex(req.UserId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid user ID")
+	}
+
+	balance, err := s.credits.Balance(ctx, userID)
+	if err != nil {
+		return nil, storeCreditError(err)
+	}
+	txs, err := s.credits.List(ctx, userID, storeCreditHistoryLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &proto.GetStoreCreditResponse{Balance: balance}
+	for _, tx := range txs {
+		resp.Transactions = append(resp.Transactions, &proto.StoreCreditTransaction{
+			Id:        tx.ID.Hex(),
+			Amount:    tx.Amount,
+			Reason:    tx.Reason,
+			Reference: tx.Reference,
+			CreatedAt: tx.CreatedAt,
+		})
+	}
+	return resp, nil
+}
+
+func validateStoreCredit(req *proto.AddStoreCreditRequest) error {
+	if req.Amount <= 0 || math.IsInf(req.Amount, 0) || math.IsNaN(req.Amount) {
+		return status.Errorf(codes.InvalidArgument, "сумма начисления должна быть положительной")
+	}
+	if strings.TrimSpace(req.Reason) == "" {
+		return status.Errorf(codes.InvalidArgument, "не указана причина начисления")
+	}
+	if strings.TrimSpace(req.Reference) == "" {
+		return status.Errorf(codes.InvalidArgument, "не указано основание начисления")
+	}
+	return nil
+}
+
+func storeCreditError(err error) error {
+	if err == mongo.ErrNoDocuments {
+		return status.Errorf(codes.NotFound, "User not found")
+	}
+	return err
+}
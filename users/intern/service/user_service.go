@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"math/rand"
@@ -10,6 +11,7 @@ import (
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/redis/go-redis/v9"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -36,6 +38,7 @@ type EmailSender interface {
 
 type UserService struct {
 	repo           repository.UserRepository
+	credits        repository.StoreCreditRepository
 	eventPublisher *events.EventPublisher
 	cache          *redis.Client
 	emailSender    EmailSender
@@ -50,12 +53,14 @@ type TestUserService struct {
 
 func NewUserService(
 	repo repository.UserRepository,
+	credits repository.StoreCreditRepository,
 	eventPublisher *events.EventPublisher,
 	cache *redis.Client,
 	emailSender EmailSender,
 ) *UserService {
 	return &UserService{
 		repo:           repo,
+		credits:        credits,
 		eventPublisher: eventPublisher,
 		cache:          cache,
 		emailSender:    emailSender,
@@ -96,9 +101,15 @@ func (s *UserService) RegisterUser(ctx context.Context, req *proto.RegisterUserR
 			Username: req.Username,
 			Email:    req.Email,
 			Password: hashedPassword,
+			Version:  1,
 		}
 
-		if err := s.repo.Create(sessCtx, user); err != nil {
+		err = s.repo.Create(sessCtx, user)
+		if mongo.IsDuplicateKeyError(err) {
+			// проверку FindByEmail обогнала параллельная регистрация
+			return nil, status.Errorf(codes.AlreadyExists, "Email уже зарегистрирован")
+		}
+		if err != nil {
 			return nil, err
 		}
 
@@ -148,7 +159,8 @@ func (s *UserService) LoginUser(ctx context.Context, req *proto.LoginUserRequest
 	token := "fake-jwt-token-for-" + user.ID.Hex()
 
 	return &proto.LoginUserResponse{
-		Token: token,
+		Token:  token,
+		UserId: user.ID.Hex(),
 	}, nil
 }
 
@@ -202,9 +214,11 @@ func (s *UserService) GetUser(ctx context.Context, req *proto.GetUserRequest) (*
 	}
 
 	resp := &proto.GetUserResponse{
-		UserId:   user.ID.Hex(),
-		Username: user.Username,
-		Email:    user.Email,
+		UserId:              user.ID.Hex(),
+		Username:            user.Username,
+		Email:               user.Email,
+		CartRemindersOptOut: user.CartRemindersOptOut,
+		Version:             user.Version,
 	}
 
 	data, _ := json.Marshal(resp)
@@ -213,39 +227,60 @@ func (s *UserService) GetUser(ctx context.Context, req *proto.GetUserRequest) (*
 	return resp, nil
 }
 
+// UpdateUser с маской обновляет только перечисленные поля, без маски —
+// только непустые поля запроса. Версия профиля должна совпадать
+// с expected_version, иначе ABORTED.
 func (s *UserService) UpdateUser(ctx context.Context, req *proto.UpdateUserRequest) (*proto.UpdateUserResponse, error) {
+	paths, err := userUpdatePaths(req)
+	if err != nil {
+		return nil, err
+	}
+	if req.ExpectedVersion <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "не указана версия профиля")
+	}
 	user, err := s.repo.FindByID(ctx, req.UserId)
 	if err != nil {
 		return nil, status.Errorf(codes.NotFound, "User not found")
 	}
-	if req.Username != "" {
-		user.Username = req.Username
+	if user.Version != req.ExpectedVersion {
+		return nil, userVersionConflict()
 	}
 
-	if req.Email != "" {
-		existingUser, err := s.repo.FindByEmail(ctx, req.Email)
-		if err == nil && existingUser.ID.Hex() != req.UserId {
-			return nil, status.Errorf(codes.AlreadyExists, "Email already registered")
+	for _, p := range paths {
+		switch p {
+		case "username":
+			user.Username = req.Username
+		case "email":
+			existingUser, err := s.repo.FindByEmail(ctx, req.Email)
+			if err == nil && existingUser.ID.Hex() != req.UserId {
+				return nil, status.Errorf(codes.AlreadyExists, "Email already registered")
+			}
+			user.Email = req.Email
+		case "password":
+			hashedPassword, err := hash.HashPassword(req.Password)
+			if err != nil {
+				return nil, err
+			}
+			user.Password = hashedPassword
 		}
-		user.Email = req.Email
 	}
 
-	if req.Password != "" {
-		hashedPassword, err := hash.HashPassword(req.Password)
+	if len(paths) > 0 {
+		err := s.repo.Update(ctx, user, paths...)
+		if errors.Is(err, repository.ErrVersionConflict) {
+			return nil, userVersionConflict()
+		}
 		if err != nil {
 			return nil, err
 		}
-		user.Password = hashedPassword
-	}
-
-	if err := s.repo.Update(ctx, user); err != nil {
-		return nil, err
 	}
 
 	resp := &proto.GetUserResponse{
-		UserId:   user.ID.Hex(),
-		Username: user.Username,
-		Email:    user.Email,
+		UserId:              user.ID.Hex(),
+		Username:            user.Username,
+		Email:               user.Email,
+		CartRemindersOptOut: user.CartRemindersOptOut,
+		Version:             user.Version,
 	}
 	data, _ := json.Marshal(resp)
 	s.cache.Set(ctx, userCacheKeyPrefix+req.UserId, data, userCacheExpiration)
@@ -257,20 +292,68 @@ func (s *UserService) UpdateUser(ctx context.Context, req *proto.UpdateUserReque
 
 	return &proto.UpdateUserResponse{
 		Success: true,
+		Version: user.Version,
 	}, nil
 }
 
+func userVersionConflict() error {
+	return status.Errorf(codes.Aborted, "профиль изменён, обновите данные и повторите")
+}
+
+// userUpdatePaths возвращает обновляемые поля. Поля из маски не могут
+// быть пустыми: очистить имя, email или пароль нельзя.
+func userUpdatePaths(req *proto.UpdateUserRequest) ([]string, error) {
+	values := map[string]string{
+		"username": req.Username,
+		"email":    req.Email,
+		"password": req.Password,
+	}
+
+	var paths []string
+	if len(req.GetUpdateMask().GetPaths()) == 0 {
+		for _, p := range []string{"username", "email", "password"} {
+			if values[p] != "" {
+				paths = append(paths, p)
+			}
+		}
+		return paths, nil
+	}
+
+	seen := map[string]bool{}
+	for _, p := range req.UpdateMask.Paths {
+		v, ok := values[p]
+		if !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "поле %q нельзя обновить", p)
+		}
+		if v == "" {
+			return nil, status.Errorf(codes.InvalidArgument, "поле %q не может быть пустым", p)
+		}
+		if !seen[p] {
+			seen[p] = true
+			paths = append(paths, p)
+		}
+	}
+	return paths, nil
+}
+
+// DeleteUser помечает пользователя удалённым; заказы продолжают ссылаться
+// на него, окончательно запись удаляет PurgeJob.
 func (s *UserService) DeleteUser(ctx context.Context, req *proto.DeleteUserRequest) (*proto.DeleteUserResponse, error) {
 	user, err := s.repo.FindByID(ctx, req.UserId)
 	if err != nil {
 		return nil, status.Errorf(codes.NotFound, "User not found")
 	}
 
-	if err := s.repo.Delete(ctx, req.UserId); err != nil {
+	err = s.repo.SoftDelete(ctx, req.UserId, time.Now())
+	if err == mongo.ErrNoDocuments {
+		return nil, status.Errorf(codes.NotFound, "User not found")
+	}
+	if err != nil {
 		return nil, err
 	}
 
 	s.invalidateUserCache(ctx, req.UserId)
+	s.cache.Del(ctx, allUsersCacheKey)
 
 	_ = s.eventPublisher.Publish("user_deleted", map[string]string{
 		"user_id": req.UserId,
@@ -282,6 +365,34 @@ func (s *UserService) DeleteUser(ctx context.Context, req *proto.DeleteUserReque
 	}, nil
 }
 
+func (s *UserService) RestoreUser(ctx context.Context, req *proto.RestoreUserRequest) (*proto.RestoreUserResponse, error) {
+	if _, err := primitive.ObjectIDFromHex(req.UserId); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid user ID: %v", err)
+	}
+
+	err := s.repo.Restore(ctx, req.UserId)
+	if err == mongo.ErrNoDocuments {
+		return nil, status.Errorf(codes.NotFound, "Deleted user not found")
+	}
+	if mongo.IsDuplicateKeyError(err) {
+		return nil, status.Errorf(codes.AlreadyExists, "Email уже зарегистрирован")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s.invalidateUserCache(ctx, req.UserId)
+	s.cache.Del(ctx, allUsersCacheKey)
+
+	_ = s.eventPublisher.Publish("user_restored", map[string]string{
+		"user_id": req.UserId,
+	})
+
+	return &proto.RestoreUserResponse{
+		Success: true,
+	}, nil
+}
+
 func (s *UserService) DeleteAllUsersCache(ctx context.Context, req *proto.DeleteAllUsersCacheRequest) (*proto.DeleteAllUsersCacheResponse, error) {
 	keys, err := s.cache.Keys(ctx, userCacheKeyPrefix+"*").Result()
 	if err != nil {
@@ -324,6 +435,8 @@ func (s *UserService) ConfirmUser(ctx context.Context, req *proto.ConfirmUserReq
 	if err := s.repo.Update(ctx, user); err != nil {
 		return nil, err
 	}
+	// версия профиля изменилась, кэш GetUser отдал бы устаревший ETag
+	s.invalidateUserCache(ctx, user.ID.Hex())
 
 	s.cache.Del(ctx, "confirm_code:"+req.Email)
 
@@ -337,3 +450,13 @@ func (s *UserService) ConfirmUser(ctx context.Context, req *proto.ConfirmUserReq
 		Message: "User confirmed successfully",
 	}, nil
 }
+
+func (s *UserService) SetCartRemindersOptOut(ctx context.Context, req *proto.SetCartRemindersOptOutRequest) (*proto.SetCartRemindersOptOutResponse, error) {
+	if err := s.repo.SetCartRemindersOptOut(ctx, req.UserId, req.OptOut); err != nil {
+		return nil, status.Errorf(codes.NotFound, "User not found")
+	}
+
+	s.cache.Del(ctx, userCacheKeyPrefix+req.UserId)
+
+	return &proto.SetCartRemindersOptOutResponse{Success: true}, nil
+}
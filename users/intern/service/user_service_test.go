@@ -1,10 +1,14 @@
 package service
 
 import (
+	"gotune/users/intern/entity"
 	"gotune/users/pkg/hash"
+	"gotune/users/proto"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 )
 
 func TestHashPassword(t *testing.T) {
@@ -18,3 +22,52 @@ func TestCheckPassword(t *testing.T) {
 	match := hash.CheckPasswordHash("secure123", hashVal)
 	assert.True(t, match)
 }
+
+func TestUserUpdatePaths(t *testing.T) {
+	paths, err := userUpdatePaths(&proto.UpdateUserRequest{Email: "new@example.com"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"email"}, paths)
+
+	paths, err = userUpdatePaths(&proto.UpdateUserRequest{
+		Username:   "ivan",
+		Email:      "ignored@example.com",
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"username"}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"username"}, paths)
+
+	_, err = userUpdatePaths(&proto.UpdateUserRequest{UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"email"}}})
+	assert.Error(t, err)
+	_, err = userUpdatePaths(&proto.UpdateUserRequest{UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"confirmed"}}})
+	assert.Error(t, err)
+}
+
+func TestValidateStoreCredit(t *testing.T) {
+	valid := &proto.AddStoreCreditRequest{Amount: 150, Reason: "trade_in", Reference: "trade_in:1"}
+	assert.NoError(t, validateStoreCredit(valid))
+
+	assert.Error(t, validateStoreCredit(&proto.AddStoreCreditRequest{Amount: 0, Reason: "trade_in", Reference: "trade_in:1"}))
+	assert.Error(t, validateStoreCredit(&proto.AddStoreCreditRequest{Amount: -5, Reason: "trade_in", Reference: "trade_in:1"}))
+	assert.Error(t, validateStoreCredit(&proto.AddStoreCreditRequest{Amount: 10, Reference: "trade_in:1"}))
+	assert.Error(t, validateStoreCredit(&proto.AddStoreCreditRequest{Amount: 10, Reason: "trade_in", Reference: "  "}))
+}
+
+func TestValidateAddress(t *testing.T) {
+	valid := &proto.Address{FullName: "Иван Петров", Line1: "ул. Абая, 10", City: "Алматы", PostalCode: "050000", Country: "KZ"}
+	assert.NoError(t, validateAddress(valid))
+
+	assert.Error(t, validateAddress(nil))
+	assert.Error(t, validateAddress(&proto.Address{Line1: "ул. Абая, 10", City: "Алматы", PostalCode: "050000", Country: "KZ"}))
+	assert.Error(t, validateAddress(&proto.Address{FullName: "Иван Петров", Line1: "ул. Абая, 10", City: "Алматы", Country: "KZ"}))
+	assert.Error(t, validateAddress(&proto.Address{FullName: "Иван Петров", Line1: "ул. Абая, 10", City: "Алматы", PostalCode: "050000"}))
+}
+
+func TestWasDefault(t *testing.T) {
+	home := entity.Address{ID: primitive.NewObjectID(), IsDefault: true}
+	office := entity.Address{ID: primitive.NewObjectID()}
+	addresses := []entity.Address{home, office}
+
+	assert.True(t, wasDefault(addresses, home.ID))
+	assert.False(t, wasDefault(addresses, office.ID))
+	assert.False(t, wasDefault(addresses, primitive.NewObjectID()))
+}
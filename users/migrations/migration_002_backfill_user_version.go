@@ -0,0 +1,25 @@
+// users/migrations/migration_002_backfill_user_version.go
+package migrations
+
+import (
+	"context"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func Migration002_BackfillUserVersion(db *mongo.Database) error {
+	users := db.Collection("users")
+
+	// без версии профиль нельзя обновить: фильтр по version его не найдёт
+	_, err := users.UpdateMany(context.Background(),
+		bson.M{"version": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"version": 1}},
+	)
+	if err != nil {
+		return err
+	}
+	log.Println("✅ Migration002_BackfillUserVersion applied")
+	return nil
+}
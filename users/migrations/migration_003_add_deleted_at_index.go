@@ -0,0 +1,47 @@
+// users/migrations/migration_003_add_deleted_at_index.go
+package migrations
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// indexNotFoundCode — код ошибки MongoDB IndexNotFound.
+const indexNotFoundCode = 27
+
+func Migration003_AddDeletedAtIndex(db *mongo.Database) error {
+	users := db.Collection("users")
+
+	// для задачи очистки; в индекс попадают только удалённые пользователи
+	_, err := users.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "deleted_at", Value: 1}},
+		Options: options.Index().SetSparse(true),
+	})
+	if err != nil {
+		return err
+	}
+
+	// email_1 из Migration001 учитывал удалённых, и email нельзя было
+	// зарегистрировать заново до очистки. Частичный индекс не умеет
+	// $exists: false, поэтому email уникален в паре с deleted_at: у
+	// активных пользователей deleted_at пуст, у удалённых — время удаления.
+	_, err = users.Indexes().DropOne(context.Background(), "email_1")
+	var cmdErr mongo.CommandError
+	if err != nil && !(errors.As(err, &cmdErr) && cmdErr.Code == indexNotFoundCode) {
+		return err
+	}
+	_, err = users.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "email", Value: 1}, {Key: "deleted_at", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+	log.Println("✅ Migration003_AddDeletedAtIndex applied")
+	return nil
+}
@@ -0,0 +1,26 @@
+package migrations
+
+import (
+	"context"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func Migration004_AddStoreCreditIndexes(db *mongo.Database) error {
+	_, err := db.Collection("store_credit_transactions").Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		// одно начисление на основание
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "reference", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}}},
+	})
+	if err != nil {
+		return err
+	}
+	log.Println("✅ Migration004_AddStoreCreditIndexes applied")
+	return nil
+}
@@ -17,6 +17,9 @@ type Migration struct {
 func RunAll(db *mongo.Database) error {
 	migrations := []Migration{
 		{Name: "Migration001_AddUserIndex", Func: Migration001_AddUserIndex},
+		{Name: "Migration002_BackfillUserVersion", Func: Migration002_BackfillUserVersion},
+		{Name: "Migration003_AddDeletedAtIndex", Func: Migration003_AddDeletedAtIndex},
+		{Name: "Migration004_AddStoreCreditIndexes", Func: Migration004_AddStoreCreditIndexes},
 	}
 
 	applied := db.Collection("migrations")
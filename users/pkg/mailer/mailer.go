@@ -1,8 +1,15 @@
 package mailer
 
 import (
+	"bytes"
+	"encoding/base64"
 	"fmt"
+	"mime"
+	"mime/multipart"
 	"net/smtp"
+	"net/textproto"
+	"os"
+	"strconv"
 )
 
 type SMTPMailer struct {
@@ -25,3 +32,85 @@ func (m *SMTPMailer) SendEmail(to, subject, body string) error {
 
 	return smtp.SendMail(addr, auth, m.From, []string{to}, msg)
 }
+
+// SendEmailWithAttachment отправляет письмо с одним вложением (multipart/mixed).
+func (m *SMTPMailer) SendEmailWithAttachment(to, subject, body, filename, contentType string, attachment []byte) error {
+	auth := smtp.PlainAuth("", m.Username, m.Password, m.Host)
+
+	addr := fmt.Sprintf("%s:%d", m.Host, m.Port)
+
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+
+	buf.WriteString("To: " + to + "\r\n")
+	buf.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", subject) + "\r\n")
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: multipart/mixed; boundary=" + writer.Boundary() + "\r\n")
+	buf.WriteString("\r\n")
+
+	textPart, err := writer.CreatePart(textproto.MIMEHeader{
+		"Content-Type": {"text/plain; charset=utf-8"},
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := textPart.Write([]byte(body + "\r\n")); err != nil {
+		return err
+	}
+
+	filePart, err := writer.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {contentType},
+		"Content-Transfer-Encoding": {"base64"},
+		"Content-Disposition":       {fmt.Sprintf("attachment; filename=%q", filename)},
+	})
+	if err != nil {
+		return err
+	}
+	encoded := base64.StdEncoding.EncodeToString(attachment)
+	for len(encoded) > 76 {
+		if _, err := filePart.Write([]byte(encoded[:76] + "\r\n")); err != nil {
+			return err
+		}
+		encoded = encoded[76:]
+	}
+	if _, err := filePart.Write([]byte(encoded + "\r\n")); err != nil {
+		return err
+	}
+
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	return smtp.SendMail(addr, auth, m.From, []string{to}, buf.Bytes())
+}
+
+// FromEnv настраивает отправку через SMTP по переменным окружения
+// SMTP_HOST, SMTP_PORT, SMTP_USERNAME, SMTP_PASSWORD и SMTP_FROM.
+// По умолчанию используется smtp.gmail.com:587, отправитель — SMTP_USERNAME.
+func FromEnv() (*SMTPMailer, error) {
+	m := &SMTPMailer{
+		Host:     envOr("SMTP_HOST", "smtp.gmail.com"),
+		Port:     587,
+		Username: os.Getenv("SMTP_USERNAME"),
+		Password: os.Getenv("SMTP_PASSWORD"),
+	}
+	m.From = envOr("SMTP_FROM", m.Username)
+	if v := os.Getenv("SMTP_PORT"); v != "" {
+		port, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("некорректный SMTP_PORT %q", v)
+		}
+		m.Port = port
+	}
+	if m.Username == "" || m.Password == "" {
+		return nil, fmt.Errorf("не заданы SMTP_USERNAME и SMTP_PASSWORD")
+	}
+	return m, nil
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
@@ -0,0 +1,30 @@
+package entity
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Wishlist struct {
+	ID     primitive.ObjectID `bson:"_id,omitempty"`
+	UserID primitive.ObjectID `bson:"user_id"`
+	Name   string             `bson:"name"`
+	Items  []WishlistItem     `bson:"items"`
+	// Токен публичной ссылки, пусто — список виден только владельцу
+	ShareToken string    `bson:"share_token,omitempty"`
+	CreatedAt  time.Time `bson:"created_at"`
+}
+
+type WishlistItem struct {
+	InstrumentID      primitive.ObjectID `bson:"instrument_id"`
+	AddedPrice        float64            `bson:"added_price"`
+	AddedAt           time.Time          `bson:"added_at"`
+	NotifyPriceDrop   bool               `bson:"notify_price_drop"`
+	NotifyBackInStock bool               `bson:"notify_back_in_stock"`
+	// Цена и наличие, о которых пользователь уже знает. С ними сравнивается
+	// каталог при обновлении инструмента, чтобы заметить снижение цены
+	// или поступление на склад.
+	LastPrice   float64 `bson:"last_price"`
+	LastInStock bool    `bson:"last_in_stock"`
+}
@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"gotune/wishlist/internal/entity"
+)
+
+var (
+	ErrWishlistNotFound = errors.New("список желаний не найден")
+	ErrItemExists       = errors.New("инструмент уже есть в списке желаний")
+)
+
+type WishlistRepository interface {
+	Create(ctx context.Context, wishlist *entity.Wishlist) (primitive.ObjectID, error)
+	FindByID(ctx context.Context, id primitive.ObjectID) (*entity.Wishlist, error)
+	FindByUser(ctx context.Context, userID primitive.ObjectID) ([]entity.Wishlist, error)
+	FindByShareToken(ctx context.Context, token string) (*entity.Wishlist, error)
+	FindByInstrument(ctx context.Context, instrumentID primitive.ObjectID) ([]entity.Wishlist, error)
+	CountByInstrument(ctx context.Context, instrumentID primitive.ObjectID) (int64, error)
+	Delete(ctx context.Context, id, userID primitive.ObjectID) error
+	AddItem(ctx context.Context, id, userID primitive.ObjectID, item entity.WishlistItem) error
+	RemoveItem(ctx context.Context, id, userID, instrumentID primitive.ObjectID) error
+	SetShareToken(ctx context.Context, id, userID primitive.ObjectID, token string) error
+	UpdateItemSnapshot(ctx context.Context, id, instrumentID primitive.ObjectID, price float64, inStock bool) error
+}
+
+type wishlistRepository struct {
+	collection *mongo.Collection
+}
+
+func NewWishlistRepository(db *mongo.Database) WishlistRepository {
+	return &wishlistRepository{
+		collection: db.Collection("wishlists"),
+	}
+}
+
+func (r *wishlistRepository) Create(ctx context.Context, wishlist *entity.Wishlist) (primitive.ObjectID, error) {
+	res, err := r.collection.InsertOne(ctx, wishlist)
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+	return res.InsertedID.(primitive.ObjectID), nil
+}
+
+func (r *wishlistRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*entity.Wishlist, error) {
+	return r.findOne(ctx, bson.M{"_id": id})
+}
+
+func (r *wishlistRepository) FindByUser(ctx context.Context, userID primitive.ObjectID) ([]entity.Wishlist, error) {
+	return r.find(ctx, bson.M{"user_id": userID})
+}
+
+func (r *wishlistRepository) FindByShareToken(ctx context.Context, token string) (*entity.Wishlist, error) {
+	return r.findOne(ctx, bson.M{"share_token": token})
+}
+
+func (r *wishlistRepository) FindByInstrument(ctx context.Context, instrumentID primitive.ObjectID) ([]entity.Wishlist, error) {
+	return r.find(ctx, bson.M{"items.instrument_id": instrumentID})
+}
+
+func (r *wishlistRepository) CountByInstrument(ctx context.Context, instrumentID primitive.ObjectID) (int64, error) {
+	return r.collection.CountDocuments(ctx, bson.M{"items.instrument_id": instrumentID})
+}
+
+func (r *wishlistRepository) Delete(ctx context.Context, id, userID primitive.ObjectID) error {
+	res, err := r.collection.DeleteOne(ctx, bson.M{"_id": id, "user_id": userID})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return ErrWishlistNotFound
+	}
+	return nil
+}
+
+// AddItem добавляет инструмент, если его ещё нет в списке.
+func (r *wishlistRepository) AddItem(ctx context.Context, id, userID primitive.ObjectID, item entity.WishlistItem) error {
+	if _, err := r.findOne(ctx, bson.M{"_id": id, "user_id": userID}); err != nil {
+		return err
+	}
+
+	filter := bson.M{
+		"_id":                 id,
+		"user_id":             userID,
+		"items.instrument_id": bson.M{"$ne": item.InstrumentID},
+	}
+	res, err := r.collection.UpdateOne(ctx, filter, bson.M{"$push": bson.M{"items": item}})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrItemExists
+	}
+	return nil
+}
+
+func (r *wishlistRepository) RemoveItem(ctx context.Context, id, userID, instrumentID primitive.ObjectID) error {
+	res, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id, "user_id": userID},
+		bson.M{"$pull": bson.M{"items": bson.M{"instrument_id": instrumentID}}},
+	)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrWishlistNotFound
+	}
+	return nil
+}
+
+// SetShareToken публикует список по токену; пустой токен снимает публикацию.
+func (r *wishlistRepository) SetShareToken(ctx context.Context, id, userID primitive.ObjectID, token string) error {
+	update := bson.M{"$set": bson.M{"share_token": token}}
+	if token == "" {
+		update = bson.M{"$unset": bson.M{"share_token": ""}}
+	}
+	res, err := r.collection.UpdateOne(ctx, bson.M{"_id": id, "user_id": userID}, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrWishlistNotFound
+	}
+	return nil
+}
+
+// UpdateItemSnapshot запоминает цену и наличие, о которых пользователь уже
+// знает, чтобы не присылать одно и то же уведомление повторно.
+func (r *wishlistRepository) UpdateItemSnapshot(ctx context.Context, id, instrumentID primitive.ObjectID, price float64, inStock bool) error {
+	_, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id, "items.instrument_id": instrumentID},
+		bson.M{"$set": bson.M{
+			"items.$.last_price":    price,
+			"items.$.last_in_stock": inStock,
+		}},
+	)
+	return err
+}
+
+func (r *wishlistRepository) findOne(ctx context.Context, filter bson.M) (*entity.Wishlist, error) {
+	var wishlist entity.Wishlist
+	err := r.collection.FindOne(ctx, filter).Decode(&wishlist)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrWishlistNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &wishlist, nil
+}
+
+func (r *wishlistRepository) find(ctx context.Context, filter bson.M) ([]entity.Wishlist, error) {
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var wishlists []entity.Wishlist
+	if err := cursor.All(ctx, &wishlists); err != nil {
+		return nil, err
+	}
+	return wishlists, nil
+}
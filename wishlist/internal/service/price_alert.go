@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	usersproto "gotune/users/proto"
+	"gotune/wishlist/internal/entity"
+	"gotune/wishlist/internal/repository"
+	"gotune/wishlist/metrics"
+)
+
+type EmailSender interface {
+	SendEmail(to, subject, body string) error
+}
+
+// alert описывает, о чём нужно сообщить владельцу списка.
+type alert struct {
+	PriceDrop   bool
+	OldPrice    float64
+	BackInStock bool
+}
+
+func (a alert) any() bool {
+	return a.PriceDrop || a.BackInStock
+}
+
+// PriceAlert обрабатывает событие instrument_updated: сравнивает новые цену
+// и наличие с тем, что пользователи видели последний раз, и присылает
+// письмо о снижении цены или поступлении на склад.
+type PriceAlert struct {
+	repo        repository.WishlistRepository
+	userClient  usersproto.UserServiceClient
+	emailSender EmailSender
+}
+
+func NewPriceAlert(repo repository.WishlistRepository, userClient usersproto.UserServiceClient, emailSender EmailSender) *PriceAlert {
+	return &PriceAlert{
+		repo:        repo,
+		userClient:  userClient,
+		emailSender: emailSender,
+	}
+}
+
+func (p *PriceAlert) HandleInstrumentUpdated(payload map[string]string) error {
+	ctx := context.Background()
+
+	instrumentID, err := primitive.ObjectIDFromHex(payload["id"])
+	if err != nil {
+		log.Printf("Некорректный id в событии instrument_updated: %v", err)
+		return nil
+	}
+	price, err := strconv.ParseFloat(payload["price"], 64)
+	if err != nil {
+		log.Printf("Некорректная цена в событии instrument_updated: %v", err)
+		return nil
+	}
+	stock, _ := strconv.Atoi(payload["stock"])
+	inStock := stock > 0
+
+	wishlists, err := p.repo.FindByInstrument(ctx, instrumentID)
+	if err != nil {
+		return err
+	}
+
+	// один инструмент может лежать в нескольких списках пользователя,
+	// письмо при этом отправляется одно
+	var userIDs []primitive.ObjectID
+	byUser := map[primitive.ObjectID][]entity.Wishlist{}
+	for _, w := range wishlists {
+		if _, ok := byUser[w.UserID]; !ok {
+			userIDs = append(userIDs, w.UserID)
+		}
+		byUser[w.UserID] = append(byUser[w.UserID], w)
+	}
+
+	for _, userID := range userIDs {
+		var a alert
+		var changed bool
+		for _, w := range byUser[userID] {
+			item, ok := findItem(w, instrumentID)
+			if !ok {
+				continue
+			}
+			if item.LastPrice != price || item.LastInStock != inStock {
+				changed = true
+			}
+			a = mergeAlerts(a, detectAlert(item, price, inStock))
+		}
+		if !changed {
+			continue
+		}
+
+		if a.any() {
+			if err := p.notify(ctx, userID, payload["name"], price, a); err != nil {
+				// снимок не обновлён — при повторной доставке письмо уйдёт снова
+				return err
+			}
+		}
+
+		for _, w := range byUser[userID] {
+			if err := p.repo.UpdateItemSnapshot(ctx, w.ID, instrumentID, price, inStock); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (p *PriceAlert) notify(ctx context.Context, userID primitive.ObjectID, name string, price float64, a alert) error {
+	user, err := p.userClient.GetUser(ctx, &usersproto.GetUserRequest{UserId: userID.Hex()})
+	if err != nil {
+		return err
+	}
+
+	subject, body := alertEmail(user.Username, name, price, a)
+	if err := p.emailSender.SendEmail(user.Email, subject, body); err != nil {
+		return err
+	}
+
+	if a.PriceDrop {
+		metrics.WishlistAlertsSentTotal.WithLabelValues("price_drop").Inc()
+	}
+	if a.BackInStock {
+		metrics.WishlistAlertsSentTotal.WithLabelValues("back_in_stock").Inc()
+	}
+	return nil
+}
+
+func findItem(w entity.Wishlist, instrumentID primitive.ObjectID) (entity.WishlistItem, bool) {
+	for _, item := range w.Items {
+		if item.InstrumentID == instrumentID {
+			return item, true
+		}
+	}
+	return entity.WishlistItem{}, false
+}
+
+// detectAlert сравнивает новое состояние инструмента с последним известным
+// пользователю с учётом его подписок.
+func detectAlert(item entity.WishlistItem, price float64, inStock bool) alert {
+	var a alert
+	if item.NotifyPriceDrop && price < item.LastPrice {
+		a.PriceDrop = true
+		a.OldPrice = item.LastPrice
+	}
+	if item.NotifyBackInStock && inStock && !item.LastInStock {
+		a.BackInStock = true
+	}
+	return a
+}
+
+func mergeAlerts(a, b alert) alert {
+	if b.PriceDrop && (!a.PriceDrop || b.OldPrice > a.OldPrice) {
+		a.PriceDrop = true
+		a.OldPrice = b.OldPrice
+	}
+	a.BackInStock = a.BackInStock || b.BackInStock
+	return a
+}
+
+func alertEmail(username, name string, price float64, a alert) (string, string) {
+	var subject string
+	var lines []string
+	switch {
+	case a.PriceDrop && a.BackInStock:
+		subject = fmt.Sprintf("GoTune: %s снова в наличии и дешевле", name)
+	case a.PriceDrop:
+		subject = fmt.Sprintf("GoTune: %s подешевел", name)
+	default:
+		subject = fmt.Sprintf("GoTune: %s снова в наличии", name)
+	}
+	if a.PriceDrop {
+		lines = append(lines, fmt.Sprintf("Цена снизилась с %.2f до %.2f.", a.OldPrice, price))
+	}
+	if a.BackInStock {
+		lines = append(lines, fmt.Sprintf("Инструмент снова в наличии по цене %.2f.", price))
+	}
+
+	body := fmt.Sprintf("Здравствуйте, %s!\n\n%s из вашего списка желаний:\n%s\n\n"+
+		"Чтобы не получать такие письма, отключите уведомления для товара в списке желаний.",
+		username, name, strings.Join(lines, "\n"))
+	return subject, body
+}
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"gotune/wishlist/internal/entity"
+)
+
+func TestDetectAlertPriceDrop(t *testing.T) {
+	item := entity.WishlistItem{NotifyPriceDrop: true, LastPrice: 1200, LastInStock: true}
+
+	a := detectAlert(item, 999, true)
+	assert.True(t, a.PriceDrop)
+	assert.Equal(t, 1200.0, a.OldPrice)
+	assert.False(t, a.BackInStock)
+
+	assert.False(t, detectAlert(item, 1300, true).any())
+
+	item.NotifyPriceDrop = false
+	assert.False(t, detectAlert(item, 999, true).any())
+}
+
+func TestDetectAlertBackInStock(t *testing.T) {
+	item := entity.WishlistItem{NotifyBackInStock: true, LastPrice: 500}
+
+	assert.True(t, detectAlert(item, 500, true).BackInStock)
+	assert.False(t, detectAlert(item, 500, false).any())
+
+	item.LastInStock = true
+	assert.False(t, detectAlert(item, 500, true).any())
+}
+
+func TestMergeAlertsKeepsHighestOldPrice(t *testing.T) {
+	a := mergeAlerts(alert{}, alert{PriceDrop: true, OldPrice: 900})
+	a = mergeAlerts(a, alert{PriceDrop: true, OldPrice: 1000})
+	a = mergeAlerts(a, alert{BackInStock: true})
+
+	assert.Equal(t, alert{PriceDrop: true, OldPrice: 1000, BackInStock: true}, a)
+}
+
+func TestAlertEmail(t *testing.T) {
+	subject, body := alertEmail("berik", "Fender Stratocaster", 799, alert{PriceDrop: true, OldPrice: 899})
+	assert.Equal(t, "GoTune: Fender Stratocaster подешевел", subject)
+	assert.Contains(t, body, "Здравствуйте, berik!")
+	assert.Contains(t, body, "Цена снизилась с 899.00 до 799.00.")
+
+	subject, _ = alertEmail("berik", "Fender Stratocaster", 799, alert{BackInStock: true})
+	assert.Equal(t, "GoTune: Fender Stratocaster снова в наличии", subject)
+}
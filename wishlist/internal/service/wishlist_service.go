@@ -0,0 +1,281 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	instrumentsproto "gotune/instruments/proto"
+	"gotune/wishlist/internal/entity"
+	"gotune/wishlist/internal/repository"
+	"gotune/wishlist/metrics"
+	"gotune/wishlist/proto"
+)
+
+const (
+	maxWishlists          = 20
+	maxWishlistNameLength = 50
+)
+
+type WishlistService struct {
+	repo             repository.WishlistRepository
+	instrumentClient instrumentsproto.InstrumentServiceClient
+	proto.UnimplementedWishlistServiceServer
+}
+
+func NewWishlistService(repo repository.WishlistRepository, instrumentClient instrumentsproto.InstrumentServiceClient) *WishlistService {
+	return &WishlistService{
+		repo:             repo,
+		instrumentClient: instrumentClient,
+	}
+}
+
+func (s *WishlistService) CreateWishlist(ctx context.Context, req *proto.CreateWishlistRequest) (*proto.CreateWishlistResponse, error) {
+	userID, err := primitive.ObjectIDFromHex(req.UserId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid user ID: %v", err)
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len([]rune(name)) > maxWishlistNameLength {
+		return nil, status.Errorf(codes.InvalidArgument, "название списка должно быть от 1 до %d символов", maxWishlistNameLength)
+	}
+
+	existing, err := s.repo.FindByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) >= maxWishlists {
+		return nil, status.Errorf(codes.FailedPrecondition, "можно создать не более %d списков желаний", maxWishlists)
+	}
+
+	id, err := s.repo.Create(ctx, &entity.Wishlist{
+		UserID:    userID,
+		Name:      name,
+		Items:     []entity.WishlistItem{},
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.CreateWishlistResponse{WishlistId: id.Hex()}, nil
+}
+
+func (s *WishlistService) GetWishlists(ctx context.Context, req *proto.GetWishlistsRequest) (*proto.GetWishlistsResponse, error) {
+	userID, err := primitive.ObjectIDFromHex(req.UserId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid user ID: %v", err)
+	}
+
+	wishlists, err := s.repo.FindByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &proto.GetWishlistsResponse{}
+	for i := range wishlists {
+		resp.Wishlists = append(resp.Wishlists, s.wishlistToProto(ctx, &wishlists[i]))
+	}
+	return resp, nil
+}
+
+func (s *WishlistService) DeleteWishlist(ctx context.Context, req *proto.DeleteWishlistRequest) (*proto.DeleteWishlistResponse, error) {
+	userID, id, err := parseWishlistRef(req.UserId, req.WishlistId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Delete(ctx, id, userID); err != nil {
+		return nil, wishlistError(err)
+	}
+
+	return &proto.DeleteWishlistResponse{Success: true}, nil
+}
+
+// AddWishlistItem добавляет инструмент в список. Текущие цена и наличие
+// запоминаются как отправная точка для уведомлений.
+func (s *WishlistService) AddWishlistItem(ctx context.Context, req *proto.AddWishlistItemRequest) (*proto.AddWishlistItemResponse, error) {
+	userID, id, err := parseWishlistRef(req.UserId, req.WishlistId)
+	if err != nil {
+		return nil, err
+	}
+	instrumentID, err := primitive.ObjectIDFromHex(req.InstrumentId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid instrument ID: %v", err)
+	}
+
+	inst, err := s.instrumentClient.GetInstrumentByID(ctx, &instrumentsproto.GetInstrumentByIDRequest{Id: req.InstrumentId})
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "инструмент %s не найден", req.InstrumentId)
+	}
+
+	item := entity.WishlistItem{
+		InstrumentID:      instrumentID,
+		AddedPrice:        inst.Price,
+		AddedAt:           time.Now(),
+		NotifyPriceDrop:   req.NotifyPriceDrop,
+		NotifyBackInStock: req.NotifyBackInStock,
+		LastPrice:         inst.Price,
+		LastInStock:       inst.Stock > 0,
+	}
+	if err := s.repo.AddItem(ctx, id, userID, item); err != nil {
+		return nil, wishlistError(err)
+	}
+
+	metrics.WishlistItemsAddedTotal.Inc()
+
+	return &proto.AddWishlistItemResponse{Success: true}, nil
+}
+
+func (s *WishlistService) RemoveWishlistItem(ctx context.Context, req *proto.RemoveWishlistItemRequest) (*proto.RemoveWishlistItemResponse, error) {
+	userID, id, err := parseWishlistRef(req.UserId, req.WishlistId)
+	if err != nil {
+		return nil, err
+	}
+	instrumentID, err := primitive.ObjectIDFromHex(req.InstrumentId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid instrument ID: %v", err)
+	}
+
+	if err := s.repo.RemoveItem(ctx, id, userID, instrumentID); err != nil {
+		return nil, wishlistError(err)
+	}
+
+	return &proto.RemoveWishlistItemResponse{Success: true}, nil
+}
+
+// ShareWishlist публикует список по случайному токену или отзывает ссылку.
+// Повторная публикация возвращает уже выданный токен.
+func (s *WishlistService) ShareWishlist(ctx context.Context, req *proto.ShareWishlistRequest) (*proto.ShareWishlistResponse, error) {
+	userID, id, err := parseWishlistRef(req.UserId, req.WishlistId)
+	if err != nil {
+		return nil, err
+	}
+
+	if !req.Public {
+		if err := s.repo.SetShareToken(ctx, id, userID, ""); err != nil {
+			return nil, wishlistError(err)
+		}
+		return &proto.ShareWishlistResponse{}, nil
+	}
+
+	wishlist, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, wishlistError(err)
+	}
+	if wishlist.UserID != userID {
+		return nil, wishlistError(repository.ErrWishlistNotFound)
+	}
+	if wishlist.ShareToken != "" {
+		return &proto.ShareWishlistResponse{ShareToken: wishlist.ShareToken}, nil
+	}
+
+	token, err := newShareToken()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.SetShareToken(ctx, id, userID, token); err != nil {
+		return nil, wishlistError(err)
+	}
+
+	return &proto.ShareWishlistResponse{ShareToken: token}, nil
+}
+
+// GetSharedWishlist отдаёт опубликованный список без идентификатора владельца.
+func (s *WishlistService) GetSharedWishlist(ctx context.Context, req *proto.GetSharedWishlistRequest) (*proto.Wishlist, error) {
+	if req.ShareToken == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "не указан токен списка")
+	}
+
+	wishlist, err := s.repo.FindByShareToken(ctx, req.ShareToken)
+	if err != nil {
+		return nil, wishlistError(err)
+	}
+
+	resp := s.wishlistToProto(ctx, wishlist)
+	resp.UserId = ""
+	return resp, nil
+}
+
+// CountWishlistReferences считает списки, в которых есть инструмент.
+// Вызывается задачей очистки каталога перед окончательным удалением.
+func (s *WishlistService) CountWishlistReferences(ctx context.Context, req *proto.CountWishlistReferencesRequest) (*proto.CountWishlistReferencesResponse, error) {
+	instrumentID, err := primitive.ObjectIDFromHex(req.InstrumentId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid instrument ID: %v", err)
+	}
+
+	wishlists, err := s.repo.CountByInstrument(ctx, instrumentID)
+	if err != nil {
+		return nil, err
+	}
+	return &proto.CountWishlistReferencesResponse{Wishlists: wishlists}, nil
+}
+
+// wishlistToProto дополняет позиции текущими названием, ценой и наличием
+// из каталога.
+func (s *WishlistService) wishlistToProto(ctx context.Context, wishlist *entity.Wishlist) *proto.Wishlist {
+	resp := &proto.Wishlist{
+		Id:         wishlist.ID.Hex(),
+		UserId:     wishlist.UserID.Hex(),
+		Name:       wishlist.Name,
+		ShareToken: wishlist.ShareToken,
+		CreatedAt:  wishlist.CreatedAt.Unix(),
+	}
+	for _, item := range wishlist.Items {
+		p := &proto.WishlistItem{
+			InstrumentId:      item.InstrumentID.Hex(),
+			AddedPrice:        item.AddedPrice,
+			AddedAt:           item.AddedAt.Unix(),
+			NotifyPriceDrop:   item.NotifyPriceDrop,
+			NotifyBackInStock: item.NotifyBackInStock,
+		}
+		inst, err := s.instrumentClient.GetInstrumentByID(ctx, &instrumentsproto.GetInstrumentByIDRequest{Id: p.InstrumentId})
+		if err == nil {
+			p.Name = inst.Name
+			p.Price = inst.Price
+			p.InStock = inst.Stock > 0
+			p.Available = true
+		}
+		resp.Items = append(resp.Items, p)
+	}
+	return resp
+}
+
+func parseWishlistRef(userIDHex, wishlistIDHex string) (primitive.ObjectID, primitive.ObjectID, error) {
+	userID, err := primitive.ObjectIDFromHex(userIDHex)
+	if err != nil {
+		return primitive.NilObjectID, primitive.NilObjectID, status.Errorf(codes.InvalidArgument, "invalid user ID: %v", err)
+	}
+	id, err := primitive.ObjectIDFromHex(wishlistIDHex)
+	if err != nil {
+		return primitive.NilObjectID, primitive.NilObjectID, status.Errorf(codes.InvalidArgument, "invalid wishlist ID: %v", err)
+	}
+	return userID, id, nil
+}
+
+func wishlistError(err error) error {
+	switch {
+	case errors.Is(err, repository.ErrWishlistNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, repository.ErrItemExists):
+		return status.Error(codes.AlreadyExists, err.Error())
+	}
+	return err
+}
+
+func newShareToken() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
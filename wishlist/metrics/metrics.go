@@ -0,0 +1,28 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	WishlistItemsAddedTotal = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "wishlist_items_added_total",
+			Help: "Total number of instruments added to wishlists",
+		},
+	)
+	WishlistAlertsSentTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "wishlist_alerts_sent_total",
+			Help: "Total number of wishlist alert emails sent",
+		},
+		[]string{"kind"},
+	)
+)
+
+func Register() {
+	prometheus.MustRegister(
+		WishlistItemsAddedTotal,
+		WishlistAlertsSentTotal,
+	)
+}
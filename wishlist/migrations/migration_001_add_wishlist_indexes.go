@@ -0,0 +1,33 @@
+package migrations
+
+import (
+	"context"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func Migration001_AddWishlistIndexes(db *mongo.Database) error {
+	wishlists := db.Collection("wishlists")
+
+	_, err := wishlists.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}},
+		},
+		{
+			// по нему consumer находит списки с обновлённым инструментом
+			Keys: bson.D{{Key: "items.instrument_id", Value: 1}},
+		},
+		{
+			Keys:    bson.D{{Key: "share_token", Value: 1}},
+			Options: options.Index().SetUnique(true).SetSparse(true),
+		},
+	})
+	if err != nil {
+		return err
+	}
+	log.Println("✅ Migration001_AddWishlistIndexes applied")
+	return nil
+}